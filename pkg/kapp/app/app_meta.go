@@ -19,6 +19,25 @@ type Meta struct {
 
 	UsedGVs []schema.GroupVersion `json:"usedGVs,omitempty"`
 	UsedGKs *[]schema.GroupKind   `json:"usedGKs,omitempty"`
+
+	// IdentityAnnotationDisabled is set once app was deployed without
+	// identity annotation, hence some of its resources may not have it
+	IdentityAnnotationDisabled bool `json:"identityAnnotationDisabled,omitempty"`
+
+	// SharedResources are resources co-owned via kapp.k14s.io/shared annotation.
+	// They are recorded since they may be labeled with label of another co-owner.
+	SharedResources []SharedResourceRef `json:"sharedResources,omitempty"`
+}
+
+type SharedResourceRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (r SharedResourceRef) key() string {
+	return r.APIVersion + "/" + r.Kind + "/" + r.Namespace + "/" + r.Name
 }
 
 func NewAppMetaFromData(data map[string]string) (Meta, error) {
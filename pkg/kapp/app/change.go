@@ -29,12 +29,13 @@ var _ Change = &ChangeImpl{}
 func (c *ChangeImpl) Name() string     { return c.name }
 func (c *ChangeImpl) Meta() ChangeMeta { return c.meta }
 
-func (c *ChangeImpl) Fail() error {
+func (c *ChangeImpl) Fail(failedResources []string) error {
 	return c.update(func(meta *ChangeMeta) {
 		falseBool := false
 
 		meta.Successful = &falseBool
 		meta.FinishedAt = time.Now().UTC()
+		meta.FailedResources = failedResources
 	})
 }
 
@@ -47,6 +48,18 @@ func (c *ChangeImpl) Succeed() error {
 	})
 }
 
+func (c *ChangeImpl) Pause(pausedBefore, pendingResources []string) error {
+	return c.update(func(meta *ChangeMeta) {
+		falseBool := false
+
+		meta.Successful = &falseBool
+		meta.FinishedAt = time.Now().UTC()
+		meta.Paused = true
+		meta.PausedBefore = pausedBefore
+		meta.PendingResources = pendingResources
+	})
+}
+
 func (c *ChangeImpl) Delete() error {
 	err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Delete(context.TODO(), c.name, metav1.DeleteOptions{})
 	if err != nil {
@@ -85,8 +98,10 @@ type NoopChange struct{}
 
 var _ Change = NoopChange{}
 
-func (NoopChange) Name() string     { return "" }
-func (NoopChange) Meta() ChangeMeta { return ChangeMeta{} }
-func (NoopChange) Fail() error      { return nil }
-func (NoopChange) Succeed() error   { return nil }
-func (NoopChange) Delete() error    { return nil }
+func (NoopChange) Name() string        { return "" }
+func (NoopChange) Meta() ChangeMeta    { return ChangeMeta{} }
+func (NoopChange) Fail([]string) error { return nil }
+func (NoopChange) Succeed() error      { return nil }
+func (NoopChange) Delete() error       { return nil }
+
+func (NoopChange) Pause([]string, []string) error { return nil }
@@ -4,8 +4,13 @@
 package app
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 )
 
@@ -17,6 +22,102 @@ type ChangeMeta struct {
 	Description string `json:"description,omitempty"`
 
 	Namespaces []string `json:"namespaces,omitempty"`
+
+	// FailedResources contains unique keys of resources
+	// that did not successfully finish when change failed
+	FailedResources []string `json:"failedResources,omitempty"`
+
+	// Paused indicates that change stopped before applying PausedBefore
+	// resources (marked with kapp.k14s.io/pause-before annotation);
+	// PendingResources were not applied and are deployed on resume
+	Paused           bool     `json:"paused,omitempty"`
+	PausedBefore     []string `json:"pausedBefore,omitempty"`
+	PendingResources []string `json:"pendingResources,omitempty"`
+
+	// Resources contains unique keys of resources that were deployed
+	// during change (used to detect resources deleted outside of kapp).
+	// Keys are stored compressed since large apps may have many of them.
+	Resources []string `json:"resources,omitempty"`
+}
+
+type changeMetaAlias ChangeMeta
+
+type changeMetaJSON struct {
+	changeMetaAlias
+	CompressedResources string `json:"compressedResources,omitempty"`
+}
+
+func (m ChangeMeta) MarshalJSON() ([]byte, error) {
+	compressed, err := m.compressResources(m.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	typedMeta := changeMetaJSON{changeMetaAlias(m), compressed}
+	typedMeta.changeMetaAlias.Resources = nil
+
+	return json.Marshal(typedMeta)
+}
+
+func (m *ChangeMeta) UnmarshalJSON(data []byte) error {
+	var typedMeta changeMetaJSON
+
+	err := json.Unmarshal(data, &typedMeta)
+	if err != nil {
+		return err
+	}
+
+	*m = ChangeMeta(typedMeta.changeMetaAlias)
+
+	if len(typedMeta.CompressedResources) > 0 {
+		m.Resources, err = m.decompressResources(typedMeta.CompressedResources)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ChangeMeta) compressResources(keys []string) (string, error) {
+	if len(keys) == 0 {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+
+	writer := gzip.NewWriter(&buf)
+
+	_, err := writer.Write([]byte(strings.Join(keys, "\n")))
+	if err != nil {
+		return "", fmt.Errorf("Compressing resource keys: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return "", fmt.Errorf("Compressing resource keys: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func (ChangeMeta) decompressResources(data string) ([]string, error) {
+	bs, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("Decoding resource keys: %w", err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(bs))
+	if err != nil {
+		return nil, fmt.Errorf("Decompressing resource keys: %w", err)
+	}
+
+	keysBs, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("Decompressing resource keys: %w", err)
+	}
+
+	return strings.Split(string(keysBs), "\n"), nil
 }
 
 func NewChangeMetaFromString(data string) ChangeMeta {
@@ -0,0 +1,45 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeMetaCompressesResources(t *testing.T) {
+	var keys []string
+	for i := 0; i < 1000; i++ {
+		keys = append(keys, fmt.Sprintf("default//ConfigMap/config-%d", i))
+	}
+
+	meta := ctlapp.ChangeMeta{Description: "update: ...", Resources: keys}
+	data := meta.AsString()
+
+	require.NotContains(t, data, "config-1")
+	require.NotContains(t, data, `"resources"`)
+	require.Contains(t, data, `"compressedResources"`)
+	require.Less(t, len(data), len(strings.Join(keys, ","))/5, "Expected resource keys to be compressed")
+
+	decodedMeta := ctlapp.NewChangeMetaFromString(data)
+	require.Equal(t, keys, decodedMeta.Resources)
+	require.Equal(t, "update: ...", decodedMeta.Description)
+}
+
+func TestChangeMetaWithoutResources(t *testing.T) {
+	data := ctlapp.ChangeMeta{Description: "update: ..."}.AsString()
+	require.NotContains(t, data, "esources")
+
+	decodedMeta := ctlapp.NewChangeMetaFromString(data)
+	require.Nil(t, decodedMeta.Resources)
+}
+
+func TestChangeMetaReadsUncompressedResources(t *testing.T) {
+	decodedMeta := ctlapp.NewChangeMetaFromString(`{"startedAt":"2024-01-01T00:00:00Z","resources":["default//ConfigMap/config"]}`)
+	require.Equal(t, []string{"default//ConfigMap/config"}, decodedMeta.Resources)
+}
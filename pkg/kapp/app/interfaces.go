@@ -21,8 +21,10 @@ type App interface {
 	UsedGVs() ([]schema.GroupVersion, error)
 	UsedGKs() (*[]schema.GroupKind, error)
 	UpdateUsedGVsAndGKs([]schema.GroupVersion, []schema.GroupKind) error
+	MarkIdentityAnnotationDisabled() error
+	UpdateSharedResources([]SharedResourceRef) error
 
-	CreateOrUpdate(string, map[string]string, bool) (bool, error)
+	CreateOrUpdate(string, map[string]string, CreateOrUpdateOpts) (bool, error)
 	Exists() (bool, string, error)
 	Delete() error
 	Rename(string, string) error
@@ -34,12 +36,22 @@ type App interface {
 	GCChanges(max int, reviewFunc func(changesToDelete []Change) error) (int, int, error)
 }
 
+type CreateOrUpdateOpts struct {
+	IsDiffRun bool
+	// LabelValue is used as app label value when app is created
+	// (existing apps keep their label value to avoid orphaning resources)
+	LabelValue string
+}
+
 type Change interface {
 	Name() string
 	Meta() ChangeMeta
 
-	Fail() error
+	// Fail records resources that did not successfully finish (if known)
+	Fail(failedResources []string) error
 	Succeed() error
+	// Pause records resources that are deployed once change is resumed
+	Pause(pausedBefore, pendingResources []string) error
 
 	Delete() error
 }
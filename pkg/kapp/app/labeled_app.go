@@ -43,8 +43,10 @@ func (a *LabeledApp) LabelSelector() (labels.Selector, error) {
 func (a *LabeledApp) UsedGVs() ([]schema.GroupVersion, error)                             { return nil, nil }
 func (a *LabeledApp) UsedGKs() (*[]schema.GroupKind, error)                               { return nil, nil }
 func (a *LabeledApp) UpdateUsedGVsAndGKs([]schema.GroupVersion, []schema.GroupKind) error { return nil }
+func (a *LabeledApp) MarkIdentityAnnotationDisabled() error                               { return nil }
+func (a *LabeledApp) UpdateSharedResources([]SharedResourceRef) error                     { return nil }
 
-func (a *LabeledApp) CreateOrUpdate(_ string, _ map[string]string, _ bool) (bool, error) {
+func (a *LabeledApp) CreateOrUpdate(_ string, _ map[string]string, _ CreateOrUpdateOpts) (bool, error) {
 	return false, nil
 }
 func (a *LabeledApp) Exists() (bool, string, error) { return true, "", nil }
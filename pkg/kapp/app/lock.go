@@ -0,0 +1,243 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"carvel.dev/kapp/pkg/kapp/logger"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcoordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+)
+
+const (
+	lockSuffix            = ".lock" + AppSuffix
+	lockAcquireRetryDelay = 1 * time.Second
+)
+
+type LockOpts struct {
+	// Timeout specifies how long to wait for lock held by someone else;
+	// zero value means fail immediately
+	Timeout time.Duration
+	// TTL specifies how long lock stays valid without being renewed
+	// (e.g. when holder crashed)
+	TTL time.Duration
+}
+
+// Lock is an advisory lock for an app backed by a coordination.k8s.io Lease.
+// Lock is renewed periodically while it's held.
+type Lock struct {
+	appName    string
+	nsName     string
+	holder     string
+	opts       LockOpts
+	coreClient kubernetes.Interface
+	logger     logger.Logger
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	doneCh   chan struct{}
+}
+
+func NewLock(app App, coreClient kubernetes.Interface, opts LockOpts, logger logger.Logger) *Lock {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return &Lock{
+		appName:    app.Name(),
+		nsName:     app.Namespace(),
+		holder:     fmt.Sprintf("%s/%d", hostname, os.Getpid()),
+		opts:       opts,
+		coreClient: coreClient,
+		logger:     logger.NewPrefixed("Lock"),
+	}
+}
+
+func (l *Lock) Acquire() error {
+	startTime := time.Now()
+
+	for {
+		acquired, holder, expiresAt, err := l.tryAcquire()
+		if err != nil {
+			return fmt.Errorf("Acquiring lock for app '%s': %w", l.appName, err)
+		}
+		if acquired {
+			l.stopCh = make(chan struct{})
+			l.doneCh = make(chan struct{})
+			go l.renew()
+			return nil
+		}
+
+		if time.Since(startTime) >= l.opts.Timeout {
+			if len(holder) == 0 {
+				return fmt.Errorf("Expected to acquire lock for app '%s', but it was concurrently modified", l.appName)
+			}
+			return fmt.Errorf("Expected to acquire lock for app '%s', but it is held by '%s' (expires at %s)",
+				l.appName, holder, expiresAt.Format(time.RFC3339))
+		}
+
+		if len(holder) == 0 {
+			// Lease was concurrently modified, hence check again
+			l.logger.Debug("waiting for concurrently modified lock")
+		} else {
+			l.logger.Debug("waiting for lock held by '%s'", holder)
+		}
+		time.Sleep(lockAcquireRetryDelay)
+	}
+}
+
+func (l *Lock) Release() error {
+	if l.stopCh == nil {
+		return nil
+	}
+
+	l.stopOnce.Do(func() { close(l.stopCh) })
+	<-l.doneCh
+
+	lease, err := l.leases().Get(context.TODO(), l.leaseName(), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("Releasing lock for app '%s': %w", l.appName, err)
+	}
+
+	if !l.isHeldByUs(lease) {
+		return nil
+	}
+
+	err = l.leases().Delete(context.TODO(), l.leaseName(), metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("Releasing lock for app '%s': %w", l.appName, err)
+	}
+
+	return nil
+}
+
+// tryAcquire returns current holder (and its expiration) when lock is held by someone else.
+// Empty holder is returned if lease was concurrently modified.
+func (l *Lock) tryAcquire() (bool, string, time.Time, error) {
+	lease, err := l.leases().Get(context.TODO(), l.leaseName(), metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return false, "", time.Time{}, err
+		}
+
+		_, err = l.leases().Create(context.TODO(), l.newLease(), metav1.CreateOptions{})
+		if err != nil {
+			if errors.IsAlreadyExists(err) {
+				return false, "", time.Time{}, nil
+			}
+			return false, "", time.Time{}, err
+		}
+		return true, "", time.Time{}, nil
+	}
+
+	holder := l.holderOf(lease)
+	expiresAt := l.expiresAt(lease)
+
+	if len(holder) > 0 && !l.isHeldByUs(lease) && time.Now().Before(expiresAt) {
+		return false, holder, expiresAt, nil
+	}
+
+	// Lock is expired (e.g. holder crashed) or released, hence take it over
+	l.populateSpec(lease)
+
+	_, err = l.leases().Update(context.TODO(), lease, metav1.UpdateOptions{})
+	if err != nil {
+		if errors.IsConflict(err) {
+			return false, "", time.Time{}, nil
+		}
+		return false, "", time.Time{}, err
+	}
+
+	return true, "", time.Time{}, nil
+}
+
+func (l *Lock) renew() {
+	defer close(l.doneCh)
+
+	interval := l.opts.TTL / 3
+
+	for {
+		select {
+		case <-l.stopCh:
+			return
+		case <-time.After(interval):
+		}
+
+		lease, err := l.leases().Get(context.TODO(), l.leaseName(), metav1.GetOptions{})
+		if err != nil {
+			l.logger.Error("renewing lock: %s", err)
+			continue
+		}
+		if !l.isHeldByUs(lease) {
+			l.logger.Error("renewing lock: lock is held by '%s'", l.holderOf(lease))
+			continue
+		}
+
+		now := metav1.NewMicroTime(time.Now())
+		lease.Spec.RenewTime = &now
+
+		_, err = l.leases().Update(context.TODO(), lease, metav1.UpdateOptions{})
+		if err != nil {
+			l.logger.Error("renewing lock: %s", err)
+		}
+	}
+}
+
+func (l *Lock) newLease() *coordinationv1.Lease {
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      l.leaseName(),
+			Namespace: l.nsName,
+		},
+	}
+	l.populateSpec(lease)
+	return lease
+}
+
+func (l *Lock) populateSpec(lease *coordinationv1.Lease) {
+	now := metav1.NewMicroTime(time.Now())
+	ttlSecs := int32(l.opts.TTL.Seconds())
+
+	lease.Spec.HolderIdentity = &l.holder
+	lease.Spec.LeaseDurationSeconds = &ttlSecs
+	lease.Spec.AcquireTime = &now
+	lease.Spec.RenewTime = &now
+}
+
+func (l *Lock) expiresAt(lease *coordinationv1.Lease) time.Time {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return time.Time{}
+	}
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+}
+
+func (l *Lock) isHeldByUs(lease *coordinationv1.Lease) bool {
+	return lease.Spec.HolderIdentity != nil && *lease.Spec.HolderIdentity == l.holder
+}
+
+func (l *Lock) holderOf(lease *coordinationv1.Lease) string {
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
+
+func (l *Lock) leaseName() string { return l.appName + lockSuffix }
+
+func (l *Lock) leases() typedcoordinationv1.LeaseInterface {
+	return l.coreClient.CoordinationV1().Leases(l.nsName)
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"carvel.dev/kapp/pkg/kapp/logger"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	typedcoordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+)
+
+var leasesGroupResource = schema.GroupResource{Group: "coordination.k8s.io", Resource: "leases"}
+
+// fakeLeases keeps leases in memory and detects conflicting updates
+type fakeLeases struct {
+	typedcoordinationv1.LeaseInterface
+
+	lock       sync.Mutex
+	leases     map[string]*coordinationv1.Lease
+	lastVerNum int
+}
+
+func (l *fakeLeases) Get(_ context.Context, name string, _ metav1.GetOptions) (*coordinationv1.Lease, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	lease, found := l.leases[name]
+	if !found {
+		return nil, errors.NewNotFound(leasesGroupResource, name)
+	}
+	return lease.DeepCopy(), nil
+}
+
+func (l *fakeLeases) Create(_ context.Context, lease *coordinationv1.Lease, _ metav1.CreateOptions) (*coordinationv1.Lease, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, found := l.leases[lease.Name]; found {
+		return nil, errors.NewAlreadyExists(leasesGroupResource, lease.Name)
+	}
+	return l.store(lease), nil
+}
+
+func (l *fakeLeases) Update(_ context.Context, lease *coordinationv1.Lease, _ metav1.UpdateOptions) (*coordinationv1.Lease, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	existing, found := l.leases[lease.Name]
+	if !found {
+		return nil, errors.NewNotFound(leasesGroupResource, lease.Name)
+	}
+	if existing.ResourceVersion != lease.ResourceVersion {
+		return nil, errors.NewConflict(leasesGroupResource, lease.Name, nil)
+	}
+	return l.store(lease), nil
+}
+
+func (l *fakeLeases) Delete(_ context.Context, name string, opts metav1.DeleteOptions) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	existing, found := l.leases[name]
+	if !found {
+		return errors.NewNotFound(leasesGroupResource, name)
+	}
+	if opts.Preconditions != nil && opts.Preconditions.ResourceVersion != nil &&
+		*opts.Preconditions.ResourceVersion != existing.ResourceVersion {
+		return errors.NewConflict(leasesGroupResource, name, nil)
+	}
+	delete(l.leases, name)
+	return nil
+}
+
+func (l *fakeLeases) store(lease *coordinationv1.Lease) *coordinationv1.Lease {
+	l.lastVerNum++
+	lease = lease.DeepCopy()
+	lease.ResourceVersion = strconv.Itoa(l.lastVerNum)
+	l.leases[lease.Name] = lease
+	return lease.DeepCopy()
+}
+
+type fakeCoordinationV1 struct {
+	typedcoordinationv1.CoordinationV1Interface
+	leases typedcoordinationv1.LeaseInterface
+}
+
+func (c fakeCoordinationV1) Leases(string) typedcoordinationv1.LeaseInterface { return c.leases }
+
+type fakeLeasesCoreClient struct {
+	kubernetes.Interface
+	coordination fakeCoordinationV1
+}
+
+func (c fakeLeasesCoreClient) CoordinationV1() typedcoordinationv1.CoordinationV1Interface {
+	return c.coordination
+}
+
+func newFakeLeasesCoreClient() (kubernetes.Interface, *fakeLeases) {
+	leases := &fakeLeases{leases: map[string]*coordinationv1.Lease{}}
+	return fakeLeasesCoreClient{coordination: fakeCoordinationV1{leases: leases}}, leases
+}
+
+func newTestLock(holder string, coreClient kubernetes.Interface) *Lock {
+	// Long TTL avoids renewals during tests
+	return &Lock{appName: "app", nsName: "default", holder: holder,
+		opts: LockOpts{TTL: time.Hour}, coreClient: coreClient, logger: logger.NewNoopLogger()}
+}
+
+func TestLockExcludesOtherHolders(t *testing.T) {
+	coreClient, leases := newFakeLeasesCoreClient()
+
+	lock1 := newTestLock("holder1", coreClient)
+	lock2 := newTestLock("holder2", coreClient)
+
+	require.NoError(t, lock1.Acquire())
+
+	lease, err := leases.Get(context.TODO(), "app"+lockSuffix, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "holder1", *lease.Spec.HolderIdentity)
+	require.Equal(t, int32(3600), *lease.Spec.LeaseDurationSeconds)
+
+	err = lock2.Acquire()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Expected to acquire lock for app 'app', but it is held by 'holder1'")
+
+	require.NoError(t, lock1.Release())
+	require.Empty(t, leases.leases, "Expected lease to be deleted on release")
+
+	require.NoError(t, lock2.Acquire())
+	require.NoError(t, lock2.Release())
+}
+
+func TestLockTakesOverExpiredLease(t *testing.T) {
+	coreClient, leases := newFakeLeasesCoreClient()
+
+	crashedHolder := "crashed"
+	ttlSecs := int32(10)
+	renewTime := metav1.NewMicroTime(time.Now().Add(-time.Minute))
+
+	_, err := leases.Create(context.TODO(), &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "app" + lockSuffix, Namespace: "default"},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &crashedHolder,
+			LeaseDurationSeconds: &ttlSecs,
+			RenewTime:            &renewTime,
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	lock := newTestLock("holder1", coreClient)
+	require.NoError(t, lock.Acquire())
+
+	lease, err := leases.Get(context.TODO(), "app"+lockSuffix, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "holder1", *lease.Spec.HolderIdentity)
+
+	require.NoError(t, lock.Release())
+}
+
+func TestLockReleaseKeepsLeaseOfOtherHolder(t *testing.T) {
+	coreClient, leases := newFakeLeasesCoreClient()
+
+	lock := newTestLock("holder1", coreClient)
+	require.NoError(t, lock.Acquire())
+
+	// Simulate lease being taken over after it expired
+	lease, err := leases.Get(context.TODO(), "app"+lockSuffix, metav1.GetOptions{})
+	require.NoError(t, err)
+	otherHolder := "holder2"
+	lease.Spec.HolderIdentity = &otherHolder
+	_, err = leases.Update(context.TODO(), lease, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, lock.Release())
+	require.Len(t, leases.leases, 1, "Expected lease held by someone else to be kept")
+}
+
+func TestLockReleaseWithoutAcquire(t *testing.T) {
+	coreClient, _ := newFakeLeasesCoreClient()
+	require.NoError(t, newTestLock("holder1", coreClient).Release())
+}
+
+// conflictingLeases simulates lease being concurrently created on every attempt
+type conflictingLeases struct {
+	*fakeLeases
+	attempts int
+}
+
+func (l *conflictingLeases) Create(_ context.Context, lease *coordinationv1.Lease, _ metav1.CreateOptions) (*coordinationv1.Lease, error) {
+	l.attempts++
+	return nil, errors.NewAlreadyExists(leasesGroupResource, lease.Name)
+}
+
+func TestLockAcquireConcurrentlyModifiedLeaseRespectsTimeout(t *testing.T) {
+	leases := &conflictingLeases{fakeLeases: &fakeLeases{leases: map[string]*coordinationv1.Lease{}}}
+	coreClient := fakeLeasesCoreClient{coordination: fakeCoordinationV1{leases: leases}}
+
+	err := newTestLock("holder1", coreClient).Acquire()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Expected to acquire lock for app 'app', but it was concurrently modified")
+	require.Equal(t, 1, leases.attempts)
+}
@@ -5,11 +5,14 @@ package app
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -18,6 +21,7 @@ const (
 
 type Preparation struct {
 	resourceTypes ctlres.ResourceTypes
+	openAPISchema *ctlres.OpenAPISchema
 	opts          PrepareResourcesOpts
 }
 
@@ -32,10 +36,44 @@ type PrepareResourcesOpts struct {
 	IntoNamespace    string   // this ns is allowed automatically
 	MapNamespaces    []string // this ns is allowed automatically
 	DefaultNamespace string   // this ns is allowed automatically
+
+	// NamespaceFromLabel places namespaced resources into namespace
+	// specified by the value of this label (applied before IntoNamespace and MapNamespaces)
+	NamespaceFromLabel string
+	// NamespaceExistsFunc is used to check that namespaces
+	// derived from labels exist when they are not part of provided resources
+	NamespaceExistsFunc func(string) (bool, error)
+
+	// SetLabels and SetAnnotations (in 'key=val' format) are added
+	// to all provided resources (resources that already have same keys
+	// with different values are rejected instead of being overridden)
+	SetLabels      []string
+	SetAnnotations []string
+
+	// RequiredLabels are label keys that every provided resource must have
+	RequiredLabels []string
+
+	// MaxResources and MaxResourcesPerKind limit number of provided
+	// resources to catch runaway generated configuration (0 means no limit)
+	MaxResources        int
+	MaxResourcesPerKind int
+
+	StrictUnknownFields bool
+
+	// ValidateCustomResources validates custom resources against schemas
+	// of CRDs provided in the same set of resources (unknown fields
+	// are only reported when StrictUnknownFields is enabled)
+	ValidateCustomResources bool
+
+	// StripManagedFields removes metadata.managedFields from provided resources
+	// (e.g. resources exported from another cluster) before they are applied
+	StripManagedFields bool
 }
 
-func NewPreparation(resourceTypes ctlres.ResourceTypes, opts PrepareResourcesOpts) Preparation {
-	return Preparation{resourceTypes, opts}
+func NewPreparation(resourceTypes ctlres.ResourceTypes,
+	openAPISchema *ctlres.OpenAPISchema, opts PrepareResourcesOpts) Preparation {
+
+	return Preparation{resourceTypes, openAPISchema, opts}
 }
 
 func (a Preparation) PrepareResources(resources []ctlres.Resource) ([]ctlres.Resource, error) {
@@ -49,6 +87,11 @@ func (a Preparation) PrepareResources(resources []ctlres.Resource) ([]ctlres.Res
 		return nil, err
 	}
 
+	err = a.validateResourceCounts(resources)
+	if err != nil {
+		return nil, err
+	}
+
 	resources = a.opts.BeforeModificationFunc(resources)
 
 	resources, err = a.placeIntoNamespace(resources)
@@ -56,11 +99,41 @@ func (a Preparation) PrepareResources(resources []ctlres.Resource) ([]ctlres.Res
 		return nil, err
 	}
 
+	err = a.validateNamespacesFromLabel(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err = a.setLabelsAndAnnotations(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.validateRequiredLabels(resources)
+	if err != nil {
+		return nil, err
+	}
+
 	resources, err = a.addNonce(resources)
 	if err != nil {
 		return nil, err
 	}
 
+	resources, err = a.stripManagedFields(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.validateUnknownFields(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.validateCustomResources(resources)
+	if err != nil {
+		return nil, err
+	}
+
 	return resources, nil
 }
 
@@ -89,6 +162,14 @@ func (a Preparation) placeIntoNamespace(resources []ctlres.Resource) ([]ctlres.R
 				}
 			}
 
+			if len(a.opts.NamespaceFromLabel) > 0 {
+				ns, err := a.namespaceFromLabel(res)
+				if err != nil {
+					return nil, err
+				}
+				res.SetNamespace(ns)
+			}
+
 			if len(a.opts.IntoNamespace) > 0 {
 				res.SetNamespace(a.opts.IntoNamespace)
 			}
@@ -110,6 +191,223 @@ func (a Preparation) placeIntoNamespace(resources []ctlres.Resource) ([]ctlres.R
 	return resources, nil
 }
 
+func (a Preparation) namespaceFromLabel(res ctlres.Resource) (string, error) {
+	ns, found := res.Labels()[a.opts.NamespaceFromLabel]
+	if !found || len(ns) == 0 {
+		return "", fmt.Errorf("Expected resource '%s' to have non-empty label '%s' to determine its namespace (%s)",
+			res.Description(), a.opts.NamespaceFromLabel, res.Origin())
+	}
+	if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+		return "", fmt.Errorf("Expected label '%s' on resource '%s' to be a valid namespace name: %s (%s)",
+			a.opts.NamespaceFromLabel, res.Description(), strings.Join(errs, "; "), res.Origin())
+	}
+	return ns, nil
+}
+
+// validateNamespacesFromLabel makes sure that namespaces derived from labels
+// are either provided as part of resources or already exist in the cluster
+func (a Preparation) validateNamespacesFromLabel(resources []ctlres.Resource) error {
+	if len(a.opts.NamespaceFromLabel) == 0 {
+		return nil
+	}
+
+	providedNss := map[string]struct{}{}
+	for _, res := range resources {
+		if res.APIGroup() == "" && res.Kind() == "Namespace" {
+			providedNss[res.Name()] = struct{}{}
+		}
+	}
+
+	var errs []error
+	checkedNss := map[string]struct{}{}
+
+	for _, res := range resources {
+		ns, found := res.Labels()[a.opts.NamespaceFromLabel]
+		if !found || res.Namespace() != ns {
+			continue // cluster level or placed into another namespace
+		}
+		if _, found := providedNss[ns]; found {
+			continue
+		}
+		if _, found := checkedNss[ns]; found {
+			continue
+		}
+		checkedNss[ns] = struct{}{}
+
+		if a.opts.NamespaceExistsFunc == nil {
+			continue
+		}
+
+		exists, err := a.opts.NamespaceExistsFunc(ns)
+		if err != nil {
+			return fmt.Errorf("Checking namespace '%s' derived from label '%s': %w", ns, a.opts.NamespaceFromLabel, err)
+		}
+		if !exists {
+			errs = append(errs, fmt.Errorf("Namespace '%s' derived from label '%s' on resource '%s' "+
+				"does not exist and is not part of provided resources (%s)", ns, a.opts.NamespaceFromLabel, res.Description(), res.Origin()))
+		}
+	}
+
+	return a.combinedErr(errs)
+}
+
+func (a Preparation) setLabelsAndAnnotations(resources []ctlres.Resource) ([]ctlres.Resource, error) {
+	labels, err := a.parseKVs(a.opts.SetLabels, "--set-label", true)
+	if err != nil {
+		return nil, err
+	}
+
+	anns, err := a.parseKVs(a.opts.SetAnnotations, "--set-annotation", false)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.validateSetKVConflicts(resources, labels, anns)
+	if err != nil {
+		return nil, err
+	}
+
+	var mods []ctlres.StringMapAppendMod
+
+	if len(labels) > 0 {
+		mods = append(mods, ctlres.StringMapAppendMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings([]string{"metadata", "labels"}),
+			KVs:             labels,
+		})
+	}
+	if len(anns) > 0 {
+		mods = append(mods, ctlres.StringMapAppendMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings([]string{"metadata", "annotations"}),
+			KVs:             anns,
+		})
+	}
+
+	for _, res := range resources {
+		for _, mod := range mods {
+			err := mod.Apply(res)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+// validateSetKVConflicts makes sure that values provided in manifests
+// are not silently overridden by values provided via flags
+func (a Preparation) validateSetKVConflicts(resources []ctlres.Resource, labels, anns map[string]string) error {
+	var errs []error
+
+	conflictingKeys := func(existing, kvs map[string]string) []string {
+		var keys []string
+		for key, val := range kvs {
+			if existingVal, found := existing[key]; found && existingVal != val {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	for _, res := range resources {
+		if keys := conflictingKeys(res.Labels(), labels); len(keys) > 0 {
+			errs = append(errs, fmt.Errorf("Expected resource '%s' to not have label(s) '%s' with values different from --set-label (%s)",
+				res.Description(), strings.Join(keys, "', '"), res.Origin()))
+		}
+		if keys := conflictingKeys(res.Annotations(), anns); len(keys) > 0 {
+			errs = append(errs, fmt.Errorf("Expected resource '%s' to not have annotation(s) '%s' with values different from --set-annotation (%s)",
+				res.Description(), strings.Join(keys, "', '"), res.Origin()))
+		}
+	}
+
+	return a.combinedErr(errs)
+}
+
+func (a Preparation) parseKVs(vals []string, flagName string, isLabel bool) (map[string]string, error) {
+	result := map[string]string{}
+
+	for _, kv := range vals {
+		pieces := strings.SplitN(kv, "=", 2)
+		if len(pieces) != 2 {
+			return nil, fmt.Errorf("Expected %s '%s' to be in 'key=val' format", flagName, kv)
+		}
+
+		key, val := pieces[0], pieces[1]
+
+		// Keys used by kapp (e.g. app label) determine ownership of resources
+		if strings.HasPrefix(key, "kapp.k14s.io/") {
+			return nil, fmt.Errorf("Expected %s key '%s' to not use reserved 'kapp.k14s.io/' prefix", flagName, key)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("Expected %s key '%s' to be valid: %s", flagName, key, strings.Join(errs, "; "))
+		}
+		if isLabel {
+			if errs := validation.IsValidLabelValue(val); len(errs) > 0 {
+				return nil, fmt.Errorf("Expected %s value '%s' to be valid: %s", flagName, val, strings.Join(errs, "; "))
+			}
+		}
+
+		result[key] = val
+	}
+
+	return result, nil
+}
+
+// validateRequiredLabels is checked after labels are set via --set-label
+// so that they could be used to satisfy requirements
+func (a Preparation) validateRequiredLabels(resources []ctlres.Resource) error {
+	var errs []error
+
+	for _, res := range resources {
+		var missingKeys []string
+		for _, key := range a.opts.RequiredLabels {
+			if _, found := res.Labels()[key]; !found {
+				missingKeys = append(missingKeys, key)
+			}
+		}
+		if len(missingKeys) > 0 {
+			errs = append(errs, fmt.Errorf("Expected resource '%s' to have required label(s) '%s' (%s)",
+				res.Description(), strings.Join(missingKeys, "', '"), res.Origin()))
+		}
+	}
+
+	return a.combinedErr(errs)
+}
+
+func (a Preparation) validateResourceCounts(resources []ctlres.Resource) error {
+	var errs []error
+
+	if a.opts.MaxResources > 0 && len(resources) > a.opts.MaxResources {
+		errs = append(errs, fmt.Errorf("Expected at most %d resources, but found %d (see --max-resources)",
+			a.opts.MaxResources, len(resources)))
+	}
+
+	if a.opts.MaxResourcesPerKind > 0 {
+		var gks []schema.GroupKind
+		countsByGK := map[schema.GroupKind]int{}
+
+		for _, res := range resources {
+			gk := res.GroupKind()
+			if _, found := countsByGK[gk]; !found {
+				gks = append(gks, gk)
+			}
+			countsByGK[gk]++
+		}
+
+		for _, gk := range gks {
+			if countsByGK[gk] > a.opts.MaxResourcesPerKind {
+				errs = append(errs, fmt.Errorf("Expected at most %d resources of kind '%s', but found %d (see --max-resources-per-kind)",
+					a.opts.MaxResourcesPerKind, gk.String(), countsByGK[gk]))
+			}
+		}
+	}
+
+	return a.combinedErr(errs)
+}
+
 func (a Preparation) addNonce(resources []ctlres.Resource) ([]ctlres.Resource, error) {
 	addNonceMod := ctlres.StringMapAppendMod{
 		ResourceMatcher: ctlres.AllMatcher{},
@@ -135,6 +433,22 @@ func (a Preparation) addNonce(resources []ctlres.Resource) ([]ctlres.Resource, e
 	return resources, nil
 }
 
+func (a Preparation) stripManagedFields(resources []ctlres.Resource) ([]ctlres.Resource, error) {
+	if !a.opts.StripManagedFields {
+		return resources, nil
+	}
+
+	for i, res := range resources {
+		strippedRes, err := ctlres.NewResourceWithManagedFields(res, false).Resource()
+		if err != nil {
+			return nil, err
+		}
+		resources[i] = strippedRes
+	}
+
+	return resources, nil
+}
+
 func (a Preparation) validateBasicInfo(resources []ctlres.Resource) error {
 	var errs []error
 
@@ -153,6 +467,26 @@ func (a Preparation) validateBasicInfo(resources []ctlres.Resource) error {
 	return a.combinedErr(errs)
 }
 
+func (a Preparation) validateUnknownFields(resources []ctlres.Resource) error {
+	if !a.opts.StrictUnknownFields {
+		return nil
+	}
+
+	var errs []error
+
+	for _, res := range resources {
+		paths, err := a.openAPISchema.UnknownFields(res)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			errs = append(errs, fmt.Errorf("Unknown field '%s' on resource '%s' (%s)", path, res.Description(), res.Origin()))
+		}
+	}
+
+	return a.combinedErr(errs)
+}
+
 func (a Preparation) ValidateResources(resources []ctlres.Resource) error {
 	return a.validateAllows(resources)
 }
@@ -179,6 +513,28 @@ func (a Preparation) validateAllows(resources []ctlres.Resource) error {
 	return a.combinedErr(errs)
 }
 
+func (a Preparation) validateCustomResources(resources []ctlres.Resource) error {
+	if !a.opts.ValidateCustomResources {
+		return nil
+	}
+
+	crdSchemas, err := ctlres.NewCRDSchemas(resources)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, res := range resources {
+		for _, desc := range crdSchemas.InvalidFields(res, a.opts.StrictUnknownFields) {
+			errs = append(errs, fmt.Errorf("Invalid resource '%s' according to its CRD schema: %s (%s)",
+				res.Description(), desc, res.Origin()))
+		}
+	}
+
+	return a.combinedErr(errs)
+}
+
 func (a Preparation) combinedErr(errs []error) error {
 	if len(errs) > 0 {
 		var msgs []string
@@ -0,0 +1,331 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"strconv"
+	"testing"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestPreparationSetLabelsAndAnnotations(t *testing.T) {
+	newResources := func() []ctlres.Resource {
+		return []ctlres.Resource{
+			ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+  namespace: default
+`)),
+			ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: same-values
+  namespace: default
+  labels:
+    cost-center: eng
+  annotations:
+    owner: team-a
+`)),
+		}
+	}
+
+	prepare := func(rs []ctlres.Resource, opts ctlapp.PrepareResourcesOpts) ([]ctlres.Resource, error) {
+		opts.BeforeModificationFunc = func(rs []ctlres.Resource) []ctlres.Resource { return rs }
+		return ctlapp.NewPreparation(namespacedResourceTypes{}, nil, opts).PrepareResources(rs)
+	}
+
+	t.Run("adds labels and annotations keeping same manifest values", func(t *testing.T) {
+		rs, err := prepare(newResources(), ctlapp.PrepareResourcesOpts{
+			SetLabels:      []string{"cost-center=eng", "env=prod"},
+			SetAnnotations: []string{"owner=team-a"},
+		})
+		require.NoError(t, err)
+		require.Len(t, rs, 2)
+
+		for _, res := range rs {
+			require.Equal(t, map[string]string{"cost-center": "eng", "env": "prod"}, res.Labels())
+			require.Equal(t, map[string]string{"owner": "team-a"}, res.Annotations())
+		}
+	})
+
+	t.Run("fails when manifest values conflict", func(t *testing.T) {
+		_, err := prepare(newResources(), ctlapp.PrepareResourcesOpts{
+			SetLabels:      []string{"cost-center=ops", "env=prod"},
+			SetAnnotations: []string{"owner=team-b"},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected resource 'configmap/same-values (v1) namespace: default' "+
+			"to not have label(s) 'cost-center' with values different from --set-label")
+		require.Contains(t, err.Error(), "Expected resource 'configmap/same-values (v1) namespace: default' "+
+			"to not have annotation(s) 'owner' with values different from --set-annotation")
+		require.NotContains(t, err.Error(), "configmap/plain")
+	})
+
+	t.Run("rejects reserved keys", func(t *testing.T) {
+		_, err := prepare(newResources(), ctlapp.PrepareResourcesOpts{SetLabels: []string{"kapp.k14s.io/app=123"}})
+		require.EqualError(t, err, "Expected --set-label key 'kapp.k14s.io/app' to not use reserved 'kapp.k14s.io/' prefix")
+	})
+}
+
+func TestPreparationStripManagedFields(t *testing.T) {
+	newResources := func() []ctlres.Resource {
+		return []ctlres.Resource{
+			ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: exported
+  namespace: default
+  managedFields:
+  - manager: kubectl
+    operation: Update
+    apiVersion: v1
+data:
+  key: val
+`)),
+		}
+	}
+
+	prepare := func(rs []ctlres.Resource, opts ctlapp.PrepareResourcesOpts) ([]ctlres.Resource, error) {
+		opts.BeforeModificationFunc = func(rs []ctlres.Resource) []ctlres.Resource { return rs }
+		return ctlapp.NewPreparation(namespacedResourceTypes{}, nil, opts).PrepareResources(rs)
+	}
+
+	t.Run("removes managed fields when enabled", func(t *testing.T) {
+		rs, err := prepare(newResources(), ctlapp.PrepareResourcesOpts{StripManagedFields: true})
+		require.NoError(t, err)
+		require.Len(t, rs, 1)
+
+		require.NotContains(t, rs[0].UnstructuredObject()["metadata"], "managedFields")
+		require.Equal(t, map[string]interface{}{"key": "val"}, rs[0].UnstructuredObject()["data"])
+	})
+
+	t.Run("keeps managed fields by default", func(t *testing.T) {
+		rs, err := prepare(newResources(), ctlapp.PrepareResourcesOpts{})
+		require.NoError(t, err)
+		require.Len(t, rs, 1)
+
+		require.Contains(t, rs[0].UnstructuredObject()["metadata"], "managedFields")
+	})
+}
+
+func TestPreparationNamespaceFromLabel(t *testing.T) {
+	newResources := func(tenantLabels ...string) []ctlres.Resource {
+		var rs []ctlres.Resource
+		for i, tenant := range tenantLabels {
+			labels := "{}"
+			if len(tenant) > 0 {
+				labels = "{tenant: " + tenant + "}"
+			}
+			rs = append(rs, ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-`+strconv.Itoa(i)+`
+  namespace: default
+  labels: `+labels+`
+`)))
+		}
+		return rs
+	}
+
+	tenantNs := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: tenant-b
+  labels:
+    tenant: other
+`))
+
+	prepare := func(rs []ctlres.Resource, opts ctlapp.PrepareResourcesOpts) ([]ctlres.Resource, error) {
+		opts.BeforeModificationFunc = func(rs []ctlres.Resource) []ctlres.Resource { return rs }
+		opts.NamespaceFromLabel = "tenant"
+		return ctlapp.NewPreparation(namespacedResourceTypes{}, nil, opts).PrepareResources(rs)
+	}
+
+	t.Run("places namespaced resources into namespaces from label", func(t *testing.T) {
+		var checkedNss []string
+
+		rs, err := prepare(append(newResources("tenant-a", "tenant-b", "tenant-a"), tenantNs), ctlapp.PrepareResourcesOpts{
+			NamespaceExistsFunc: func(ns string) (bool, error) {
+				checkedNss = append(checkedNss, ns)
+				return true, nil
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, rs, 4)
+
+		require.Equal(t, "tenant-a", rs[0].Namespace())
+		require.Equal(t, "tenant-b", rs[1].Namespace())
+		require.Equal(t, "tenant-a", rs[2].Namespace())
+		require.Equal(t, "", rs[3].Namespace(), "Expected cluster-scoped resources to be skipped")
+
+		require.Equal(t, []string{"tenant-a"}, checkedNss,
+			"Expected each namespace to be checked once unless it is part of provided resources")
+	})
+
+	t.Run("fails when derived namespace does not exist", func(t *testing.T) {
+		_, err := prepare(newResources("tenant-a"), ctlapp.PrepareResourcesOpts{
+			NamespaceExistsFunc: func(string) (bool, error) { return false, nil },
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Namespace 'tenant-a' derived from label 'tenant' on resource "+
+			"'configmap/config-0 (v1) namespace: tenant-a' does not exist and is not part of provided resources")
+	})
+
+	t.Run("fails when label is missing", func(t *testing.T) {
+		_, err := prepare(newResources(""), ctlapp.PrepareResourcesOpts{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected resource 'configmap/config-0 (v1) namespace: default' "+
+			"to have non-empty label 'tenant' to determine its namespace")
+	})
+
+	t.Run("fails when label is not a valid namespace name", func(t *testing.T) {
+		_, err := prepare(newResources("Tenant_A"), ctlapp.PrepareResourcesOpts{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected label 'tenant' on resource 'configmap/config-0 (v1) namespace: default' "+
+			"to be a valid namespace name")
+	})
+
+	t.Run("into-ns takes precedence", func(t *testing.T) {
+		rs, err := prepare(newResources("tenant-a"), ctlapp.PrepareResourcesOpts{
+			IntoNamespace:       "shared",
+			NamespaceExistsFunc: func(string) (bool, error) { return false, nil },
+		})
+		require.NoError(t, err)
+		require.Equal(t, "shared", rs[0].Namespace())
+	})
+}
+
+func TestPreparationRequiredLabels(t *testing.T) {
+	newResources := func() []ctlres.Resource {
+		return []ctlres.Resource{
+			ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: labeled
+  namespace: default
+  labels:
+    team: platform
+    env: prod
+`)),
+			ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: partially-labeled
+  namespace: default
+  labels:
+    team: platform
+`)),
+			ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unlabeled
+  namespace: default
+`)),
+		}
+	}
+
+	prepare := func(rs []ctlres.Resource, opts ctlapp.PrepareResourcesOpts) ([]ctlres.Resource, error) {
+		opts.BeforeModificationFunc = func(rs []ctlres.Resource) []ctlres.Resource { return rs }
+		return ctlapp.NewPreparation(namespacedResourceTypes{}, nil, opts).PrepareResources(rs)
+	}
+
+	t.Run("reports all resources missing labels", func(t *testing.T) {
+		_, err := prepare(newResources(), ctlapp.PrepareResourcesOpts{RequiredLabels: []string{"team", "env"}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Validation errors:")
+		require.Contains(t, err.Error(), "Expected resource 'configmap/partially-labeled (v1) namespace: default' "+
+			"to have required label(s) 'env'")
+		require.Contains(t, err.Error(), "Expected resource 'configmap/unlabeled (v1) namespace: default' "+
+			"to have required label(s) 'team', 'env'")
+		require.NotContains(t, err.Error(), "configmap/labeled")
+	})
+
+	t.Run("allows labels set via --set-label", func(t *testing.T) {
+		rs, err := prepare(newResources(), ctlapp.PrepareResourcesOpts{
+			RequiredLabels: []string{"team"},
+			SetLabels:      []string{"team=platform"},
+		})
+		require.NoError(t, err)
+		require.Len(t, rs, 3)
+	})
+
+	t.Run("does not require labels by default", func(t *testing.T) {
+		_, err := prepare(newResources(), ctlapp.PrepareResourcesOpts{})
+		require.NoError(t, err)
+	})
+}
+
+func TestPreparationResourceCounts(t *testing.T) {
+	newResources := func() []ctlres.Resource {
+		var rs []ctlres.Resource
+		for i := 0; i < 3; i++ {
+			rs = append(rs, ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-`+strconv.Itoa(i)+`
+  namespace: default
+`)))
+		}
+		return append(rs, ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: tenant
+`)))
+	}
+
+	prepare := func(rs []ctlres.Resource, opts ctlapp.PrepareResourcesOpts) ([]ctlres.Resource, error) {
+		opts.BeforeModificationFunc = func(rs []ctlres.Resource) []ctlres.Resource { return rs }
+		return ctlapp.NewPreparation(namespacedResourceTypes{}, nil, opts).PrepareResources(rs)
+	}
+
+	t.Run("allows resources within limits", func(t *testing.T) {
+		rs, err := prepare(newResources(), ctlapp.PrepareResourcesOpts{MaxResources: 4, MaxResourcesPerKind: 3})
+		require.NoError(t, err)
+		require.Len(t, rs, 4)
+	})
+
+	t.Run("reports actual counts when exceeding limits", func(t *testing.T) {
+		_, err := prepare(newResources(), ctlapp.PrepareResourcesOpts{MaxResources: 3, MaxResourcesPerKind: 2})
+		require.EqualError(t, err, `Validation errors:
+- Expected at most 3 resources, but found 4 (see --max-resources)
+- Expected at most 2 resources of kind 'ConfigMap', but found 3 (see --max-resources-per-kind)`)
+	})
+
+	t.Run("does not limit resources by default", func(t *testing.T) {
+		_, err := prepare(newResources(), ctlapp.PrepareResourcesOpts{})
+		require.NoError(t, err)
+	})
+}
+
+// namespacedResourceTypes only knows about ConfigMaps (and cluster-scoped Namespaces)
+type namespacedResourceTypes struct{}
+
+var _ ctlres.ResourceTypes = namespacedResourceTypes{}
+
+func (namespacedResourceTypes) All(bool) ([]ctlres.ResourceType, error) {
+	return []ctlres.ResourceType{
+		{APIResource: metav1.APIResource{Version: "v1", Kind: "ConfigMap", Namespaced: true}},
+		{APIResource: metav1.APIResource{Version: "v1", Kind: "Namespace", Namespaced: false}},
+	}, nil
+}
+func (namespacedResourceTypes) Find(ctlres.Resource) (ctlres.ResourceType, error) {
+	return ctlres.ResourceType{}, nil
+}
+func (namespacedResourceTypes) CanIgnoreFailingGroupVersion(schema.GroupVersion) bool { return false }
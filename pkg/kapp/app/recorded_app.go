@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
@@ -22,7 +23,7 @@ import (
 )
 
 const (
-	kappAppLabelKey                        = "kapp.k14s.io/app"
+	KappAppLabelKey                        = "kapp.k14s.io/app"
 	KappIsConfigmapMigratedAnnotationKey   = "kapp.k14s.io/is-configmap-migrated"
 	KappIsConfigmapMigratedAnnotationValue = ""
 	AppSuffix                              = ".apps.k14s.io"
@@ -130,9 +131,47 @@ func (a *RecordedApp) UpdateUsedGVsAndGKs(gvs []schema.GroupVersion, gks []schem
 	})
 }
 
-func (a *RecordedApp) CreateOrUpdate(prevAppName string, labels map[string]string, isDiffRun bool) (bool, error) {
+// MarkIdentityAnnotationDisabled records that app resources may lack identity annotation.
+// It's never unset since resources that are not updated keep missing the annotation.
+func (a *RecordedApp) MarkIdentityAnnotationDisabled() error {
+	meta, err := a.meta()
+	if err != nil {
+		return err
+	}
+	if meta.IdentityAnnotationDisabled {
+		return nil
+	}
+	return a.update(func(meta *Meta) {
+		meta.IdentityAnnotationDisabled = true
+	})
+}
+
+// UpdateSharedResources records shared resources co-owned by app
+func (a *RecordedApp) UpdateSharedResources(refs []SharedResourceRef) error {
+	refs = append([]SharedResourceRef{}, refs...)
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].key() < refs[j].key()
+	})
+
+	meta, err := a.meta()
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(meta.SharedResources, refs) || (len(meta.SharedResources) == 0 && len(refs) == 0) {
+		return nil
+	}
+
+	return a.update(func(meta *Meta) {
+		meta.SharedResources = refs
+	})
+}
+
+func (a *RecordedApp) CreateOrUpdate(prevAppName string, labels map[string]string, opts CreateOrUpdateOpts) (bool, error) {
 	defer a.logger.DebugFunc("CreateOrUpdate").Finish()
 
+	isDiffRun := opts.IsDiffRun
+
 	app, foundMigratedApp, err := a.find(a.fqName())
 	if err != nil {
 		return false, err
@@ -160,7 +199,7 @@ func (a *RecordedApp) CreateOrUpdate(prevAppName string, labels map[string]strin
 	}
 
 	if prevAppName == "" {
-		return true, a.create(labels, isDiffRun)
+		return true, a.create(labels, opts)
 	}
 
 	app, foundMigratedPrevApp, err := a.find(prevAppName + AppSuffix)
@@ -189,7 +228,7 @@ func (a *RecordedApp) CreateOrUpdate(prevAppName string, labels map[string]strin
 		return false, a.renameConfigMap(app, a.name, a.nsName)
 	}
 
-	return true, a.create(labels, isDiffRun)
+	return true, a.create(labels, opts)
 }
 
 func (a *RecordedApp) find(name string) (*corev1.ConfigMap, bool, error) {
@@ -203,7 +242,12 @@ func (a *RecordedApp) find(name string) (*corev1.ConfigMap, bool, error) {
 	return cm, true, nil
 }
 
-func (a *RecordedApp) create(labels map[string]string, isDiffRun bool) error {
+func (a *RecordedApp) create(labels map[string]string, opts CreateOrUpdateOpts) error {
+	labelValue := opts.LabelValue
+	if len(labelValue) == 0 {
+		labelValue = fmt.Sprintf("%d", time.Now().UTC().UnixNano())
+	}
+
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      a.name,
@@ -217,8 +261,8 @@ func (a *RecordedApp) create(labels map[string]string, isDiffRun bool) error {
 			},
 		},
 		Data: Meta{
-			LabelKey:   kappAppLabelKey,
-			LabelValue: fmt.Sprintf("%d", time.Now().UTC().UnixNano()),
+			LabelKey:   KappAppLabelKey,
+			LabelValue: labelValue,
 			UsedGKs:    &[]schema.GroupKind{},
 		}.AsData(),
 	}
@@ -238,7 +282,7 @@ func (a *RecordedApp) create(labels map[string]string, isDiffRun bool) error {
 		return err
 	}
 
-	if isDiffRun {
+	if opts.IsDiffRun {
 		a.setMeta(*configMap)
 		return nil
 	}
@@ -540,7 +584,7 @@ func (a *RecordedApp) BeginChange(meta ChangeMeta, appChangesMaxToKeep int) (Cha
 
 	err = memoizingChange.syncOnApp()
 	if err != nil {
-		_ = change.Fail()
+		_ = change.Fail(nil)
 		return nil, err
 	}
 
@@ -590,8 +634,8 @@ var _ Change = appTrackingChange{}
 func (c appTrackingChange) Name() string     { return c.change.Name() }
 func (c appTrackingChange) Meta() ChangeMeta { return c.change.meta }
 
-func (c appTrackingChange) Fail() error {
-	err := c.change.Fail()
+func (c appTrackingChange) Fail(failedResources []string) error {
+	err := c.change.Fail(failedResources)
 	if err != nil {
 		return err
 	}
@@ -612,6 +656,17 @@ func (c appTrackingChange) Succeed() error {
 	return err
 }
 
+func (c appTrackingChange) Pause(pausedBefore, pendingResources []string) error {
+	err := c.change.Pause(pausedBefore, pendingResources)
+	if err != nil {
+		return err
+	}
+
+	_ = c.syncOnApp()
+
+	return err
+}
+
 func (c appTrackingChange) Delete() error {
 	return c.change.Delete()
 }
@@ -117,6 +117,7 @@ func (a RecordedAppChanges) Begin(meta ChangeMeta, appChangesMaxToKeep int) (*Ch
 		StartedAt:   time.Now().UTC(),
 		Description: meta.Description,
 		Namespaces:  meta.Namespaces,
+		Resources:   meta.Resources,
 	}
 
 	configMap := &corev1.ConfigMap{
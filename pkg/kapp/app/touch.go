@@ -9,26 +9,62 @@ type Touch struct {
 	Namespaces       []string
 	IgnoreSuccessErr bool
 
+	// Resources is optional and is recorded as part of change
+	Resources []string
+
+	// FailedResourcesFunc is optional and is called when work fails
+	// to record which resources did not successfully finish
+	FailedResourcesFunc func() []string
+
+	// PausedResourcesFunc is optional and is called when work succeeds;
+	// if it returns pending resources change is recorded as paused
+	PausedResourcesFunc func() (pausedBefore []string, pendingResources []string)
+
 	AppChangesMaxToKeep int
+
+	// SkipChangeRecord indicates that work should not be recorded
+	// as an app change (app's last change stays as is)
+	SkipChangeRecord bool
 }
 
 func (t Touch) Do(doFunc func() error) error {
 	meta := ChangeMeta{
 		Description: t.Description,
 		Namespaces:  t.Namespaces,
+		Resources:   t.Resources,
 	}
 
-	change, err := t.App.BeginChange(meta, t.AppChangesMaxToKeep)
-	if err != nil {
-		return err
+	var change Change = NoopChange{}
+
+	if !t.SkipChangeRecord {
+		var err error
+		change, err = t.App.BeginChange(meta, t.AppChangesMaxToKeep)
+		if err != nil {
+			return err
+		}
 	}
 
 	workErr := doFunc()
 	if workErr != nil {
-		_ = change.Fail()
+		var failedResources []string
+		if t.FailedResourcesFunc != nil {
+			failedResources = t.FailedResourcesFunc()
+		}
+		_ = change.Fail(failedResources)
 		return workErr
 	}
 
+	if t.PausedResourcesFunc != nil {
+		pausedBefore, pendingResources := t.PausedResourcesFunc()
+		if len(pendingResources) > 0 {
+			pauseErr := change.Pause(pausedBefore, pendingResources)
+			if pauseErr != nil && !t.IgnoreSuccessErr {
+				return pauseErr
+			}
+			return nil
+		}
+	}
+
 	successErr := change.Succeed()
 	if successErr != nil {
 		if !t.IgnoreSuccessErr {
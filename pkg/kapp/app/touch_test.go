@@ -0,0 +1,112 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"fmt"
+	"testing"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTouchRecordsFailedResources(t *testing.T) {
+	app := &touchRecordingApp{}
+
+	touch := ctlapp.Touch{
+		App:         app,
+		Description: "update: ...",
+		FailedResourcesFunc: func() []string {
+			return []string{"default//ConfigMap/app"}
+		},
+	}
+
+	err := touch.Do(func() error { return fmt.Errorf("apply error") })
+	require.EqualError(t, err, "apply error")
+
+	require.True(t, app.change.failed)
+	require.False(t, app.change.succeeded)
+	require.Equal(t, []string{"default//ConfigMap/app"}, app.change.failedResources)
+}
+
+func TestTouchSucceedsWithoutRecordingFailedResources(t *testing.T) {
+	app := &touchRecordingApp{}
+
+	touch := ctlapp.Touch{
+		App:         app,
+		Description: "update: ...",
+		FailedResourcesFunc: func() []string {
+			require.FailNow(t, "Expected failed resources to not be requested")
+			return nil
+		},
+	}
+
+	err := touch.Do(func() error { return nil })
+	require.NoError(t, err)
+
+	require.True(t, app.change.succeeded)
+	require.False(t, app.change.failed)
+}
+
+func TestTouchFailsWithoutFailedResourcesFunc(t *testing.T) {
+	app := &touchRecordingApp{}
+
+	err := ctlapp.Touch{App: app}.Do(func() error { return fmt.Errorf("apply error") })
+	require.EqualError(t, err, "apply error")
+
+	require.True(t, app.change.failed)
+	require.Nil(t, app.change.failedResources)
+}
+
+func TestTouchSkipsChangeRecord(t *testing.T) {
+	app := &touchRecordingApp{}
+
+	var didWork bool
+
+	err := ctlapp.Touch{App: app, SkipChangeRecord: true}.Do(func() error {
+		didWork = true
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, didWork)
+	require.Nil(t, app.change, "Expected app change to not be started")
+
+	err = ctlapp.Touch{App: app, SkipChangeRecord: true}.Do(func() error { return fmt.Errorf("apply error") })
+	require.EqualError(t, err, "apply error")
+	require.Nil(t, app.change, "Expected app change to not be started")
+}
+
+// touchRecordingApp records outcome of a single change
+type touchRecordingApp struct {
+	ctlapp.App
+
+	change *touchRecordingChange
+}
+
+func (a *touchRecordingApp) BeginChange(meta ctlapp.ChangeMeta, _ int) (ctlapp.Change, error) {
+	a.change = &touchRecordingChange{meta: meta}
+	return a.change, nil
+}
+
+type touchRecordingChange struct {
+	ctlapp.NoopChange
+
+	meta            ctlapp.ChangeMeta
+	failed          bool
+	failedResources []string
+	succeeded       bool
+}
+
+func (c *touchRecordingChange) Meta() ctlapp.ChangeMeta { return c.meta }
+
+func (c *touchRecordingChange) Fail(failedResources []string) error {
+	c.failed = true
+	c.failedResources = failedResources
+	return nil
+}
+
+func (c *touchRecordingChange) Succeed() error {
+	c.succeeded = true
+	return nil
+}
@@ -29,6 +29,11 @@ const (
 
 type AddOrUpdateChangeOpts struct {
 	DefaultUpdateStrategy string
+	// DisableOriginalAnnotation skips recording last applied resource onto resources
+	DisableOriginalAnnotation bool
+	// ReplaceImmutable deletes and recreates immutable Secrets and ConfigMaps
+	// when their content changes (instead of failing)
+	ReplaceImmutable bool
 }
 
 type AddOrUpdateChange struct {
@@ -38,6 +43,7 @@ type AddOrUpdateChange struct {
 	changeSetFactory    ctldiff.ChangeSetFactory
 	opts                AddOrUpdateChangeOpts
 	diffMaskRules       []ctlconf.DiffMaskRule
+	applyStrategyRules  []ctlconf.ApplyStrategyRule
 }
 
 func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
@@ -48,6 +54,9 @@ func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
 		newRes := c.change.NewResource()
 
 		strategy, found := newRes.Annotations()[createStrategyAnnKey]
+		if !found {
+			strategy, found = c.strategyFromRules(newRes, func(rule ctlconf.ApplyStrategyRule) string { return rule.CreateStrategy })
+		}
 		if !found {
 			strategy = string(createStrategyPlainAnnValue)
 		}
@@ -70,12 +79,21 @@ func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
 		newRes := c.change.NewResource()
 
 		strategy, found := newRes.Annotations()[updateStrategyAnnKey]
+		if !found {
+			strategy, found = c.strategyFromRules(newRes, func(rule ctlconf.ApplyStrategyRule) string { return rule.UpdateStrategy })
+		}
 		if !found {
 			strategy = c.opts.DefaultUpdateStrategy
 		}
 
 		switch ClusterChangeApplyStrategyOp(strategy) {
 		case updateStrategyPlainAnnValue:
+			if c.isImmutableDataChange() {
+				if c.opts.ReplaceImmutable {
+					return UpdateAlwaysReplaceStrategy{c}, nil
+				}
+				return UpdateImmutableStrategy{c}, nil
+			}
 			return UpdatePlainStrategy{newRes, c}, nil
 
 		case updateStrategyFallbackOnReplaceAnnValue:
@@ -96,9 +114,32 @@ func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
 	}
 }
 
+// strategyFromRules returns strategy from the last matching rule
+// so that user provided configuration overrides default configuration
+func (c AddOrUpdateChange) strategyFromRules(res ctlres.Resource,
+	strategyFunc func(ctlconf.ApplyStrategyRule) string) (string, bool) {
+
+	var strategy string
+	var found bool
+
+	for _, rule := range c.applyStrategyRules {
+		ruleStrategy := strategyFunc(rule)
+		if len(ruleStrategy) == 0 {
+			continue
+		}
+		matcher := ctlres.AnyMatcher{Matchers: ctlconf.ResourceMatchers(rule.ResourceMatchers).AsResourceMatchers()}
+		if matcher.Matches(res) {
+			strategy = ruleStrategy
+			found = true
+		}
+	}
+
+	return strategy, found
+}
+
 func (c AddOrUpdateChange) replace() error {
 	// TODO do we have to wait for delete to finish?
-	err := c.identifiedResources.Delete(c.change.ExistingResource())
+	err := c.identifiedResources.Delete(c.change.ExistingResource(), ctlres.DeleteOpts{})
 	if err != nil {
 		return err
 	}
@@ -223,7 +264,7 @@ func (c AddOrUpdateChange) recordAppliedResource(savedRes ctlres.Resource) error
 	// It may not be benefitial to record last applied conf
 	// onto resource. This could be useful for resources that
 	// are very large, hence go over annotation value max length.
-	if !savedResWithHistory.AllowsRecordingLastApplied() {
+	if c.opts.DisableOriginalAnnotation || !savedResWithHistory.AllowsRecordingLastApplied() {
 		return nil
 	}
 
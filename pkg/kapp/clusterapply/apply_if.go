@@ -0,0 +1,66 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"strings"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+const (
+	// Value format: '<apiVersion>/<kind>/<namespace>/<name> <condition>'
+	// (e.g. 'apiextensions.k8s.io/v1/CustomResourceDefinition//foos.example.com .spec.versions[0].name == v1')
+	applyIfAnnKey = "kapp.k14s.io/apply-if"
+)
+
+// ApplyIfCondition checks whether referenced live resource
+// satisfies condition before resource is created or updated
+type ApplyIfCondition struct {
+	ref  ctlres.Resource
+	expr *ctlres.ResourceFilterExpr
+}
+
+// NewApplyIfCondition returns nil if resource does not specify apply-if annotation
+func NewApplyIfCondition(res ctlres.Resource) (*ApplyIfCondition, error) {
+	val, found := res.Annotations()[applyIfAnnKey]
+	if !found {
+		return nil, nil
+	}
+
+	pieces := strings.SplitN(strings.TrimSpace(val), " ", 2)
+	if len(pieces) != 2 {
+		return nil, fmt.Errorf("Expected annotation '%s' on resource '%s' to be in "+
+			"'<apiVersion>/<kind>/<namespace>/<name> <condition>' format, but was '%s'", applyIfAnnKey, res.Description(), val)
+	}
+
+	ref, err := ctlres.NewResourceFromRef(pieces[0])
+	if err != nil {
+		return nil, fmt.Errorf("Parsing annotation '%s' on resource '%s': %w", applyIfAnnKey, res.Description(), err)
+	}
+
+	expr, err := ctlres.NewResourceFilterExpr(pieces[1])
+	if err != nil {
+		return nil, fmt.Errorf("Parsing annotation '%s' on resource '%s': %w", applyIfAnnKey, res.Description(), err)
+	}
+
+	return &ApplyIfCondition{ref: ref, expr: expr}, nil
+}
+
+// IsMet returns false if referenced resource does not exist or does not match condition
+func (c ApplyIfCondition) IsMet(identifiedResources ctlres.IdentifiedResources) (bool, error) {
+	liveRes, exists, err := identifiedResources.Exists(c.ref, ctlres.ExistsOpts{})
+	if err != nil {
+		return false, fmt.Errorf("Checking precondition resource '%s': %w", c.ref.Description(), err)
+	}
+	if !exists {
+		return false, nil
+	}
+	return c.expr.Matches(liveRes), nil
+}
+
+func (c ApplyIfCondition) String() string {
+	return fmt.Sprintf("%s %s", c.ref.Description(), c.expr)
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"testing"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddOrUpdateChangeApplyStrategyRules(t *testing.T) {
+	deploymentMatchers := []ctlconf.ResourceMatcher{{
+		APIVersionKindMatcher: &ctlconf.APIVersionKindMatcher{APIVersion: "apps/v1", Kind: "Deployment"},
+	}}
+
+	rules := []ctlconf.ApplyStrategyRule{
+		{ResourceMatchers: deploymentMatchers, UpdateStrategy: string(updateStrategyFallbackOnReplaceAnnValue)},
+		{ResourceMatchers: deploymentMatchers, CreateStrategy: string(createStrategyFallbackOnUpdateAnnValue)},
+		// Later rules take precedence
+		{ResourceMatchers: deploymentMatchers, UpdateStrategy: string(updateStrategyAlwaysReplaceAnnValue)},
+	}
+
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  replicas: 1
+`))
+
+	newAddOrUpdateChange := func(t *testing.T, existingRes, newRes ctlres.Resource) AddOrUpdateChange {
+		change, err := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{}).NewExactChange(existingRes, newRes)
+		require.NoError(t, err)
+		return AddOrUpdateChange{change: change, applyStrategyRules: rules,
+			opts: AddOrUpdateChangeOpts{DefaultUpdateStrategy: string(updateStrategyPlainAnnValue)}}
+	}
+
+	t.Run("uses create strategy from matching rule", func(t *testing.T) {
+		strategy, err := newAddOrUpdateChange(t, nil, existingRes).ApplyStrategy()
+		require.NoError(t, err)
+		require.IsType(t, AddOrFallbackOnUpdateStrategy{}, strategy)
+	})
+
+	t.Run("uses update strategy from last matching rule", func(t *testing.T) {
+		newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  replicas: 2
+`))
+
+		strategy, err := newAddOrUpdateChange(t, existingRes, newRes).ApplyStrategy()
+		require.NoError(t, err)
+		require.IsType(t, UpdateAlwaysReplaceStrategy{}, strategy)
+	})
+
+	t.Run("prefers annotation over matching rule", func(t *testing.T) {
+		newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+  annotations:
+    kapp.k14s.io/update-strategy: skip
+spec:
+  replicas: 2
+`))
+
+		strategy, err := newAddOrUpdateChange(t, existingRes, newRes).ApplyStrategy()
+		require.NoError(t, err)
+		require.IsType(t, UpdateSkipStrategy{}, strategy)
+	})
+
+	t.Run("uses default strategy when no rule matches", func(t *testing.T) {
+		existingCM := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+data:
+  key: old
+`))
+		newCM := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+data:
+  key: new
+`))
+
+		strategy, err := newAddOrUpdateChange(t, existingCM, newCM).ApplyStrategy()
+		require.NoError(t, err)
+		require.IsType(t, UpdatePlainStrategy{}, strategy)
+	})
+}
@@ -23,11 +23,16 @@ type ApplyingChanges struct {
 	applied              map[*ctldgraph.Change]struct{}
 	clusterChangeFactory ClusterChangeFactory
 	ui                   UI
+	metrics              Metrics
 	exitOnError          bool
+
+	// applyFunc is swapped in tests
+	applyFunc func(*ClusterChange) (bool, []string, error)
 }
 
-func NewApplyingChanges(numTotal int, opts ApplyingChangesOpts, clusterChangeFactory ClusterChangeFactory, ui UI, exitOnError bool) *ApplyingChanges {
-	return &ApplyingChanges{numTotal, opts, map[*ctldgraph.Change]struct{}{}, clusterChangeFactory, ui, exitOnError}
+func NewApplyingChanges(numTotal int, opts ApplyingChangesOpts, clusterChangeFactory ClusterChangeFactory, ui UI, metrics Metrics, exitOnError bool) *ApplyingChanges {
+	return &ApplyingChanges{numTotal, opts, map[*ctldgraph.Change]struct{}{},
+		clusterChangeFactory, ui, metrics, exitOnError, (*ClusterChange).Apply}
 }
 
 type applyResult struct {
@@ -69,7 +74,7 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 				defer applyThrottle.Done()
 
 				clusterChange := change.Change.(wrappedClusterChange).ClusterChange
-				retryable, descMsgs, err := clusterChange.Apply()
+				retryable, descMsgs, err := c.applyFunc(clusterChange)
 
 				applyCh <- applyResult{
 					Change:        change,
@@ -89,9 +94,15 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 
 			c.ui.Notify(result.DescMsgs)
 
+			applyOp := string(result.ClusterChange.ApplyOp())
+
 			if result.Err != nil {
 				lastErr = result.Err
-				if !result.Retryable {
+				if result.Retryable {
+					c.metrics.ChangeApplyRetried(applyOp)
+				} else {
+					c.metrics.ChangeApplied(applyOp, false)
+
 					if c.exitOnError {
 						return nil, nil, result.Err
 					}
@@ -101,8 +112,9 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 				continue
 			}
 
+			c.metrics.ChangeApplied(applyOp, true)
 			c.markApplied(result.Change)
-			appliedChanges = append(appliedChanges, WaitingChange{result.Change, result.ClusterChange, time.Now()})
+			appliedChanges = append(appliedChanges, WaitingChange{Graph: result.Change, Cluster: result.ClusterChange, startTime: time.Now()})
 		}
 
 		if len(appliedChanges) > 0 {
@@ -5,6 +5,7 @@ package clusterapply
 
 import (
 	"fmt"
+	"sort"
 
 	ctlconf "carvel.dev/kapp/pkg/kapp/config"
 	"carvel.dev/kapp/pkg/kapp/diff"
@@ -16,8 +17,14 @@ import (
 
 type ChangeSetViewOpts struct {
 	Summary     bool
+	SummaryOnly bool
 	Changes     bool
 	ChangesYAML bool
+	// OpsFilter only affects which changes are shown in detail
+	// (summary still includes all changes that will be applied)
+	OpsFilter ChangeSetViewOpsFilter
+	// Sort only affects order of changes shown in detail
+	Sort ChangeSetViewSort
 	ctldiff.TextDiffViewOpts
 }
 
@@ -27,28 +34,49 @@ type ChangeSetView struct {
 	opts        ChangeSetViewOpts
 
 	changesView *ChangesView
+	// notes are keyed by unique resource key
+	notes map[string]string
 }
 
 func NewChangeSetView(changeViews []ChangeView,
 	maskRules []ctlconf.DiffMaskRule, opts ChangeSetViewOpts) *ChangeSetView {
 
-	return &ChangeSetView{changeViews, maskRules, opts, nil}
+	return &ChangeSetView{changeViews, maskRules, opts, nil, nil}
+}
+
+// WithNotes adds notes (keyed by unique resource key)
+// to headers of corresponding changes shown in detail
+func (v *ChangeSetView) WithNotes(notes map[string]string) *ChangeSetView {
+	v.notes = notes
+	return v
 }
 
 func (v *ChangeSetView) Print(ui ui.UI) {
+	v.changesView = &ChangesView{ChangeViews: v.changeViews, Sort: true, countsView: NewChangesCountsView()}
+
+	if v.opts.SummaryOnly {
+		v.printSummaryOnly(ui)
+		return
+	}
+
 	if v.opts.ChangesYAML {
 		v.printChangesYAML(ui)
 	}
 	if v.opts.Changes {
-		for _, view := range v.changeViews {
+		for _, view := range v.opts.Sort.Apply(v.changeViews) {
+			if !v.opts.OpsFilter.Matches(view.ApplyOp()) {
+				continue
+			}
 			textDiffView := ctldiff.NewTextDiffView(view.ConfigurableTextDiff(), v.maskRules, v.opts.TextDiffViewOpts)
-			ui.BeginLinef("@@ %s %s @@\n", applyOpCodeUI[view.ApplyOp()], view.Resource().Description())
+			header := fmt.Sprintf("@@ %s %s @@", applyOpCodeUI[view.ApplyOp()], view.Resource().Description())
+			if note, found := v.notes[ctlres.NewUniqueResourceKey(view.Resource()).String()]; found {
+				header += " " + note
+			}
+			ui.BeginLinef("%s\n", header)
 			ui.PrintBlock([]byte(textDiffView.String()))
 		}
 	}
 
-	v.changesView = &ChangesView{ChangeViews: v.changeViews, Sort: true, countsView: NewChangesCountsView()}
-
 	if v.opts.Summary {
 		v.changesView.Print(ui)
 	}
@@ -58,8 +86,49 @@ func (v *ChangeSetView) Summary() string {
 	return v.changesView.Summary() // assumes Print was used before
 }
 
-func (v ChangeSetView) printChangesYAML(ui ui.UI) error {
+// printSummaryOnly shows one line per change (e.g. 'create v1/ConfigMap ns/name')
+// without any field level details so that output is easy to grep
+func (v *ChangeSetView) printSummaryOnly(ui ui.UI) {
+	type summaryLine struct {
+		op, kind, nsAndName string
+	}
+
+	var lines []summaryLine
+
 	for _, view := range v.changeViews {
+		v.changesView.countsView.Add(view.ApplyOp(), view.WaitOp())
+
+		if view.ApplyOp() == ClusterChangeApplyOpNoop || !v.opts.OpsFilter.Matches(view.ApplyOp()) {
+			continue
+		}
+
+		resource := view.Resource()
+		nsAndName := resource.Name()
+		if len(resource.Namespace()) > 0 {
+			nsAndName = resource.Namespace() + "/" + nsAndName
+		}
+
+		lines = append(lines, summaryLine{
+			op:        applyOpCodeUI[view.ApplyOp()],
+			kind:      resource.APIVersion() + "/" + resource.Kind(),
+			nsAndName: nsAndName,
+		})
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		if lines[i].nsAndName != lines[j].nsAndName {
+			return lines[i].nsAndName < lines[j].nsAndName
+		}
+		return lines[i].kind < lines[j].kind
+	})
+
+	for _, line := range lines {
+		ui.PrintLinef("%s %s %s", line.op, line.kind, line.nsAndName)
+	}
+}
+
+func (v ChangeSetView) printChangesYAML(ui ui.UI) error {
+	for _, view := range v.opts.Sort.Apply(v.changeViews) {
 		resYAML := ""
 		opAndResDesc := fmt.Sprintf("# %s: %s", applyOpCodeUI[view.ApplyOp()], view.Resource().Description())
 		strategy, err := view.ApplyStrategyOp()
@@ -70,6 +139,10 @@ func (v ChangeSetView) printChangesYAML(ui ui.UI) error {
 			opAndResDesc = fmt.Sprintf("%s (strategy: %s)", opAndResDesc, strategy)
 		}
 
+		if !v.opts.OpsFilter.Matches(view.ApplyOp()) {
+			continue
+		}
+
 		switch view.ApplyOp() {
 		case ClusterChangeApplyOpNoop:
 			continue
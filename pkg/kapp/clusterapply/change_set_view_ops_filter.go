@@ -0,0 +1,55 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// Letters follow git diff --diff-filter
+	changeSetViewOpsFilterLetters = map[rune]ClusterChangeApplyOp{
+		'A': ClusterChangeApplyOpAdd,
+		'M': ClusterChangeApplyOpUpdate,
+		'D': ClusterChangeApplyOpDelete,
+	}
+)
+
+// ChangeSetViewOpsFilter selects which changes are shown
+// in diff details based on their apply operation (e.g. AD).
+// It implements pflag.Value interface.
+type ChangeSetViewOpsFilter struct {
+	letters string
+	ops     map[ClusterChangeApplyOp]struct{}
+}
+
+func (f *ChangeSetViewOpsFilter) String() string { return f.letters }
+func (f *ChangeSetViewOpsFilter) Type() string   { return "string" }
+
+func (f *ChangeSetViewOpsFilter) Set(val string) error {
+	ops := map[ClusterChangeApplyOp]struct{}{}
+
+	for _, letter := range strings.ToUpper(val) {
+		op, found := changeSetViewOpsFilterLetters[letter]
+		if !found {
+			return fmt.Errorf("Unknown op '%c' (known: A (create), M (update), D (delete))", letter)
+		}
+		ops[op] = struct{}{}
+	}
+
+	f.letters = val
+	f.ops = ops
+
+	return nil
+}
+
+// Matches returns true if filter is empty or includes op
+func (f ChangeSetViewOpsFilter) Matches(op ClusterChangeApplyOp) bool {
+	if len(f.ops) == 0 {
+		return true
+	}
+	_, found := f.ops[op]
+	return found
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply_test
+
+import (
+	"testing"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeSetViewOpsFilter_Matches(t *testing.T) {
+	filter := ctlcap.ChangeSetViewOpsFilter{}
+
+	for _, op := range []ctlcap.ClusterChangeApplyOp{ctlcap.ClusterChangeApplyOpAdd,
+		ctlcap.ClusterChangeApplyOpUpdate, ctlcap.ClusterChangeApplyOpDelete, ctlcap.ClusterChangeApplyOpNoop} {
+		require.True(t, filter.Matches(op), "Expected empty filter to match all ops")
+	}
+
+	require.NoError(t, filter.Set("aD"))
+	require.Equal(t, "aD", filter.String())
+
+	require.True(t, filter.Matches(ctlcap.ClusterChangeApplyOpAdd))
+	require.True(t, filter.Matches(ctlcap.ClusterChangeApplyOpDelete))
+	require.False(t, filter.Matches(ctlcap.ClusterChangeApplyOpUpdate))
+	require.False(t, filter.Matches(ctlcap.ClusterChangeApplyOpNoop))
+}
+
+func TestChangeSetViewOpsFilter_SetUnknownOp(t *testing.T) {
+	filter := ctlcap.ChangeSetViewOpsFilter{}
+	require.NoError(t, filter.Set("M"))
+
+	err := filter.Set("AX")
+	require.EqualError(t, err, "Unknown op 'X' (known: A (create), M (update), D (delete))")
+
+	require.Equal(t, "M", filter.String(), "Expected filter to not change on error")
+	require.True(t, filter.Matches(ctlcap.ClusterChangeApplyOpUpdate))
+	require.False(t, filter.Matches(ctlcap.ClusterChangeApplyOpAdd))
+}
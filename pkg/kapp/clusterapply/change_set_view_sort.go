@@ -0,0 +1,57 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	changeSetViewSortSeverity = "severity"
+)
+
+var (
+	// Destructive changes are shown first
+	changeSetViewSeverityRanks = map[ClusterChangeApplyOp]int{
+		ClusterChangeApplyOpDelete: 0,
+		ClusterChangeApplyOpUpdate: 1,
+		ClusterChangeApplyOpAdd:    2,
+		ClusterChangeApplyOpExists: 3,
+		ClusterChangeApplyOpNoop:   4,
+	}
+)
+
+// ChangeSetViewSort determines order in which changes
+// are shown in diff details (empty keeps calculated order).
+// It implements pflag.Value interface.
+type ChangeSetViewSort struct {
+	val string
+}
+
+func (s *ChangeSetViewSort) String() string { return s.val }
+func (s *ChangeSetViewSort) Type() string   { return "string" }
+
+func (s *ChangeSetViewSort) Set(val string) error {
+	switch val {
+	case "", changeSetViewSortSeverity:
+		s.val = val
+		return nil
+	default:
+		return fmt.Errorf("Unknown diff sort '%s' (known: %s)", val, changeSetViewSortSeverity)
+	}
+}
+
+// Apply returns sorted copy of provided change views
+func (s ChangeSetViewSort) Apply(views []ChangeView) []ChangeView {
+	result := append([]ChangeView{}, views...)
+
+	if s.val == changeSetViewSortSeverity {
+		sort.SliceStable(result, func(i, j int) bool {
+			return changeSetViewSeverityRanks[result[i].ApplyOp()] < changeSetViewSeverityRanks[result[j].ApplyOp()]
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeSetViewSort_Apply(t *testing.T) {
+	changeViews := newChangeSetViewFixture(t)
+
+	applyOps := func(views []ctlcap.ChangeView) []ctlcap.ClusterChangeApplyOp {
+		var ops []ctlcap.ClusterChangeApplyOp
+		for _, view := range views {
+			ops = append(ops, view.ApplyOp())
+		}
+		return ops
+	}
+
+	sort := ctlcap.ChangeSetViewSort{}
+	require.Equal(t, applyOps(changeViews), applyOps(sort.Apply(changeViews)), "Expected empty sort to keep order")
+
+	require.NoError(t, sort.Set("severity"))
+	require.Equal(t, "severity", sort.String())
+
+	require.Equal(t, []ctlcap.ClusterChangeApplyOp{
+		ctlcap.ClusterChangeApplyOpDelete,
+		ctlcap.ClusterChangeApplyOpUpdate,
+		ctlcap.ClusterChangeApplyOpAdd,
+		ctlcap.ClusterChangeApplyOpNoop,
+	}, applyOps(sort.Apply(changeViews)))
+
+	require.Equal(t, ctlcap.ClusterChangeApplyOpUpdate, changeViews[0].ApplyOp(), "Expected original slice to be unchanged")
+}
+
+func TestChangeSetViewSort_SetUnknown(t *testing.T) {
+	sort := ctlcap.ChangeSetViewSort{}
+	require.NoError(t, sort.Set("severity"))
+
+	err := sort.Set("name")
+	require.EqualError(t, err, "Unknown diff sort 'name' (known: severity)")
+	require.Equal(t, "severity", sort.String(), "Expected sort to not change on error")
+}
+
+func TestChangeSetViewSortSeverity(t *testing.T) {
+	sort := ctlcap.ChangeSetViewSort{}
+	require.NoError(t, sort.Set("severity"))
+
+	out := &bytes.Buffer{}
+	view := ctlcap.NewChangeSetView(newChangeSetViewFixture(t), nil, ctlcap.ChangeSetViewOpts{Changes: true, Sort: sort})
+	view.Print(ui.NewWriterUI(out, out, ui.NewNoopLogger()))
+
+	deleteIdx := strings.Index(out.String(), "@@ delete namespace/deleted (v1) cluster @@")
+	updateIdx := strings.Index(out.String(), "@@ update deployment/updated (apps/v1) namespace: ns @@")
+	createIdx := strings.Index(out.String(), "@@ create configmap/added (v1) namespace: ns @@")
+
+	require.True(t, deleteIdx >= 0 && updateIdx >= 0 && createIdx >= 0, "Expected all changes in output: %s", out.String())
+	require.Less(t, deleteIdx, updateIdx)
+	require.Less(t, updateIdx, createIdx)
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply_test
+
+import (
+	"bytes"
+	"testing"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeSetViewSummaryOnly(t *testing.T) {
+	changeViews := newChangeSetViewFixture(t)
+
+	out := &bytes.Buffer{}
+	view := ctlcap.NewChangeSetView(changeViews, nil, ctlcap.ChangeSetViewOpts{SummaryOnly: true})
+	view.Print(ui.NewWriterUI(out, out, ui.NewNoopLogger()))
+
+	require.Equal(t, `delete v1/Namespace deleted
+create v1/ConfigMap ns/added
+update apps/v1/Deployment ns/updated
+`, out.String())
+	require.Contains(t, view.Summary(), "Op: 1 create, 1 delete, 1 update, 1 noop")
+}
+
+func TestChangeSetViewOpsFilter(t *testing.T) {
+	printView := func(t *testing.T, letters string, opts ctlcap.ChangeSetViewOpts) (string, *ctlcap.ChangeSetView) {
+		require.NoError(t, opts.OpsFilter.Set(letters))
+
+		out := &bytes.Buffer{}
+		view := ctlcap.NewChangeSetView(newChangeSetViewFixture(t), nil, opts)
+		view.Print(ui.NewWriterUI(out, out, ui.NewNoopLogger()))
+
+		return out.String(), view
+	}
+
+	out, view := printView(t, "AD", ctlcap.ChangeSetViewOpts{Summary: true, Changes: true})
+
+	require.Contains(t, out, "@@ create configmap/added (v1) namespace: ns @@")
+	require.Contains(t, out, "@@ delete namespace/deleted (v1) cluster @@")
+	require.NotContains(t, out, "deployment/updated")
+	require.NotContains(t, out, "configmap/kept")
+	require.Contains(t, view.Summary(), "Op: 1 create, 1 delete, 1 update, 1 noop", "Expected summary to include all changes")
+
+	t.Run("summary only", func(t *testing.T) {
+		out, _ := printView(t, "m", ctlcap.ChangeSetViewOpts{SummaryOnly: true})
+		require.Equal(t, "update apps/v1/Deployment ns/updated\n", out)
+	})
+}
+
+// newChangeSetViewFixture returns update, create, delete and noop changes
+func newChangeSetViewFixture(t *testing.T) []ctlcap.ChangeView {
+	addedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: added
+  namespace: ns
+`))
+
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: updated
+  namespace: ns
+spec:
+  replicas: 1
+`))
+
+	updatedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: updated
+  namespace: ns
+spec:
+  replicas: 2
+`))
+
+	deletedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: deleted
+`))
+
+	keptRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kept
+  namespace: ns
+`))
+
+	changeFactory := ctlcap.NewTestChangeFactory(ctlcap.ClusterChangeOpts{}, ctlres.IdentifiedResources{})
+
+	var changeViews []ctlcap.ChangeView
+
+	for _, pair := range [][2]ctlres.Resource{
+		{existingRes, updatedRes},
+		{nil, addedRes},
+		{deletedRes, nil},
+		{keptRes, keptRes.DeepCopy()},
+	} {
+		changeViews = append(changeViews, changeFactory.NewClusterChange(t, pair[0], pair[1]))
+	}
+
+	return changeViews
+}
@@ -154,7 +154,8 @@ var (
 		},
 
 		ClusterChangeApplyOpNoop: {
-			noopStrategyOp: "",
+			noopStrategyOp:   "",
+			pausedStrategyOp: "paused",
 		},
 
 		ClusterChangeApplyOpExists: {
@@ -6,6 +6,7 @@ package clusterapply
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	ctlconf "carvel.dev/kapp/pkg/kapp/config"
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
@@ -15,7 +16,9 @@ import (
 )
 
 const (
-	disableWaitAnnKey = "kapp.k14s.io/disable-wait" // valid values: ''
+	disableWaitAnnKey   = "kapp.k14s.io/disable-wait"    // valid values: ''
+	waitTimeoutAnnKey   = "kapp.k14s.io/wait-timeout"    // valid values: duration (e.g. 5m)
+	waitStableForAnnKey = "kapp.k14s.io/wait-stable-for" // valid values: duration (e.g. 30s)
 )
 
 type ClusterChangeApplyOp string
@@ -40,6 +43,7 @@ type ClusterChangeApplyStrategyOp string
 
 const (
 	noopStrategyOp    ClusterChangeApplyStrategyOp = ""
+	pausedStrategyOp  ClusterChangeApplyStrategyOp = "paused"
 	UnknownStrategyOp ClusterChangeApplyStrategyOp = "unknown"
 )
 
@@ -52,8 +56,12 @@ type ClusterChangeOpts struct {
 	ApplyIgnored bool
 	Wait         bool
 	WaitIgnored  bool
+	// WaitUnchanged waits for resources without changes that are not
+	// converged yet (otherwise they are assumed to have converged previously)
+	WaitUnchanged bool
 
 	AddOrUpdateChangeOpts
+	DeleteChangeOpts
 }
 
 type ClusterChange struct {
@@ -66,8 +74,12 @@ type ClusterChange struct {
 	ui                  UI
 
 	markedNeedsWaiting bool
+	// skippedPrecondition is set when apply-if condition was not met during apply
+	skippedPrecondition bool
 
-	diffMaskRules []ctlconf.DiffMaskRule
+	diffMaskRules      []ctlconf.DiffMaskRule
+	applyStrategyRules []ctlconf.ApplyStrategyRule
+	waitTimeouts       []ctlconf.WaitTimeout
 }
 
 var _ ChangeView = &ClusterChange{}
@@ -77,10 +89,11 @@ func NewClusterChange(change ctldiff.Change, opts ClusterChangeOpts,
 	changeFactory ctldiff.ChangeFactory,
 	changeSetFactory ctldiff.ChangeSetFactory,
 	convergedResFactory ConvergedResourceFactory, ui UI,
-	diffMaskRules []ctlconf.DiffMaskRule) *ClusterChange {
+	diffMaskRules []ctlconf.DiffMaskRule, applyStrategyRules []ctlconf.ApplyStrategyRule,
+	waitTimeouts []ctlconf.WaitTimeout) *ClusterChange {
 
 	return &ClusterChange{change, opts, identifiedResources,
-		changeFactory, changeSetFactory, convergedResFactory, ui, false, diffMaskRules}
+		changeFactory, changeSetFactory, convergedResFactory, ui, false, false, diffMaskRules, applyStrategyRules, waitTimeouts}
 }
 
 func (c *ClusterChange) ApplyOp() ClusterChangeApplyOp {
@@ -143,6 +156,10 @@ func (c *ClusterChange) WaitOp() ClusterChangeWaitOp {
 			return ClusterChangeWaitOpOK
 		}
 
+		if !c.opts.WaitUnchanged {
+			return ClusterChangeWaitOpNoop
+		}
+
 		// TODO associated resources
 		// If existing resource is not in a "done successful" state,
 		// indicate that this will be something we need to wait for
@@ -173,6 +190,19 @@ func (c *ClusterChange) ApplyStrategyOp() (ClusterChangeApplyStrategyOp, error)
 	return strategy.Op(), nil
 }
 
+// DeletesResource returns true if applying this change removes resource from the cluster
+// (ignored changes and deletes with orphan strategy leave resource in place)
+func (c *ClusterChange) DeletesResource() (bool, error) {
+	if c.ApplyOp() != ClusterChangeApplyOpDelete {
+		return false, nil
+	}
+	op, err := c.ApplyStrategyOp()
+	if err != nil {
+		return false, err
+	}
+	return op != deleteStrategyOrphanAnnValue, nil
+}
+
 func (c *ClusterChange) Apply() (bool, []string, error) {
 	descMsgs := []string{c.ApplyDescription()}
 	var retryable bool
@@ -182,6 +212,14 @@ func (c *ClusterChange) Apply() (bool, []string, error) {
 		return false, descMsgs, err
 	}
 
+	skipped, skippedMsg, err := c.checkApplyIfCondition()
+	if err != nil {
+		return false, descMsgs, c.applyErr(err)
+	}
+	if skipped {
+		return false, append(descMsgs, skippedMsg), nil
+	}
+
 	err = strategy.Apply()
 	if err != nil {
 		switch err.(type) {
@@ -199,6 +237,29 @@ func (c *ClusterChange) Apply() (bool, []string, error) {
 	return retryable, descMsgs, c.applyErr(err)
 }
 
+// checkApplyIfCondition evaluates apply-if condition against live
+// referenced resource right before resource is created or updated
+func (c *ClusterChange) checkApplyIfCondition() (bool, string, error) {
+	op := c.ApplyOp()
+	if op != ClusterChangeApplyOpAdd && op != ClusterChangeApplyOpUpdate {
+		return false, "", nil
+	}
+
+	cond, err := NewApplyIfCondition(c.change.NewResource())
+	if err != nil || cond == nil {
+		return false, "", err
+	}
+
+	met, err := cond.IsMet(c.identifiedResources)
+	if err != nil || met {
+		return false, "", err
+	}
+
+	c.skippedPrecondition = true
+
+	return true, uiWaitMsgPrefix + fmt.Sprintf("skipped (precondition): %s", cond), nil
+}
+
 func (c *ClusterChange) applyStrategy() (ApplyStrategy, error) {
 	op := c.ApplyOp()
 
@@ -206,12 +267,15 @@ func (c *ClusterChange) applyStrategy() (ApplyStrategy, error) {
 	case ClusterChangeApplyOpAdd, ClusterChangeApplyOpUpdate:
 		return AddOrUpdateChange{
 			c.change, c.identifiedResources, c.changeFactory,
-			c.changeSetFactory, c.opts.AddOrUpdateChangeOpts, c.diffMaskRules}.ApplyStrategy()
+			c.changeSetFactory, c.opts.AddOrUpdateChangeOpts, c.diffMaskRules, c.applyStrategyRules}.ApplyStrategy()
 
 	case ClusterChangeApplyOpDelete:
-		return DeleteChange{c.change, c.identifiedResources}.ApplyStrategy()
+		return DeleteChange{c.change, c.identifiedResources, c.opts.DeleteChangeOpts}.ApplyStrategy()
 
 	case ClusterChangeApplyOpNoop:
+		if c.isPaused() {
+			return PausedStrategy{}, nil
+		}
 		return NoopStrategy{}, nil
 
 	case ClusterChangeApplyOpExists:
@@ -222,6 +286,25 @@ func (c *ClusterChange) applyStrategy() (ApplyStrategy, error) {
 	}
 }
 
+// Rollback restores resource to its state before this change was applied
+func (c *ClusterChange) Rollback() error {
+	if c.skippedPrecondition {
+		return nil
+	}
+
+	switch c.ApplyOp() {
+	case ClusterChangeApplyOpAdd, ClusterChangeApplyOpUpdate, ClusterChangeApplyOpDelete:
+		err := RollbackChange{c.change, c.identifiedResources}.Rollback()
+		if err != nil {
+			return fmt.Errorf("Rolling back %s: %w", c.change.NewOrExistingResource().Description(), err)
+		}
+		return nil
+
+	default:
+		return nil
+	}
+}
+
 func (c *ClusterChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, error) {
 	state, descMsgs, err := c.isDoneApplying()
 	primaryDescMsg := fmt.Sprintf("%s: %s", NewDoneApplyStateUI(state, err).State, c.WaitDescription())
@@ -229,6 +312,10 @@ func (c *ClusterChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, erro
 }
 
 func (c *ClusterChange) isDoneApplying() (ctlresm.DoneApplyState, []string, error) {
+	if c.skippedPrecondition {
+		return ctlresm.DoneApplyState{Done: true, Successful: true, Message: "Skipped (precondition)"}, nil, nil
+	}
+
 	op := c.WaitOp()
 
 	switch op {
@@ -236,7 +323,7 @@ func (c *ClusterChange) isDoneApplying() (ctlresm.DoneApplyState, []string, erro
 		return ReconcilingChange{c.change, c.identifiedResources, c.convergedResFactory}.IsDoneApplying()
 
 	case ClusterChangeWaitOpDelete:
-		return DeleteChange{c.change, c.identifiedResources}.IsDoneApplying()
+		return DeleteChange{c.change, c.identifiedResources, c.opts.DeleteChangeOpts}.IsDoneApplying()
 
 	case ClusterChangeWaitOpNoop:
 		return ctlresm.DoneApplyState{Done: true, Successful: true}, nil, nil
@@ -246,6 +333,60 @@ func (c *ClusterChange) isDoneApplying() (ctlresm.DoneApplyState, []string, erro
 	}
 }
 
+// WaitTimeout returns how long to wait for resource to converge.
+// Annotation takes precedence over per-kind config which takes precedence
+// over the default (0 means no resource specific timeout).
+func (c *ClusterChange) WaitTimeout(defaultTimeout time.Duration) (time.Duration, error) {
+	res := c.change.NewOrExistingResource()
+
+	if val, found := res.Annotations()[waitTimeoutAnnKey]; found {
+		timeout, err := time.ParseDuration(val)
+		if err != nil || timeout <= 0 {
+			return 0, fmt.Errorf("Expected annotation '%s' on resource '%s' to be a positive duration, but was '%s'",
+				waitTimeoutAnnKey, res.Description(), val)
+		}
+		return timeout, nil
+	}
+
+	timeout := defaultTimeout
+
+	for _, waitTimeout := range c.waitTimeouts {
+		if waitTimeout.Kind == res.Kind() {
+			var err error
+			timeout, err = waitTimeout.Duration()
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return timeout, nil
+}
+
+// WaitStableFor returns how long resource has to remain successfully
+// converged before waiting on it is considered done (0 if not required).
+// Only applies to resources that are waited on to be applied.
+func (c *ClusterChange) WaitStableFor() (time.Duration, error) {
+	if c.WaitOp() != ClusterChangeWaitOpOK {
+		return 0, nil
+	}
+
+	res := c.change.NewOrExistingResource()
+
+	val, found := res.Annotations()[waitStableForAnnKey]
+	if !found {
+		return 0, nil
+	}
+
+	stableFor, err := time.ParseDuration(val)
+	if err != nil || stableFor <= 0 {
+		return 0, fmt.Errorf("Expected annotation '%s' on resource '%s' to be a positive duration, but was '%s'",
+			waitStableForAnnKey, res.Description(), val)
+	}
+
+	return stableFor, nil
+}
+
 func (c *ClusterChange) ApplyDescription() string {
 	return fmt.Sprintf("%s %s", applyOpCodeUI[c.ApplyOp()], c.change.NewOrExistingResource().Description())
 }
@@ -256,6 +397,14 @@ func (c *ClusterChange) WaitDescription() string {
 
 func (c *ClusterChange) Resource() ctlres.Resource { return c.change.NewOrExistingResource() }
 
+func (c *ClusterChange) isPaused() bool {
+	if c.change.Op() != ctldiff.ChangeOpKeep {
+		return false
+	}
+	_, found := c.Resource().Annotations()[ctlres.PauseAnnKey]
+	return found
+}
+
 func (c *ClusterChange) ClusterOriginalResource() ctlres.Resource {
 	return c.change.ClusterOriginalResource()
 }
@@ -291,3 +440,8 @@ type NoopStrategy struct{}
 
 func (s NoopStrategy) Op() ClusterChangeApplyStrategyOp { return noopStrategyOp }
 func (s NoopStrategy) Apply() error                     { return nil }
+
+type PausedStrategy struct{}
+
+func (s PausedStrategy) Op() ClusterChangeApplyStrategyOp { return pausedStrategyOp }
+func (s PausedStrategy) Apply() error                     { return nil }
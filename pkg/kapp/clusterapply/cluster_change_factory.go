@@ -17,6 +17,8 @@ type ClusterChangeFactory struct {
 	convergedResFactory ConvergedResourceFactory
 	ui                  UI
 	diffMaskRules       []ctlconf.DiffMaskRule
+	applyStrategyRules  []ctlconf.ApplyStrategyRule
+	waitTimeouts        []ctlconf.WaitTimeout
 }
 
 func NewClusterChangeFactory(
@@ -26,12 +28,14 @@ func NewClusterChangeFactory(
 	changeSetFactory ctldiff.ChangeSetFactory,
 	convergedResFactory ConvergedResourceFactory,
 	ui UI, diffMaskRules []ctlconf.DiffMaskRule,
+	applyStrategyRules []ctlconf.ApplyStrategyRule,
+	waitTimeouts []ctlconf.WaitTimeout,
 ) ClusterChangeFactory {
 	return ClusterChangeFactory{opts, identifiedResources,
-		changeFactory, changeSetFactory, convergedResFactory, ui, diffMaskRules}
+		changeFactory, changeSetFactory, convergedResFactory, ui, diffMaskRules, applyStrategyRules, waitTimeouts}
 }
 
 func (f ClusterChangeFactory) NewClusterChange(change ctldiff.Change) *ClusterChange {
 	return NewClusterChange(change, f.opts, f.identifiedResources,
-		f.changeFactory, f.changeSetFactory, f.convergedResFactory, f.ui, f.diffMaskRules)
+		f.changeFactory, f.changeSetFactory, f.convergedResFactory, f.ui, f.diffMaskRules, f.applyStrategyRules, f.waitTimeouts)
 }
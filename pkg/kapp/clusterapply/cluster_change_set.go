@@ -14,12 +14,37 @@ import (
 	uierrs "github.com/cppforlife/go-cli-ui/errors"
 )
 
+type WaitPhase string
+
+const (
+	// WaitPhasePerGroup waits for changes as they are applied
+	// so that dependent changes are applied once dependencies are ready
+	WaitPhasePerGroup WaitPhase = "per-group"
+	// WaitPhaseAfterAll applies all changes (still respecting their order)
+	// before waiting for any of them
+	WaitPhaseAfterAll WaitPhase = "after-all"
+)
+
 type ClusterChangeSetOpts struct {
 	ApplyingChangesOpts
 	WaitingChangesOpts
 
 	ExitEarlyOnApplyError bool
 	ExitEarlyOnWaitError  bool
+
+	// WaitPhase defaults to WaitPhasePerGroup
+	WaitPhase WaitPhase
+
+	// ApplyBatchSize limits number of changes applied before
+	// waiting for all of them to finish (0 means no limit)
+	ApplyBatchSize int
+
+	StagedRollout StagedRolloutOpts
+
+	Pauses PauseOpts
+
+	// Metrics is optional
+	Metrics Metrics
 }
 
 type ClusterChangeSet struct {
@@ -41,6 +66,11 @@ func NewClusterChangeSet(changes []ctldiff.Change, opts ClusterChangeSetOpts,
 }
 
 func (c ClusterChangeSet) Calculate() ([]*ClusterChange, *ctldgraph.ChangeGraph, error) {
+	err := c.opts.validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var wrappedClusterChanges []ctldgraph.ActualChange
 
 	for _, change := range c.changes {
@@ -94,19 +124,87 @@ func (c ClusterChangeSet) markChangesToWait(change *ctldgraph.Change) bool {
 }
 
 func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
+	_, err := c.ApplyAndListUnsuccessful(changesGraph)
+	return err
+}
+
+// ApplyAndListUnsuccessful applies changes similar to Apply and, in case of an error,
+// returns changes that did not successfully finish (including changes
+// that were not attempted because their dependencies did not finish)
+func (c ClusterChangeSet) ApplyAndListUnsuccessful(changesGraph *ctldgraph.ChangeGraph) ([]*ClusterChange, error) {
 	defer c.logger.DebugFunc("Apply").Finish()
 
 	expectedNumChanges := len(changesGraph.All())
 
 	blockedChanges := ctldgraph.NewBlockedChanges(changesGraph)
+	metrics := c.opts.Metrics
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+
 	applyingChanges := NewApplyingChanges(
-		expectedNumChanges, c.opts.ApplyingChangesOpts, c.clusterChangeFactory, c.ui, c.opts.ExitEarlyOnApplyError)
-	waitingChanges := NewWaitingChanges(expectedNumChanges, c.opts.WaitingChangesOpts, c.ui, c.opts.ExitEarlyOnWaitError)
+		expectedNumChanges, c.opts.ApplyingChangesOpts, c.clusterChangeFactory, c.ui, metrics, c.opts.ExitEarlyOnApplyError)
+	waitingChanges := NewWaitingChanges(expectedNumChanges, c.opts.WaitingChangesOpts, c.ui, metrics, c.opts.ExitEarlyOnWaitError)
+
+	var err error
+
+	if c.opts.WaitPhase == WaitPhaseAfterAll {
+		err = c.applyAllThenWait(blockedChanges, applyingChanges, waitingChanges)
+	} else {
+		err = c.applyPerGroup(changesGraph, blockedChanges, applyingChanges, waitingChanges)
+	}
+	if err != nil {
+		if pausedErr, isPaused := err.(PausedErr); isPaused {
+			// Changes that were applied already finished successfully
+			for _, change := range changesGraph.All() {
+				if !applyingChanges.isApplied(change) {
+					pausedErr.Pending = append(pausedErr.Pending, change.Change.(wrappedClusterChange).ClusterChange)
+				}
+			}
+			return pausedErr.Pending, pausedErr
+		}
+
+		return c.unsuccessfulChanges(changesGraph, waitingChanges), err
+	}
+
+	return nil, nil
+}
+
+func (c ClusterChangeSet) unsuccessfulChanges(changesGraph *ctldgraph.ChangeGraph, waitingChanges *WaitingChanges) []*ClusterChange {
+	var result []*ClusterChange
+	for _, change := range ClusterChangesFromGraph(changesGraph) {
+		if !waitingChanges.IsSucceeded(change) {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+func (c ClusterChangeSet) applyPerGroup(changesGraph *ctldgraph.ChangeGraph, blockedChanges *ctldgraph.BlockedChanges,
+	applyingChanges *ApplyingChanges, waitingChanges *WaitingChanges) error {
+
+	stagedRollout, err := newStagedRollout(c.opts.StagedRollout, changesGraph, c.ui)
+	if err != nil {
+		return err
+	}
+
+	pauses := newPauses(c.opts.Pauses)
 
 	var unsuccessfulChanges []string
+	var batchNum int
 
 	for {
-		appliedChanges, unsuccessfulChangeDesc, err := applyingChanges.Apply(blockedChanges.Unblocked())
+		changesToApply := pauses.Filter(stagedRollout.Filter(blockedChanges.Unblocked()))
+
+		if c.opts.ApplyBatchSize > 0 {
+			changesToApply = c.nextBatch(changesToApply, applyingChanges, waitingChanges)
+			if len(changesToApply) > 0 {
+				batchNum++
+				c.ui.NotifySection("applying batch %d (%d changes)", batchNum, len(changesToApply))
+			}
+		}
+
+		appliedChanges, unsuccessfulChangeDesc, err := applyingChanges.Apply(changesToApply)
 		if err != nil {
 			return err
 		}
@@ -116,12 +214,13 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 		waitingChanges.Track(appliedChanges)
 
 		if waitingChanges.IsEmpty() {
-			if len(unsuccessfulChanges) == 1 {
-				return fmt.Errorf("%s", unsuccessfulChanges[0])
+			if len(unsuccessfulChanges) > 0 {
+				return unsuccessfulChangesErr(unsuccessfulChanges)
 			}
 
-			if len(unsuccessfulChanges) > 0 {
-				return uierrs.NewSemiStructuredError(fmt.Errorf("[%s]", strings.Join(unsuccessfulChanges, ", ")))
+			// Remaining changes are applied once deploy is resumed
+			if err := pauses.Err(); err != nil {
+				return err
 			}
 
 			err := applyingChanges.Complete()
@@ -140,10 +239,136 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 
 		unsuccessfulChanges = append(unsuccessfulChanges, unsuccessfulChangeDesc...)
 
+		var doneGraphChanges []*ctldgraph.Change
+
 		for _, change := range doneChanges {
 			blockedChanges.Unblock(change.Graph)
+			doneGraphChanges = append(doneGraphChanges, change.Graph)
+		}
+
+		err = stagedRollout.Done(doneGraphChanges)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// nextBatch returns changes to apply only once all changes
+// from the previous batch finished waiting
+func (c ClusterChangeSet) nextBatch(changes []*ctldgraph.Change,
+	applyingChanges *ApplyingChanges, waitingChanges *WaitingChanges) []*ctldgraph.Change {
+
+	if !waitingChanges.IsEmpty() {
+		return nil
+	}
+
+	batch := applyingChanges.nonAppliedChanges(changes)
+	if len(batch) > c.opts.ApplyBatchSize {
+		batch = batch[:c.opts.ApplyBatchSize]
+	}
+	return batch
+}
+
+// applyAllThenWait applies changes in order defined by the graph
+// without waiting for changes to be ready before unblocking their dependents,
+// and only then waits for all applied changes
+func (c ClusterChangeSet) applyAllThenWait(blockedChanges *ctldgraph.BlockedChanges,
+	applyingChanges *ApplyingChanges, waitingChanges *WaitingChanges) error {
+
+	pauses := newPauses(c.opts.Pauses)
+
+	var unsuccessfulChanges []string
+
+	for {
+		appliedChanges, unsuccessfulChangeDesc, err := applyingChanges.Apply(pauses.Filter(blockedChanges.Unblocked()))
+		if err != nil {
+			return err
+		}
+
+		unsuccessfulChanges = append(unsuccessfulChanges, unsuccessfulChangeDesc...)
+
+		if len(appliedChanges) == 0 {
+			break
+		}
+
+		for _, change := range appliedChanges {
+			blockedChanges.Unblock(change.Graph)
+		}
+
+		waitingChanges.Track(appliedChanges)
+	}
+
+	if len(unsuccessfulChanges) > 0 {
+		return unsuccessfulChangesErr(unsuccessfulChanges)
+	}
+
+	// Changes that were applied before pause markers are still waited for
+	pausedErr := pauses.Err()
+	if pausedErr == nil {
+		err := applyingChanges.Complete()
+		if err != nil {
+			c.ui.Notify([]string{fmt.Sprintf("Blocked changes:\n%s\n", blockedChanges.WhyBlocked(blockedChanges.Blocked()))})
+			return err
+		}
+	}
+
+	for !waitingChanges.IsEmpty() {
+		_, unsuccessfulChangeDesc, err := waitingChanges.WaitForAny()
+		if err != nil {
+			return err
 		}
+
+		unsuccessfulChanges = append(unsuccessfulChanges, unsuccessfulChangeDesc...)
+	}
+
+	if len(unsuccessfulChanges) > 0 {
+		return unsuccessfulChangesErr(unsuccessfulChanges)
+	}
+
+	if pausedErr != nil {
+		return pausedErr
+	}
+
+	return waitingChanges.Complete()
+}
+
+func unsuccessfulChangesErr(unsuccessfulChanges []string) error {
+	if len(unsuccessfulChanges) == 1 {
+		return fmt.Errorf("%s", unsuccessfulChanges[0])
 	}
+	return uierrs.NewSemiStructuredError(fmt.Errorf("[%s]", strings.Join(unsuccessfulChanges, ", ")))
+}
+
+func (o ClusterChangeSetOpts) validate() error {
+	if o.ApplyBatchSize < 0 {
+		return fmt.Errorf("Expected apply batch size to be non-negative")
+	}
+
+	switch o.WaitPhase {
+	case "", WaitPhasePerGroup:
+		return nil
+	case WaitPhaseAfterAll:
+		if o.StagedRollout.Enabled {
+			return fmt.Errorf("Expected staged rollout to not be used with wait phase '%s'", WaitPhaseAfterAll)
+		}
+		if o.ApplyBatchSize > 0 {
+			return fmt.Errorf("Expected apply batch size to not be used with wait phase '%s'", WaitPhaseAfterAll)
+		}
+		return nil
+	default:
+		return fmt.Errorf("Expected wait phase to be one of: %s, %s (but was '%s')",
+			WaitPhasePerGroup, WaitPhaseAfterAll, o.WaitPhase)
+	}
+}
+
+// ClusterChangesFromGraph returns cluster changes contained in a graph
+// returned by ClusterChangeSet.Calculate
+func ClusterChangesFromGraph(changesGraph *ctldgraph.ChangeGraph) []*ClusterChange {
+	var result []*ClusterChange
+	for _, change := range changesGraph.All() {
+		result = append(result, change.Change.(wrappedClusterChange).ClusterChange)
+	}
+	return result
 }
 
 func ClusterChangesAsChangeViews(changes []*ClusterChange) []ChangeView {
@@ -0,0 +1,135 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterChangeSetUnsuccessfulChanges(t *testing.T) {
+	t.Run("includes changes that were not attempted due to failed dependencies", func(t *testing.T) {
+		names, events := listUnsuccessfulAfterFailure(t, "db")
+		require.Equal(t, []string{"apply db"}, events)
+		require.ElementsMatch(t, []string{"db", "app"}, names)
+	})
+
+	t.Run("excludes changes that finished successfully", func(t *testing.T) {
+		names, events := listUnsuccessfulAfterFailure(t, "app")
+		require.Equal(t, []string{"apply db", "apply app"}, events)
+		require.Equal(t, []string{"app"}, names)
+	})
+}
+
+// listUnsuccessfulAfterFailure applies changes (app depends on db)
+// with change with failingName finishing unsuccessfully
+func listUnsuccessfulAfterFailure(t *testing.T, failingName string) ([]string, []string) {
+	changeSet := newWaitPhaseChangeSet(t, ClusterChangeSetOpts{})
+
+	_, graph, err := changeSet.Calculate()
+	require.NoError(t, err)
+	require.Len(t, graph.All(), 2)
+
+	recorder := &applyWaitRecorder{}
+
+	applyingChanges := NewApplyingChanges(len(graph.All()),
+		ApplyingChangesOpts{Timeout: time.Minute, CheckInterval: time.Millisecond, Concurrency: 5},
+		changeSet.clusterChangeFactory, noopUI{}, noopMetrics{}, false)
+	applyingChanges.applyFunc = recorder.Apply
+
+	waitingChanges := NewWaitingChanges(len(graph.All()),
+		WaitingChangesOpts{Timeout: time.Minute, CheckInterval: time.Millisecond, Concurrency: 5},
+		noopUI{}, noopMetrics{}, false)
+	waitingChanges.isDoneApplyingFunc = func(change *ClusterChange) (ctlresm.DoneApplyState, []string, error) {
+		if change.Resource().Name() == failingName {
+			return ctlresm.DoneApplyState{Done: true, Successful: false, Message: "failed"}, nil, nil
+		}
+		return ctlresm.DoneApplyState{Done: true, Successful: true}, nil, nil
+	}
+
+	err = changeSet.applyPerGroup(graph, ctldgraph.NewBlockedChanges(graph), applyingChanges, waitingChanges)
+	require.Error(t, err)
+
+	var names []string
+	for _, change := range changeSet.unsuccessfulChanges(graph, waitingChanges) {
+		names = append(names, change.Resource().Name())
+	}
+
+	return names, recorder.events
+}
+
+func TestClusterChangeSetApplyBatchSize(t *testing.T) {
+	ui := &sectionsUI{}
+	changeSet := newIndependentChangeSet(t, 5, ClusterChangeSetOpts{ApplyBatchSize: 2}, ui)
+
+	_, graph, err := changeSet.Calculate()
+	require.NoError(t, err)
+
+	recorder := &applyWaitRecorder{}
+
+	applyingChanges := NewApplyingChanges(len(graph.All()),
+		ApplyingChangesOpts{Timeout: time.Minute, CheckInterval: time.Millisecond, Concurrency: 5},
+		changeSet.clusterChangeFactory, noopUI{}, noopMetrics{}, false)
+	applyingChanges.applyFunc = recorder.Apply
+
+	waitingChanges := NewWaitingChanges(len(graph.All()),
+		WaitingChangesOpts{Timeout: time.Minute, CheckInterval: time.Millisecond, Concurrency: 5},
+		noopUI{}, noopMetrics{}, false)
+	waitingChanges.isDoneApplyingFunc = recorder.IsDoneApplying
+
+	err = changeSet.applyPerGroup(graph, ctldgraph.NewBlockedChanges(graph), applyingChanges, waitingChanges)
+	require.NoError(t, err)
+
+	// Each batch is fully waited for before next batch is applied
+	var ops []string
+	for _, event := range recorder.events {
+		ops = append(ops, strings.Fields(event)[0])
+	}
+	require.Equal(t, []string{"apply", "apply", "wait", "wait", "apply", "apply", "wait", "wait", "apply", "wait"}, ops)
+
+	require.Equal(t, []string{"applying batch 1 (2 changes)", "applying batch 2 (2 changes)",
+		"applying batch 3 (1 changes)"}, ui.sections)
+}
+
+func TestClusterChangeSetApplyBatchSizeValidation(t *testing.T) {
+	_, _, err := newIndependentChangeSet(t, 1, ClusterChangeSetOpts{ApplyBatchSize: -1}, noopUI{}).Calculate()
+	require.EqualError(t, err, "Expected apply batch size to be non-negative")
+
+	_, _, err = newIndependentChangeSet(t, 1, ClusterChangeSetOpts{ApplyBatchSize: 2,
+		WaitPhase: WaitPhaseAfterAll}, noopUI{}).Calculate()
+	require.EqualError(t, err, "Expected apply batch size to not be used with wait phase 'after-all'")
+}
+
+// newIndependentChangeSet returns change set with num changes that do not depend on each other
+func newIndependentChangeSet(t *testing.T, num int, opts ClusterChangeSetOpts, ui UI) ClusterChangeSet {
+	changeFactory := newTestChangeFactory(ClusterChangeOpts{Wait: true}, ctlres.IdentifiedResources{})
+
+	var changes []ctldiff.Change
+
+	for i := 0; i < num; i++ {
+		changes = append(changes, changeFactory.NewChange(t, nil, newTestConfigMap(fmt.Sprintf("cm-%d", i), nil)))
+	}
+
+	return NewClusterChangeSet(changes, opts, changeFactory.clusterChangeFactory, nil, nil, ui, logger.NewNoopLogger())
+}
+
+// sectionsUI records formatted section messages
+type sectionsUI struct {
+	sections []string
+}
+
+func (u *sectionsUI) NotifySection(msg string, args ...interface{}) {
+	u.sections = append(u.sections, fmt.Sprintf(msg, args...))
+}
+
+func (u *sectionsUI) Notify([]string) {}
@@ -0,0 +1,145 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply_test
+
+import (
+	"testing"
+	"time"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterChangeDeletesResource(t *testing.T) {
+	plainRes := ctlcap.NewTestConfigMap("plain", nil)
+	orphanRes := ctlcap.NewTestConfigMap("orphan", map[string]string{"kapp.k14s.io/delete-strategy": "orphan"})
+
+	transientRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: transient
+  namespace: default
+`))
+	transientRes.MarkTransient(true)
+
+	inoperableRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: default
+`))
+
+	keptRes := ctlcap.NewTestConfigMap("kept", nil)
+
+	changeFactory := ctlcap.NewTestChangeFactory(ctlcap.ClusterChangeOpts{}, ctlres.IdentifiedResources{})
+
+	testCases := []struct {
+		desc        string
+		existingRes ctlres.Resource
+		newRes      ctlres.Resource
+		deletes     bool
+	}{
+		{"plain delete", plainRes, nil, true},
+		{"orphan delete strategy", orphanRes, nil, false},
+		{"ignored transient resource", transientRes, nil, false},
+		{"inoperable resource", inoperableRes, nil, false},
+		{"resource kept", keptRes, keptRes.DeepCopy(), false},
+	}
+
+	for _, tc := range testCases {
+		deletes, err := changeFactory.NewClusterChange(t, tc.existingRes, tc.newRes).DeletesResource()
+		require.NoError(t, err)
+		require.Equal(t, tc.deletes, deletes, tc.desc)
+	}
+}
+
+func TestClusterChangeWaitOpForUnchanged(t *testing.T) {
+	notConvergedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pending
+  namespace: default
+status:
+  phase: Pending
+`))
+
+	convergedRes := ctlcap.NewTestConfigMap("config", nil)
+
+	updatedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+data:
+  key: val
+`))
+
+	waitOp := func(t *testing.T, opts ctlcap.ClusterChangeOpts, existingRes, newRes ctlres.Resource) ctlcap.ClusterChangeWaitOp {
+		return ctlcap.NewTestChangeFactory(opts, ctlres.IdentifiedResources{}).NewClusterChange(t, existingRes, newRes).WaitOp()
+	}
+
+	t.Run("skips waiting for unchanged resources by default", func(t *testing.T) {
+		opts := ctlcap.ClusterChangeOpts{Wait: true}
+
+		require.Equal(t, ctlcap.ClusterChangeWaitOpNoop, waitOp(t, opts, notConvergedRes, notConvergedRes))
+		require.Equal(t, ctlcap.ClusterChangeWaitOpNoop, waitOp(t, opts, convergedRes, convergedRes))
+	})
+
+	t.Run("waits for unchanged resources that have not converged when requested", func(t *testing.T) {
+		opts := ctlcap.ClusterChangeOpts{Wait: true, WaitUnchanged: true}
+
+		require.Equal(t, ctlcap.ClusterChangeWaitOpOK, waitOp(t, opts, notConvergedRes, notConvergedRes))
+		require.Equal(t, ctlcap.ClusterChangeWaitOpNoop, waitOp(t, opts, convergedRes, convergedRes))
+	})
+
+	t.Run("still waits for changed resources by default", func(t *testing.T) {
+		opts := ctlcap.ClusterChangeOpts{Wait: true}
+
+		require.Equal(t, ctlcap.ClusterChangeWaitOpOK, waitOp(t, opts, convergedRes, updatedRes))
+		require.Equal(t, ctlcap.ClusterChangeWaitOpOK, waitOp(t, opts, nil, updatedRes))
+	})
+}
+
+func TestClusterChangeWaitStableFor(t *testing.T) {
+	waitStableFor := func(t *testing.T, opts ctlcap.ClusterChangeOpts, annVal string) (time.Duration, error) {
+		var anns map[string]string
+		if len(annVal) > 0 {
+			anns = map[string]string{"kapp.k14s.io/wait-stable-for": annVal}
+		}
+
+		res := ctlcap.NewTestConfigMap("config", anns)
+
+		return ctlcap.NewTestChangeFactory(opts, ctlres.IdentifiedResources{}).NewClusterChange(t, nil, res).WaitStableFor()
+	}
+
+	t.Run("returns duration from annotation", func(t *testing.T) {
+		stableFor, err := waitStableFor(t, ctlcap.ClusterChangeOpts{Wait: true}, "30s")
+		require.NoError(t, err)
+		require.Equal(t, 30*time.Second, stableFor)
+	})
+
+	t.Run("returns zero without annotation", func(t *testing.T) {
+		stableFor, err := waitStableFor(t, ctlcap.ClusterChangeOpts{Wait: true}, "")
+		require.NoError(t, err)
+		require.Zero(t, stableFor)
+	})
+
+	t.Run("returns zero when resource is not waited on", func(t *testing.T) {
+		stableFor, err := waitStableFor(t, ctlcap.ClusterChangeOpts{Wait: false}, "invalid")
+		require.NoError(t, err)
+		require.Zero(t, stableFor)
+	})
+
+	for _, annVal := range []string{"invalid", "0s", "-5s"} {
+		t.Run("errors for "+annVal, func(t *testing.T) {
+			_, err := waitStableFor(t, ctlcap.ClusterChangeOpts{Wait: true}, annVal)
+			require.EqualError(t, err, "Expected annotation 'kapp.k14s.io/wait-stable-for' on resource "+
+				"'configmap/config (v1) namespace: default' to be a positive duration, but was '"+annVal+"'")
+		})
+	}
+}
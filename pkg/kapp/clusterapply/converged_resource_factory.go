@@ -12,6 +12,10 @@ import (
 
 type ConvergedResourceFactoryOpts struct {
 	IgnoreFailingAPIServices bool
+
+	// CRDFunc (optional) returns CRD of a custom resource so that readiness
+	// of custom resources without wait rules is based on CRD printer columns
+	CRDFunc func(ctlres.Resource) ctlres.Resource
 }
 
 type ConvergedResourceFactory struct {
@@ -43,6 +47,12 @@ func (f ConvergedResourceFactory) New(res ctlres.Resource,
 			return ctlresm.NewPackagingCarvelDevV1alpha1PackageRepo(res), nil
 		},
 
+		// Namespace waiter fails fast when namespace is terminating
+		// instead of waiting for it to be deleted
+		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			return ctlresm.NewCoreV1Namespace(res), nil
+		},
+
 		// Deal with deletion generically since below resource waiters do not not know about that
 		// TODO shoud we make all of them deal with deletion internally?
 		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
@@ -60,9 +70,21 @@ func (f ConvergedResourceFactory) New(res ctlres.Resource,
 		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
 			return ctlresm.NewCoreV1Pod(res), nil
 		},
-		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+		func(res ctlres.Resource, aRs []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			if f.waitsForEndpoints(res) {
+				// EndpointSlices inherit service labels (including association label)
+				return ctlresm.NewCoreV1ServiceWithEndpoints(res, aRs, true), []ctlres.ResourceRef{
+					{schema.GroupVersionResource{Group: "discovery.k8s.io", Resource: "endpointslices"}},
+				}
+			}
 			return ctlresm.NewCoreV1Service(res), nil
 		},
+		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			return ctlresm.NewAutoscalingVxHorizontalPodAutoscaler(res), nil
+		},
+		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			return ctlresm.NewCertManagerIoVxCertificate(res), nil
+		},
 		func(res ctlres.Resource, aRs []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
 			// Use newly provided associated resources as they may be modified by ConvergedResource
 			return ctlresm.NewAppsV1Deployment(res, aRs), []ctlres.ResourceRef{
@@ -92,7 +114,27 @@ func (f ConvergedResourceFactory) New(res ctlres.Resource,
 				// omit ControllerRevisions: we'll rarely (if ever) wait on them; reporting on them is noise
 			}
 		},
+		// Custom resources without more specific waiters fall back to existence check
+		// when their CRD does not have a suitable printer column
+		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			if f.opts.CRDFunc == nil {
+				return ctlresm.NewCRDPrinterColumnsReady(res, nil), nil
+			}
+			return ctlresm.NewCRDPrinterColumnsReady(res, f.opts.CRDFunc(res)), nil
+		},
 	}
 
 	return NewConvergedResource(res, associatedRsFunc, specificResFactories)
 }
+
+func (f ConvergedResourceFactory) waitsForEndpoints(res ctlres.Resource) bool {
+	if ctlresm.CoreV1ServiceWaitsForEndpoints(res) {
+		return true
+	}
+	for _, rule := range f.waitRules {
+		if rule.WaitForEndpoints && rule.ResourceMatcher().Matches(res) {
+			return true
+		}
+	}
+	return false
+}
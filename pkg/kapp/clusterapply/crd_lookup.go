@@ -0,0 +1,104 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"sync"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// CRDLookup finds CRDs that define custom resources. CRDs included
+// in the provided resources are preferred over the ones found on the cluster.
+type CRDLookup struct {
+	localCRDs           []ctlres.Resource
+	resourceTypes       ctlres.ResourceTypes
+	identifiedResources ctlres.IdentifiedResources
+
+	memoizedCRDs     map[string]ctlres.Resource
+	memoizedCRDsLock sync.Mutex
+}
+
+func NewCRDLookup(newResources []ctlres.Resource, resourceTypes ctlres.ResourceTypes,
+	identifiedResources ctlres.IdentifiedResources) *CRDLookup {
+
+	var localCRDs []ctlres.Resource
+
+	for _, res := range newResources {
+		if ctlresm.NewAPIExtensionsVxCRD(res) != nil {
+			localCRDs = append(localCRDs, res)
+		}
+	}
+
+	return &CRDLookup{
+		localCRDs:           localCRDs,
+		resourceTypes:       resourceTypes,
+		identifiedResources: identifiedResources,
+		memoizedCRDs:        map[string]ctlres.Resource{},
+	}
+}
+
+// Find returns nil if CRD is not found (e.g. resource is not a custom resource)
+func (l *CRDLookup) Find(res ctlres.Resource) ctlres.Resource {
+	key := res.APIGroup() + "/" + res.Kind()
+
+	l.memoizedCRDsLock.Lock()
+	defer l.memoizedCRDsLock.Unlock()
+
+	if crd, found := l.memoizedCRDs[key]; found {
+		return crd
+	}
+
+	crd := l.find(res)
+	l.memoizedCRDs[key] = crd
+
+	return crd
+}
+
+func (l *CRDLookup) find(res ctlres.Resource) ctlres.Resource {
+	for _, crd := range l.localCRDs {
+		crdRes := ctlresm.NewAPIExtensionsVxCRD(crd)
+
+		group, err := crdRes.Group()
+		if err != nil {
+			continue
+		}
+		kind, err := crdRes.Kind()
+		if err != nil {
+			continue
+		}
+		if group == res.APIGroup() && kind == res.Kind() {
+			return crd
+		}
+	}
+
+	// Built-in resources are not backed by CRDs
+	if len(res.APIGroup()) == 0 {
+		return nil
+	}
+
+	resType, err := l.resourceTypes.Find(res)
+	if err != nil {
+		return nil
+	}
+
+	crdStub := ctlres.NewResourceUnstructured(unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata": map[string]interface{}{
+				"name": resType.APIResource.Name + "." + res.APIGroup(),
+			},
+		},
+	}, ctlres.ResourceType{})
+
+	crd, found, err := l.identifiedResources.Exists(crdStub, ctlres.ExistsOpts{})
+	if err != nil || !found {
+		return nil
+	}
+
+	return crd
+}
@@ -11,6 +11,7 @@ import (
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -20,6 +21,8 @@ const (
 	deleteStrategyPlainAnnValue  ClusterChangeApplyStrategyOp = ""
 	deleteStrategyOrphanAnnValue ClusterChangeApplyStrategyOp = "orphan"
 
+	deletePropagationAnnKey = "kapp.k14s.io/delete-propagation"
+
 	appLabelKey      = "kapp.k14s.io/app" // TODO duplicated here
 	orphanedLabelKey = "kapp.k14s.io/orphaned"
 )
@@ -28,9 +31,21 @@ var (
 	jsonPointerEncoder = strings.NewReplacer("~", "~0", "/", "~1")
 )
 
+type DeleteChangeOpts struct {
+	// DefaultPropagationPolicy is used for resources that do not specify
+	// kapp.k14s.io/delete-propagation annotation (empty means Background)
+	DefaultPropagationPolicy string
+}
+
+func (o DeleteChangeOpts) Validate() error {
+	_, err := deletePropagationPolicy(o.DefaultPropagationPolicy)
+	return err
+}
+
 type DeleteChange struct {
 	change              ctldiff.Change
 	identifiedResources ctlres.IdentifiedResources
+	opts                DeleteChangeOpts
 }
 
 type inoperableResourceRef struct {
@@ -68,7 +83,17 @@ func (c DeleteChange) ApplyStrategy() (ApplyStrategy, error) {
 
 	switch ClusterChangeApplyStrategyOp(strategy) {
 	case deleteStrategyPlainAnnValue:
-		return DeletePlainStrategy{res, c}, nil
+		propagationPolicy := c.opts.DefaultPropagationPolicy
+		if val, found := res.Annotations()[deletePropagationAnnKey]; found {
+			propagationPolicy = val
+		}
+
+		policy, err := deletePropagationPolicy(propagationPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("Resource '%s': %w", res.Description(), err)
+		}
+
+		return DeletePlainStrategy{res, c, policy}, nil
 
 	case deleteStrategyOrphanAnnValue:
 		return DeleteOrphanStrategy{res, c}, nil
@@ -107,16 +132,18 @@ func (c DeleteChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, error)
 }
 
 type DeletePlainStrategy struct {
-	res ctlres.Resource
-	d   DeleteChange
+	res               ctlres.Resource
+	d                 DeleteChange
+	propagationPolicy metav1.DeletionPropagation
 }
 
 func (c DeletePlainStrategy) Op() ClusterChangeApplyStrategyOp { return deleteStrategyPlainAnnValue }
 
 func (c DeletePlainStrategy) Apply() error {
-	// TODO should we be configuring default garbage collection policy to background?
-	// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/
-	return c.d.identifiedResources.Delete(c.res)
+	// Foreground policy keeps resource around until its dependents are deleted,
+	// hence waiting for resource to be gone includes waiting for its dependents
+	// https://kubernetes.io/docs/concepts/architecture/garbage-collection/
+	return c.d.identifiedResources.Delete(c.res, ctlres.DeleteOpts{PropagationPolicy: c.propagationPolicy})
 }
 
 type DeleteOrphanStrategy struct {
@@ -150,6 +177,18 @@ func (c DeleteOrphanStrategy) Apply() error {
 	return err
 }
 
+func deletePropagationPolicy(val string) (metav1.DeletionPropagation, error) {
+	switch policy := metav1.DeletionPropagation(val); policy {
+	case "":
+		return metav1.DeletePropagationBackground, nil
+	case metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("Expected delete propagation policy to be one of: %s, %s, %s (but was '%s')",
+			metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan, val)
+	}
+}
+
 func descMessage(res ctlres.Resource) []string {
 	if res.IsDeleting() {
 		return []string{uiWaitMsgPrefix +
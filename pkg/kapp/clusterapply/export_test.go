@@ -0,0 +1,10 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+// Test helpers shared with external tests (package clusterapply_test)
+var (
+	NewTestChangeFactory = newTestChangeFactory
+	NewTestConfigMap     = newTestConfigMap
+)
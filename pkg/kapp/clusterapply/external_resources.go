@@ -0,0 +1,92 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"time"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// ExternalResources waits for resources that are not part of the app
+// but are referenced by rebase rules. Similar to ExistsChange,
+// missing resources are checked again until they exist or timeout is reached.
+type ExternalResources struct {
+	identifiedResources ctlres.IdentifiedResources
+	checkInterval       time.Duration
+	ui                  UI
+}
+
+func NewExternalResources(identifiedResources ctlres.IdentifiedResources,
+	checkInterval time.Duration, ui UI) ExternalResources {
+
+	return ExternalResources{identifiedResources, checkInterval, ui}
+}
+
+// Wait returns found resources keyed by rebase source
+func (e ExternalResources) Wait(refs []ctlconf.RebaseRuleExternalResource) (map[ctlres.FieldCopyModSource]ctlres.Resource, error) {
+	result := map[ctlres.FieldCopyModSource]ctlres.Resource{}
+
+	for _, ref := range refs {
+		if _, found := result[ref.Source()]; found {
+			continue
+		}
+
+		res, err := e.wait(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		result[ref.Source()] = res
+	}
+
+	return result, nil
+}
+
+func (e ExternalResources) wait(ref ctlconf.RebaseRuleExternalResource) (ctlres.Resource, error) {
+	timeout, err := ref.Duration()
+	if err != nil {
+		return nil, err
+	}
+
+	stubRes := ref.AsResource()
+	startTime := time.Now()
+	notified := false
+
+	for {
+		res, err := e.find(stubRes)
+		if err == nil {
+			return res, nil
+		}
+		if _, ok := err.(ExistsChangeError); !ok {
+			return nil, fmt.Errorf("Checking external resource '%s' referenced by rebase rule: %w", stubRes.Description(), err)
+		}
+
+		if time.Now().Sub(startTime) > timeout {
+			return nil, fmt.Errorf("Timed out waiting after %s for external resource '%s' "+
+				"referenced by rebase rule to exist", timeout, stubRes.Description())
+		}
+
+		if !notified {
+			e.ui.Notify([]string{fmt.Sprintf("waiting for external resource '%s' referenced by rebase rule to exist",
+				stubRes.Description())})
+			notified = true
+		}
+
+		time.Sleep(e.checkInterval)
+	}
+}
+
+func (e ExternalResources) find(res ctlres.Resource) (ctlres.Resource, error) {
+	existingRes, exists, err := e.identifiedResources.Exists(res, ctlres.ExistsOpts{})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ExistsChangeError{}
+	}
+	return existingRes, nil
+}
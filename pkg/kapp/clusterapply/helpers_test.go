@@ -0,0 +1,58 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"encoding/json"
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+// testChangeFactory builds changes and their cluster changes
+// without accessing a cluster (e.g. for waiting or applying)
+type testChangeFactory struct {
+	changeFactory        ctldiff.ChangeFactory
+	clusterChangeFactory ClusterChangeFactory
+}
+
+func newTestChangeFactory(opts ClusterChangeOpts, identifiedResources ctlres.IdentifiedResources) testChangeFactory {
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{})
+
+	return testChangeFactory{
+		changeFactory: changeFactory,
+		clusterChangeFactory: NewClusterChangeFactory(opts, identifiedResources,
+			changeFactory, ctldiff.NewChangeSetFactory(ctldiff.ChangeSetOpts{}, changeFactory),
+			NewConvergedResourceFactory(nil, ConvergedResourceFactoryOpts{}), nil, nil, nil, nil),
+	}
+}
+
+// NewChange returns change from existing to new resource (either may be nil)
+func (f testChangeFactory) NewChange(t *testing.T, existingRes, newRes ctlres.Resource) ctldiff.Change {
+	change, err := f.changeFactory.NewExactChange(existingRes, newRes)
+	require.NoError(t, err)
+	return change
+}
+
+// NewClusterChange returns cluster change from existing to new resource (either may be nil)
+func (f testChangeFactory) NewClusterChange(t *testing.T, existingRes, newRes ctlres.Resource) *ClusterChange {
+	return f.clusterChangeFactory.NewClusterChange(f.NewChange(t, existingRes, newRes))
+}
+
+// newTestConfigMap returns ConfigMap in default namespace with given annotations (if any)
+func newTestConfigMap(name string, anns map[string]string) ctlres.Resource {
+	meta := map[string]interface{}{"name": name, "namespace": "default"}
+	if len(anns) > 0 {
+		meta["annotations"] = anns
+	}
+
+	bs, err := json.Marshal(map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "metadata": meta})
+	if err != nil {
+		panic(err)
+	}
+
+	return ctlres.MustNewResourceFromBytes(bs)
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+var (
+	immutableResMatcher = ctlres.AnyMatcher{Matchers: []ctlres.ResourceMatcher{
+		ctlres.APIVersionKindMatcher{APIVersion: "v1", Kind: "Secret"},
+		ctlres.APIVersionKindMatcher{APIVersion: "v1", Kind: "ConfigMap"},
+	}}
+)
+
+// isImmutableDataChange returns true when existing Secret or ConfigMap
+// is marked as immutable and its content changes, hence it cannot be updated in place
+// (metadata such as labels and annotations can still be updated)
+func (c AddOrUpdateChange) isImmutableDataChange() bool {
+	existingRes := c.change.ExistingResource()
+	newRes := c.change.NewResource()
+
+	if existingRes == nil || newRes == nil || !immutableResMatcher.Matches(existingRes) {
+		return false
+	}
+
+	existingObj := existingRes.UnstructuredObject()
+	newObj := newRes.UnstructuredObject()
+
+	if immutable, _ := existingObj["immutable"].(bool); !immutable {
+		return false
+	}
+
+	if !reflect.DeepEqual(existingObj["immutable"], newObj["immutable"]) {
+		return true
+	}
+	if !reflect.DeepEqual(c.immutableData(existingObj), c.immutableData(newObj)) {
+		return true
+	}
+	return !reflect.DeepEqual(existingObj["binaryData"], newObj["binaryData"])
+}
+
+// immutableData returns data merged with Secret's stringData
+// since API server stores stringData as base64 encoded data
+func (AddOrUpdateChange) immutableData(obj map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+
+	if data, ok := obj["data"].(map[string]interface{}); ok {
+		for key, val := range data {
+			result[key] = val
+		}
+	}
+	if stringData, ok := obj["stringData"].(map[string]interface{}); ok {
+		for key, val := range stringData {
+			result[key] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%v", val)))
+		}
+	}
+
+	return result
+}
+
+type UpdateImmutableStrategy struct {
+	aou AddOrUpdateChange
+}
+
+func (c UpdateImmutableStrategy) Op() ClusterChangeApplyStrategyOp {
+	return updateStrategyPlainAnnValue
+}
+
+func (c UpdateImmutableStrategy) Apply() error {
+	return fmt.Errorf("Expected immutable resource '%s' to not change its content "+
+		"(use --apply-replace-immutable or '%s: %s' annotation to delete and recreate it, "+
+		"or consider using 'kapp.k14s.io/versioned' annotation so that new copy is created instead)",
+		c.aou.change.ExistingResource().Description(), updateStrategyAnnKey, updateStrategyAlwaysReplaceAnnValue)
+}
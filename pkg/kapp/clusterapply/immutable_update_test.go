@@ -0,0 +1,133 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddOrUpdateChangeImmutableStrategy(t *testing.T) {
+	existingSecret := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: default
+immutable: true
+data:
+  password: b2xk
+`))
+
+	newAddOrUpdateChange := func(t *testing.T, existingRes, newRes ctlres.Resource, opts AddOrUpdateChangeOpts) AddOrUpdateChange {
+		change, err := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{}).NewExactChange(existingRes, newRes)
+		require.NoError(t, err)
+		require.Equal(t, ctldiff.ChangeOpUpdate, change.Op())
+		return AddOrUpdateChange{change: change, opts: opts}
+	}
+
+	t.Run("fails by default when content changes", func(t *testing.T) {
+		newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: default
+immutable: true
+stringData:
+  password: new
+`))
+
+		strategy, err := newAddOrUpdateChange(t, existingSecret, newRes, AddOrUpdateChangeOpts{}).ApplyStrategy()
+		require.NoError(t, err)
+		require.IsType(t, UpdateImmutableStrategy{}, strategy)
+
+		err = strategy.Apply()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected immutable resource 'secret/creds (v1) namespace: default' to not change its content")
+		require.Contains(t, err.Error(), "--apply-replace-immutable")
+	})
+
+	t.Run("replaces when opted in and content changes", func(t *testing.T) {
+		newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: default
+immutable: true
+data:
+  password: bmV3
+`))
+
+		strategy, err := newAddOrUpdateChange(t, existingSecret, newRes, AddOrUpdateChangeOpts{ReplaceImmutable: true}).ApplyStrategy()
+		require.NoError(t, err)
+		require.IsType(t, UpdateAlwaysReplaceStrategy{}, strategy)
+		require.Equal(t, updateStrategyAlwaysReplaceAnnValue, strategy.Op())
+	})
+
+	t.Run("replaces when immutable field is removed", func(t *testing.T) {
+		newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: default
+data:
+  password: b2xk
+`))
+
+		strategy, err := newAddOrUpdateChange(t, existingSecret, newRes, AddOrUpdateChangeOpts{ReplaceImmutable: true}).ApplyStrategy()
+		require.NoError(t, err)
+		require.IsType(t, UpdateAlwaysReplaceStrategy{}, strategy)
+	})
+
+	t.Run("updates in place when only metadata changes", func(t *testing.T) {
+		newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: default
+  labels:
+    new: label
+immutable: true
+stringData:
+  password: old
+`))
+
+		strategy, err := newAddOrUpdateChange(t, existingSecret, newRes, AddOrUpdateChangeOpts{}).ApplyStrategy()
+		require.NoError(t, err)
+		require.IsType(t, UpdatePlainStrategy{}, strategy)
+	})
+
+	t.Run("updates in place when existing resource is mutable", func(t *testing.T) {
+		existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+data:
+  key: old
+`))
+		newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+immutable: true
+data:
+  key: new
+`))
+
+		strategy, err := newAddOrUpdateChange(t, existingRes, newRes, AddOrUpdateChangeOpts{}).ApplyStrategy()
+		require.NoError(t, err)
+		require.IsType(t, UpdatePlainStrategy{}, strategy)
+	})
+}
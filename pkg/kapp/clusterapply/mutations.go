@@ -0,0 +1,141 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+)
+
+var (
+	// Fields that are always set by the server and are not interesting
+	mutationsIgnoredPaths = [][]string{
+		{"metadata", "resourceVersion"},
+		{"metadata", "uid"},
+		{"metadata", "creationTimestamp"},
+		{"metadata", "generation"},
+		{"metadata", "managedFields"},
+		{"metadata", "selfLink"},
+		{"status"},
+	}
+)
+
+// DetectedMutation shows how resource was changed by the server
+// (defaulting, admission webhooks) when it was applied in dry run mode
+type DetectedMutation struct {
+	Resource ctlres.Resource
+	TextDiff *ctldiff.ConfigurableTextDiff
+	Err      error
+}
+
+type MutationsDetector struct {
+	changes []*ClusterChange
+}
+
+func NewMutationsDetector(changes []*ClusterChange) MutationsDetector {
+	return MutationsDetector{changes}
+}
+
+// Detect returns mutations for created and updated resources.
+// Failed dry runs are reported individually (e.g. namespace
+// does not exist yet) since they should not prevent a deploy.
+func (d MutationsDetector) Detect() ([]DetectedMutation, error) {
+	var result []DetectedMutation
+
+	for _, change := range d.changes {
+		sentRes := change.change.NewResource()
+
+		var returnedRes ctlres.Resource
+		var err error
+
+		switch change.ApplyOp() {
+		case ClusterChangeApplyOpAdd:
+			returnedRes, err = change.identifiedResources.CreateDryRun(sentRes)
+		case ClusterChangeApplyOpUpdate:
+			returnedRes, err = change.identifiedResources.UpdateDryRun(sentRes)
+		default:
+			continue
+		}
+		if err != nil {
+			result = append(result, DetectedMutation{Resource: sentRes, Err: err})
+			continue
+		}
+
+		sentRes, err = d.withoutIgnoredFields(sentRes)
+		if err != nil {
+			return nil, err
+		}
+
+		returnedRes, err = d.withoutIgnoredFields(returnedRes)
+		if err != nil {
+			return nil, err
+		}
+
+		textDiff := ctldiff.NewConfigurableTextDiff(sentRes, returnedRes, false, ctldiff.ChangeOpts{})
+		if textDiff.Full().HasChanges() {
+			result = append(result, DetectedMutation{Resource: sentRes, TextDiff: textDiff})
+		}
+	}
+
+	return result, nil
+}
+
+func (MutationsDetector) withoutIgnoredFields(res ctlres.Resource) (ctlres.Resource, error) {
+	res = res.DeepCopy()
+
+	paths := append([][]string{}, mutationsIgnoredPaths...)
+
+	// Removing identity annotation from returned resource may leave empty annotations
+	if len(res.Annotations()) == 0 {
+		paths = append(paths, []string{"metadata", "annotations"})
+	}
+
+	for _, path := range paths {
+		mod := ctlres.FieldRemoveMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings(path),
+		}
+		err := mod.Apply(res)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+type MutationsView struct {
+	mutations []DetectedMutation
+	maskRules []ctlconf.DiffMaskRule
+	opts      ctldiff.TextDiffViewOpts
+}
+
+func NewMutationsView(mutations []DetectedMutation,
+	maskRules []ctlconf.DiffMaskRule, opts ctldiff.TextDiffViewOpts) MutationsView {
+
+	return MutationsView{mutations, maskRules, opts}
+}
+
+func (v MutationsView) Print(ui ui.UI) {
+	var numMutated int
+
+	for _, mutation := range v.mutations {
+		if mutation.Err != nil {
+			ui.ErrorLinef("Warning: Could not detect mutations for %s: %s", mutation.Resource.Description(), mutation.Err)
+			continue
+		}
+
+		numMutated++
+
+		textDiffView := ctldiff.NewTextDiffView(mutation.TextDiff, v.maskRules, v.opts)
+		ui.BeginLinef("@@ mutated by server %s @@\n", mutation.Resource.Description())
+		ui.PrintBlock([]byte(textDiffView.String()))
+	}
+
+	if numMutated == 0 {
+		ui.PrintLinef("No resources were mutated by server during dry run")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+// dryRunResources simulates server that adds default fields
+// (along with server managed fields) to created resources
+type dryRunResources struct {
+	ctlres.Resources
+
+	updateErr error
+	requests  []ctlres.Resource
+}
+
+func (r *dryRunResources) CreateDryRun(res ctlres.Resource) (ctlres.Resource, error) {
+	r.requests = append(r.requests, res.DeepCopy())
+
+	res = res.DeepCopy()
+	for _, mod := range []ctlres.StringMapAppendMod{
+		{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings([]string{"metadata"}),
+			KVs:             map[string]string{"uid": "server-uid", "resourceVersion": "1"},
+		},
+		{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings([]string{"data"}),
+			KVs:             map[string]string{"defaulted": "by-webhook"},
+		},
+	} {
+		err := mod.Apply(res)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (r *dryRunResources) UpdateDryRun(res ctlres.Resource) (ctlres.Resource, error) {
+	r.requests = append(r.requests, res.DeepCopy())
+	if r.updateErr != nil {
+		return nil, r.updateErr
+	}
+	return res, nil
+}
+
+func TestMutationsDetector(t *testing.T) {
+	resources := &dryRunResources{}
+
+	mutations, err := NewMutationsDetector(newMutationsFixture(t, resources)).Detect()
+	require.NoError(t, err)
+
+	require.Len(t, mutations, 1, "Expected unmutated update and noop to not be reported")
+	require.NoError(t, mutations[0].Err)
+	require.Equal(t, "created", mutations[0].Resource.Name())
+
+	diff := mutations[0].TextDiff.Full().FullString()
+	require.Contains(t, diff, "defaulted: by-webhook")
+	require.NotContains(t, diff, "server-uid", "Expected server managed fields to be ignored")
+	require.NotContains(t, diff, "resourceVersion")
+	require.NotContains(t, diff, "kapp.k14s.io/identity", "Expected identity annotation to be ignored")
+
+	require.Len(t, resources.requests, 2)
+	for _, req := range resources.requests {
+		require.Contains(t, req.Annotations(), "kapp.k14s.io/identity", "Expected dry run request to match actual request")
+	}
+}
+
+func TestMutationsDetectorReportsFailedDryRuns(t *testing.T) {
+	resources := &dryRunResources{updateErr: fmt.Errorf("namespaces \"default\" not found")}
+
+	mutations, err := NewMutationsDetector(newMutationsFixture(t, resources)).Detect()
+	require.NoError(t, err)
+	require.Len(t, mutations, 2)
+
+	require.Equal(t, "updated", mutations[1].Resource.Name())
+	require.EqualError(t, mutations[1].Err, "namespaces \"default\" not found")
+}
+
+func TestMutationsView(t *testing.T) {
+	resources := &dryRunResources{updateErr: fmt.Errorf("webhook unavailable")}
+
+	mutations, err := NewMutationsDetector(newMutationsFixture(t, resources)).Detect()
+	require.NoError(t, err)
+
+	out := bytes.NewBufferString("")
+	NewMutationsView(mutations, nil, ctldiff.TextDiffViewOpts{}).Print(ui.NewWriterUI(out, out, ui.NewNoopLogger()))
+
+	require.Contains(t, out.String(), "@@ mutated by server configmap/created (v1) namespace: default @@")
+	require.Contains(t, out.String(), "defaulted: by-webhook")
+	require.Contains(t, out.String(), "Warning: Could not detect mutations for configmap/updated (v1) namespace: default: webhook unavailable")
+	require.NotContains(t, out.String(), "No resources were mutated")
+
+	out.Reset()
+	NewMutationsView(nil, nil, ctldiff.TextDiffViewOpts{}).Print(ui.NewWriterUI(out, out, ui.NewNoopLogger()))
+	require.Equal(t, "No resources were mutated by server during dry run\n", out.String())
+}
+
+// newMutationsFixture returns create, update and noop changes
+func newMutationsFixture(t *testing.T, resources ctlres.Resources) []*ClusterChange {
+	newConfigMap := func(name, val string) ctlres.Resource {
+		return ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + name + `
+  namespace: default
+data:
+  key: ` + val + `
+`))
+	}
+
+	identifiedResources := ctlres.NewIdentifiedResources(nil, nil, resources, nil, logger.NewNoopLogger())
+
+	changeFactory := newTestChangeFactory(ClusterChangeOpts{}, identifiedResources)
+
+	var result []*ClusterChange
+
+	for _, pair := range [][]ctlres.Resource{
+		{nil, newConfigMap("created", "val")},
+		{newConfigMap("updated", "old"), newConfigMap("updated", "new")},
+		{newConfigMap("noop", "val"), newConfigMap("noop", "val")},
+	} {
+		result = append(result, changeFactory.NewClusterChange(t, pair[0], pair[1]))
+	}
+
+	return result
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"strings"
+
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+const (
+	pauseBeforeAnnKey = "kapp.k14s.io/pause-before" // valid values: ''
+)
+
+type PauseOpts struct {
+	// Enabled holds back changes to resources annotated with
+	// kapp.k14s.io/pause-before (and changes that depend on them)
+	Enabled bool
+	// Resumed contains unique keys of resources which
+	// pause markers were already passed (e.g. approved)
+	Resumed []string
+}
+
+// PausedErr indicates that all changes that were not held back by
+// pause markers finished successfully, and remaining changes were not applied
+type PausedErr struct {
+	PausedBefore []*ClusterChange
+	// Pending contains changes that were not applied
+	// (held back changes and changes that depend on them)
+	Pending []*ClusterChange
+}
+
+func (e PausedErr) Error() string {
+	var descs []string
+	for _, change := range e.PausedBefore {
+		descs = append(descs, change.Resource().Description())
+	}
+	return fmt.Sprintf("Paused before applying: %s", strings.Join(descs, ", "))
+}
+
+// pauses holds back add and update changes for resources with pause markers.
+// Held back changes are never unblocked so their dependents are not applied either.
+type pauses struct {
+	opts    PauseOpts
+	resumed map[string]struct{}
+	held    map[*ctldgraph.Change]struct{}
+	heldAll []*ClusterChange
+}
+
+func newPauses(opts PauseOpts) *pauses {
+	resumed := map[string]struct{}{}
+	for _, key := range opts.Resumed {
+		resumed[key] = struct{}{}
+	}
+	return &pauses{opts: opts, resumed: resumed, held: map[*ctldgraph.Change]struct{}{}}
+}
+
+// Filter removes changes that have to wait until deploy is resumed
+func (p *pauses) Filter(changes []*ctldgraph.Change) []*ctldgraph.Change {
+	if !p.opts.Enabled {
+		return changes
+	}
+
+	var result []*ctldgraph.Change
+
+	for _, change := range changes {
+		if p.isPaused(change) {
+			if _, found := p.held[change]; !found {
+				p.held[change] = struct{}{}
+				p.heldAll = append(p.heldAll, change.Change.(wrappedClusterChange).ClusterChange)
+			}
+			continue
+		}
+		result = append(result, change)
+	}
+
+	return result
+}
+
+// Err returns PausedErr if any changes were held back
+func (p *pauses) Err() error {
+	if len(p.heldAll) == 0 {
+		return nil
+	}
+	return PausedErr{PausedBefore: p.heldAll}
+}
+
+func (p *pauses) isPaused(change *ctldgraph.Change) bool {
+	clusterChange := change.Change.(wrappedClusterChange).ClusterChange
+
+	switch clusterChange.ApplyOp() {
+	case ClusterChangeApplyOpAdd, ClusterChangeApplyOpUpdate:
+	default:
+		return false
+	}
+
+	res := clusterChange.Resource()
+
+	if _, found := res.Annotations()[pauseBeforeAnnKey]; !found {
+		return false
+	}
+	_, resumed := p.resumed[ctlres.NewUniqueResourceKey(res).String()]
+	return !resumed
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"testing"
+
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPausesFilter(t *testing.T) {
+	newRes := func(name string, paused bool) ctlres.Resource {
+		if paused {
+			return newTestConfigMap(name, map[string]string{pauseBeforeAnnKey: ""})
+		}
+		return newTestConfigMap(name, nil)
+	}
+
+	changeFactory := newTestChangeFactory(ClusterChangeOpts{}, ctlres.IdentifiedResources{})
+
+	newGraphChanges := func(t *testing.T) []*ctldgraph.Change {
+		var actualChanges []ctldgraph.ActualChange
+
+		for _, pair := range [][2]ctlres.Resource{
+			{nil, newRes("added", false)},
+			{nil, newRes("added-paused", true)},
+			{newRes("updated-paused", false), newRes("updated-paused", true)},
+			{newRes("resumed", false), newRes("resumed", true)},
+			{newRes("deleted-paused", true), nil},
+		} {
+			actualChanges = append(actualChanges, wrappedClusterChange{changeFactory.NewClusterChange(t, pair[0], pair[1])})
+		}
+
+		graph, err := ctldgraph.NewChangeGraph(actualChanges, nil, nil, logger.NewTODOLogger())
+		require.NoError(t, err)
+
+		return graph.All()
+	}
+
+	names := func(changes []*ctldgraph.Change) []string {
+		var result []string
+		for _, change := range changes {
+			result = append(result, change.Change.(wrappedClusterChange).ClusterChange.Resource().Name())
+		}
+		return result
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		pauses := newPauses(PauseOpts{})
+		changes := newGraphChanges(t)
+
+		require.Equal(t, names(changes), names(pauses.Filter(changes)))
+		require.NoError(t, pauses.Err())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		pauses := newPauses(PauseOpts{
+			Enabled: true,
+			Resumed: []string{"default//ConfigMap/resumed"},
+		})
+		changes := newGraphChanges(t)
+
+		require.Equal(t, []string{"added", "resumed", "deleted-paused"}, names(pauses.Filter(changes)))
+
+		// Held back changes are reported once even if filtered repeatedly
+		pauses.Filter(changes)
+
+		err := pauses.Err()
+		require.Error(t, err)
+
+		pausedErr, ok := err.(PausedErr)
+		require.True(t, ok)
+
+		var pausedBefore []string
+		for _, change := range pausedErr.PausedBefore {
+			pausedBefore = append(pausedBefore, change.Resource().Name())
+		}
+		require.Equal(t, []string{"added-paused", "updated-paused"}, pausedBefore)
+	})
+}
@@ -4,12 +4,19 @@
 package clusterapply
 
 import (
+	"fmt"
+
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	"carvel.dev/kapp/pkg/kapp/logger"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
 )
 
+const (
+	waitAnnKey                = "kapp.k14s.io/wait" // valid values: existence-only
+	waitExistenceOnlyAnnValue = "existence-only"
+)
+
 type ReconcilingChange struct {
 	change              ctldiff.Change
 	identifiedResources ctlres.IdentifiedResources
@@ -21,15 +28,41 @@ type SpecificResource interface {
 }
 
 func (c ReconcilingChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, error) {
+	res := c.change.NewOrExistingResource()
+
+	if val, found := res.Annotations()[waitAnnKey]; found {
+		if val != waitExistenceOnlyAnnValue {
+			return ctlresm.DoneApplyState{Done: true}, nil, fmt.Errorf(
+				"Expected annotation '%s' on resource '%s' to have value '%s', but was '%s'",
+				waitAnnKey, res.Description(), waitExistenceOnlyAnnValue, val)
+		}
+		return c.isDoneExisting(res)
+	}
+
 	labeledResources := ctlres.NewLabeledResources(nil, c.identifiedResources, logger.NewTODOLogger())
 
 	// Refresh resource with latest changes from the server
 	// Pick up new or existing resource (and not just new resource),
 	// as some changes may be apply->noop, wait->reconcile.
-	parentRes, err := c.identifiedResources.Get(c.change.NewOrExistingResource())
+	parentRes, err := c.identifiedResources.Get(res)
 	if err != nil {
 		return ctlresm.DoneApplyState{}, nil, err
 	}
 
 	return c.convergedResFactory.New(parentRes, labeledResources.GetAssociated).IsDoneApplying()
 }
+
+// isDoneExisting does not consider any resource conditions
+// (useful for resources that do not report their readiness)
+func (c ReconcilingChange) isDoneExisting(res ctlres.Resource) (ctlresm.DoneApplyState, []string, error) {
+	_, exists, err := c.identifiedResources.Exists(res, ctlres.ExistsOpts{})
+	if err != nil {
+		return ctlresm.DoneApplyState{}, nil, err
+	}
+	if !exists {
+		state := ctlresm.DoneApplyState{Done: false, Message: "Waiting for resource to exist"}
+		return state, []string{uiWaitMsgPrefix + state.Message}, nil
+	}
+	state := ctlresm.DoneApplyState{Done: true, Successful: true, Message: "Resource exists"}
+	return state, []string{uiWaitMsgPrefix + state.Message}, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcilingChangeWaitExistenceOnly(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: default
+  annotations:
+    kapp.k14s.io/wait: existence-only
+`))
+
+	resources := &existenceResources{}
+	reconcilingChange := newReconcilingChangeFixture(t, res, resources)
+
+	state, descMsgs, err := reconcilingChange.IsDoneApplying()
+	require.NoError(t, err)
+	require.Equal(t, ctlresm.DoneApplyState{Done: false, Message: "Waiting for resource to exist"}, state)
+	require.Equal(t, []string{uiWaitMsgPrefix + "Waiting for resource to exist"}, descMsgs)
+
+	resources.exists = true
+
+	state, descMsgs, err = reconcilingChange.IsDoneApplying()
+	require.NoError(t, err)
+	require.Equal(t, ctlresm.DoneApplyState{Done: true, Successful: true, Message: "Resource exists"}, state)
+	require.Equal(t, []string{uiWaitMsgPrefix + "Resource exists"}, descMsgs)
+
+	require.Equal(t, 0, resources.getCalls, "Expected resource conditions to not be checked")
+}
+
+func TestReconcilingChangeWaitInvalidValue(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: default
+  annotations:
+    kapp.k14s.io/wait: never
+`))
+
+	state, _, err := newReconcilingChangeFixture(t, res, &existenceResources{exists: true}).IsDoneApplying()
+	require.EqualError(t, err, "Expected annotation 'kapp.k14s.io/wait' on resource 'widget/widget (example.com/v1) namespace: default' "+
+		"to have value 'existence-only', but was 'never'")
+	require.True(t, state.Done)
+}
+
+func newReconcilingChangeFixture(t *testing.T, res ctlres.Resource, resources ctlres.Resources) ReconcilingChange {
+	identifiedResources := ctlres.NewIdentifiedResources(nil, nil, resources, nil, logger.NewNoopLogger())
+	change := newTestChangeFactory(ClusterChangeOpts{}, identifiedResources).NewChange(t, nil, res)
+
+	return ReconcilingChange{change, identifiedResources, NewConvergedResourceFactory(nil, ConvergedResourceFactoryOpts{})}
+}
+
+// existenceResources reports resources as existing based on a toggle
+type existenceResources struct {
+	ctlres.Resources
+
+	exists   bool
+	getCalls int
+}
+
+func (r *existenceResources) Exists(res ctlres.Resource, _ ctlres.ExistsOpts) (ctlres.Resource, bool, error) {
+	if !r.exists {
+		return nil, false, nil
+	}
+	return res, true, nil
+}
+
+func (r *existenceResources) Get(res ctlres.Resource) (ctlres.Resource, error) {
+	r.getCalls++
+	return res, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+var (
+	// Fields that are set by the server and cannot be specified when recreating a resource
+	rollbackServerSetFieldPaths = [][]string{
+		{"metadata", "uid"},
+		{"metadata", "resourceVersion"},
+		{"metadata", "generation"},
+		{"metadata", "creationTimestamp"},
+		{"metadata", "deletionTimestamp"},
+		{"metadata", "deletionGracePeriodSeconds"},
+		{"metadata", "managedFields"},
+		{"status"},
+	}
+)
+
+// RollbackChange restores resource to a state it was in
+// on the cluster before change was applied
+type RollbackChange struct {
+	change              ctldiff.Change
+	identifiedResources ctlres.IdentifiedResources
+}
+
+func (c RollbackChange) Rollback() error {
+	originalRes := c.change.ClusterOriginalResource()
+
+	if originalRes == nil {
+		// Resource did not exist before, hence delete it
+		err := c.identifiedResources.Delete(c.change.NewOrExistingResource(), ctlres.DeleteOpts{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	latestRes, exists, err := c.identifiedResources.Exists(originalRes, ctlres.ExistsOpts{})
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		recreatedRes := originalRes.DeepCopy()
+
+		for _, path := range rollbackServerSetFieldPaths {
+			err := ctlres.FieldRemoveMod{
+				ResourceMatcher: ctlres.AllMatcher{},
+				Path:            ctlres.NewPathFromStrings(path),
+			}.Apply(recreatedRes)
+			if err != nil {
+				return fmt.Errorf("Preparing resource for recreation: %w", err)
+			}
+		}
+
+		_, err = c.identifiedResources.Create(recreatedRes)
+		return err
+	}
+
+	restoredRes := originalRes.DeepCopy()
+
+	// Use latest resource version to avoid update conflict
+	err = ctlres.FieldCopyMod{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"metadata", "resourceVersion"}),
+		Sources:         []ctlres.FieldCopyModSource{ctlres.FieldCopyModSourceExisting},
+	}.ApplyFromMultiple(restoredRes, map[ctlres.FieldCopyModSource]ctlres.Resource{
+		ctlres.FieldCopyModSourceExisting: latestRes,
+	})
+	if err != nil {
+		return fmt.Errorf("Preparing resource for update: %w", err)
+	}
+
+	_, err = c.identifiedResources.Update(restoredRes)
+	return err
+}
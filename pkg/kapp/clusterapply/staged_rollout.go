@@ -0,0 +1,191 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+)
+
+const (
+	stagedRolloutChangeGroupEnvKey = "KAPP_CHANGE_GROUP"
+)
+
+type StagedRolloutOpts struct {
+	Enabled bool
+	// VerifyCmds maps change group name to a shell command that has to succeed
+	// after all changes within that change group are applied and waited for
+	VerifyCmds map[string]string
+}
+
+// stagedRollout holds back changes that depend on a change group
+// until that change group is verified. Changes that belong
+// to a change group that fails verification are rolled back.
+type stagedRollout struct {
+	opts StagedRolloutOpts
+	ui   UI
+
+	groupNames []string
+	members    map[string][]*ctldgraph.Change
+	done       map[*ctldgraph.Change]struct{}
+	verified   map[string]struct{}
+}
+
+func newStagedRollout(opts StagedRolloutOpts, graph *ctldgraph.ChangeGraph, ui UI) (*stagedRollout, error) {
+	rollout := &stagedRollout{
+		opts:     opts,
+		ui:       ui,
+		members:  map[string][]*ctldgraph.Change{},
+		done:     map[*ctldgraph.Change]struct{}{},
+		verified: map[string]struct{}{},
+	}
+
+	if !opts.Enabled {
+		return rollout, nil
+	}
+
+	for _, change := range graph.All() {
+		groups, err := change.Groups()
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			if _, found := opts.VerifyCmds[group.Name]; found {
+				rollout.members[group.Name] = append(rollout.members[group.Name], change)
+			}
+		}
+	}
+
+	for groupName := range rollout.members {
+		rollout.groupNames = append(rollout.groupNames, groupName)
+	}
+	sort.Strings(rollout.groupNames)
+
+	return rollout, nil
+}
+
+// Filter removes changes that are waiting for not yet verified change groups
+func (s *stagedRollout) Filter(changes []*ctldgraph.Change) []*ctldgraph.Change {
+	if !s.opts.Enabled {
+		return changes
+	}
+
+	var result []*ctldgraph.Change
+
+	for _, change := range changes {
+		if !s.isHeldBack(change) {
+			result = append(result, change)
+		}
+	}
+
+	return result
+}
+
+// Done verifies change groups which had all of their changes complete.
+// On verification failure changes in that change group are rolled back.
+func (s *stagedRollout) Done(changes []*ctldgraph.Change) error {
+	if !s.opts.Enabled {
+		return nil
+	}
+
+	for _, change := range changes {
+		s.done[change] = struct{}{}
+	}
+
+	for _, groupName := range s.groupNames {
+		if _, verified := s.verified[groupName]; verified || !s.isGroupDone(groupName) {
+			continue
+		}
+
+		err := s.verify(groupName)
+		if err != nil {
+			rollbackErr := s.rollback(groupName)
+			if rollbackErr != nil {
+				return fmt.Errorf("%w (rollback failed: %s)", err, rollbackErr)
+			}
+			return fmt.Errorf("%w (rolled back changes in change group)", err)
+		}
+
+		s.verified[groupName] = struct{}{}
+	}
+
+	return nil
+}
+
+func (s *stagedRollout) isHeldBack(change *ctldgraph.Change) bool {
+	for _, groupName := range s.groupNames {
+		if _, verified := s.verified[groupName]; verified {
+			continue
+		}
+		if s.isMember(groupName, change) {
+			continue
+		}
+		for _, member := range s.members[groupName] {
+			if change.IsDirectlyWaitingFor(member) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *stagedRollout) isMember(groupName string, change *ctldgraph.Change) bool {
+	for _, member := range s.members[groupName] {
+		if member == change {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *stagedRollout) isGroupDone(groupName string) bool {
+	for _, member := range s.members[groupName] {
+		if _, done := s.done[member]; !done {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *stagedRollout) verify(groupName string) error {
+	s.ui.NotifySection("verifying change group %s", groupName)
+
+	cmd := exec.Command("sh", "-c", s.opts.VerifyCmds[groupName])
+	cmd.Env = append(os.Environ(), stagedRolloutChangeGroupEnvKey+"="+groupName)
+
+	output, err := cmd.CombinedOutput()
+
+	if outputStr := strings.TrimSpace(string(output)); len(outputStr) > 0 {
+		s.ui.Notify([]string{outputStr})
+	}
+
+	if err != nil {
+		return fmt.Errorf("Verifying change group '%s': %w", groupName, err)
+	}
+
+	return nil
+}
+
+func (s *stagedRollout) rollback(groupName string) error {
+	s.ui.NotifySection("rolling back change group %s", groupName)
+
+	var errs []string
+
+	for _, member := range s.members[groupName] {
+		err := member.Change.(wrappedClusterChange).Rollback()
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"testing"
+
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStagedRolloutHoldsBackUntilVerified(t *testing.T) {
+	graph := buildStagedRolloutGraph(t)
+	db, app := graph.All()[0], graph.All()[1]
+
+	rollout, err := newStagedRollout(StagedRolloutOpts{
+		Enabled:    true,
+		VerifyCmds: map[string]string{"db": `test "$KAPP_CHANGE_GROUP" = db`},
+	}, graph, noopUI{})
+	require.NoError(t, err)
+
+	require.Equal(t, []*ctldgraph.Change{db}, rollout.Filter([]*ctldgraph.Change{db, app}),
+		"Expected change waiting for unverified change group to be held back")
+
+	require.NoError(t, rollout.Done([]*ctldgraph.Change{db}))
+
+	require.Equal(t, []*ctldgraph.Change{app}, rollout.Filter([]*ctldgraph.Change{app}),
+		"Expected change to proceed once change group is verified")
+}
+
+func TestStagedRolloutRollsBackOnFailedVerification(t *testing.T) {
+	graph := buildStagedRolloutGraph(t)
+	db, app := graph.All()[0], graph.All()[1]
+
+	rollout, err := newStagedRollout(StagedRolloutOpts{
+		Enabled:    true,
+		VerifyCmds: map[string]string{"db": "echo db not ready; exit 1"},
+	}, graph, noopUI{})
+	require.NoError(t, err)
+
+	err = rollout.Done([]*ctldgraph.Change{db})
+	require.EqualError(t, err, "Verifying change group 'db': exit status 1 (rolled back changes in change group)")
+
+	require.Empty(t, rollout.Filter([]*ctldgraph.Change{app}), "Expected change to be held back after failed verification")
+}
+
+func TestStagedRolloutDisabled(t *testing.T) {
+	graph := buildStagedRolloutGraph(t)
+
+	rollout, err := newStagedRollout(StagedRolloutOpts{
+		VerifyCmds: map[string]string{"db": "exit 1"},
+	}, graph, noopUI{})
+	require.NoError(t, err)
+
+	require.Equal(t, graph.All(), rollout.Filter(graph.All()))
+	require.NoError(t, rollout.Done(graph.All()))
+}
+
+func buildStagedRolloutGraph(t *testing.T) *ctldgraph.ChangeGraph {
+	// Resources marked with exists annotation do not need cluster access
+	// to be applied or rolled back, while still being ordered as upserts
+	dbRes := newTestConfigMap("db", map[string]string{
+		"kapp.k14s.io/exists":       "",
+		"kapp.k14s.io/change-group": "db",
+	})
+	appRes := newTestConfigMap("app", map[string]string{
+		"kapp.k14s.io/exists":      "",
+		"kapp.k14s.io/change-rule": "upsert after upserting db",
+	})
+
+	changeFactory := newTestChangeFactory(ClusterChangeOpts{}, ctlres.IdentifiedResources{})
+
+	var changes []ctldgraph.ActualChange
+
+	for _, res := range []ctlres.Resource{dbRes, appRes} {
+		clusterChange := changeFactory.NewClusterChange(t, nil, res)
+		require.Equal(t, ClusterChangeApplyOpExists, clusterChange.ApplyOp())
+
+		changes = append(changes, wrappedClusterChange{clusterChange})
+	}
+
+	graph, err := ctldgraph.NewChangeGraph(changes, nil, nil, logger.NewNoopLogger())
+	require.NoError(t, err)
+
+	return graph
+}
@@ -4,6 +4,8 @@
 package clusterapply
 
 import (
+	"time"
+
 	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
 )
 
@@ -12,6 +14,21 @@ type UI interface {
 	Notify(msgs []string)
 }
 
+// Metrics records apply and wait activity (e.g. to expose it to Prometheus)
+type Metrics interface {
+	ChangeApplied(op string, successful bool)
+	ChangeApplyRetried(op string)
+	ChangeWaited(op string, duration time.Duration, successful bool)
+}
+
+type noopMetrics struct{}
+
+var _ Metrics = noopMetrics{}
+
+func (noopMetrics) ChangeApplied(string, bool)               {}
+func (noopMetrics) ChangeApplyRetried(string)                {}
+func (noopMetrics) ChangeWaited(string, time.Duration, bool) {}
+
 type DoneApplyStateUI struct {
 	State   string
 	Message string
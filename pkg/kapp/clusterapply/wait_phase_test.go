@@ -0,0 +1,107 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"github.com/stretchr/testify/require"
+)
+
+// applyWaitRecorder records order in which changes are applied and waited for
+type applyWaitRecorder struct {
+	lock   sync.Mutex
+	events []string
+}
+
+func (r *applyWaitRecorder) Apply(change *ClusterChange) (bool, []string, error) {
+	r.record("apply " + change.Resource().Name())
+	return false, nil, nil
+}
+
+func (r *applyWaitRecorder) IsDoneApplying(change *ClusterChange) (ctlresm.DoneApplyState, []string, error) {
+	r.record("wait " + change.Resource().Name())
+	return ctlresm.DoneApplyState{Done: true, Successful: true}, nil, nil
+}
+
+func (r *applyWaitRecorder) record(event string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestClusterChangeSetWaitPhase(t *testing.T) {
+	t.Run("per group waits for dependencies before applying dependents", func(t *testing.T) {
+		events := applyWithWaitPhase(t, WaitPhasePerGroup)
+		require.Equal(t, []string{"apply db", "wait db", "apply app", "wait app"}, events)
+	})
+
+	t.Run("after all applies all changes in order before waiting", func(t *testing.T) {
+		events := applyWithWaitPhase(t, WaitPhaseAfterAll)
+		require.Equal(t, []string{"apply db", "apply app"}, events[:2])
+		require.ElementsMatch(t, []string{"wait db", "wait app"}, events[2:])
+	})
+}
+
+func TestClusterChangeSetWaitPhaseValidation(t *testing.T) {
+	_, _, err := newWaitPhaseChangeSet(t, ClusterChangeSetOpts{WaitPhase: "unknown"}).Calculate()
+	require.EqualError(t, err, "Expected wait phase to be one of: per-group, after-all (but was 'unknown')")
+
+	_, _, err = newWaitPhaseChangeSet(t, ClusterChangeSetOpts{WaitPhase: WaitPhaseAfterAll,
+		StagedRollout: StagedRolloutOpts{Enabled: true}}).Calculate()
+	require.EqualError(t, err, "Expected staged rollout to not be used with wait phase 'after-all'")
+}
+
+func applyWithWaitPhase(t *testing.T, waitPhase WaitPhase) []string {
+	changeSet := newWaitPhaseChangeSet(t, ClusterChangeSetOpts{WaitPhase: waitPhase})
+
+	_, graph, err := changeSet.Calculate()
+	require.NoError(t, err)
+	require.Len(t, graph.All(), 2)
+
+	recorder := &applyWaitRecorder{}
+
+	applyingChanges := NewApplyingChanges(len(graph.All()),
+		ApplyingChangesOpts{Timeout: time.Minute, CheckInterval: time.Millisecond, Concurrency: 5},
+		changeSet.clusterChangeFactory, noopUI{}, noopMetrics{}, false)
+	applyingChanges.applyFunc = recorder.Apply
+
+	waitingChanges := NewWaitingChanges(len(graph.All()),
+		WaitingChangesOpts{Timeout: time.Minute, CheckInterval: time.Millisecond, Concurrency: 5},
+		noopUI{}, noopMetrics{}, false)
+	waitingChanges.isDoneApplyingFunc = recorder.IsDoneApplying
+
+	blockedChanges := ctldgraph.NewBlockedChanges(graph)
+
+	if waitPhase == WaitPhaseAfterAll {
+		err = changeSet.applyAllThenWait(blockedChanges, applyingChanges, waitingChanges)
+	} else {
+		err = changeSet.applyPerGroup(graph, blockedChanges, applyingChanges, waitingChanges)
+	}
+	require.NoError(t, err)
+
+	return recorder.events
+}
+
+func newWaitPhaseChangeSet(t *testing.T, opts ClusterChangeSetOpts) ClusterChangeSet {
+	dbRes := newTestConfigMap("db", map[string]string{"kapp.k14s.io/change-group": "db"})
+	appRes := newTestConfigMap("app", map[string]string{"kapp.k14s.io/change-rule": "upsert after upserting db"})
+
+	changeFactory := newTestChangeFactory(ClusterChangeOpts{Wait: true}, ctlres.IdentifiedResources{})
+
+	var changes []ctldiff.Change
+
+	for _, res := range []ctlres.Resource{appRes, dbRes} {
+		changes = append(changes, changeFactory.NewChange(t, nil, res))
+	}
+
+	return NewClusterChangeSet(changes, opts, changeFactory.clusterChangeFactory, nil, nil, noopUI{}, logger.NewNoopLogger())
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// WaitTimings records how long each change took to finish waiting
+// to help find slow resources (e.g. to tune timeouts or change ordering)
+type WaitTimings struct {
+	timings     []WaitTiming
+	timingsLock sync.Mutex
+}
+
+type WaitTiming struct {
+	Change     *ClusterChange
+	StartTime  time.Time
+	Duration   time.Duration
+	Successful bool
+}
+
+// Op returns operation name as shown in changes table (e.g. create instead of add)
+func (t WaitTiming) Op() string {
+	return applyOpCodeUI[t.Change.ApplyOp()]
+}
+
+func NewWaitTimings() *WaitTimings {
+	return &WaitTimings{}
+}
+
+func (t *WaitTimings) record(change *ClusterChange, startTime time.Time, successful bool) {
+	t.timingsLock.Lock()
+	defer t.timingsLock.Unlock()
+
+	t.timings = append(t.timings, WaitTiming{
+		Change:     change,
+		StartTime:  startTime,
+		Duration:   time.Now().Sub(startTime),
+		Successful: successful,
+	})
+}
+
+// Timings returns recorded timings, slowest first
+func (t *WaitTimings) Timings() []WaitTiming {
+	t.timingsLock.Lock()
+	defer t.timingsLock.Unlock()
+
+	result := append([]WaitTiming{}, t.timings...)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Duration > result[j].Duration
+	})
+
+	return result
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"testing"
+	"time"
+
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitTimings(t *testing.T) {
+	readiness := newStaggeredReadiness(map[string][]ctlresm.DoneApplyState{
+		"cm-a": {readinessSucceeded},
+		"cm-b": {readinessFailed},
+		"cm-c": {readinessInProgress, readinessSucceeded},
+	})
+
+	timings := NewWaitTimings()
+
+	waitingChanges := readiness.newWaitingChanges(false)
+	waitingChanges.opts.Timings = timings
+
+	changes := newWaitingChangesFixture(t, map[string]string{"cm-a": "", "cm-b": "", "cm-c": ""})
+	// Pretend that changes started waiting some time ago
+	waitedBefore := map[string]time.Duration{"cm-a": time.Second, "cm-b": 3 * time.Second, "cm-c": 2 * time.Second}
+	startTimes := map[string]time.Time{}
+
+	for i, change := range changes {
+		name := change.Cluster.Resource().Name()
+		startTimes[name] = time.Now().Add(-waitedBefore[name])
+		changes[i].startTime = startTimes[name]
+	}
+
+	waitingChanges.Track(changes)
+
+	for !waitingChanges.IsEmpty() {
+		_, _, err := waitingChanges.WaitForAny()
+		require.NoError(t, err)
+	}
+
+	var names []string
+	var successful []bool
+
+	for _, timing := range timings.Timings() {
+		name := timing.Change.Resource().Name()
+		names = append(names, name)
+		successful = append(successful, timing.Successful)
+
+		require.Equal(t, startTimes[name], timing.StartTime)
+		require.GreaterOrEqual(t, timing.Duration, waitedBefore[name])
+	}
+
+	require.Equal(t, []string{"cm-b", "cm-c", "cm-a"}, names, "Expected slowest changes first")
+	require.Equal(t, []bool{false, true, true}, successful)
+}
+
+func TestWaitTimingsNotRecordedForInProgressChanges(t *testing.T) {
+	readiness := newStaggeredReadiness(map[string][]ctlresm.DoneApplyState{
+		"cm-a": {readinessSucceeded},
+		"cm-b": {readinessInProgress, readinessInProgress, readinessInProgress},
+	})
+
+	timings := NewWaitTimings()
+
+	waitingChanges := readiness.newWaitingChanges(false)
+	waitingChanges.opts.Timings = timings
+	waitingChanges.Track(newWaitingChangesFixture(t, map[string]string{"cm-a": "", "cm-b": ""}))
+
+	doneChanges, _, err := waitingChanges.WaitForAny()
+	require.NoError(t, err)
+	require.Len(t, doneChanges, 1)
+
+	require.Len(t, timings.Timings(), 1)
+	require.Equal(t, "cm-a", timings.Timings()[0].Change.Resource().Name())
+}
@@ -19,6 +19,9 @@ type WaitingChangesOpts struct {
 	ResourceTimeout time.Duration
 	CheckInterval   time.Duration
 	Concurrency     int
+
+	// Timings is optional
+	Timings *WaitTimings
 }
 
 type WaitingChanges struct {
@@ -27,17 +30,30 @@ type WaitingChanges struct {
 	trackedChanges []WaitingChange
 	opts           WaitingChangesOpts
 	ui             UI
+	metrics        Metrics
 	exitOnError    bool
+
+	succeededChanges map[*ClusterChange]struct{}
+
+	// isDoneApplyingFunc and nowFunc are swapped in tests
+	isDoneApplyingFunc func(*ClusterChange) (ctlresm.DoneApplyState, []string, error)
+	nowFunc            func() time.Time
 }
 
 type WaitingChange struct {
 	Graph     *ctldgraph.Change
 	Cluster   *ClusterChange
 	startTime time.Time
+
+	// stableSince is set when resource was first seen successfully
+	// converged (and reset when it is seen not converged again)
+	stableSince time.Time
 }
 
-func NewWaitingChanges(numTotal int, opts WaitingChangesOpts, ui UI, exitOnError bool) *WaitingChanges {
-	return &WaitingChanges{numTotal, 0, nil, opts, ui, exitOnError}
+func NewWaitingChanges(numTotal int, opts WaitingChangesOpts, ui UI, metrics Metrics, exitOnError bool) *WaitingChanges {
+	return &WaitingChanges{numTotal: numTotal, opts: opts, ui: ui, metrics: metrics,
+		exitOnError: exitOnError, succeededChanges: map[*ClusterChange]struct{}{},
+		isDoneApplyingFunc: (*ClusterChange).IsDoneApplying, nowFunc: time.Now}
 }
 
 func (c *WaitingChanges) Track(changes []WaitingChange) {
@@ -48,11 +64,18 @@ func (c *WaitingChanges) IsEmpty() bool {
 	return len(c.trackedChanges) == 0
 }
 
+// IsSucceeded returns true if change was waited on and finished successfully
+func (c *WaitingChanges) IsSucceeded(change *ClusterChange) bool {
+	_, found := c.succeededChanges[change]
+	return found
+}
+
 type waitResult struct {
-	Change   WaitingChange
-	State    ctlresm.DoneApplyState
-	DescMsgs []string
-	Err      error
+	Change    WaitingChange
+	State     ctlresm.DoneApplyState
+	DescMsgs  []string
+	StableFor time.Duration
+	Err       error
 }
 
 func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
@@ -71,14 +94,21 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 				waitThrottle.Take()
 				defer waitThrottle.Done()
 
-				state, descMsgs, err := change.Cluster.IsDoneApplying()
-				// check for resource timeout
+				var stableFor time.Duration
+
+				state, descMsgs, err := c.isDoneApplyingFunc(change.Cluster)
+				// check for resource timeout (overall timeout still applies)
 				if err == nil {
-					if c.opts.ResourceTimeout != 0 && time.Now().Sub(change.startTime) > c.opts.ResourceTimeout {
-						err = fmt.Errorf("Resource timed out waiting after %s", c.opts.ResourceTimeout)
+					var resourceTimeout time.Duration
+					resourceTimeout, err = change.Cluster.WaitTimeout(c.opts.ResourceTimeout)
+					if err == nil && resourceTimeout != 0 && time.Now().Sub(change.startTime) > resourceTimeout {
+						err = fmt.Errorf("Resource timed out waiting after %s", resourceTimeout)
 					}
 				}
-				waitCh <- waitResult{Change: change, State: state, DescMsgs: descMsgs, Err: err}
+				if err == nil {
+					stableFor, err = change.Cluster.WaitStableFor()
+				}
+				waitCh <- waitResult{Change: change, State: state, DescMsgs: descMsgs, StableFor: stableFor, Err: err}
 			}()
 		}
 
@@ -90,14 +120,24 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 			result := <-waitCh
 			change, state, descMsgs, err := result.Change, result.State, result.DescMsgs, result.Err
 
+			if err == nil && result.StableFor > 0 {
+				change, state, descMsgs = c.checkStable(change, state, descMsgs, result.StableFor)
+			}
+
 			desc := fmt.Sprintf("waiting on %s", change.Cluster.WaitDescription())
 			c.ui.Notify(descMsgs)
 
+			if err != nil || state.Done {
+				c.metrics.ChangeWaited(string(change.Cluster.ApplyOp()),
+					time.Now().Sub(change.startTime), err == nil && state.Successful)
+
+				if c.opts.Timings != nil {
+					c.opts.Timings.record(change.Cluster, change.startTime, err == nil && state.Successful)
+				}
+			}
+
 			if err != nil {
 				err = fmt.Errorf("%s: Errored: %w", desc, err)
-				if c.exitOnError {
-					return nil, nil, err
-				}
 				unsuccessfulChangeDesc = append(unsuccessfulChangeDesc, err.Error())
 				continue
 			}
@@ -119,18 +159,22 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 					msg += " (" + state.Message + ")"
 				}
 				err := fmt.Errorf("%s: Finished unsuccessfully%s", desc, msg)
-				if c.exitOnError {
-					return nil, nil, err
-				}
 				unsuccessfulChangeDesc = append(unsuccessfulChangeDesc, err.Error())
 
 			case state.Done && state.Successful:
 				doneChanges = append(doneChanges, change)
+				c.succeededChanges[change.Cluster] = struct{}{}
 			}
 		}
 
 		c.trackedChanges = newInProgressChanges
 
+		// Report all changes that failed while being checked concurrently
+		// instead of only the first one to finish checking
+		if c.exitOnError && len(unsuccessfulChangeDesc) > 0 {
+			return nil, nil, unsuccessfulChangesErr(unsuccessfulChangeDesc)
+		}
+
 		if len(c.trackedChanges) == 0 || len(doneChanges) > 0 || len(unsuccessfulChangeDesc) > 0 {
 			return doneChanges, unsuccessfulChangeDesc, nil
 		}
@@ -147,6 +191,32 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 	}
 }
 
+// checkStable keeps waiting on successfully converged resource until
+// it remains converged for specified duration (any poll that sees it
+// not converged restarts the period)
+func (c *WaitingChanges) checkStable(change WaitingChange, state ctlresm.DoneApplyState,
+	descMsgs []string, stableFor time.Duration) (WaitingChange, ctlresm.DoneApplyState, []string) {
+
+	if !state.Done || !state.Successful {
+		change.stableSince = time.Time{}
+		return change, state, descMsgs
+	}
+
+	if change.stableSince.IsZero() {
+		change.stableSince = c.nowFunc()
+	}
+
+	stableDur := c.nowFunc().Sub(change.stableSince)
+	if stableDur >= stableFor {
+		return change, state, descMsgs
+	}
+
+	msg := fmt.Sprintf("Waiting for resource to remain converged for %s (converged for %s)",
+		stableFor, stableDur.Round(time.Second))
+
+	return change, ctlresm.DoneApplyState{Done: false, Message: msg}, append(descMsgs, uiWaitMsgPrefix+msg)
+}
+
 func (c *WaitingChanges) Complete() error {
 	c.ui.NotifySection("waiting complete %s", c.stats())
 	return nil
@@ -0,0 +1,243 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	readinessInProgress = ctlresm.DoneApplyState{Done: false}
+	readinessSucceeded  = ctlresm.DoneApplyState{Done: true, Successful: true}
+	readinessFailed     = ctlresm.DoneApplyState{Done: true, Successful: false, Message: "failed"}
+)
+
+func TestWaitingChangesStaggeredReadiness(t *testing.T) {
+	readiness := newStaggeredReadiness(map[string][]ctlresm.DoneApplyState{
+		"cm-a": {readinessSucceeded},
+		"cm-b": {readinessInProgress, readinessInProgress, readinessSucceeded},
+		"cm-c": {readinessInProgress, readinessSucceeded},
+	})
+
+	waitingChanges := readiness.newWaitingChanges(false)
+	changes := newWaitingChangesFixture(t, map[string]string{"cm-a": "", "cm-b": "", "cm-c": ""})
+	waitingChanges.Track(changes)
+
+	for _, expectedName := range []string{"cm-a", "cm-c", "cm-b"} {
+		doneChanges, unsuccessful, err := waitingChanges.WaitForAny()
+		require.NoError(t, err)
+		require.Empty(t, unsuccessful)
+		require.Len(t, doneChanges, 1)
+		require.Equal(t, expectedName, doneChanges[0].Cluster.Resource().Name())
+	}
+
+	require.True(t, waitingChanges.IsEmpty())
+	for _, change := range changes {
+		require.True(t, waitingChanges.IsSucceeded(change.Cluster))
+	}
+}
+
+func TestWaitingChangesConcurrency(t *testing.T) {
+	readiness := newStaggeredReadiness(map[string][]ctlresm.DoneApplyState{
+		"cm-a": {readinessSucceeded},
+		"cm-b": {readinessSucceeded},
+		"cm-c": {readinessSucceeded},
+	})
+	// Block checks until released so that number
+	// of concurrent checks does not depend on timing
+	readiness.arrivedCh = make(chan struct{})
+	readiness.releaseCh = make(chan struct{})
+
+	waitingChanges := readiness.newWaitingChanges(false)
+	waitingChanges.Track(newWaitingChangesFixture(t, map[string]string{"cm-a": "", "cm-b": "", "cm-c": ""}))
+
+	doneCh := make(chan error, 1)
+	go func() {
+		_, _, err := waitingChanges.WaitForAny()
+		doneCh <- err
+	}()
+
+	// Concurrency limit is 2, hence third check only starts after one is released
+	<-readiness.arrivedCh
+	<-readiness.arrivedCh
+	require.Equal(t, 2, readiness.currInFlight())
+
+	readiness.releaseCh <- struct{}{}
+	<-readiness.arrivedCh
+	require.Equal(t, 2, readiness.currInFlight())
+
+	readiness.releaseCh <- struct{}{}
+	readiness.releaseCh <- struct{}{}
+
+	require.NoError(t, <-doneCh)
+	require.Equal(t, 2, readiness.maxInFlight, "Expected checks to run concurrently up to concurrency limit")
+}
+
+func TestWaitingChangesReportsAllFailures(t *testing.T) {
+	readiness := newStaggeredReadiness(map[string][]ctlresm.DoneApplyState{
+		"cm-a": {readinessInProgress, readinessFailed},
+		"cm-b": {readinessInProgress, readinessFailed},
+		"cm-c": {readinessInProgress},
+	})
+
+	waitingChanges := readiness.newWaitingChanges(true)
+	waitingChanges.Track(newWaitingChangesFixture(t, map[string]string{"cm-a": "", "cm-b": "", "cm-c": ""}))
+
+	_, _, err := waitingChanges.WaitForAny()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "waiting on reconcile configmap/cm-a (v1) namespace: default: Finished unsuccessfully (failed)")
+	require.Contains(t, err.Error(), "waiting on reconcile configmap/cm-b (v1) namespace: default: Finished unsuccessfully (failed)")
+	require.NotContains(t, err.Error(), "cm-c")
+}
+
+func TestWaitingChangesStableFor(t *testing.T) {
+	readiness := newStaggeredReadiness(map[string][]ctlresm.DoneApplyState{
+		"cm-a": {readinessSucceeded, readinessInProgress, readinessSucceeded, readinessSucceeded, readinessSucceeded},
+	})
+
+	startTime := time.Now()
+	// Clock advances on each poll so that resource converges at 0s,
+	// regresses at 5s, converges again at 6s, and is checked at 12s and 16s
+	readiness.pollTimes = []time.Time{
+		startTime,
+		startTime.Add(5 * time.Second),
+		startTime.Add(6 * time.Second),
+		startTime.Add(12 * time.Second),
+		startTime.Add(16 * time.Second),
+	}
+
+	waitingChanges := readiness.newWaitingChanges(false)
+	waitingChanges.Track(newWaitingChangesFixture(t, map[string]string{"cm-a": "10s"}))
+
+	doneChanges, unsuccessful, err := waitingChanges.WaitForAny()
+	require.NoError(t, err)
+	require.Empty(t, unsuccessful)
+	require.Len(t, doneChanges, 1)
+
+	require.Equal(t, 5, readiness.polls["cm-a"], "Expected stable period to restart after resource regressed")
+	require.Equal(t, startTime.Add(6*time.Second), doneChanges[0].stableSince)
+}
+
+func TestWaitingChangesInvalidStableFor(t *testing.T) {
+	readiness := newStaggeredReadiness(map[string][]ctlresm.DoneApplyState{
+		"cm-a": {readinessSucceeded},
+	})
+
+	waitingChanges := readiness.newWaitingChanges(false)
+	waitingChanges.Track(newWaitingChangesFixture(t, map[string]string{"cm-a": "soon"}))
+
+	doneChanges, unsuccessful, err := waitingChanges.WaitForAny()
+	require.NoError(t, err)
+	require.Empty(t, doneChanges)
+	require.Equal(t, []string{"waiting on reconcile configmap/cm-a (v1) namespace: default: Errored: " +
+		"Expected annotation 'kapp.k14s.io/wait-stable-for' on resource 'configmap/cm-a (v1) namespace: default' " +
+		"to be a positive duration, but was 'soon'"}, unsuccessful)
+}
+
+func newWaitingChangesFixture(t *testing.T, stableForByName map[string]string) []WaitingChange {
+	changeFactory := newTestChangeFactory(ClusterChangeOpts{Wait: true}, ctlres.IdentifiedResources{})
+
+	var result []WaitingChange
+
+	for _, name := range []string{"cm-a", "cm-b", "cm-c"} {
+		stableFor, found := stableForByName[name]
+		if !found {
+			continue
+		}
+		var anns map[string]string
+		if len(stableFor) > 0 {
+			anns = map[string]string{waitStableForAnnKey: stableFor}
+		}
+
+		clusterChange := changeFactory.NewClusterChange(t, nil, newTestConfigMap(name, anns))
+		require.Equal(t, ClusterChangeWaitOpOK, clusterChange.WaitOp())
+
+		result = append(result, WaitingChange{Cluster: clusterChange, startTime: time.Now()})
+	}
+
+	return result
+}
+
+// staggeredReadiness simulates resources that become ready
+// (or fail) after different number of checks
+type staggeredReadiness struct {
+	statesByName map[string][]ctlresm.DoneApplyState
+	pollTimes    []time.Time
+
+	// When set, each check signals its start and blocks until released
+	arrivedCh chan struct{}
+	releaseCh chan struct{}
+
+	lock        sync.Mutex
+	polls       map[string]int
+	now         time.Time
+	inFlight    int
+	maxInFlight int
+}
+
+func newStaggeredReadiness(statesByName map[string][]ctlresm.DoneApplyState) *staggeredReadiness {
+	return &staggeredReadiness{statesByName: statesByName, polls: map[string]int{}, now: time.Now()}
+}
+
+func (r *staggeredReadiness) newWaitingChanges(exitOnError bool) *WaitingChanges {
+	opts := WaitingChangesOpts{Timeout: time.Minute, CheckInterval: time.Millisecond, Concurrency: 2}
+	waitingChanges := NewWaitingChanges(len(r.statesByName), opts, noopUI{}, noopMetrics{}, exitOnError)
+	waitingChanges.isDoneApplyingFunc = r.IsDoneApplying
+	waitingChanges.nowFunc = r.Now
+	return waitingChanges
+}
+
+func (r *staggeredReadiness) IsDoneApplying(change *ClusterChange) (ctlresm.DoneApplyState, []string, error) {
+	name := change.Resource().Name()
+
+	r.lock.Lock()
+	r.inFlight++
+	if r.inFlight > r.maxInFlight {
+		r.maxInFlight = r.inFlight
+	}
+	if poll := r.polls[name]; poll < len(r.pollTimes) {
+		r.now = r.pollTimes[poll]
+	}
+	r.polls[name]++
+	poll := r.polls[name]
+	r.lock.Unlock()
+
+	if r.arrivedCh != nil {
+		r.arrivedCh <- struct{}{}
+		<-r.releaseCh
+	}
+
+	r.lock.Lock()
+	r.inFlight--
+	r.lock.Unlock()
+
+	states := r.statesByName[name]
+	if poll > len(states) {
+		return states[len(states)-1], nil, nil
+	}
+	return states[poll-1], nil, nil
+}
+
+func (r *staggeredReadiness) currInFlight() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.inFlight
+}
+
+func (r *staggeredReadiness) Now() time.Time {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.now
+}
+
+type noopUI struct{}
+
+func (noopUI) NotifySection(string, ...interface{}) {}
+func (noopUI) Notify([]string)                      {}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctlmetrics "carvel.dev/kapp/pkg/kapp/metrics"
+	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -32,7 +34,12 @@ type ApplyFlags struct {
 	ctlcap.ClusterChangeSetOpts
 	ctlcap.ClusterChangeOpts
 
-	ExitStatus bool
+	ExitStatus          bool
+	ExitStatusNoChanges int
+	ExitStatusChanges   int
+
+	MetricsBind string
+	WaitTimings bool
 }
 
 func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *cobra.Command) {
@@ -46,14 +53,23 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 	cmd.Flags().DurationVar(&s.ApplyingChangesOpts.CheckInterval, prefix+"apply-check-interval",
 		mustParseDuration("1s"), "Amount of time to sleep between applies")
 	cmd.Flags().IntVar(&s.ApplyingChangesOpts.Concurrency, prefix+"apply-concurrency", 5, "Maximum number of concurrent apply operations")
+	cmd.Flags().IntVar(&s.ApplyBatchSize, prefix+"apply-batch-size", 0,
+		"Apply changes in batches of this size, waiting for each batch to finish before applying next one (0 means no batching)")
 
 	cmd.Flags().StringVar(&s.AddOrUpdateChangeOpts.DefaultUpdateStrategy, prefix+"apply-default-update-strategy",
 		defaults.AddOrUpdateChangeOpts.DefaultUpdateStrategy, "Change default update strategy")
+	cmd.Flags().BoolVar(&s.AddOrUpdateChangeOpts.ReplaceImmutable, prefix+"apply-replace-immutable", false,
+		"Delete and recreate immutable Secrets and ConfigMaps when their content changes (by default such changes fail)")
 
 	cmd.Flags().BoolVar(&s.ExitEarlyOnApplyError, prefix+"exit-early-on-apply-error", true, "Exit quickly on apply failure")
 
+	cmd.Flags().StringVar(&s.DeleteChangeOpts.DefaultPropagationPolicy, prefix+"delete-propagation", "Background",
+		"Set propagation policy used when deleting resources (Foreground, Background, Orphan); can be overridden per resource via kapp.k14s.io/delete-propagation annotation")
+
 	cmd.Flags().BoolVar(&s.Wait, prefix+"wait", defaults.Wait, "Set to wait for changes to be applied")
 	cmd.Flags().BoolVar(&s.WaitIgnored, prefix+"wait-ignored", defaults.WaitIgnored, "Set to wait for ignored changes to be applied")
+	cmd.Flags().BoolVar(&s.WaitUnchanged, prefix+"wait-unchanged", defaults.WaitUnchanged,
+		"Set to wait for resources without changes that have not converged (by default they are assumed to have converged during previous deploys)")
 
 	cmd.Flags().DurationVar(&s.WaitingChangesOpts.Timeout, prefix+"wait-timeout",
 		mustParseDuration("15m"), "Maximum amount of time to wait in wait phase")
@@ -63,10 +79,65 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 		mustParseDuration("3s"), "Amount of time to sleep between checks while waiting")
 	cmd.Flags().IntVar(&s.WaitingChangesOpts.Concurrency, prefix+"wait-concurrency",
 		5, "Maximum number of concurrent wait operations")
+	cmd.Flags().BoolVar(&s.WaitTimings, prefix+"wait-timings", false,
+		"Show how long each resource took to converge in wait phase (slowest first)")
+	cmd.Flags().StringVar((*string)(&s.WaitPhase), prefix+"wait-phase", string(ctlcap.WaitPhasePerGroup),
+		"Set when to wait for changes (per-group: before applying dependent changes, after-all: after all changes are applied)")
 
-	cmd.Flags().BoolVar(&s.ExitStatus, prefix+"apply-exit-status", false, "Return specific exit status based on number of changes")
+	cmd.Flags().BoolVar(&s.ExitStatus, prefix+"apply-exit-status", false,
+		"Return specific exit status based on number of changes (2: no changes, 3: changes applied, 1: failure)")
+	cmd.Flags().IntVar(&s.ExitStatusNoChanges, prefix+"apply-exit-status-no-changes", defaultNoChangesExitStatus,
+		"Exit status to return when there were no changes to apply (used with --apply-exit-status)")
+	cmd.Flags().IntVar(&s.ExitStatusChanges, prefix+"apply-exit-status-changes", defaultChangesExitStatus,
+		"Exit status to return when changes were applied (used with --apply-exit-status)")
 
 	cmd.Flags().BoolVar(&s.ExitEarlyOnWaitError, prefix+"exit-early-on-wait-error", true, "Exit quickly on wait failure")
+
+	cmd.Flags().StringVar(&s.MetricsBind, prefix+"metrics-bind", "", "Set address to expose Prometheus metrics for apply and wait phases on (e.g. :8080)")
+}
+
+// NewExitStatus returns error carrying exit status for --apply-exit-status
+func (s *ApplyFlags) NewExitStatus(hasNoChanges bool) DeployApplyExitStatus {
+	return DeployApplyExitStatus{
+		hasNoChanges:        hasNoChanges,
+		noChangesExitStatus: s.ExitStatusNoChanges,
+		changesExitStatus:   s.ExitStatusChanges,
+	}
+}
+
+// StartMetricsServer starts metrics server if address is configured
+// and configures apply and wait phases to record metrics into it
+func (s *ApplyFlags) StartMetricsServer() (func(), error) {
+	if len(s.MetricsBind) == 0 {
+		return func() {}, nil
+	}
+
+	registry := ctlmetrics.NewRegistry()
+	server := ctlmetrics.NewServer(s.MetricsBind, registry)
+
+	err := server.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	s.ClusterChangeSetOpts.Metrics = ctlmetrics.NewApplyMetrics(registry)
+
+	return func() { server.Stop() }, nil
+}
+
+// StartWaitTimings configures wait phase to record per resource timings
+// if requested; returned function prints recorded timings
+func (s *ApplyFlags) StartWaitTimings(ui ui.UI) func() {
+	if !s.WaitTimings {
+		return func() {}
+	}
+
+	timings := ctlcap.NewWaitTimings()
+	s.ClusterChangeSetOpts.WaitingChangesOpts.Timings = timings
+
+	return func() {
+		WaitTimingsView{Timings: timings.Timings()}.Print(ui)
+	}
 }
 
 func mustParseDuration(str string) time.Duration {
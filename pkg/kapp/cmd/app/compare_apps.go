@@ -0,0 +1,178 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+var (
+	// Fields that are set by the server hence differ between otherwise same resources
+	compareAppsIgnoredPaths = [][]string{
+		{"metadata", "resourceVersion"},
+		{"metadata", "uid"},
+		{"metadata", "creationTimestamp"},
+		{"metadata", "generation"},
+		{"metadata", "managedFields"},
+		{"metadata", "selfLink"},
+		{"status"},
+	}
+)
+
+type CompareAppsOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	NamespaceFlags     cmdcore.NamespaceFlags
+	AppNames           []string
+	AppNamespace       string
+	ResourceTypesFlags ResourceTypesFlags
+	DiffFlags          cmdtools.DiffFlags
+}
+
+func NewCompareAppsOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *CompareAppsOptions {
+	return &CompareAppsOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewCompareAppsCmd(o *CompareAppsOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare-apps",
+		Short: "Compare resources of two apps",
+		Long: `Compare resources of two apps
+
+Resources are matched by their identity (api group, kind, namespace and name).
+Resources only found in first app are shown as deletes, only found in second app
+as adds, and common resources with differences as updates. App labels
+and fields set by the server (e.g. resourceVersion, status) are ignored.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Verify that app 'app1' was migrated to 'app2'
+  kapp tools compare-apps -a app1 -a app2 --diff-changes`,
+	}
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringSliceVarP(&o.AppNames, "app", "a", nil, "Set app name (or label selector) (format: name, label:key=val, !key) (specify twice)")
+	cmd.Flags().StringVar(&o.AppNamespace, "app-namespace", "", "Set app namespace (to store app state)")
+	o.ResourceTypesFlags.Set(cmd)
+	o.DiffFlags.SetWithPrefix("diff", cmd)
+	return cmd
+}
+
+func (o *CompareAppsOptions) Run() error {
+	if len(o.AppNames) != 2 {
+		return fmt.Errorf("Expected exactly two apps to be specified via --app")
+	}
+
+	supportObjs, err := FactoryClients(o.depsFactory, o.NamespaceFlags, o.AppNamespace, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	var appsResources [][]ctlres.Resource
+
+	for _, appName := range o.AppNames {
+		app, err := supportObjs.Apps.Find(appName)
+		if err != nil {
+			return err
+		}
+
+		rs, err := o.appResources(app, supportObjs)
+		if err != nil {
+			return fmt.Errorf("Listing resources of app '%s': %w", app.Name(), err)
+		}
+
+		appsResources = append(appsResources, rs)
+	}
+
+	return o.printDiff(appsResources[0], appsResources[1])
+}
+
+func (o *CompareAppsOptions) printDiff(firstRs, secondRs []ctlres.Resource) error {
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, o.DiffFlags.ChangeOpts())
+
+	changes, err := ctldiff.NewChangeSet(firstRs, secondRs, o.DiffFlags.ChangeSetOpts, changeFactory).Calculate()
+	if err != nil {
+		return err
+	}
+
+	var changeViews []ctlcap.ChangeView
+
+	for _, change := range changes {
+		changeViews = append(changeViews, cmdtools.NewDiffChangeView(change))
+	}
+
+	ctlcap.NewChangeSetView(changeViews, nil, o.DiffFlags.ChangeSetViewOpts).Print(o.ui)
+
+	return nil
+}
+
+// appResources returns resources created by kapp for an app
+// without details that are expected to differ between apps
+func (o *CompareAppsOptions) appResources(app ctlapp.App, supportObjs FactorySupportObjs) ([]ctlres.Resource, error) {
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return nil, err
+	}
+
+	appLabelKey, _, err := ctlres.NewSimpleLabel(labelSelector).KV()
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := supportObjs.IdentifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{
+		ResourceNamespaces: meta.LastChange.Namespaces})
+	if err != nil {
+		return nil, err
+	}
+
+	return comparableAppResources(rs, appLabelKey)
+}
+
+func comparableAppResources(rs []ctlres.Resource, appLabelKey string) ([]ctlres.Resource, error) {
+	mods := []ctlres.FieldRemoveMod{{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"metadata", "labels", appLabelKey}),
+	}}
+
+	for _, path := range compareAppsIgnoredPaths {
+		mods = append(mods, ctlres.FieldRemoveMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings(path),
+		})
+	}
+
+	var result []ctlres.Resource
+
+	for _, res := range rs {
+		// Resources created by controllers (e.g. ReplicaSets) are not compared
+		if res.Transient() {
+			continue
+		}
+
+		res, err := ctldiff.NewResourceWithoutHistory(res, mods).Resource()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, res)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparableAppResources(t *testing.T) {
+	rs := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+  uid: uid-1
+  resourceVersion: "123"
+  creationTimestamp: "2024-01-01T00:00:00Z"
+  labels:
+    kapp.k14s.io/app: "1234"
+    team: platform
+data:
+  key: val
+`)),
+		ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: app-1
+  namespace: default
+  labels:
+    kapp.k14s.io/app: "1234"
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: app
+    uid: deployment-uid
+`)),
+	}
+	rs[1].MarkTransient(true)
+
+	result, err := comparableAppResources(rs, "kapp.k14s.io/app")
+	require.NoError(t, err)
+	require.Len(t, result, 1, "Expected transient resources to be skipped")
+
+	require.Equal(t, `apiVersion: v1
+data:
+  key: val
+kind: ConfigMap
+metadata:
+  labels:
+    team: platform
+  name: config
+  namespace: default
+`, string(mustResourceAsYAML(t, result[0])))
+}
+
+func TestCompareAppsPrintDiff(t *testing.T) {
+	newConfigMap := func(name, val string) ctlres.Resource {
+		return ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + name + `
+  namespace: default
+data:
+  key: ` + val + `
+`))
+	}
+
+	firstRs := []ctlres.Resource{newConfigMap("only-first", "val"), newConfigMap("common", "val1"), newConfigMap("same", "val")}
+	secondRs := []ctlres.Resource{newConfigMap("common", "val2"), newConfigMap("only-second", "val"), newConfigMap("same", "val")}
+
+	out := bytes.NewBufferString("")
+
+	opts := &CompareAppsOptions{
+		ui: ui.NewWriterUI(out, out, ui.NewNoopLogger()),
+		DiffFlags: cmdtools.DiffFlags{
+			ChangeSetViewOpts: ctlcap.ChangeSetViewOpts{Summary: true, Changes: true},
+		},
+	}
+
+	err := opts.printDiff(firstRs, secondRs)
+	require.NoError(t, err)
+
+	require.Contains(t, out.String(), "@@ delete configmap/only-first (v1) namespace: default @@")
+	require.Contains(t, out.String(), "@@ create configmap/only-second (v1) namespace: default @@")
+	require.Contains(t, out.String(), "@@ update configmap/common (v1) namespace: default @@")
+	require.Contains(t, out.String(), "-   key: val1")
+	require.Contains(t, out.String(), "+   key: val2")
+	require.Contains(t, out.String(), "@@ noop configmap/same (v1) namespace: default @@\n\n")
+	require.Contains(t, out.String(), "Op:      1 create, 1 delete, 1 update, 1 noop, 0 exists")
+}
+
+func TestCompareAppsRequiresTwoApps(t *testing.T) {
+	opts := &CompareAppsOptions{AppNames: []string{"app1"}}
+
+	err := opts.Run()
+	require.EqualError(t, err, "Expected exactly two apps to be specified via --app")
+}
+
+func mustResourceAsYAML(t *testing.T, res ctlres.Resource) []byte {
+	bs, err := res.AsYAMLBytes()
+	require.NoError(t, err)
+	return bs
+}
@@ -62,6 +62,24 @@ func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 func (o *DeleteOptions) Run() error {
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
+	err := o.ApplyFlags.DeleteChangeOpts.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = validateExitStatusFlags(o.DiffFlags, o.ApplyFlags)
+	if err != nil {
+		return err
+	}
+
+	stopMetricsServer, err := o.ApplyFlags.StartMetricsServer()
+	if err != nil {
+		return err
+	}
+	defer stopMetricsServer()
+
+	printWaitTimings := o.ApplyFlags.StartWaitTimings(o.ui)
+
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
 	if err != nil {
 		return err
@@ -97,6 +115,11 @@ func (o *DeleteOptions) Run() error {
 		}
 	}
 
+	err = o.depsFactory.NamespaceGuard().ValidateAppNamespace(app.Namespace())
+	if err != nil {
+		return err
+	}
+
 	usedGVs, err := app.UsedGVs()
 	if err != nil {
 		return err
@@ -104,6 +127,11 @@ func (o *DeleteOptions) Run() error {
 
 	failingAPIServicesPolicy.MarkRequiredGVs(usedGVs)
 
+	supportObjs.IdentifiedResources, err = appIdentifiedResources(app, supportObjs.IdentifiedResources)
+	if err != nil {
+		return err
+	}
+
 	existingResources, shouldFullyDeleteApp, err := o.existingResources(app, supportObjs)
 	if err != nil {
 		return err
@@ -114,8 +142,13 @@ func (o *DeleteOptions) Run() error {
 		return err
 	}
 
+	existingResources, releasedResources, err := o.releasedSharedResources(app, existingResources, supportObjs)
+	if err != nil {
+		return err
+	}
+
 	clusterChangeSet, clusterChangesGraph, changesSummary, err :=
-		o.calculateAndPresentChanges(existingResources, conf, supportObjs)
+		o.calculateAndPresentChanges(existingResources, releasedResources, conf, supportObjs)
 	if err != nil {
 		if o.DiffFlags.UI && clusterChangesGraph != nil {
 			return o.presentDiffUI(clusterChangesGraph)
@@ -139,7 +172,7 @@ func (o *DeleteOptions) Run() error {
 
 	if o.DiffFlags.Run {
 		if o.DiffFlags.ExitStatus {
-			return DeployDiffExitStatus{changesSummary.HasNoChanges}
+			return NewDeployDiffExitStatus(changesSummary.HasNoChanges, o.DiffFlags)
 		}
 		return nil
 	}
@@ -161,6 +194,8 @@ func (o *DeleteOptions) Run() error {
 	touch := ctlapp.Touch{App: app, Description: "delete", IgnoreSuccessErr: true}
 
 	err = touch.Do(func() error {
+		defer printWaitTimings()
+
 		err := clusterChangeSet.Apply(clusterChangesGraph)
 		if err != nil {
 			if shouldFullyDeleteApp {
@@ -181,7 +216,7 @@ func (o *DeleteOptions) Run() error {
 	}
 
 	if o.ApplyFlags.ExitStatus {
-		return DeployApplyExitStatus{changesSummary.HasNoChanges}
+		return o.ApplyFlags.NewExitStatus(changesSummary.HasNoChanges)
 	}
 	return nil
 }
@@ -224,7 +259,49 @@ func (o *DeleteOptions) existingResources(app ctlapp.App,
 	return existingResources, fullyDeleteApp, nil
 }
 
-func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Resource, conf ctlconf.Conf,
+// releasedSharedResources returns shared resources that are co-owned by other apps,
+// hence should be updated to not include fields applied by this app instead of being deleted.
+// Existing resources are extended with co-owned resources labeled by other apps.
+func (o *DeleteOptions) releasedSharedResources(app ctlapp.App, existingResources []ctlres.Resource,
+	supportObjs FactorySupportObjs) ([]ctlres.Resource, []ctlres.Resource, error) {
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	appLabelKey, appLabelVal, err := ctlres.NewSimpleLabel(labelSelector).KV()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coOwnedResources, err := coOwnedSharedResources(meta.SharedResources, nil,
+		existingResources, appLabelVal, resourceFilter, supportObjs.IdentifiedResources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	existingResources = append(existingResources, coOwnedResources...)
+
+	releasedResources, err := ctldiff.NewSharedResources(existingResources, nil, appLabelKey, appLabelVal).Prepare()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return existingResources, releasedResources, nil
+}
+
+func (o *DeleteOptions) calculateAndPresentChanges(existingResources, releasedResources []ctlres.Resource, conf ctlconf.Conf,
 	supportObjs FactorySupportObjs) (ctlcap.ClusterChangeSet, *ctldgraph.ChangeGraph, changesSummary, error) {
 
 	var (
@@ -232,11 +309,11 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 		skippedChanges   bool
 	)
 
-	{ // Figure out changes for X existing resources -> 0 new resources
-		changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{o.DiffFlags.AnchoredDiff})
+	{ // Figure out changes for X existing resources -> 0 new resources (except released shared resources)
+		changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, o.DiffFlags.ChangeOpts())
 		changeSetFactory := ctldiff.NewChangeSetFactory(o.DiffFlags.ChangeSetOpts, changeFactory)
 
-		changes, err := changeSetFactory.New(existingResources, nil).Calculate()
+		changes, err := changeSetFactory.New(existingResources, releasedResources).Calculate()
 		if err != nil {
 			return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
 		}
@@ -262,7 +339,7 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 
 			clusterChangeFactory := ctlcap.NewClusterChangeFactory(
 				o.ApplyFlags.ClusterChangeOpts, supportObjs.IdentifiedResources,
-				changeFactory, changeSetFactory, convergedResFactory, msgsUI, conf.DiffMaskRules())
+				changeFactory, changeSetFactory, convergedResFactory, msgsUI, conf.DiffMaskRules(), conf.ApplyStrategyRules(), conf.WaitTimeouts())
 
 			clusterChangeSet = ctlcap.NewClusterChangeSet(
 				appliedChanges, o.ApplyFlags.ClusterChangeSetOpts, clusterChangeFactory,
@@ -275,6 +352,16 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 		return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
 	}
 
+	err = o.depsFactory.NamespaceGuard().Validate(changedResources(clusterChanges))
+	if err != nil {
+		return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
+	}
+
+	err = validateOwnedNamespaceDeletes(clusterChanges, supportObjs.IdentifiedResources)
+	if err != nil {
+		return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
+	}
+
 	{ // Present cluster changes in UI
 		changeViews := ctlcap.ClusterChangesAsChangeViews(clusterChanges)
 		changeSetView := ctlcap.NewChangeSetView(
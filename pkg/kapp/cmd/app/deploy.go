@@ -8,14 +8,20 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 
 	ctlapp "carvel.dev/kapp/pkg/kapp/app"
@@ -31,6 +37,7 @@ import (
 	"carvel.dev/kapp/pkg/kapp/preflight"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"carvel.dev/kapp/pkg/kapp/yttresmod"
 )
 
 const (
@@ -55,6 +62,10 @@ type DeployOptions struct {
 	PreflightChecks *preflight.Registry
 
 	FileSystem fs.FS
+
+	// fileResources are read once per retried deploy
+	// since some sources (e.g. stdin) cannot be read again
+	fileResources *[]ctlres.Resource
 }
 
 func NewDeployOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger, preflights *preflight.Registry) *DeployOptions {
@@ -101,24 +112,170 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 }
 
 func (o *DeployOptions) Run() error {
+	if o.DeployFlags.Watch {
+		return o.watch()
+	}
+	return o.runWithRetries()
+}
+
+func (o *DeployOptions) runWithRetries() error {
+	retryRegexp, err := o.DeployFlags.RetryDeployRegexp()
+	if err != nil {
+		return err
+	}
+
+	if retryRegexp != nil {
+		fileResources, err := o.newResourcesFromFiles()
+		if err != nil {
+			return err
+		}
+		o.fileResources = &fileResources
+		defer func() { o.fileResources = nil }()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = o.run()
+		if err == nil || retryRegexp == nil || !retryRegexp.MatchString(err.Error()) {
+			return err
+		}
+		if attempt > o.DeployFlags.RetryDeployCount {
+			return fmt.Errorf("Deploy failed after %d attempts: %w", attempt, err)
+		}
+
+		o.ui.ErrorLinef("Deploy attempt %d failed with error matching --retry-deploy-on (retrying in %s): %s",
+			attempt, o.DeployFlags.RetryDeployBackoff, err)
+
+		time.Sleep(o.DeployFlags.RetryDeployBackoff)
+	}
+}
+
+func (o *DeployOptions) run() error {
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
+	lockOpts, err := o.DeployFlags.LockOpts()
+	if err != nil {
+		return err
+	}
+
+	err = o.DeployFlags.ValidateDumpOrder()
+	if err != nil {
+		return err
+	}
+
+	err = o.ApplyFlags.DeleteChangeOpts.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = validateExitStatusFlags(o.DiffFlags, o.ApplyFlags)
+	if err != nil {
+		return err
+	}
+
+	err = o.DeployFlags.ValidatePlan()
+	if err != nil {
+		return err
+	}
+
+	err = o.DeployFlags.ValidateVerifyOnly()
+	if err != nil {
+		return err
+	}
+
+	// Writing deploy plan or verifying does not make any changes, similar to diff run
+	isDiffRun := o.DiffFlags.Run || len(o.DeployFlags.PlanOut) > 0 || o.DeployFlags.VerifyOnly
+
+	err = o.DeployFlags.ValidateNoAppChangeRecord()
+	if err != nil {
+		return err
+	}
+
+	if len(o.DeployFlags.DiffAgainstFile) > 0 {
+		return o.diffAgainstFile()
+	}
+
+	if o.DeployFlags.Resume {
+		if o.DeployFlags.RetryFailed {
+			return fmt.Errorf("Expected --resume to not be set when --retry-failed is specified")
+		}
+	}
+
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
 	if err != nil {
 		return err
 	}
 
+	// Check before app (and its lock) is created or updated
+	err = o.depsFactory.NamespaceGuard().ValidateAppNamespace(app.Namespace())
+	if err != nil {
+		return err
+	}
+
+	err = o.checkMinServerVersion(supportObjs.CoreClient)
+	if err != nil {
+		return err
+	}
+
+	stopMetricsServer, err := o.ApplyFlags.StartMetricsServer()
+	if err != nil {
+		return err
+	}
+	defer stopMetricsServer()
+
+	printWaitTimings := o.ApplyFlags.StartWaitTimings(o.ui)
+
+	if o.DeployFlags.Lock && !isDiffRun {
+		lock := ctlapp.NewLock(app, supportObjs.CoreClient, lockOpts, o.logger)
+
+		err = lock.Acquire()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			releaseErr := lock.Release()
+			if releaseErr != nil {
+				o.ui.ErrorLinef("Error: %s", releaseErr)
+			}
+		}()
+	}
+
 	appLabels, err := o.LabelFlags.AsMap()
 	if err != nil {
 		return err
 	}
 
-	isNewApp, err := app.CreateOrUpdate(o.PrevAppFlags.PrevAppName, appLabels, o.DiffFlags.Run)
+	if len(o.DeployFlags.AppLabelValue) > 0 {
+		// Label values have to be unique across all namespaces
+		// since app resources may be placed into any namespace
+		allApps := ctlapp.NewApps("", supportObjs.CoreClient, supportObjs.IdentifiedResources, o.logger)
+
+		err = o.checkAppLabelValue(app, allApps)
+		if err != nil {
+			return err
+		}
+	}
+
+	isNewApp, err := app.CreateOrUpdate(o.PrevAppFlags.PrevAppName, appLabels, ctlapp.CreateOrUpdateOpts{
+		IsDiffRun:  isDiffRun,
+		LabelValue: o.DeployFlags.AppLabelValue,
+	})
 
 	if err != nil {
 		return err
 	}
 
+	if len(o.DeployFlags.AppLabelValue) > 0 {
+		meta, err := app.Meta()
+		if err != nil {
+			return err
+		}
+		if meta.LabelValue != o.DeployFlags.AppLabelValue {
+			o.ui.ErrorLinef("Warning: Ignoring --app-label-value '%s' since existing app uses label value '%s' "+
+				"(changing it would orphan app resources)", o.DeployFlags.AppLabelValue, meta.LabelValue)
+		}
+	}
+
 	usedGVs, err := app.UsedGVs()
 	if err != nil {
 		return err
@@ -133,7 +290,19 @@ func (o *DeployOptions) Run() error {
 
 	o.DeployFlags.PrepareResourcesOpts.DefaultNamespace = o.AppFlags.NamespaceFlags.Name
 
-	prep := ctlapp.NewPreparation(supportObjs.ResourceTypes, o.DeployFlags.PrepareResourcesOpts)
+	o.DeployFlags.PrepareResourcesOpts.NamespaceExistsFunc = func(name string) (bool, error) {
+		_, err := supportObjs.CoreClient.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
+	prep := ctlapp.NewPreparation(supportObjs.ResourceTypes,
+		ctlres.NewOpenAPISchema(supportObjs.CoreClient), o.DeployFlags.PrepareResourcesOpts)
 
 	labelSelector, err := app.LabelSelector()
 	if err != nil {
@@ -152,49 +321,161 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
-	usedGKs, err := o.newAndUsedGKs(newGKs, app)
+	meta, err := app.Meta()
 	if err != nil {
 		return err
 	}
 
-	meta, err := app.Meta()
+	identityAnnotationDisabled := conf.IsManagedAnnotationDisabled(ctlconf.ManagedAnnotationIdentity)
+
+	switch {
+	case identityAnnotationDisabled:
+		supportObjs.IdentifiedResources = supportObjs.IdentifiedResources.WithoutIdentityAnnotation()
+		labeledResources = ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
+	case meta.IdentityAnnotationDisabled:
+		supportObjs.IdentifiedResources = supportObjs.IdentifiedResources.WithOptionalIdentityAnnotation()
+		labeledResources = ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
+	}
+
+	usedGKs, err := o.newAndUsedGKs(newGKs, app)
 	if err != nil {
 		return err
 	}
 
-	existingResources, existingPodRs, err := o.existingResources(
+	existingResources, existingPodRs, adoptedResources, err := o.existingResources(
 		newResources, labeledResources, resourceFilter, supportObjs.Apps, usedGKs, append(meta.LastChange.Namespaces, nsNames...), isNewApp)
 	if err != nil {
 		return err
 	}
 
+	appLabelKey, appLabelVal, err := ctlres.NewSimpleLabel(labelSelector).KV()
+	if err != nil {
+		return err
+	}
+
+	if o.DeployFlags.AdoptKeepExisting {
+		newResources, err = ctldiff.NewAdoptedResources(adoptedResources, newResources).Prepare()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Shared resources may be labeled by another co-owner, hence are looked up explicitly
+	coOwnedResources, err := coOwnedSharedResources(meta.SharedResources, newResources,
+		existingResources, appLabelVal, resourceFilter, supportObjs.IdentifiedResources)
+	if err != nil {
+		return err
+	}
+
+	existingResources = append(existingResources, coOwnedResources...)
+
+	newResources, err = ctldiff.NewSharedResources(existingResources, newResources, appLabelKey, appLabelVal).Prepare()
+	if err != nil {
+		return err
+	}
+
+	// Used GVs and GKs are tracked based on all resources even when only some are retried
+	allNewResources, allExistingResources := newResources, existingResources
+
+	if o.DeployFlags.RetryFailed {
+		newResources, existingResources = o.retryFailedResources(meta.LastChange, newResources, existingResources)
+	}
+
+	// Pauses are recorded as part of app change so they cannot be used without it
+	o.ApplyFlags.ClusterChangeSetOpts.Pauses = ctlcap.PauseOpts{Enabled: !o.DeployFlags.NoAppChangeRecord}
+
+	if o.DeployFlags.Resume {
+		newResources, existingResources, err = o.resumePausedResources(meta.LastChange, newResources, existingResources)
+		if err != nil {
+			return err
+		}
+		o.ApplyFlags.ClusterChangeSetOpts.Pauses.Resumed = meta.LastChange.PausedBefore
+	}
+
+	// App change is only known once it's started (right before applying changes)
+	var appChangeID string
+
+	if o.DeployFlags.ChangeIDAnnotation {
+		supportObjs.IdentifiedResources = supportObjs.IdentifiedResources.WithChangeIDAnnotation(
+			func() string { return appChangeID })
+	}
+
 	clusterChangeSet, clusterChangesGraph, hasNoChanges, changeSummary, err :=
-		o.calculateAndPresentChanges(existingResources, newResources, conf, supportObjs)
+		o.calculateAndPresentChanges(existingResources, newResources, meta.LastChange, conf, supportObjs)
 	if err != nil {
 		if o.DiffFlags.UI && clusterChangesGraph != nil {
 			return o.presentDiffUI(clusterChangesGraph)
 		}
+		if len(o.DeployFlags.DumpOrder) > 0 && clusterChangesGraph != nil {
+			// Graph is useful for finding out why cycle was formed
+			dumpErr := o.dumpOrder(clusterChangesGraph)
+			if dumpErr != nil {
+				return dumpErr
+			}
+		}
 		return err
 	}
 
+	if len(o.DeployFlags.DumpOrder) > 0 {
+		return o.dumpOrder(clusterChangesGraph)
+	}
+
 	// Validate new resources _after_ presenting changes to make it easier to see big picture
 	err = prep.ValidateResources(newResources)
 	if err != nil {
 		return err
 	}
 
+	if o.DeployFlags.VerifyOnly {
+		if !hasNoChanges {
+			return DeployDriftExitStatus{ChangesSummary: changeSummary}
+		}
+		o.ui.PrintLinef("Verified: no drift detected")
+		return nil
+	}
+
+	if len(o.DeployFlags.PlanOut) > 0 || len(o.DeployFlags.ApplyPlan) > 0 {
+		plan, err := NewDeployPlan(app.Name(), app.Namespace(), appLabelVal,
+			ctlcap.ClusterChangesFromGraph(clusterChangesGraph), conf.DiffMaskRules())
+		if err != nil {
+			return fmt.Errorf("Calculating deploy plan: %w", err)
+		}
+
+		if len(o.DeployFlags.PlanOut) > 0 {
+			err = plan.WriteToFile(o.DeployFlags.PlanOut)
+			if err != nil {
+				return err
+			}
+			o.ui.PrintLinef("Wrote deploy plan with %d changes to %s", len(plan.Changes), o.DeployFlags.PlanOut)
+			return nil
+		}
+
+		err = o.checkPlanDrift(plan)
+		if err != nil {
+			return err
+		}
+	}
+
 	if o.DiffFlags.UI {
 		return o.presentDiffUI(clusterChangesGraph)
 	}
 
+	if o.DeployFlags.DetectMutations {
+		mutations, err := ctlcap.NewMutationsDetector(ctlcap.ClusterChangesFromGraph(clusterChangesGraph)).Detect()
+		if err != nil {
+			return fmt.Errorf("Detecting mutations: %w", err)
+		}
+		ctlcap.NewMutationsView(mutations, conf.DiffMaskRules(), o.DiffFlags.TextDiffViewOpts).Print(o.ui)
+	}
+
 	if o.DiffFlags.Run || hasNoChanges {
 		o.writeAppMetadataToFile(app)
 
 		if o.DiffFlags.Run && o.DiffFlags.ExitStatus {
-			return DeployDiffExitStatus{hasNoChanges}
+			return NewDeployDiffExitStatus(hasNoChanges, o.DiffFlags)
 		}
 		if o.ApplyFlags.ExitStatus {
-			return DeployApplyExitStatus{hasNoChanges}
+			return o.ApplyFlags.NewExitStatus(hasNoChanges)
 		}
 		return nil
 	}
@@ -216,8 +497,25 @@ func (o *DeployOptions) Run() error {
 	}
 
 	// Track newly added GVs and GKs
-	err = app.UpdateUsedGVsAndGKs(failingAPIServicesPolicy.GVs(newResources, existingResources),
-		NewUsedGKsScope(append(newResources, existingResources...)).GKs())
+	err = app.UpdateUsedGVsAndGKs(failingAPIServicesPolicy.GVs(allNewResources, allExistingResources),
+		NewUsedGKsScope(append(allNewResources, allExistingResources...)).GKs())
+	if err != nil {
+		return err
+	}
+
+	// Recorded before applying so that delete and inspect
+	// recognize resources created without identity annotation
+	if identityAnnotationDisabled {
+		err = app.MarkIdentityAnnotationDisabled()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Recorded before applying so that shared resources labeled
+	// by other co-owners can be released in subsequent deploys
+	err = app.UpdateSharedResources(sharedResourceRefs(meta.SharedResources,
+		allNewResources, allExistingResources, appLabelVal, resourceFilter))
 	if err != nil {
 		return err
 	}
@@ -228,12 +526,28 @@ func (o *DeployOptions) Run() error {
 		go o.showLogs(supportObjs.CoreClient, supportObjs.IdentifiedResources, existingPodRs, labelSelector, cancelLogsCh, append(meta.LastChange.Namespaces, nsNames...))
 	}
 
-	defer func() {
-		_, numDeleted, _ := app.GCChanges(o.DeployFlags.AppChangesMaxToKeep, nil)
-		if numDeleted > 0 {
-			o.ui.PrintLinef("Deleted %d older app changes", numDeleted)
-		}
-	}()
+	if o.DeployFlags.LogsFailing {
+		cancelLogsCh := make(chan struct{})
+		defer func() { close(cancelLogsCh) }()
+		go o.showFailingLogs(supportObjs.CoreClient, supportObjs.IdentifiedResources, existingPodRs, labelSelector, cancelLogsCh, append(meta.LastChange.Namespaces, nsNames...))
+	}
+
+	if !o.DeployFlags.NoAppChangeRecord {
+		defer func() {
+			_, numDeleted, _ := app.GCChanges(o.DeployFlags.AppChangesMaxToKeep, nil)
+			if numDeleted > 0 {
+				o.ui.PrintLinef("Deleted %d older app changes", numDeleted)
+			}
+		}()
+	}
+
+	if o.DeployFlags.ShowDeprecationWarnings {
+		supportObjs.ResourceWarnings.Enable()
+		defer o.printResourceWarnings(supportObjs.ResourceWarnings)
+	}
+
+	var unsuccessfulChanges []*ctlcap.ClusterChange
+	var pausedErr *ctlcap.PausedErr
 
 	touch := ctlapp.Touch{
 		App:                 app,
@@ -241,30 +555,153 @@ func (o *DeployOptions) Run() error {
 		Namespaces:          nsNames,
 		IgnoreSuccessErr:    true,
 		AppChangesMaxToKeep: o.DeployFlags.AppChangesMaxToKeep,
+		SkipChangeRecord:    o.DeployFlags.NoAppChangeRecord,
+		Resources:           uniqueResourceKeys(allNewResources),
+		FailedResourcesFunc: func() []string {
+			var keys []string
+			for _, change := range unsuccessfulChanges {
+				keys = append(keys, ctlres.NewUniqueResourceKey(change.Resource()).String())
+			}
+			return keys
+		},
+		PausedResourcesFunc: func() ([]string, []string) {
+			if pausedErr == nil {
+				return nil, nil
+			}
+			var pausedBeforeKeys, pendingKeys []string
+			for _, change := range pausedErr.PausedBefore {
+				pausedBeforeKeys = append(pausedBeforeKeys, ctlres.NewUniqueResourceKey(change.Resource()).String())
+			}
+			// Only changes that were not applied are pending
+			for _, change := range pausedErr.Pending {
+				pendingKeys = append(pendingKeys, ctlres.NewUniqueResourceKey(change.Resource()).String())
+			}
+			return pausedBeforeKeys, pendingKeys
+		},
 	}
 
 	err = touch.Do(func() error {
 		defer o.writeAppMetadataToFile(app)
+		defer printWaitTimings()
+
+		if o.DeployFlags.ChangeIDAnnotation {
+			appMeta, err := app.Meta()
+			if err != nil {
+				return err
+			}
+			appChangeID = appMeta.LastChangeName
+		}
 
-		err := clusterChangeSet.Apply(clusterChangesGraph)
+		var err error
+
+		unsuccessfulChanges, err = clusterChangeSet.ApplyAndListUnsuccessful(clusterChangesGraph)
 		if err != nil {
-			return err
+			typedErr, isPaused := err.(ctlcap.PausedErr)
+			if !isPaused {
+				return err
+			}
+			pausedErr = &typedErr
 		}
 
 		// Remove unused GVs and GKs
-		return app.UpdateUsedGVsAndGKs(failingAPIServicesPolicy.GVs(newResources, nil),
-			NewUsedGKsScope(newResources).GKs())
+		return app.UpdateUsedGVsAndGKs(failingAPIServicesPolicy.GVs(allNewResources, nil),
+			NewUsedGKsScope(allNewResources).GKs())
 	})
 	if err != nil {
 		return err
 	}
 
+	err = o.writeAppliedResourcesToDir(clusterChangesGraph)
+	if err != nil {
+		return err
+	}
+
+	if pausedErr != nil {
+		return DeployPausedExitStatus{PausedErr: *pausedErr}
+	}
+
 	if o.ApplyFlags.ExitStatus {
-		return DeployApplyExitStatus{hasNoChanges}
+		return o.ApplyFlags.NewExitStatus(hasNoChanges)
 	}
 	return nil
 }
 
+// diffAgainstFile diffs provided resources against previously
+// rendered resources (e.g. for review of manifest changes) without a cluster
+func (o *DeployOptions) diffAgainstFile() error {
+	diffOpts := cmdtools.NewDiffOptions(o.ui, o.depsFactory)
+	diffOpts.FileFlags = o.FileFlags
+	diffOpts.FileFlags2 = cmdtools.FileFlags2{Files: []string{o.DeployFlags.DiffAgainstFile}}
+	diffOpts.DiffFlags = o.DiffFlags
+	diffOpts.FileSystem = o.FileSystem
+
+	hasNoChanges, err := diffOpts.DiffAndPrint()
+	if err != nil {
+		return err
+	}
+
+	if o.DiffFlags.ExitStatus {
+		return NewDeployDiffExitStatus(hasNoChanges, o.DiffFlags)
+	}
+	return nil
+}
+
+// retryFailedResources narrows down resources to ones that did not
+// successfully finish during last app change, if it failed and recorded them
+func (o *DeployOptions) retryFailedResources(lastChange ctlapp.ChangeMeta,
+	newResources, existingResources []ctlres.Resource) ([]ctlres.Resource, []ctlres.Resource) {
+
+	if lastChange.Successful == nil || *lastChange.Successful || len(lastChange.FailedResources) == 0 {
+		o.ui.PrintLinef("No failed resources recorded for last app change, deploying all resources")
+		return newResources, existingResources
+	}
+
+	o.ui.PrintLinef("Retrying %d resource(s) that did not succeed during last app change", len(lastChange.FailedResources))
+
+	return resourcesWithKeys(newResources, lastChange.FailedResources),
+		resourcesWithKeys(existingResources, lastChange.FailedResources)
+}
+
+// resumePausedResources narrows down resources to ones that were not applied
+// during last app change because it paused before some of them
+func (o *DeployOptions) resumePausedResources(lastChange ctlapp.ChangeMeta,
+	newResources, existingResources []ctlres.Resource) ([]ctlres.Resource, []ctlres.Resource, error) {
+
+	if !lastChange.Paused {
+		return nil, nil, fmt.Errorf("Expected last app change to be paused (via '%s' annotation) to resume it",
+			"kapp.k14s.io/pause-before")
+	}
+
+	o.ui.PrintLinef("Resuming %d resource(s) that were not applied during last app change", len(lastChange.PendingResources))
+
+	return resourcesWithKeys(newResources, lastChange.PendingResources),
+		resourcesWithKeys(existingResources, lastChange.PendingResources), nil
+}
+
+func resourcesWithKeys(rs []ctlres.Resource, keys []string) []ctlres.Resource {
+	keysMap := map[string]struct{}{}
+	for _, key := range keys {
+		keysMap[key] = struct{}{}
+	}
+
+	var result []ctlres.Resource
+	for _, res := range rs {
+		if _, found := keysMap[ctlres.NewUniqueResourceKey(res).String()]; found {
+			result = append(result, res)
+		}
+	}
+	return result
+}
+
+func (o *DeployOptions) printResourceWarnings(resWarnings *ctlres.ResourceWarnings) {
+	for _, resWarning := range resWarnings.List() {
+		o.ui.ErrorLinef("Warning: API server returned warnings for %s:", resWarning.ResourceDescription)
+		for _, msg := range resWarning.Messages {
+			o.ui.ErrorLinef("  - %s", msg)
+		}
+	}
+}
+
 func (o *DeployOptions) newAndUsedGKs(newGKs []schema.GroupKind, app ctlapp.App) ([]schema.GroupKind, error) {
 	if o.DeployFlags.DisableGKScoping {
 		return []schema.GroupKind{}, nil
@@ -303,7 +740,7 @@ func (o *DeployOptions) newAndUsedGKs(newGKs []schema.GroupKind, app ctlapp.App)
 	}
 
 	// adding Pod in GKs to get existing Pod resources (#460)
-	if _, exists := gksByGK[podGK]; !exists && o.DeployFlags.Logs {
+	if _, exists := gksByGK[podGK]; !exists && (o.DeployFlags.Logs || o.DeployFlags.LogsFailing) {
 		uniqGKs = append(uniqGKs, podGK)
 	}
 
@@ -314,7 +751,7 @@ func (o *DeployOptions) newResources(
 	prep ctlapp.Preparation, labeledResources *ctlres.LabeledResources,
 	resourceFilter ctlres.ResourceFilter) ([]ctlres.Resource, ctlconf.Conf, []string, []schema.GroupKind, error) {
 
-	newResources, err := o.newResourcesFromFiles()
+	newResources, err := o.resourcesFromFiles()
 	if err != nil {
 		return nil, ctlconf.Conf{}, nil, nil, err
 	}
@@ -324,11 +761,33 @@ func (o *DeployOptions) newResources(
 		return nil, ctlconf.Conf{}, nil, nil, err
 	}
 
+	newResources, err = o.overlayResources(newResources)
+	if err != nil {
+		return nil, ctlconf.Conf{}, nil, nil, err
+	}
+
 	newResources, err = prep.PrepareResources(newResources)
 	if err != nil {
 		return nil, ctlconf.Conf{}, nil, nil, err
 	}
 
+	if o.DeployFlags.OwnNamespace {
+		newResources, err = o.withOwnedNamespace(newResources)
+		if err != nil {
+			return nil, ctlconf.Conf{}, nil, nil, err
+		}
+	}
+
+	if len(o.DeployFlags.NameSuffix) > 0 {
+		warnings, err := ctldiff.NewNameSuffixedResources(newResources, conf.TemplateRules()).Apply(o.DeployFlags.NameSuffix)
+		if err != nil {
+			return nil, ctlconf.Conf{}, nil, nil, err
+		}
+		for _, warning := range warnings {
+			o.ui.ErrorLinef("Warning: %s", warning)
+		}
+	}
+
 	err = labeledResources.Prepare(newResources, conf.OwnershipLabelMods(),
 		conf.LabelScopingMods(o.DeployFlags.DefaultLabelScopingRules), conf.AdditionalLabels())
 	if err != nil {
@@ -343,13 +802,63 @@ func (o *DeployOptions) newResources(
 	return resourceFilter.Apply(newResources), conf, nsNames, newGKs, nil
 }
 
+// checkAppLabelValue makes sure that label value is valid and is not
+// used by another app as otherwise apps would share resources
+func (o *DeployOptions) checkAppLabelValue(app ctlapp.App, apps ctlapp.Apps) error {
+	labelValue := o.DeployFlags.AppLabelValue
+
+	if errs := validation.IsValidLabelValue(labelValue); len(errs) > 0 {
+		return fmt.Errorf("Expected --app-label-value '%s' to be a valid label value: %s", labelValue, strings.Join(errs, "; "))
+	}
+
+	items, err := apps.List(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item.Name() == app.Name() && item.Namespace() == app.Namespace() {
+			continue
+		}
+		meta, err := item.Meta()
+		if err != nil {
+			return err
+		}
+		if meta.LabelValue == labelValue {
+			return fmt.Errorf("Expected --app-label-value '%s' to be unique, but it is already used by %s", labelValue, item.Description())
+		}
+	}
+
+	return nil
+}
+
+// resourcesFromFiles returns copies of previously read resources
+// (when deploy is retried) since preparation modifies them
+func (o *DeployOptions) resourcesFromFiles() ([]ctlres.Resource, error) {
+	if o.fileResources == nil {
+		return o.newResourcesFromFiles()
+	}
+	var result []ctlres.Resource
+	for _, res := range *o.fileResources {
+		resCopy := res.DeepCopy()
+		resCopy.SetOrigin(res.Origin())
+		result = append(result, resCopy)
+	}
+	return result, nil
+}
+
 func (o *DeployOptions) newResourcesFromFiles() ([]ctlres.Resource, error) {
 	var allResources []ctlres.Resource
 
-	if len(o.FileFlags.Files) == 0 {
+	files, err := o.FileFlags.AllFiles(o.FileSystem)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
 		return nil, fmt.Errorf("Expected at least one --file (-f) specified with a file or directory path")
 	}
-	for _, file := range o.FileFlags.Files {
+	for _, file := range files {
 		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
 		if err != nil {
 			return nil, err
@@ -367,9 +876,34 @@ func (o *DeployOptions) newResourcesFromFiles() ([]ctlres.Resource, error) {
 	return allResources, nil
 }
 
+func (o *DeployOptions) overlayResources(resources []ctlres.Resource) ([]ctlres.Resource, error) {
+	for _, file := range o.DeployFlags.OverlayFiles {
+		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		if err != nil {
+			return nil, err
+		}
+
+		// Directory contents are applied in a sorted order
+		for _, fileRes := range fileRs {
+			overlayBs, err := fileRes.Bytes()
+			if err != nil {
+				return nil, err
+			}
+
+			resources, err = yttresmod.NewResourcesOverlay(overlayBs, fileRes.Description()).Apply(resources)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return resources, nil
+}
+
 func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
 	labeledResources *ctlres.LabeledResources, resourceFilter ctlres.ResourceFilter,
-	apps ctlapp.Apps, usedGKs []schema.GroupKind, resourceNamespaces []string, isNewApp bool) ([]ctlres.Resource, []ctlres.Resource, error) {
+	apps ctlapp.Apps, usedGKs []schema.GroupKind, resourceNamespaces []string, isNewApp bool) ([]ctlres.Resource, []ctlres.Resource, []ctlres.Resource, error) {
+
+	var adoptedResources []ctlres.Resource
 
 	labelErrorResolutionFunc := func(key string, val string) string {
 		items, _ := apps.List(nil)
@@ -391,6 +925,10 @@ func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
 		// Prevent accidently overriding kapp state records
 		DisallowedResourcesByLabelKeys: []string{ctlapp.KappIsAppLabelKey},
 		LabelErrorResolutionFunc:       labelErrorResolutionFunc,
+		AdoptedResourcesCheckFunc: func(rs []ctlres.Resource) error {
+			adoptedResources = rs
+			return o.checkHelmManagedResources(rs)
+		},
 
 		//Scope resource searching to UsedGKs
 		IdentifiedResourcesListOpts: ctlres.IdentifiedResourcesListOpts{
@@ -401,35 +939,74 @@ func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
 
 	existingResources, err := labeledResources.AllAndMatching(newResources, matchingOpts)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if o.DeployFlags.Patch {
 		existingResources, err = ctlres.NewUniqueResources(existingResources).Match(newResources)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	} else {
 		if len(newResources) == 0 && !o.DeployFlags.AllowEmpty {
-			return nil, nil, fmt.Errorf("Trying to apply empty set of resources will result in deletion of resources on cluster. " +
+			return nil, nil, nil, fmt.Errorf("Trying to apply empty set of resources will result in deletion of resources on cluster. " +
 				"Refusing to continue unless --dangerous-allow-empty-list-of-resources is specified.")
 		}
 	}
 
-	return resourceFilter.Apply(existingResources), o.existingPodResources(existingResources), nil
+	return resourceFilter.Apply(existingResources), o.existingPodResources(existingResources), adoptedResources, nil
+}
+
+// checkHelmManagedResources warns (or fails) about adopting resources
+// that are also tracked by Helm since both tools would manage them
+func (o *DeployOptions) checkHelmManagedResources(adoptedResources []ctlres.Resource) error {
+	var msgs []string
+
+	for _, res := range adoptedResources {
+		helmRelease := ctlres.NewHelmRelease(res)
+		if helmRelease.IsManaged() {
+			msgs = append(msgs, fmt.Sprintf("- Resource '%s' is managed by %s", res.Description(), helmRelease.Description()))
+		}
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if o.DeployFlags.FailOnHelmManaged {
+		return fmt.Errorf("Helm managed resources errors (--fail-on-helm-managed):\n%s", strings.Join(msgs, "\n"))
+	}
+
+	o.ui.ErrorLinef("Warning: Adopting existing resources managed by Helm:\n%s", strings.Join(msgs, "\n"))
+
+	return nil
 }
 
 func (o *DeployOptions) calculateAndPresentChanges(existingResources,
-	newResources []ctlres.Resource, conf ctlconf.Conf, supportObjs FactorySupportObjs) (
+	newResources []ctlres.Resource, lastChange ctlapp.ChangeMeta, conf ctlconf.Conf, supportObjs FactorySupportObjs) (
 	ctlcap.ClusterChangeSet, *ctldgraph.ChangeGraph, bool, string, error) {
 
 	var clusterChangeSet ctlcap.ClusterChangeSet
 
 	{ // Figure out changes for X existing resources -> X new resources
-		changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), conf.DiffAgainstLastAppliedFieldExclusionMods(), conf.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{o.DiffFlags.AnchoredDiff})
+		externalRebaseSources, err := ctlcap.NewExternalResources(supportObjs.IdentifiedResources,
+			o.ApplyFlags.WaitingChangesOpts.CheckInterval, cmdcore.NewPlainMessagesUI(o.ui)).Wait(conf.RebaseExternalResources())
+		if err != nil {
+			return clusterChangeSet, nil, false, "", err
+		}
+
+		var rebaseLog *ctldiff.RebaseLog
+		if o.DeployFlags.DebugRebase {
+			rebaseLog = ctldiff.NewRebaseLog()
+		}
+
+		changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), conf.DiffAgainstLastAppliedFieldExclusionMods(), conf.DiffAgainstExistingFieldExclusionMods(), o.DiffFlags.ChangeOpts()).
+			WithManagedFieldsExclusionRules(conf.DiffAgainstExistingManagedFieldsExclusionRules()).
+			WithExternalRebaseSources(externalRebaseSources).
+			WithRebaseLog(rebaseLog)
 		changeSetFactory := ctldiff.NewChangeSetFactory(o.DiffFlags.ChangeSetOpts, changeFactory)
 
-		err := ctldiff.NewRenewableResources(existingResources, newResources).Prepare()
+		err = ctldiff.NewRenewableResources(existingResources, newResources).Prepare()
 		if err != nil {
 			return clusterChangeSet, nil, false, "", err
 		}
@@ -448,19 +1025,55 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 
 		changes = diffFilter.Apply(changes)
 
+		if rebaseLog != nil {
+			if rebaseLogStr := rebaseLog.String(); len(rebaseLogStr) > 0 {
+				o.ui.PrintLinef("Rebase rules changed resources:\n%s\n", rebaseLogStr)
+			} else {
+				o.ui.PrintLinef("Rebase rules did not change any resources\n")
+			}
+		}
+
 		msgsUI := cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui))
 
-		convergedResFactory := ctlcap.NewConvergedResourceFactory(conf.WaitRules(), ctlcap.ConvergedResourceFactoryOpts{
+		convergedResFactoryOpts := ctlcap.ConvergedResourceFactoryOpts{
 			IgnoreFailingAPIServices: o.ResourceTypesFlags.IgnoreFailingAPIServices,
-		})
+		}
+		if o.DeployFlags.WaitCRDPrinterColumns {
+			convergedResFactoryOpts.CRDFunc = ctlcap.NewCRDLookup(
+				newResources, supportObjs.ResourceTypes, supportObjs.IdentifiedResources).Find
+		}
+
+		convergedResFactory := ctlcap.NewConvergedResourceFactory(conf.WaitRules(), convergedResFactoryOpts)
+
+		clusterChangeOpts := o.ApplyFlags.ClusterChangeOpts
+		clusterChangeOpts.AddOrUpdateChangeOpts.DisableOriginalAnnotation = conf.IsManagedAnnotationDisabled(ctlconf.ManagedAnnotationOriginal)
 
 		clusterChangeFactory := ctlcap.NewClusterChangeFactory(
-			o.ApplyFlags.ClusterChangeOpts, supportObjs.IdentifiedResources,
-			changeFactory, changeSetFactory, convergedResFactory, msgsUI, conf.DiffMaskRules())
+			clusterChangeOpts, supportObjs.IdentifiedResources,
+			changeFactory, changeSetFactory, convergedResFactory, msgsUI, conf.DiffMaskRules(), conf.ApplyStrategyRules(), conf.WaitTimeouts())
+
+		clusterChangeSetOpts := o.ApplyFlags.ClusterChangeSetOpts
+
+		clusterChangeSetOpts.StagedRollout, err = o.DeployFlags.StagedRolloutOpts()
+		if err != nil {
+			return clusterChangeSet, nil, false, "", err
+		}
+
+		changeGroupBindings := conf.ChangeGroupBindings()
+		changeRuleBindings := conf.ChangeRuleBindings()
+
+		if o.DeployFlags.InferOrdering {
+			inferredGroupBindings, inferredRuleBindings, err := ctldgraph.NewInferredOrdering(newResources).Bindings()
+			if err != nil {
+				return clusterChangeSet, nil, false, "", fmt.Errorf("Inferring ordering: %w", err)
+			}
+			changeGroupBindings = append(changeGroupBindings, inferredGroupBindings...)
+			changeRuleBindings = append(changeRuleBindings, inferredRuleBindings...)
+		}
 
 		clusterChangeSet = ctlcap.NewClusterChangeSet(
-			changes, o.ApplyFlags.ClusterChangeSetOpts, clusterChangeFactory,
-			conf.ChangeGroupBindings(), conf.ChangeRuleBindings(), msgsUI, o.logger)
+			changes, clusterChangeSetOpts, clusterChangeFactory,
+			changeGroupBindings, changeRuleBindings, msgsUI, o.logger)
 	}
 
 	clusterChanges, clusterChangesGraph, err := clusterChangeSet.Calculate()
@@ -469,19 +1082,87 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 		return clusterChangeSet, clusterChangesGraph, false, "", err
 	}
 
+	err = o.depsFactory.NamespaceGuard().Validate(changedResources(clusterChanges))
+	if err != nil {
+		return clusterChangeSet, clusterChangesGraph, false, "", err
+	}
+
+	err = validateOwnedNamespaceDeletes(clusterChanges, supportObjs.IdentifiedResources)
+	if err != nil {
+		return clusterChangeSet, clusterChangesGraph, false, "", err
+	}
+
 	var changesSummary string
 
 	{ // Present cluster changes in UI
 		changeViews := ctlcap.ClusterChangesAsChangeViews(clusterChanges)
+		externallyDeleted := o.externallyDeletedResources(lastChange, clusterChanges)
+
+		notes := map[string]string{}
+		for _, res := range externallyDeleted {
+			notes[ctlres.NewUniqueResourceKey(res).String()] = "recreate (was deleted externally)"
+		}
+
 		changeSetView := ctlcap.NewChangeSetView(
-			changeViews, conf.DiffMaskRules(), o.DiffFlags.ChangeSetViewOpts)
+			changeViews, conf.DiffMaskRules(), o.DiffFlags.ChangeSetViewOpts).WithNotes(notes)
 		changeSetView.Print(o.ui)
 		changesSummary = changeSetView.Summary()
+
+		if len(externallyDeleted) > 0 {
+			o.ui.ErrorLinef("Warning: %d resource(s) deployed by last app change were deleted externally and will be recreated:", len(externallyDeleted))
+			for _, res := range externallyDeleted {
+				o.ui.ErrorLinef("  - %s", res.Description())
+			}
+		}
 	}
 
 	return clusterChangeSet, clusterChangesGraph, (len(clusterChanges) == 0), changesSummary, err
 }
 
+// externallyDeletedResources returns resources that were successfully deployed
+// by last app change but are going to be created again (e.g. deleted via kubectl)
+func (o *DeployOptions) externallyDeletedResources(lastChange ctlapp.ChangeMeta,
+	clusterChanges []*ctlcap.ClusterChange) []ctlres.Resource {
+
+	if lastChange.Successful == nil || !*lastChange.Successful {
+		return nil
+	}
+
+	lastKeys := map[string]struct{}{}
+	for _, key := range lastChange.Resources {
+		lastKeys[key] = struct{}{}
+	}
+
+	var result []ctlres.Resource
+	for _, change := range clusterChanges {
+		if change.ApplyOp() != ctlcap.ClusterChangeApplyOpAdd {
+			continue
+		}
+		if _, found := lastKeys[ctlres.NewUniqueResourceKey(change.Resource()).String()]; found {
+			result = append(result, change.Resource())
+		}
+	}
+	return result
+}
+
+func (o *DeployOptions) checkMinServerVersion(coreClient kubernetes.Interface) error {
+	if len(o.DeployFlags.MinServerVersion) == 0 {
+		return nil
+	}
+
+	minVersion, err := ctlres.NewMinServerVersion(o.DeployFlags.MinServerVersion)
+	if err != nil {
+		return err
+	}
+
+	info, err := coreClient.Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("Fetching Kubernetes server version: %w", err)
+	}
+
+	return minVersion.Check(info)
+}
+
 func (o *DeployOptions) existingPodResources(existingResources []ctlres.Resource) []ctlres.Resource {
 	var existingPods []ctlres.Resource
 	for _, res := range existingResources {
@@ -503,6 +1184,53 @@ func (o *DeployOptions) writeAppMetadataToFile(app ctlapp.App) error {
 	return nil
 }
 
+var (
+	outputResourcesDirUnsafeCharsRegexp = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+// writeAppliedResourcesToDir writes resources as they were sent to the cluster
+// (i.e. after kapp added labels and rebased fields), hence they may differ from provided input
+func (o *DeployOptions) writeAppliedResourcesToDir(changesGraph *ctldgraph.ChangeGraph) error {
+	if len(o.DeployFlags.OutputResourcesDir) == 0 {
+		return nil
+	}
+
+	err := os.MkdirAll(o.DeployFlags.OutputResourcesDir, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Creating output resources directory: %w", err)
+	}
+
+	for _, change := range ctlcap.ClusterChangesFromGraph(changesGraph) {
+		switch change.ApplyOp() {
+		case ctlcap.ClusterChangeApplyOpAdd, ctlcap.ClusterChangeApplyOpUpdate:
+		default:
+			continue
+		}
+
+		res := change.Resource()
+
+		resBytes, err := res.AsYAMLBytes()
+		if err != nil {
+			return err
+		}
+
+		nsName := res.Namespace()
+		if len(nsName) == 0 {
+			nsName = "cluster"
+		}
+
+		fileName := strings.Join([]string{res.APIGroup(), res.Kind(), nsName, res.Name()}, "_")
+		fileName = outputResourcesDirUnsafeCharsRegexp.ReplaceAllString(fileName, "-") + ".yml"
+
+		err = os.WriteFile(filepath.Join(o.DeployFlags.OutputResourcesDir, fileName), resBytes, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("Writing resource %s: %w", res.Description(), err)
+		}
+	}
+
+	return nil
+}
+
 const (
 	deployLogsAnnKey              = "kapp.k14s.io/deploy-logs" // valid value is '' (default), for-new, for-existing, for-new-or-existing
 	deployLogsAnnDefault          = ""                         // equivalent to for-new
@@ -517,13 +1245,86 @@ func (o *DeployOptions) showLogs(
 	coreClient kubernetes.Interface, identifiedResources ctlres.IdentifiedResources,
 	existingPodRs []ctlres.Resource, labelSelector labels.Selector, cancelCh chan struct{}, resourceNamespaces []string) {
 
+	podWatcher := ctlres.FilteringPodWatcher{
+		o.deployLogsPodMatcher(existingPodRs),
+		identifiedResources.PodResources(labelSelector, resourceNamespaces),
+	}
+
+	contFilterFunc := func(pod corev1.Pod) []string {
+		ann, found := pod.Annotations[deployLogsContNamesAnnKey]
+		if found && ann != "" {
+			return strings.Split(ann, ",")
+		}
+		return nil
+	}
+
+	logOpts := ctllogs.PodLogOpts{Follow: true, ContainerTag: true, LinePrefix: "logs"}
+
+	ctllogs.NewView(logOpts, podWatcher, contFilterFunc, coreClient, o.ui).Show(cancelCh)
+}
+
+// showFailingLogs shows logs from new Pods that start failing while changes
+// are applied and waited on (Pods already shown via --logs are skipped)
+func (o *DeployOptions) showFailingLogs(
+	coreClient kubernetes.Interface, identifiedResources ctlres.IdentifiedResources,
+	existingPodRs []ctlres.Resource, labelSelector labels.Selector, cancelCh chan struct{}, resourceNamespaces []string) {
+
+	podWatcher := identifiedResources.PodResources(labelSelector, resourceNamespaces).WithMatcher(o.failingLogsPodMatcher(existingPodRs))
+
+	contFilterFunc := func(pod corev1.Pod) []string { return nil }
+
+	lines := o.DeployFlags.LogsFailingLines
+	logOpts := ctllogs.PodLogOpts{Follow: true, Lines: &lines, ContainerTag: true, LinePrefix: "failing logs", EndWithPod: true}
+
+	ctllogs.NewView(logOpts, podWatcher, contFilterFunc, coreClient, o.ui).Show(cancelCh)
+}
+
+func (o *DeployOptions) failingLogsPodMatcher(existingPodRs []ctlres.Resource) func(*corev1.Pod) bool {
+	existingPodsByUID := map[string]struct{}{}
+
+	for _, res := range existingPodRs {
+		existingPodsByUID[res.UID()] = struct{}{}
+	}
+
+	deployLogsPodMatcherFunc := o.deployLogsPodMatcher(existingPodRs)
+
+	return func(pod *corev1.Pod) bool {
+		if _, isExistingPod := existingPodsByUID[string(pod.UID)]; isExistingPod {
+			return false
+		}
+		if o.DeployFlags.Logs && deployLogsPodMatcherFunc(pod) {
+			return false
+		}
+		return isFailingPod(pod)
+	}
+}
+
+func isFailingPod(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodFailed {
+		return true
+	}
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, status := range statuses {
+		if status.RestartCount > 0 {
+			return true
+		}
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *DeployOptions) deployLogsPodMatcher(existingPodRs []ctlres.Resource) func(*corev1.Pod) bool {
 	existingPodsByUID := map[string]struct{}{}
 
 	for _, res := range existingPodRs {
 		existingPodsByUID[res.UID()] = struct{}{}
 	}
 
-	podMatcherFunc := func(pod *corev1.Pod) bool {
+	return func(pod *corev1.Pod) bool {
 		if o.DeployFlags.LogsAll {
 			return true
 		}
@@ -546,23 +1347,6 @@ func (o *DeployOptions) showLogs(
 			return false
 		}
 	}
-
-	podWatcher := ctlres.FilteringPodWatcher{
-		podMatcherFunc,
-		identifiedResources.PodResources(labelSelector, resourceNamespaces),
-	}
-
-	contFilterFunc := func(pod corev1.Pod) []string {
-		ann, found := pod.Annotations[deployLogsContNamesAnnKey]
-		if found && ann != "" {
-			return strings.Split(ann, ",")
-		}
-		return nil
-	}
-
-	logOpts := ctllogs.PodLogOpts{Follow: true, ContainerTag: true, LinePrefix: "logs"}
-
-	ctllogs.NewView(logOpts, podWatcher, contFilterFunc, coreClient, o.ui).Show(cancelCh)
 }
 
 func (o *DeployOptions) nsNames(resources []ctlres.Resource) []string {
@@ -582,9 +1366,59 @@ func (o *DeployOptions) nsNames(resources []ctlres.Resource) []string {
 	return names
 }
 
+func (o *DeployOptions) dumpOrder(graph *ctldgraph.ChangeGraph) error {
+	dotStr, err := graph.PrintDotStr()
+	if err != nil {
+		return fmt.Errorf("Printing apply order: %w", err)
+	}
+	o.ui.PrintBlock([]byte(dotStr))
+	return nil
+}
+
+func (o *DeployOptions) checkPlanDrift(currentPlan DeployPlan) error {
+	approvedPlan, err := NewDeployPlanFromFile(o.DeployFlags.ApplyPlan)
+	if err != nil {
+		return err
+	}
+
+	drift := approvedPlan.Drift(currentPlan)
+	if len(drift) == 0 {
+		return nil
+	}
+
+	msg := "Changes do not match deploy plan:\n  - " + strings.Join(drift, "\n  - ")
+
+	if o.DeployFlags.ForcePlan {
+		o.ui.ErrorLinef("Warning: %s\n(continuing since --force-plan is specified)", msg)
+		return nil
+	}
+
+	return fmt.Errorf("%s\n(use --force-plan to apply anyway)", msg)
+}
+
 func (o *DeployOptions) presentDiffUI(graph *ctldgraph.ChangeGraph) error {
 	opts := ctldiffui.ServerOpts{
 		DiffDataFunc: func() *ctldgraph.ChangeGraph { return graph },
 	}
 	return ctldiffui.NewServer(opts, o.ui).Run()
 }
+
+// changedResources returns resources that will be created, updated or deleted
+func changedResources(clusterChanges []*ctlcap.ClusterChange) []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, change := range clusterChanges {
+		switch change.ApplyOp() {
+		case ctlcap.ClusterChangeApplyOpAdd, ctlcap.ClusterChangeApplyOpUpdate, ctlcap.ClusterChangeApplyOpDelete:
+			result = append(result, change.Resource())
+		}
+	}
+	return result
+}
+
+func uniqueResourceKeys(resources []ctlres.Resource) []string {
+	var keys []string
+	for _, res := range resources {
+		keys = append(keys, ctlres.NewUniqueResourceKey(res).String())
+	}
+	return keys
+}
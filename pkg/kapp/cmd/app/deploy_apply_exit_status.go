@@ -9,6 +9,9 @@ import (
 
 type DeployApplyExitStatus struct {
 	hasNoChanges bool
+
+	noChangesExitStatus int
+	changesExitStatus   int
 }
 
 var _ ExitStatus = DeployApplyExitStatus{}
@@ -23,8 +26,5 @@ func (d DeployApplyExitStatus) Error() string {
 }
 
 func (d DeployApplyExitStatus) ExitStatus() int {
-	if d.hasNoChanges {
-		return 2
-	}
-	return 3
+	return exitStatusForChanges(d.hasNoChanges, d.noChangesExitStatus, d.changesExitStatus)
 }
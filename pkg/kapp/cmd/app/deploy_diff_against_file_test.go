@@ -0,0 +1,69 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployDiffAgainstFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"prev.yml": &fstest.MapFile{Data: []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: default
+data:
+  key: old-val
+`)},
+		"new.yml": &fstest.MapFile{Data: []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: default
+data:
+  key: new-val
+`)},
+	}
+
+	diffAgainstFile := func(file string, diffFlags cmdtools.DiffFlags) (string, error) {
+		out := &bytes.Buffer{}
+
+		// No deps factory is provided since cluster is not accessed
+		o := NewDeployOptions(ui.NewWriterUI(out, out, ui.NewNoopLogger()), nil, nil, nil)
+		o.FileFlags = cmdtools.FileFlags{Files: []string{file}}
+		o.DeployFlags.DiffAgainstFile = "prev.yml"
+		o.DiffFlags = diffFlags
+		o.FileSystem = fsys
+
+		err := o.diffAgainstFile()
+		return out.String(), err
+	}
+
+	t.Run("shows diff against file", func(t *testing.T) {
+		out, err := diffAgainstFile("new.yml", cmdtools.DiffFlags{ChangeSetViewOpts: ctlcap.ChangeSetViewOpts{Changes: true}})
+		require.NoError(t, err)
+		require.Contains(t, out, "@@ update configmap/app-config (v1) namespace: default @@")
+		require.Contains(t, out, "new-val")
+	})
+
+	t.Run("returns diff exit status", func(t *testing.T) {
+		diffFlags := cmdtools.DiffFlags{ExitStatus: true, ExitStatusNoChanges: 2, ExitStatusChanges: 3}
+
+		_, err := diffAgainstFile("new.yml", diffFlags)
+		require.Equal(t, DeployDiffExitStatus{HasNoChanges: false, NoChangesExitStatus: 2, ChangesExitStatus: 3}, err)
+
+		_, err = diffAgainstFile("prev.yml", diffFlags)
+		require.Equal(t, DeployDiffExitStatus{HasNoChanges: true, NoChangesExitStatus: 2, ChangesExitStatus: 3}, err)
+	})
+}
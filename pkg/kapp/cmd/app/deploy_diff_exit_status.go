@@ -5,6 +5,15 @@ package app
 
 import (
 	"fmt"
+
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+)
+
+const (
+	// Exit statuses used by --diff-exit-status and --apply-exit-status
+	// unless configured otherwise (1 is reserved for failures)
+	defaultNoChangesExitStatus = 2
+	defaultChangesExitStatus   = 3
 )
 
 type ExitStatus interface {
@@ -13,6 +22,10 @@ type ExitStatus interface {
 
 type DeployDiffExitStatus struct {
 	HasNoChanges bool
+
+	// Optional custom exit statuses
+	NoChangesExitStatus int
+	ChangesExitStatus   int
 }
 
 var _ ExitStatus = DeployDiffExitStatus{}
@@ -27,8 +40,70 @@ func (d DeployDiffExitStatus) Error() string {
 }
 
 func (d DeployDiffExitStatus) ExitStatus() int {
-	if d.HasNoChanges {
-		return 2
+	return exitStatusForChanges(d.HasNoChanges, d.NoChangesExitStatus, d.ChangesExitStatus)
+}
+
+func validateExitStatusFlags(diffFlags cmdtools.DiffFlags, applyFlags ApplyFlags) error {
+	if diffFlags.ExitStatus {
+		err := validateExitStatuses(diffFlags.ExitStatusNoChanges, diffFlags.ExitStatusChanges, "--diff-exit-status")
+		if err != nil {
+			return err
+		}
 	}
-	return 3
+	if applyFlags.ExitStatus {
+		return validateExitStatuses(applyFlags.ExitStatusNoChanges, applyFlags.ExitStatusChanges, "--apply-exit-status")
+	}
+	return nil
+}
+
+// validateExitStatuses makes sure that custom exit statuses
+// can be distinguished from success, failure and each other
+func validateExitStatuses(noChangesExitStatus, changesExitStatus int, flagName string) error {
+	for _, status := range []int{noChangesExitStatus, changesExitStatus} {
+		if status < 2 || status > 125 {
+			return fmt.Errorf("Expected %s exit statuses to be between 2 and 125, but was %d", flagName, status)
+		}
+	}
+	if noChangesExitStatus == changesExitStatus {
+		return fmt.Errorf("Expected %s exit statuses for no changes and changes to be different, but both were %d",
+			flagName, noChangesExitStatus)
+	}
+	return nil
 }
+
+// NewDeployDiffExitStatus returns error carrying exit status for --diff-exit-status
+func NewDeployDiffExitStatus(hasNoChanges bool, diffFlags cmdtools.DiffFlags) DeployDiffExitStatus {
+	return DeployDiffExitStatus{
+		HasNoChanges:        hasNoChanges,
+		NoChangesExitStatus: diffFlags.ExitStatusNoChanges,
+		ChangesExitStatus:   diffFlags.ExitStatusChanges,
+	}
+}
+
+func exitStatusForChanges(hasNoChanges bool, noChangesExitStatus, changesExitStatus int) int {
+	if hasNoChanges {
+		if noChangesExitStatus != 0 {
+			return noChangesExitStatus
+		}
+		return defaultNoChangesExitStatus
+	}
+	if changesExitStatus != 0 {
+		return changesExitStatus
+	}
+	return defaultChangesExitStatus
+}
+
+// DeployDriftExitStatus is returned by --verify-only when
+// cluster state does not match provided resources
+type DeployDriftExitStatus struct {
+	ChangesSummary string
+}
+
+var _ ExitStatus = DeployDriftExitStatus{}
+
+func (d DeployDriftExitStatus) Error() string {
+	return fmt.Sprintf("Detected drift between provided resources and cluster state (%s) (exit status %d)",
+		d.ChangesSummary, d.ExitStatus())
+}
+
+func (DeployDriftExitStatus) ExitStatus() int { return defaultChangesExitStatus }
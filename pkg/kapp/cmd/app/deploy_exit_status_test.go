@@ -0,0 +1,95 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployDiffExitStatus(t *testing.T) {
+	t.Run("uses default exit statuses", func(t *testing.T) {
+		require.Equal(t, 2, DeployDiffExitStatus{HasNoChanges: true}.ExitStatus())
+		require.Equal(t, 3, DeployDiffExitStatus{HasNoChanges: false}.ExitStatus())
+	})
+
+	t.Run("uses exit statuses from flags", func(t *testing.T) {
+		diffFlags := cmdtools.DiffFlags{ExitStatusNoChanges: 10, ExitStatusChanges: 11}
+
+		err := NewDeployDiffExitStatus(true, diffFlags)
+		require.Equal(t, 10, err.ExitStatus())
+		require.EqualError(t, err, "Exiting after diffing with no pending changes (exit status 10)")
+
+		err = NewDeployDiffExitStatus(false, diffFlags)
+		require.Equal(t, 11, err.ExitStatus())
+		require.EqualError(t, err, "Exiting after diffing with pending changes (exit status 11)")
+	})
+}
+
+func TestDeployApplyExitStatus(t *testing.T) {
+	applyFlags := &ApplyFlags{}
+	cmd := &cobra.Command{}
+	applyFlags.SetWithDefaults("", ApplyFlags{}, cmd)
+
+	require.Equal(t, 2, applyFlags.NewExitStatus(true).ExitStatus(), "Expected default exit status for no changes")
+	require.Equal(t, 3, applyFlags.NewExitStatus(false).ExitStatus(), "Expected default exit status for changes")
+
+	require.NoError(t, cmd.Flags().Set("apply-exit-status-no-changes", "20"))
+	require.NoError(t, cmd.Flags().Set("apply-exit-status-changes", "21"))
+
+	err := applyFlags.NewExitStatus(true)
+	require.Equal(t, 20, err.ExitStatus())
+	require.EqualError(t, err, "Exiting after applying with no changes (exit status 20)")
+
+	err = applyFlags.NewExitStatus(false)
+	require.Equal(t, 21, err.ExitStatus())
+	require.EqualError(t, err, "Exiting after applying with changes (exit status 21)")
+}
+
+func TestValidateExitStatusFlags(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		diffFlags  cmdtools.DiffFlags
+		applyFlags ApplyFlags
+		err        string
+	}{
+		{
+			desc:       "custom exit statuses are not validated when exit status is not requested",
+			diffFlags:  cmdtools.DiffFlags{ExitStatusNoChanges: 0, ExitStatusChanges: 0},
+			applyFlags: ApplyFlags{ExitStatusNoChanges: 1, ExitStatusChanges: 1},
+		},
+		{
+			desc:       "valid exit statuses",
+			diffFlags:  cmdtools.DiffFlags{ExitStatus: true, ExitStatusNoChanges: 2, ExitStatusChanges: 3},
+			applyFlags: ApplyFlags{ExitStatus: true, ExitStatusNoChanges: 10, ExitStatusChanges: 125},
+		},
+		{
+			desc:      "diff exit status conflicting with failure",
+			diffFlags: cmdtools.DiffFlags{ExitStatus: true, ExitStatusNoChanges: 1, ExitStatusChanges: 3},
+			err:       "Expected --diff-exit-status exit statuses to be between 2 and 125, but was 1",
+		},
+		{
+			desc:       "apply exit status out of range",
+			applyFlags: ApplyFlags{ExitStatus: true, ExitStatusNoChanges: 2, ExitStatusChanges: 126},
+			err:        "Expected --apply-exit-status exit statuses to be between 2 and 125, but was 126",
+		},
+		{
+			desc:       "same exit statuses",
+			applyFlags: ApplyFlags{ExitStatus: true, ExitStatusNoChanges: 4, ExitStatusChanges: 4},
+			err:        "Expected --apply-exit-status exit statuses for no changes and changes to be different, but both were 4",
+		},
+	}
+
+	for _, tc := range testCases {
+		err := validateExitStatusFlags(tc.diffFlags, tc.applyFlags)
+		if len(tc.err) == 0 {
+			require.NoError(t, err, tc.desc)
+		} else {
+			require.EqualError(t, err, tc.err, tc.desc)
+		}
+	}
+}
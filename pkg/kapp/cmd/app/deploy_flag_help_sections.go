@@ -11,11 +11,12 @@ import (
 var (
 	CommonFlagGroup = cobrautil.FlagHelpSection{
 		Title:      "Common Flags:",
-		ExactMatch: []string{"namespace", "app", "file", "diff-changes"},
+		ExactMatch: []string{"namespace", "app", "file", "file-list", "diff-changes"},
 	}
 	DiffFlagGroup = cobrautil.FlagHelpSection{
 		Title:       "Diff Flags:",
 		PrefixMatch: "diff",
+		ExactMatch:  []string{"detect-mutations", "debug-rebase", "verify-only", "max-diff-lines-per-resource"},
 	}
 	ApplyFlagGroup = cobrautil.FlagHelpSection{
 		Title:       "Apply Flags:",
@@ -23,6 +24,30 @@ var (
 		ExactMatch: []string{
 			"dangerous-allow-empty-list-of-resources",
 			"dangerous-override-ownership-of-existing-resources",
+			"fail-on-helm-managed",
+			"adopt-keep-existing",
+			"own-namespace",
+			"metrics-bind",
+			"infer-ordering",
+			"dump-order",
+			"retry-failed",
+			"resume",
+			"change-id-annotation",
+			"no-app-change-record",
+			"staged-rollout",
+			"staged-rollout-verify",
+			"lock",
+			"lock-timeout",
+			"lock-ttl",
+			"delete-propagation",
+			"plan-out",
+			"force-plan",
+			"retry-deploy-on",
+			"retry-deploy-count",
+			"retry-deploy-backoff",
+			"watch",
+			"watch-interval",
+			"watch-debounce",
 		},
 	}
 	WaitFlagGroup = cobrautil.FlagHelpSection{
@@ -38,10 +63,11 @@ var (
 	ResourceValidationFlagGroup = cobrautil.FlagHelpSection{
 		Title:       "Resource Validation Flags:",
 		PrefixMatch: "allow",
+		ExactMatch:  []string{"require-label", "max-resources", "max-resources-per-kind", "validate-custom-resources", "min-server-version"},
 	}
 	ResourceManglingFlagGroup = cobrautil.FlagHelpSection{
 		Title:      "Resource Mangling Flags:",
-		ExactMatch: []string{"into-ns", "map-ns"},
+		ExactMatch: []string{"into-ns", "map-ns", "namespace-from-label", "overlay-file", "set-label", "set-annotation"},
 	}
 	LogsFlagGroup = cobrautil.FlagHelpSection{
 		Title:       "Logs Flags:",
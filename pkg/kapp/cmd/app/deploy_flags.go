@@ -5,8 +5,12 @@ package app
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
 	"github.com/spf13/cobra"
 )
 
@@ -18,16 +22,63 @@ type DeployFlags struct {
 	ExistingNonLabeledResourcesCheck            bool
 	ExistingNonLabeledResourcesCheckConcurrency int
 	OverrideOwnershipOfExistingResources        bool
+	FailOnHelmManaged                           bool
+	AdoptKeepExisting                           bool
 
 	AppChangesMaxToKeep int
+	AppLabelValue       string
+	NoAppChangeRecord   bool
 
 	DefaultLabelScopingRules bool
 
-	Logs            bool
-	LogsAll         bool
-	AppMetadataFile string
+	Logs               bool
+	LogsAll            bool
+	LogsFailing        bool
+	LogsFailingLines   int64
+	AppMetadataFile    string
+	OutputResourcesDir string
 
 	DisableGKScoping bool
+
+	NameSuffix   string
+	OverlayFiles []string
+
+	InferOrdering   bool
+	DumpOrder       string
+	RetryFailed     bool
+	DiffAgainstFile string
+	VerifyOnly      bool
+	OwnNamespace    bool
+	Resume          bool
+	DetectMutations bool
+	DebugRebase     bool
+
+	WaitCRDPrinterColumns bool
+
+	ChangeIDAnnotation bool
+
+	StagedRollout       bool
+	StagedRolloutVerify []string
+
+	Lock        bool
+	LockTimeout time.Duration
+	LockTTL     time.Duration
+
+	ShowDeprecationWarnings bool
+
+	PlanOut   string
+	ApplyPlan string
+	ForcePlan bool
+
+	MinServerVersion string
+
+	RetryDeployOn      string
+	RetryDeployCount   int
+	RetryDeployBackoff time.Duration
+
+	Watch         bool
+	WatchInterval time.Duration
+	WatchDebounce time.Duration
 }
 
 func (s *DeployFlags) Set(cmd *cobra.Command) {
@@ -38,6 +89,31 @@ func (s *DeployFlags) Set(cmd *cobra.Command) {
 
 	cmd.Flags().StringVar(&s.IntoNamespace, "into-ns", "", "Place resources into namespace")
 	cmd.Flags().StringSliceVar(&s.MapNamespaces, "map-ns", nil, "Map resources from one namespace into another (could be specified multiple times)")
+	cmd.Flags().BoolVar(&s.OwnNamespace, "own-namespace", false,
+		"Manage namespace that resources are deployed into (--into-ns or --namespace) as part of the app, deleting it together with the app")
+	cmd.Flags().StringVar(&s.NamespaceFromLabel, "namespace-from-label", "",
+		"Place namespaced resources into namespace specified by the value of this label (e.g. tenant)")
+
+	cmd.Flags().StringArrayVar(&s.SetLabels, "set-label", nil,
+		"Add label to all resources (format: key=val) (can be specified multiple times) (fails if resource has different value)")
+	cmd.Flags().StringArrayVar(&s.SetAnnotations, "set-annotation", nil,
+		"Add annotation to all resources (format: key=val) (can be specified multiple times) (fails if resource has different value)")
+
+	cmd.Flags().StringSliceVar(&s.RequiredLabels, "require-label", nil,
+		"Fail if any resource does not have this label key (can be specified multiple times)")
+	cmd.Flags().IntVar(&s.MaxResources, "max-resources", 0,
+		"Fail if number of resources exceeds this limit (0 means no limit)")
+	cmd.Flags().IntVar(&s.MaxResourcesPerKind, "max-resources-per-kind", 0,
+		"Fail if number of resources of any kind exceeds this limit (0 means no limit)")
+
+	cmd.Flags().BoolVar(&s.StrictUnknownFields, "strict-unknown-fields", false,
+		"Fail if resources contain fields unknown to the server's OpenAPI schema")
+	cmd.Flags().StringVar(&s.MinServerVersion, "min-server-version", "",
+		"Fail if Kubernetes server version is lower than this version (e.g. 1.27)")
+	cmd.Flags().BoolVar(&s.ValidateCustomResources, "validate-custom-resources", false,
+		"Set to validate custom resources against schemas of CRDs that are deployed together with them before applying")
+	cmd.Flags().BoolVar(&s.StripManagedFields, "strip-managed-fields", false,
+		"Remove metadata.managedFields from provided resources before applying them (kapp uses client-side apply)")
 
 	cmd.Flags().BoolVarP(&s.Patch, "patch", "p", false, "Add or update existing resources only, never delete any")
 	cmd.Flags().BoolVar(&s.AllowEmpty, "dangerous-allow-empty-list-of-resources", false, "Allow to apply empty set of resources (same as running kapp delete)")
@@ -48,16 +124,197 @@ func (s *DeployFlags) Set(cmd *cobra.Command) {
 		100, "Concurrency to check for existing non-labeled resources")
 	cmd.Flags().BoolVar(&s.OverrideOwnershipOfExistingResources, "dangerous-override-ownership-of-existing-resources",
 		false, "Steal existing resources from another app")
+	cmd.Flags().BoolVar(&s.FailOnHelmManaged, "fail-on-helm-managed",
+		false, "Fail instead of warning when existing resources managed by Helm would be adopted")
+	cmd.Flags().BoolVar(&s.AdoptKeepExisting, "adopt-keep-existing", false,
+		"Only add kapp labels to existing resources that are adopted by the app without changing their contents (provided contents are applied during next deploy)")
 
 	cmd.Flags().BoolVar(&s.DefaultLabelScopingRules, "default-label-scoping-rules",
 		true, "Use default label scoping rules")
 
 	cmd.Flags().IntVar(&s.AppChangesMaxToKeep, "app-changes-max-to-keep", ctlapp.AppChangesMaxToKeepDefault, "Maximum number of app changes to keep")
+	cmd.Flags().BoolVar(&s.NoAppChangeRecord, "no-app-change-record", false,
+		"Do not record deploy as an app change (--retry-failed, --change-id-annotation and 'kapp app-change ls' will not reflect this deploy)")
+	cmd.Flags().StringVar(&s.AppLabelValue, "app-label-value", "",
+		"Set app label value used to associate resources with a new app (defaults to generated value; existing apps keep their value)")
 
 	cmd.Flags().BoolVar(&s.Logs, "logs", true, fmt.Sprintf("Show logs from Pods annotated as '%s'", deployLogsAnnKey))
 	cmd.Flags().BoolVar(&s.LogsAll, "logs-all", false, "Show logs from all Pods")
+	cmd.Flags().BoolVar(&s.LogsFailing, "logs-failing", false,
+		"Show logs from new Pods once they start failing (e.g. crash looping containers, failed Job Pods)")
+	cmd.Flags().Int64Var(&s.LogsFailingLines, "logs-failing-lines", 50,
+		"Maximum number of lines to show from failing containers (each time they are restarted)")
+	cmd.Flags().BoolVar(&s.ShowDeprecationWarnings, "show-deprecation-warnings", true,
+		"Show warnings returned by API server (e.g. use of deprecated APIs) grouped by resource after applying changes")
 	cmd.Flags().StringVar(&s.AppMetadataFile, "app-metadata-file-output", "", "Set filename to write app metadata")
+	cmd.Flags().StringVar(&s.OutputResourcesDir, "output-resources-dir", "",
+		"Set directory to write applied resources to (includes modifications made by kapp, e.g. labels and rebased fields)")
+
+	cmd.Flags().StringVar(&s.NameSuffix, "name-suffix", "",
+		"Append suffix to names of namespaced resources, updating references based on template rules (e.g. v2)")
+
+	cmd.Flags().StringSliceVar(&s.OverlayFiles, "overlay-file", nil,
+		"Set ytt overlay file to transform resources before they are applied (format: /tmp/foo, https://..., -) (can repeat; applied in order)")
 
 	cmd.Flags().BoolVar(&s.DisableGKScoping, "dangerous-disable-gk-scoping",
 		false, "Disable scoping of resource searching to used GroupKinds")
+
+	cmd.Flags().BoolVar(&s.InferOrdering, "infer-ordering", false,
+		"Order changes based on references between resources (namespaces, CRDs, service account subjects of bindings, config maps and secrets used by workloads)")
+	cmd.Flags().BoolVar(&s.WaitCRDPrinterColumns, "wait-crd-printer-columns", false,
+		"Wait for custom resources without wait rules until 'Ready' printer column of their CRD is true")
+	cmd.Flags().StringVar(&s.DumpOrder, "dump-order", "",
+		"Print apply order (change graph with change groups and rules) instead of applying changes (format: dot)")
+
+	cmd.Flags().BoolVar(&s.DetectMutations, "detect-mutations", false,
+		"Apply changes in server dry run mode and show fields changed by the server (e.g. defaulting, admission webhooks)")
+
+	cmd.Flags().StringVar(&s.DiffAgainstFile, "diff-against-file", "",
+		"Show diff against resources from this file (format: /tmp/foo, https://..., -) instead of cluster state without deploying (no cluster access is needed)")
+	cmd.Flags().BoolVar(&s.VerifyOnly, "verify-only", false,
+		"Check that cluster state matches provided resources without applying changes (exit status 3: drift detected, 1: failure)")
+
+	cmd.Flags().BoolVar(&s.DebugRebase, "debug-rebase", false,
+		"Show rebase rules that changed each resource (e.g. fields copied from existing resources); nothing is recorded on resources")
+
+	cmd.Flags().BoolVar(&s.RetryFailed, "retry-failed", false,
+		"Only apply resources that did not succeed during last app change if it failed (deploys all resources if no failures were recorded)")
+	cmd.Flags().BoolVar(&s.Resume, "resume", false,
+		"Continue last app change that paused before resources annotated with 'kapp.k14s.io/pause-before' (same configuration is expected; paused deploy exits with status 4)")
+
+	cmd.Flags().BoolVar(&s.ChangeIDAnnotation, "change-id-annotation", false,
+		"Record app change name onto created or updated resources as 'kapp.k14s.io/change-id' annotation")
+
+	cmd.Flags().BoolVar(&s.StagedRollout, "staged-rollout", false,
+		"Verify change groups before proceeding with changes that depend on them, rolling back change group on failure")
+	cmd.Flags().StringArrayVar(&s.StagedRolloutVerify, "staged-rollout-verify", nil,
+		"Set command to verify change group (format: change-group=command) (can be specified multiple times)")
+
+	cmd.Flags().StringVar(&s.PlanOut, "plan-out", "",
+		"Write calculated changes as JSON deploy plan to file instead of applying them (e.g. for external approval)")
+	cmd.Flags().StringVar(&s.ApplyPlan, "apply-plan", "",
+		"Apply changes only if they match previously written deploy plan (fails if cluster or resources changed since)")
+	cmd.Flags().BoolVar(&s.ForcePlan, "force-plan", false, "Apply changes even if they do not match deploy plan specified via --apply-plan")
+
+	cmd.Flags().StringVar(&s.RetryDeployOn, "retry-deploy-on", "",
+		"Re-run whole deploy (recalculating changes) if it fails with error matching regular expression (e.g. 'failed calling webhook')")
+	cmd.Flags().IntVar(&s.RetryDeployCount, "retry-deploy-count", 3, "Maximum number of deploy retries when --retry-deploy-on is specified")
+	cmd.Flags().DurationVar(&s.RetryDeployBackoff, "retry-deploy-backoff", 10*time.Second, "Set duration to wait before retrying deploy")
+
+	cmd.Flags().BoolVar(&s.Watch, "watch", false,
+		"Keep running and deploy again when local files change (useful for local development; typically used with --yes)")
+	cmd.Flags().DurationVar(&s.WatchInterval, "watch-interval", 0,
+		"Deploy again after this duration even if files did not change (0s means only deploy on file changes)")
+	cmd.Flags().DurationVar(&s.WatchDebounce, "watch-debounce", 2*time.Second,
+		"Amount of time files have to stay unchanged before deploying again")
+
+	cmd.Flags().BoolVar(&s.Lock, "lock", false, "Acquire app lock to prevent concurrent deploys of the same app")
+	cmd.Flags().DurationVar(&s.LockTimeout, "lock-timeout", 0, "Maximum amount of time to wait for app lock held by someone else (0 fails immediately)")
+	cmd.Flags().DurationVar(&s.LockTTL, "lock-ttl", 1*time.Minute, "Set duration app lock stays valid if not renewed (e.g. kapp crashed)")
+}
+
+func (s *DeployFlags) LockOpts() (ctlapp.LockOpts, error) {
+	if !s.Lock && (s.LockTimeout != 0 || s.LockTTL != 1*time.Minute) {
+		return ctlapp.LockOpts{}, fmt.Errorf("Expected --lock to be set when --lock-timeout or --lock-ttl is specified")
+	}
+	if s.LockTimeout < 0 {
+		return ctlapp.LockOpts{}, fmt.Errorf("Expected --lock-timeout to be non-negative")
+	}
+	if s.LockTTL < 3*time.Second {
+		return ctlapp.LockOpts{}, fmt.Errorf("Expected --lock-ttl to be at least 3s")
+	}
+	return ctlapp.LockOpts{Timeout: s.LockTimeout, TTL: s.LockTTL}, nil
+}
+
+func (s *DeployFlags) ValidateDumpOrder() error {
+	if len(s.DumpOrder) > 0 && s.DumpOrder != "dot" {
+		return fmt.Errorf("Expected --dump-order to be one of: dot")
+	}
+	return nil
+}
+
+func (s *DeployFlags) ValidateVerifyOnly() error {
+	if !s.VerifyOnly {
+		return nil
+	}
+	if len(s.PlanOut) > 0 || len(s.ApplyPlan) > 0 {
+		return fmt.Errorf("Expected --verify-only to not be used with --plan-out or --apply-plan")
+	}
+	if len(s.DiffAgainstFile) > 0 {
+		return fmt.Errorf("Expected --verify-only to not be used with --diff-against-file")
+	}
+	if s.Resume || s.RetryFailed {
+		return fmt.Errorf("Expected --verify-only to not be used with --resume or --retry-failed")
+	}
+	if s.Watch {
+		return fmt.Errorf("Expected --verify-only to not be used with --watch")
+	}
+	return nil
+}
+
+func (s *DeployFlags) ValidateNoAppChangeRecord() error {
+	if !s.NoAppChangeRecord {
+		return nil
+	}
+	if s.ChangeIDAnnotation {
+		return fmt.Errorf("Expected --change-id-annotation to not be set when --no-app-change-record is specified")
+	}
+	if s.Resume {
+		return fmt.Errorf("Expected --resume to not be set when --no-app-change-record is specified")
+	}
+	return nil
+}
+
+func (s *DeployFlags) ValidatePlan() error {
+	if len(s.PlanOut) > 0 && len(s.ApplyPlan) > 0 {
+		return fmt.Errorf("Expected only one of --plan-out or --apply-plan to be specified")
+	}
+	if s.ForcePlan && len(s.ApplyPlan) == 0 {
+		return fmt.Errorf("Expected --apply-plan to be set when --force-plan is specified")
+	}
+	return nil
+}
+
+// RetryDeployRegexp returns nil if deploy should not be retried
+func (s *DeployFlags) RetryDeployRegexp() (*regexp.Regexp, error) {
+	if len(s.RetryDeployOn) == 0 {
+		return nil, nil
+	}
+	if s.RetryDeployCount < 0 {
+		return nil, fmt.Errorf("Expected --retry-deploy-count to be non-negative")
+	}
+	if s.RetryDeployBackoff < 0 {
+		return nil, fmt.Errorf("Expected --retry-deploy-backoff to be non-negative")
+	}
+	for _, file := range s.OverlayFiles {
+		if file == "-" {
+			return nil, fmt.Errorf("Expected --overlay-file to not read from stdin when --retry-deploy-on is specified")
+		}
+	}
+	retryRegexp, err := regexp.Compile(s.RetryDeployOn)
+	if err != nil {
+		return nil, fmt.Errorf("Expected --retry-deploy-on to be a valid regular expression: %w", err)
+	}
+	return retryRegexp, nil
+}
+
+func (s *DeployFlags) StagedRolloutOpts() (ctlcap.StagedRolloutOpts, error) {
+	opts := ctlcap.StagedRolloutOpts{Enabled: s.StagedRollout, VerifyCmds: map[string]string{}}
+
+	if !s.StagedRollout && len(s.StagedRolloutVerify) > 0 {
+		return opts, fmt.Errorf("Expected --staged-rollout to be set when --staged-rollout-verify is specified")
+	}
+
+	for _, val := range s.StagedRolloutVerify {
+		pieces := strings.SplitN(val, "=", 2)
+		if len(pieces) != 2 || len(pieces[0]) == 0 || len(pieces[1]) == 0 {
+			return opts, fmt.Errorf("Expected --staged-rollout-verify '%s' to be in format change-group=command", val)
+		}
+		if _, found := opts.VerifyCmds[pieces[0]]; found {
+			return opts, fmt.Errorf("Expected --staged-rollout-verify to be specified once for change group '%s'", pieces[0])
+		}
+		opts.VerifyCmds[pieces[0]] = pieces[1]
+	}
+
+	return opts, nil
 }
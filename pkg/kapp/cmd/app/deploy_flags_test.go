@@ -0,0 +1,92 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"testing"
+
+	cmdapp "carvel.dev/kapp/pkg/kapp/cmd/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployFlagsValidateVerifyOnly(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		flags cmdapp.DeployFlags
+		err   string
+	}{
+		{"not verifying", cmdapp.DeployFlags{Watch: true, Resume: true}, ""},
+		{"only verifying", cmdapp.DeployFlags{VerifyOnly: true}, ""},
+		{"with plan out", cmdapp.DeployFlags{VerifyOnly: true, PlanOut: "plan.json"},
+			"Expected --verify-only to not be used with --plan-out or --apply-plan"},
+		{"with diff against file", cmdapp.DeployFlags{VerifyOnly: true, DiffAgainstFile: "last.yml"},
+			"Expected --verify-only to not be used with --diff-against-file"},
+		{"with resume", cmdapp.DeployFlags{VerifyOnly: true, Resume: true},
+			"Expected --verify-only to not be used with --resume or --retry-failed"},
+		{"with watch", cmdapp.DeployFlags{VerifyOnly: true, Watch: true},
+			"Expected --verify-only to not be used with --watch"},
+	}
+
+	for _, tc := range testCases {
+		err := tc.flags.ValidateVerifyOnly()
+		if len(tc.err) == 0 {
+			require.NoError(t, err, tc.desc)
+		} else {
+			require.EqualError(t, err, tc.err, tc.desc)
+		}
+	}
+}
+
+func TestDeployFlagsValidateNoAppChangeRecord(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		flags cmdapp.DeployFlags
+		err   string
+	}{
+		{"recording", cmdapp.DeployFlags{ChangeIDAnnotation: true, Resume: true}, ""},
+		{"not recording", cmdapp.DeployFlags{NoAppChangeRecord: true, RetryFailed: true}, ""},
+		{"with change ID annotation", cmdapp.DeployFlags{NoAppChangeRecord: true, ChangeIDAnnotation: true},
+			"Expected --change-id-annotation to not be set when --no-app-change-record is specified"},
+		{"with resume", cmdapp.DeployFlags{NoAppChangeRecord: true, Resume: true},
+			"Expected --resume to not be set when --no-app-change-record is specified"},
+	}
+
+	for _, tc := range testCases {
+		err := tc.flags.ValidateNoAppChangeRecord()
+		if len(tc.err) == 0 {
+			require.NoError(t, err, tc.desc)
+		} else {
+			require.EqualError(t, err, tc.err, tc.desc)
+		}
+	}
+}
+
+func TestDeployDriftExitStatus(t *testing.T) {
+	var err error = cmdapp.DeployDriftExitStatus{ChangesSummary: "Op: 1 create, 0 delete, 0 update, 0 noop, 0 exists"}
+
+	exitStatus, ok := err.(cmdapp.ExitStatus)
+	require.True(t, ok, "Expected drift to be reported via exit status")
+	require.Equal(t, 3, exitStatus.ExitStatus())
+	require.Equal(t, "Detected drift between provided resources and cluster state "+
+		"(Op: 1 create, 0 delete, 0 update, 0 noop, 0 exists) (exit status 3)", err.Error())
+}
+
+func TestDeployFlagsStagedRolloutOpts(t *testing.T) {
+	opts, err := (&cmdapp.DeployFlags{
+		StagedRollout:       true,
+		StagedRolloutVerify: []string{"db=curl -f http://db/health", "app=./check.sh --arg=val"},
+	}).StagedRolloutOpts()
+	require.NoError(t, err)
+	require.True(t, opts.Enabled)
+	require.Equal(t, map[string]string{"db": "curl -f http://db/health", "app": "./check.sh --arg=val"}, opts.VerifyCmds)
+
+	_, err = (&cmdapp.DeployFlags{StagedRolloutVerify: []string{"db=true"}}).StagedRolloutOpts()
+	require.EqualError(t, err, "Expected --staged-rollout to be set when --staged-rollout-verify is specified")
+
+	_, err = (&cmdapp.DeployFlags{StagedRollout: true, StagedRolloutVerify: []string{"db"}}).StagedRolloutOpts()
+	require.EqualError(t, err, "Expected --staged-rollout-verify 'db' to be in format change-group=command")
+
+	_, err = (&cmdapp.DeployFlags{StagedRollout: true, StagedRolloutVerify: []string{"db=true", "db=false"}}).StagedRolloutOpts()
+	require.EqualError(t, err, "Expected --staged-rollout-verify to be specified once for change group 'db'")
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestIsFailingPod(t *testing.T) {
+	tests := []struct {
+		desc    string
+		status  corev1.PodStatus
+		failing bool
+	}{
+		{"running", corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{
+			{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+		}}, false},
+		{"succeeded", corev1.PodStatus{Phase: corev1.PodSucceeded, ContainerStatuses: []corev1.ContainerStatus{
+			{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+		}}, false},
+		{"failed", corev1.PodStatus{Phase: corev1.PodFailed}, true},
+		{"restarted container", corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{
+			{RestartCount: 1, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+		}}, true},
+		{"init container exited with error", corev1.PodStatus{Phase: corev1.PodPending, InitContainerStatuses: []corev1.ContainerStatus{
+			{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}},
+		}}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			require.Equal(t, test.failing, isFailingPod(&corev1.Pod{Status: test.status}))
+		})
+	}
+}
+
+func TestFailingLogsPodMatcher(t *testing.T) {
+	existingPodRs := []ctlres.Resource{ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: existing
+  namespace: default
+  uid: existing-uid
+`))}
+
+	newFailingPod := func(uid string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid), Annotations: annotations},
+			Status:     corev1.PodStatus{Phase: corev1.PodFailed},
+		}
+	}
+
+	o := &DeployOptions{DeployFlags: DeployFlags{Logs: true}}
+	matcherFunc := o.failingLogsPodMatcher(existingPodRs)
+
+	require.True(t, matcherFunc(newFailingPod("new-uid", nil)))
+	require.False(t, matcherFunc(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "new-uid"}}), "Expected healthy Pod to not match")
+	require.False(t, matcherFunc(newFailingPod("existing-uid", nil)), "Expected existing Pod to not match")
+	require.False(t, matcherFunc(newFailingPod("new-uid", map[string]string{deployLogsAnnKey: ""})),
+		"Expected Pod already shown via --logs to not match")
+
+	t.Run("includes Pods annotated for logs when --logs is disabled", func(t *testing.T) {
+		o := &DeployOptions{DeployFlags: DeployFlags{Logs: false}}
+		require.True(t, o.failingLogsPodMatcher(existingPodRs)(newFailingPod("new-uid", map[string]string{deployLogsAnnKey: ""})))
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+type noopClusterApplyUI struct{}
+
+func (noopClusterApplyUI) NotifySection(string, ...interface{}) {}
+func (noopClusterApplyUI) Notify([]string)                      {}
+
+func TestDeployWriteAppliedResourcesToDir(t *testing.T) {
+	addedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: added
+  namespace: ns
+  labels:
+    kapp.k14s.io/app: "123"
+data:
+  key: val
+`))
+
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: system:updated
+rules: []
+`))
+
+	updatedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: system:updated
+rules:
+- apiGroups: [""]
+  resources: [configmaps]
+  verbs: [get]
+`))
+
+	keptRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kept
+  namespace: ns
+`))
+
+	deletedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: deleted
+  namespace: ns
+`))
+
+	changeFactory := newTestChangeFactory()
+
+	var changes []ctldiff.Change
+
+	for _, pair := range [][2]ctlres.Resource{
+		{nil, addedRes},
+		{existingRes, updatedRes},
+		{keptRes, keptRes.DeepCopy()},
+		{deletedRes, nil},
+	} {
+		changes = append(changes, changeFactory.NewChange(t, pair[0], pair[1]))
+	}
+
+	_, graph, err := ctlcap.NewClusterChangeSet(changes, ctlcap.ClusterChangeSetOpts{}, changeFactory.clusterChangeFactory,
+		nil, nil, noopClusterApplyUI{}, logger.NewNoopLogger()).Calculate()
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "out")
+	opts := &DeployOptions{DeployFlags: DeployFlags{OutputResourcesDir: dir}}
+
+	require.NoError(t, opts.writeAppliedResourcesToDir(graph))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var fileNames []string
+	for _, entry := range entries {
+		fileNames = append(fileNames, entry.Name())
+	}
+	require.Equal(t, []string{
+		"_ConfigMap_ns_added.yml",
+		"rbac.authorization.k8s.io_ClusterRole_cluster_system-updated.yml",
+	}, fileNames, "Expected only created and updated resources to be written")
+
+	contents, err := os.ReadFile(filepath.Join(dir, "_ConfigMap_ns_added.yml"))
+	require.NoError(t, err)
+
+	writtenRes := ctlres.MustNewResourceFromBytes(contents)
+	require.Equal(t, addedRes.Description(), writtenRes.Description())
+	require.Equal(t, map[string]string{"kapp.k14s.io/app": "123"}, writtenRes.Labels())
+}
+
+func TestDeployWriteAppliedResourcesToDirDisabled(t *testing.T) {
+	opts := &DeployOptions{}
+	require.NoError(t, opts.writeAppliedResourcesToDir(nil))
+}
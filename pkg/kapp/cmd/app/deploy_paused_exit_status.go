@@ -0,0 +1,30 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+)
+
+const (
+	// Distinct from diff and apply exit statuses so that
+	// pipelines can tell that deploy is waiting to be resumed
+	deployPausedExitStatus = 4
+)
+
+// DeployPausedExitStatus is returned when deploy stopped
+// before resources annotated with kapp.k14s.io/pause-before
+type DeployPausedExitStatus struct {
+	PausedErr ctlcap.PausedErr
+}
+
+var _ ExitStatus = DeployPausedExitStatus{}
+
+func (d DeployPausedExitStatus) Error() string {
+	return fmt.Sprintf("%s (continue with 'kapp deploy --resume') (exit status %d)", d.PausedErr, d.ExitStatus())
+}
+
+func (DeployPausedExitStatus) ExitStatus() int { return deployPausedExitStatus }
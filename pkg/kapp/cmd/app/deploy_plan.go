@@ -0,0 +1,188 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+)
+
+const (
+	deployPlanVersion = "v1"
+
+	deployPlanAppLabelValuePlaceholder = "<app-label-value>"
+)
+
+// DeployPlan captures changes calculated by deploy so that they can be
+// reviewed (e.g. by an external approval gate) before being applied.
+// Resources and diffs are masked based on diff mask rules.
+type DeployPlan struct {
+	Version   string             `json:"version"`
+	App       string             `json:"app"`
+	Namespace string             `json:"namespace"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Changes   []DeployPlanChange `json:"changes"`
+}
+
+type DeployPlanChange struct {
+	Op         string `json:"op"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+
+	Diff string `json:"diff"`
+	// DiffMD5 is calculated from unmasked diff and used to detect drift
+	DiffMD5 string `json:"diffMD5"`
+	// Resource is included for added and updated resources
+	Resource map[string]interface{} `json:"resource,omitempty"`
+}
+
+// NewDeployPlan calculates plan from changes. App label value is excluded
+// from diff hashes since it is generated for apps that do not exist yet.
+func NewDeployPlan(appName, appNamespace, appLabelValue string, changes []*ctlcap.ClusterChange,
+	diffMaskRules []ctlconf.DiffMaskRule) (DeployPlan, error) {
+
+	plan := DeployPlan{
+		Version:   deployPlanVersion,
+		App:       appName,
+		Namespace: appNamespace,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	for _, change := range changes {
+		op := change.ApplyOp()
+		if op == ctlcap.ClusterChangeApplyOpNoop {
+			continue
+		}
+
+		res := change.Resource()
+
+		maskedDiff, err := change.ConfigurableTextDiff().Masked(diffMaskRules)
+		if err != nil {
+			return DeployPlan{}, err
+		}
+
+		planChange := DeployPlanChange{
+			Op:         string(op),
+			APIVersion: res.APIVersion(),
+			Kind:       res.Kind(),
+			Namespace:  res.Namespace(),
+			Name:       res.Name(),
+			Diff:       maskedDiff.MinimalString(),
+			DiffMD5:    planDiffMD5(change.ConfigurableTextDiff().Full().MinimalString(), appLabelValue),
+		}
+
+		if op == ctlcap.ClusterChangeApplyOpAdd || op == ctlcap.ClusterChangeApplyOpUpdate {
+			maskedRes, err := ctldiff.NewMaskedResource(res, diffMaskRules).Resource()
+			if err != nil {
+				return DeployPlan{}, err
+			}
+			planChange.Resource = maskedRes.DeepCopyRaw()
+		}
+
+		plan.Changes = append(plan.Changes, planChange)
+	}
+
+	sort.Slice(plan.Changes, func(i, j int) bool { return plan.Changes[i].key() < plan.Changes[j].key() })
+
+	return plan, nil
+}
+
+func NewDeployPlanFromFile(path string) (DeployPlan, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return DeployPlan{}, fmt.Errorf("Reading deploy plan: %w", err)
+	}
+
+	var plan DeployPlan
+
+	err = json.Unmarshal(bs, &plan)
+	if err != nil {
+		return DeployPlan{}, fmt.Errorf("Unmarshaling deploy plan: %w", err)
+	}
+
+	if plan.Version != deployPlanVersion {
+		return DeployPlan{}, fmt.Errorf("Expected deploy plan version to be '%s' but was '%s'", deployPlanVersion, plan.Version)
+	}
+
+	return plan, nil
+}
+
+func (p DeployPlan) WriteToFile(path string) error {
+	bs, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, bs, 0600)
+	if err != nil {
+		return fmt.Errorf("Writing deploy plan: %w", err)
+	}
+
+	return nil
+}
+
+// Drift returns descriptions of differences between
+// approved plan and currently calculated plan
+func (p DeployPlan) Drift(current DeployPlan) []string {
+	var drift []string
+
+	if p.App != current.App || p.Namespace != current.Namespace {
+		return []string{fmt.Sprintf("Plan is for app '%s' in namespace '%s' (but deploying app '%s' in namespace '%s')",
+			p.App, p.Namespace, current.App, current.Namespace)}
+	}
+
+	currentChanges := map[string]DeployPlanChange{}
+	for _, change := range current.Changes {
+		currentChanges[change.key()] = change
+	}
+
+	for _, change := range p.Changes {
+		currentChange, found := currentChanges[change.key()]
+		delete(currentChanges, change.key())
+
+		switch {
+		case !found:
+			drift = append(drift, fmt.Sprintf("%s: planned %s is no longer needed", change.key(), change.Op))
+		case currentChange.Op != change.Op:
+			drift = append(drift, fmt.Sprintf("%s: planned %s, but now requires %s", change.key(), change.Op, currentChange.Op))
+		case currentChange.DiffMD5 != change.DiffMD5:
+			drift = append(drift, fmt.Sprintf("%s: planned %s has different diff", change.key(), change.Op))
+		}
+	}
+
+	for _, change := range current.Changes {
+		if _, found := currentChanges[change.key()]; found {
+			drift = append(drift, fmt.Sprintf("%s: %s was not planned", change.key(), change.Op))
+		}
+	}
+
+	return drift
+}
+
+func planDiffMD5(diff, appLabelValue string) string {
+	if len(appLabelValue) > 0 {
+		diff = strings.ReplaceAll(diff, appLabelValue, deployPlanAppLabelValuePlaceholder)
+	}
+	return fmt.Sprintf("%x", md5.Sum([]byte(diff)))
+}
+
+// key matches resource description (e.g. deployment/app (apps/v1) namespace: default)
+func (c DeployPlanChange) key() string {
+	key := fmt.Sprintf("%s/%s (%s)", strings.ToLower(c.Kind), c.Name, c.APIVersion)
+	if len(c.Namespace) > 0 {
+		return key + " namespace: " + c.Namespace
+	}
+	return key + " cluster"
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"testing"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	cmdapp "carvel.dev/kapp/pkg/kapp/cmd/app"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployPlanDrift(t *testing.T) {
+	approved := cmdapp.DeployPlan{App: "app", Namespace: "default", Changes: []cmdapp.DeployPlanChange{
+		{Op: "create", APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "added", DiffMD5: "a"},
+		{Op: "update", APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "updated", DiffMD5: "b"},
+		{Op: "delete", APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "deleted", DiffMD5: "c"},
+		{Op: "update", APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "same", DiffMD5: "d"},
+	}}
+
+	t.Run("no drift", func(t *testing.T) {
+		require.Empty(t, approved.Drift(approved))
+	})
+
+	t.Run("different app", func(t *testing.T) {
+		current := approved
+		current.App = "other-app"
+
+		require.Equal(t, []string{"Plan is for app 'app' in namespace 'default' " +
+			"(but deploying app 'other-app' in namespace 'default')"}, approved.Drift(current))
+	})
+
+	t.Run("changed ops and diffs", func(t *testing.T) {
+		current := cmdapp.DeployPlan{App: "app", Namespace: "default", Changes: []cmdapp.DeployPlanChange{
+			{Op: "update", APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "added", DiffMD5: "a"},
+			{Op: "update", APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "updated", DiffMD5: "other"},
+			{Op: "update", APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "same", DiffMD5: "d"},
+			{Op: "create", APIVersion: "v1", Kind: "Secret", Name: "unplanned", DiffMD5: "e"},
+		}}
+
+		require.Equal(t, []string{
+			"configmap/added (v1) namespace: default: planned create, but now requires update",
+			"configmap/updated (v1) namespace: default: planned update has different diff",
+			"configmap/deleted (v1) namespace: default: planned delete is no longer needed",
+			"secret/unplanned (v1) cluster: create was not planned",
+		}, approved.Drift(current))
+	})
+}
+
+func TestDeployPlanIgnoresAppLabelValue(t *testing.T) {
+	plan := func(labelValue string) cmdapp.DeployPlan {
+		res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+  labels:
+    kapp.k14s.io/app: "` + labelValue + `"
+spec:
+  selector:
+    matchLabels:
+      kapp.k14s.io/app: "` + labelValue + `"
+`))
+
+		plan, err := cmdapp.NewDeployPlan("app", "default", labelValue,
+			[]*ctlcap.ClusterChange{cmdapp.NewTestChangeFactory().NewClusterChange(t, nil, res)}, nil)
+		require.NoError(t, err)
+		require.Len(t, plan.Changes, 1)
+
+		return plan
+	}
+
+	approved := plan("1700000000000000001")
+	current := plan("1700000000000000002")
+
+	require.Empty(t, approved.Drift(current))
+	require.Equal(t, approved.Changes[0].DiffMD5, current.Changes[0].DiffMD5)
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployRetryFailedResources(t *testing.T) {
+	newRes1 := newRetryFailedConfigMap("cm-1")
+	newRes2 := newRetryFailedConfigMap("cm-2")
+	existingRes2 := newRetryFailedConfigMap("cm-2")
+	existingRes3 := newRetryFailedConfigMap("cm-3")
+
+	newResources := []ctlres.Resource{newRes1, newRes2}
+	existingResources := []ctlres.Resource{existingRes2, existingRes3}
+
+	falseBool := false
+	trueBool := true
+
+	t.Run("retries only failed resources", func(t *testing.T) {
+		out := bytes.NewBufferString("")
+		opts := &DeployOptions{ui: ui.NewWriterUI(out, out, ui.NewNoopLogger())}
+
+		lastChange := ctlapp.ChangeMeta{Successful: &falseBool, FailedResources: []string{
+			ctlres.NewUniqueResourceKey(newRes2).String(),
+			ctlres.NewUniqueResourceKey(existingRes3).String(),
+		}}
+
+		retriedNew, retriedExisting := opts.retryFailedResources(lastChange, newResources, existingResources)
+		require.Equal(t, []ctlres.Resource{newRes2}, retriedNew)
+		require.Equal(t, []ctlres.Resource{existingRes2, existingRes3}, retriedExisting)
+		require.Contains(t, out.String(), "Retrying 2 resource(s) that did not succeed during last app change")
+	})
+
+	for _, tc := range []struct {
+		desc       string
+		lastChange ctlapp.ChangeMeta
+	}{
+		{"no last change", ctlapp.ChangeMeta{}},
+		{"successful last change", ctlapp.ChangeMeta{Successful: &trueBool,
+			FailedResources: []string{ctlres.NewUniqueResourceKey(newRes1).String()}}},
+		{"failed last change without recorded failures", ctlapp.ChangeMeta{Successful: &falseBool}},
+	} {
+		t.Run("deploys all resources with "+tc.desc, func(t *testing.T) {
+			out := bytes.NewBufferString("")
+			opts := &DeployOptions{ui: ui.NewWriterUI(out, out, ui.NewNoopLogger())}
+
+			retriedNew, retriedExisting := opts.retryFailedResources(tc.lastChange, newResources, existingResources)
+			require.Equal(t, newResources, retriedNew)
+			require.Equal(t, existingResources, retriedExisting)
+			require.Contains(t, out.String(), "No failed resources recorded for last app change, deploying all resources")
+		})
+	}
+}
+
+func newRetryFailedConfigMap(name string) ctlres.Resource {
+	return ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + name + `
+  namespace: default
+`))
+}
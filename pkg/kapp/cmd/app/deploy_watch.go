@@ -0,0 +1,159 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+const (
+	deployWatchCheckInterval = 1 * time.Second
+)
+
+// watch keeps deploying whenever local source files change
+// (or periodically if --watch-interval is set) until interrupted.
+// Files are polled for changes (modification time and size).
+func (o *DeployOptions) watch() error {
+	err := o.validateWatch()
+	if err != nil {
+		return err
+	}
+
+	for reconcileNum := 1; ; reconcileNum++ {
+		lastFingerprint, err := o.watchedFilesFingerprint()
+		if err != nil {
+			return err
+		}
+
+		startTime := time.Now()
+
+		err = o.runWithRetries()
+		if err != nil {
+			o.ui.ErrorLinef("Reconcile %d failed (took %s): %s", reconcileNum, o.watchDuration(startTime), err)
+		} else {
+			o.ui.PrintLinef("Reconcile %d succeeded (took %s)", reconcileNum, o.watchDuration(startTime))
+		}
+
+		reason, err := o.waitForWatchTrigger(lastFingerprint, time.Now())
+		if err != nil {
+			return err
+		}
+
+		o.ui.PrintLinef("Reconciling again since %s", reason)
+	}
+}
+
+func (o *DeployOptions) validateWatch() error {
+	if o.DiffFlags.ExitStatus || o.ApplyFlags.ExitStatus {
+		return fmt.Errorf("Expected --watch to not be used with --diff-exit-status or --apply-exit-status")
+	}
+	err := o.DeployFlags.ValidateVerifyOnly()
+	if err != nil {
+		return err
+	}
+	if o.DeployFlags.WatchInterval < 0 || o.DeployFlags.WatchDebounce < 0 {
+		return fmt.Errorf("Expected --watch-interval and --watch-debounce to be non-negative")
+	}
+	for _, file := range o.FileFlags.Files {
+		if file == "-" {
+			return fmt.Errorf("Expected --watch to not be used with resources provided via stdin")
+		}
+	}
+	return nil
+}
+
+// waitForWatchTrigger returns once files changed and stayed
+// unchanged for debounce duration, or once interval elapsed
+func (o *DeployOptions) waitForWatchTrigger(lastFingerprint string, lastReconcileTime time.Time) (string, error) {
+	var changedFingerprint string
+	var changedTime time.Time
+
+	for {
+		time.Sleep(deployWatchCheckInterval)
+
+		fingerprint, err := o.watchedFilesFingerprint()
+		if err != nil {
+			return "", err
+		}
+
+		if fingerprint != lastFingerprint {
+			// Restart debounce period on every subsequent change
+			if fingerprint != changedFingerprint {
+				changedFingerprint = fingerprint
+				changedTime = time.Now()
+			}
+			if time.Now().Sub(changedTime) >= o.DeployFlags.WatchDebounce {
+				return "files changed", nil
+			}
+			continue
+		}
+
+		changedFingerprint = ""
+
+		if o.DeployFlags.WatchInterval > 0 && time.Now().Sub(lastReconcileTime) >= o.DeployFlags.WatchInterval {
+			return fmt.Sprintf("%s elapsed", o.DeployFlags.WatchInterval), nil
+		}
+	}
+}
+
+// watchedFilesFingerprint describes local files (including files within directories
+// such as kustomizations) that resources and overlays are read from; remote files are not watched
+func (o *DeployOptions) watchedFilesFingerprint() (string, error) {
+	paths := append([]string{}, o.FileFlags.Files...)
+	paths = append(paths, o.FileFlags.FileLists...)
+	paths = append(paths, o.DeployFlags.OverlayFiles...)
+
+	listedPaths, err := o.FileFlags.AllFiles(o.FileSystem)
+	if err != nil {
+		return "", err
+	}
+	paths = append(paths, listedPaths...)
+
+	var entries []string
+
+	walkFunc := func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// File may be in the middle of being replaced
+			entries = append(entries, path+": "+err.Error())
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			entries = append(entries, path+": "+err.Error())
+			return nil
+		}
+		entries = append(entries, fmt.Sprintf("%s: %d %d", path, info.Size(), info.ModTime().UnixNano()))
+		return nil
+	}
+
+	for _, path := range paths {
+		path, local := ctlres.LocalPath(path)
+		if !local {
+			continue
+		}
+		if o.FileSystem != nil {
+			_ = fs.WalkDir(o.FileSystem, path, walkFunc)
+		} else {
+			_ = filepath.WalkDir(path, walkFunc)
+		}
+	}
+
+	sort.Strings(entries)
+
+	return strings.Join(entries, "\n"), nil
+}
+
+func (*DeployOptions) watchDuration(startTime time.Time) time.Duration {
+	return time.Now().Sub(startTime).Round(time.Millisecond)
+}
@@ -0,0 +1,37 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployWatchedFilesFingerprintIncludesKustomizations(t *testing.T) {
+	dir := t.TempDir()
+	kustomizationPath := filepath.Join(dir, "kustomization.yaml")
+	resourcePath := filepath.Join(dir, "resources", "config.yml")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(resourcePath), 0700))
+	require.NoError(t, os.WriteFile(kustomizationPath, []byte("resources: [resources/config.yml]\n"), 0600))
+	require.NoError(t, os.WriteFile(resourcePath, []byte("kind: ConfigMap\n"), 0600))
+
+	opts := &DeployOptions{FileFlags: cmdtools.FileFlags{Files: []string{"kustomize://" + dir, "https://example.com/config.yml"}}}
+
+	fingerprint, err := opts.watchedFilesFingerprint()
+	require.NoError(t, err)
+	require.Contains(t, fingerprint, kustomizationPath+": ")
+	require.Contains(t, fingerprint, resourcePath+": ")
+	require.NotContains(t, fingerprint, "example.com")
+
+	require.NoError(t, os.WriteFile(resourcePath, []byte("kind: ConfigMap\nmetadata: {}\n"), 0600))
+
+	changedFingerprint, err := opts.watchedFilesFingerprint()
+	require.NoError(t, err)
+	require.NotEqual(t, fingerprint, changedFingerprint)
+}
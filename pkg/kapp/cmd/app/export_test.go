@@ -0,0 +1,9 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+// Test helpers shared with external tests (package app_test)
+var (
+	NewTestChangeFactory = newTestChangeFactory
+)
@@ -16,6 +16,8 @@ type FactorySupportObjs struct {
 	ResourceTypes       *ctlres.ResourceTypesImpl
 	IdentifiedResources ctlres.IdentifiedResources
 	Apps                ctlapp.Apps
+	// ResourceWarnings collects API server warnings once enabled
+	ResourceWarnings *ctlres.ResourceWarnings
 }
 
 func FactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.NamespaceFlags, appNamespace string,
@@ -30,7 +32,12 @@ func FactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.NamespaceFl
 		return FactorySupportObjs{}, err
 	}
 
-	dynamicClient, err := depsFactory.DynamicClient(cmdcore.DynamicClientOpts{Warnings: true})
+	resWarnings := ctlres.NewResourceWarnings()
+
+	dynamicClient, err := depsFactory.DynamicClient(cmdcore.DynamicClientOpts{
+		Warnings:      true,
+		WrapTransport: resWarnings.WrapTransport,
+	})
 	if err != nil {
 		return FactorySupportObjs{}, err
 	}
@@ -43,7 +50,7 @@ func FactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.NamespaceFl
 	resTypes := ctlres.NewResourceTypesImpl(coreClient, ctlres.ResourceTypesImplOpts{
 		IgnoreFailingAPIServices:   resTypesFlags.IgnoreFailingAPIServices,
 		CanIgnoreFailingAPIService: resTypesFlags.CanIgnoreFailingAPIService,
-	})
+	}, logger)
 
 	resourcesImplOpts := ctlres.ResourcesImplOpts{
 		FallbackAllowedNamespaces:        []string{nsFlags.Name},
@@ -61,6 +68,7 @@ func FactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.NamespaceFl
 		ResourceTypes:       resTypes,
 		IdentifiedResources: identifiedResources,
 		Apps:                ctlapp.NewApps(appNamespace, coreClient, identifiedResources, logger),
+		ResourceWarnings:    resWarnings,
 	}
 
 	return result, nil
@@ -81,3 +89,16 @@ func Factory(depsFactory cmdcore.DepsFactory, appFlags Flags,
 
 	return app, supportingObjs, nil
 }
+
+// appIdentifiedResources accounts for apps that were deployed with identity
+// annotation disabled so that their resources are not treated as transient
+func appIdentifiedResources(app ctlapp.App, identifiedResources ctlres.IdentifiedResources) (ctlres.IdentifiedResources, error) {
+	meta, err := app.Meta()
+	if err != nil {
+		return ctlres.IdentifiedResources{}, err
+	}
+	if meta.IdentityAnnotationDisabled {
+		return identifiedResources.WithOptionalIdentityAnnotation(), nil
+	}
+	return identifiedResources, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+// testChangeFactory builds changes and their cluster changes
+// without accessing a cluster (e.g. for showing or recording them)
+type testChangeFactory struct {
+	changeFactory        ctldiff.ChangeFactory
+	clusterChangeFactory ctlcap.ClusterChangeFactory
+}
+
+func newTestChangeFactory() testChangeFactory {
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{})
+
+	return testChangeFactory{
+		changeFactory: changeFactory,
+		clusterChangeFactory: ctlcap.NewClusterChangeFactory(ctlcap.ClusterChangeOpts{}, ctlres.IdentifiedResources{},
+			changeFactory, ctldiff.NewChangeSetFactory(ctldiff.ChangeSetOpts{}, changeFactory),
+			ctlcap.NewConvergedResourceFactory(nil, ctlcap.ConvergedResourceFactoryOpts{}), nil, nil, nil, nil),
+	}
+}
+
+// NewChange returns change from existing to new resource (either may be nil)
+func (f testChangeFactory) NewChange(t *testing.T, existingRes, newRes ctlres.Resource) ctldiff.Change {
+	change, err := f.changeFactory.NewExactChange(existingRes, newRes)
+	require.NoError(t, err)
+	return change
+}
+
+// NewClusterChange returns cluster change from existing to new resource (either may be nil)
+func (f testChangeFactory) NewClusterChange(t *testing.T, existingRes, newRes ctlres.Resource) *ctlcap.ClusterChange {
+	return f.clusterChangeFactory.NewClusterChange(f.NewChange(t, existingRes, newRes))
+}
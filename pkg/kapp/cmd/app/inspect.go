@@ -8,12 +8,14 @@ import (
 
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
 	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	"carvel.dev/kapp/pkg/kapp/logger"
 	"carvel.dev/kapp/pkg/kapp/resources"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 type InspectOptions struct {
@@ -29,6 +31,14 @@ type InspectOptions struct {
 	Status        bool
 	Tree          bool
 	ManagedFields bool
+	ShowSizes     bool
+
+	DiffAgainstCluster bool
+	DiffChanges        bool
+	DiffViewOpts       ctldiff.TextDiffViewOpts
+
+	Resource  string
+	ShowDrift bool
 }
 
 func NewInspectOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *InspectOptions {
@@ -52,10 +62,26 @@ func NewInspectCmd(o *InspectOptions, flagsFactory cmdcore.FlagsFactory) *cobra.
 	cmd.Flags().BoolVar(&o.Status, "status", false, "Output status content")
 	cmd.Flags().BoolVarP(&o.Tree, "tree", "t", false, "Tree view")
 	cmd.Flags().BoolVar(&o.ManagedFields, "managed-fields", false, "Keep the metadata.managedFields when printing objects")
+	cmd.Flags().BoolVar(&o.ShowSizes, "show-sizes", false,
+		"Show resource sizes and sizes of their annotations, largest first (useful for diagnosing annotation size limit failures)")
+	cmd.Flags().BoolVar(&o.DiffAgainstCluster, "diff-against-cluster", false,
+		"Show resources that were modified on the cluster since they were last applied (e.g. manually edited)")
+	cmd.Flags().BoolVarP(&o.DiffChanges, "diff-changes", "c", false, "Show changes of drifted resources")
+	cmd.Flags().IntVar(&o.DiffViewOpts.Context, "diff-context", 2, "Show number of lines around changed lines")
+	cmd.Flags().BoolVar(&o.DiffViewOpts.LineNumbers, "diff-line-numbers", true, "Show line numbers")
+	cmd.Flags().BoolVar(&o.DiffViewOpts.Mask, "diff-mask", true, "Apply masking rules")
+	cmd.Flags().StringVar(&o.Resource, "resource", "",
+		"Inspect only single app resource specified as '<apiVersion>/<kind>/<namespace>/<name>' (e.g. v1/ConfigMap/default/cfg)")
+	cmd.Flags().BoolVar(&o.ShowDrift, "show-drift", false,
+		"Show diff between last applied copy of resource specified via --resource and its current state on the cluster")
 	return cmd
 }
 
 func (o *InspectOptions) Run() error {
+	if o.ShowDrift && len(o.Resource) == 0 {
+		return fmt.Errorf("Expected --resource to be specified when using --show-drift")
+	}
+
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
@@ -70,11 +96,28 @@ func (o *InspectOptions) Run() error {
 
 	failingAPIServicesPolicy.MarkRequiredGVs(usedGVs)
 
+	supportObjs.IdentifiedResources, err = appIdentifiedResources(app, supportObjs.IdentifiedResources)
+	if err != nil {
+		return err
+	}
+
 	labelSelector, err := app.LabelSelector()
 	if err != nil {
 		return err
 	}
 
+	if len(o.Resource) > 0 {
+		res, err := o.appResource(supportObjs.IdentifiedResources, labelSelector, app.Name())
+		if err != nil {
+			return err
+		}
+		if o.ShowDrift {
+			return o.printResourceDrift(res)
+		}
+		cmdtools.InspectView{Source: fmt.Sprintf("app '%s'", app.Name()), Resources: []ctlres.Resource{res}, Sort: true}.Print(o.ui)
+		return nil
+	}
+
 	meta, err := app.Meta()
 	if err != nil {
 		return err
@@ -95,6 +138,9 @@ func (o *InspectOptions) Run() error {
 	source := fmt.Sprintf("app '%s'", app.Name())
 
 	switch {
+	case o.DiffAgainstCluster:
+		return o.printDrift(source, resources)
+
 	case o.Raw:
 		for _, res := range resources {
 			historylessRes, err := ctldiff.NewResourceWithoutHistory(res, nil).Resource()
@@ -117,6 +163,9 @@ func (o *InspectOptions) Run() error {
 	case o.Status:
 		InspectStatusView{Source: source, Resources: resources}.Print(o.ui)
 
+	case o.ShowSizes:
+		return InspectSizesView{Source: source, Resources: resources}.Print(o.ui)
+
 	default:
 		if o.Tree {
 			cmdtools.InspectTreeView{Source: source, Resources: resources, Sort: true}.Print(o.ui)
@@ -127,3 +176,89 @@ func (o *InspectOptions) Run() error {
 
 	return nil
 }
+
+func (o *InspectOptions) printDrift(source string, resources []ctlres.Resource) error {
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	if err != nil {
+		return err
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), conf.DiffAgainstLastAppliedFieldExclusionMods(),
+		conf.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{})
+
+	var driftedResources []ctlres.Resource
+
+	for _, res := range resources {
+		// Existing is the resource currently on the cluster, new is the last applied resource
+		change, drifted := changeFactory.NewResourceWithHistory(res).DriftedChange()
+		if !drifted {
+			continue
+		}
+
+		driftedResources = append(driftedResources, res)
+
+		if o.DiffChanges {
+			textDiffView := ctldiff.NewTextDiffView(change.ConfigurableTextDiff(), conf.DiffMaskRules(), o.DiffViewOpts)
+			o.ui.BeginLinef("@@ drift %s @@\n", res.Description())
+			o.ui.PrintBlock([]byte(textDiffView.String()))
+		}
+	}
+
+	cmdtools.InspectView{Source: source + " (drifted resources)", Resources: driftedResources, Sort: true}.Print(o.ui)
+
+	return nil
+}
+
+// appResource fetches single resource directly instead of listing all app resources
+func (o *InspectOptions) appResource(identifiedResources ctlres.IdentifiedResources,
+	labelSelector labels.Selector, appName string) (ctlres.Resource, error) {
+
+	stubRes, err := ctlres.NewResourceFromRef(o.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	res, exists, err := identifiedResources.Exists(stubRes, ctlres.ExistsOpts{})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("Expected resource '%s' to exist", stubRes.Description())
+	}
+
+	if !labelSelector.Matches(labels.Set(res.Labels())) {
+		return nil, fmt.Errorf("Expected resource '%s' to belong to app '%s'", res.Description(), appName)
+	}
+
+	return res, nil
+}
+
+func (o *InspectOptions) printResourceDrift(res ctlres.Resource) error {
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	if err != nil {
+		return err
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), conf.DiffAgainstLastAppliedFieldExclusionMods(),
+		conf.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{})
+
+	resWithHistory := changeFactory.NewResourceWithHistory(res)
+
+	if !resWithHistory.HasLastApplied() {
+		o.ui.PrintLinef("Resource '%s' does not have recorded last applied copy", res.Description())
+		return nil
+	}
+
+	// Existing is the resource currently on the cluster, new is the last applied resource
+	change, drifted := resWithHistory.DriftedChange()
+	if !drifted {
+		o.ui.PrintLinef("Resource '%s' has not drifted since it was last applied", res.Description())
+		return nil
+	}
+
+	textDiffView := ctldiff.NewTextDiffView(change.ConfigurableTextDiff(), conf.DiffMaskRules(), o.DiffViewOpts)
+	o.ui.BeginLinef("@@ drift %s @@\n", res.Description())
+	o.ui.PrintBlock([]byte(textDiffView.String()))
+
+	return nil
+}
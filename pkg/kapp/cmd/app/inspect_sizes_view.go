@@ -0,0 +1,87 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+)
+
+const (
+	// Kubernetes limits total size of resource annotations (keys and values)
+	inspectSizesAnnotationsLimit = 256 * 1024
+	// Annotations above this size are highlighted as approaching the limit
+	inspectSizesAnnotationsWarnThreshold = inspectSizesAnnotationsLimit * 3 / 4
+)
+
+type InspectSizesView struct {
+	Source    string
+	Resources []ctlres.Resource
+}
+
+func (v InspectSizesView) Print(ui ui.UI) error {
+	versionHeader := uitable.NewHeader("Version")
+	versionHeader.Hidden = true
+
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Resource sizes in %s", v.Source),
+		Content: "resources",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Kind"),
+			versionHeader,
+			uitable.NewHeader("Size"),
+			uitable.NewHeader("Annotations size"),
+			uitable.NewHeader("Original annotation size"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 4, Asc: false},
+			{Column: 0, Asc: true},
+			{Column: 1, Asc: true},
+		},
+
+		Notes: []string{fmt.Sprintf("Sizes are in bytes (annotations are limited to %d bytes in total)", inspectSizesAnnotationsLimit)},
+	}
+
+	for _, resource := range v.Resources {
+		resBs, err := resource.AsCompactBytes()
+		if err != nil {
+			return err
+		}
+
+		var annsSize, origAnnSize int
+
+		for key, val := range resource.Annotations() {
+			annsSize += len(key) + len(val)
+			if key == ctlconf.ManagedAnnotationOriginal {
+				origAnnSize = len(key) + len(val)
+			}
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			cmdcore.NewValueNamespace(resource.Namespace()),
+			uitable.NewValueString(resource.Name()),
+			uitable.NewValueString(resource.Kind()),
+			uitable.NewValueString(resource.APIVersion()),
+			uitable.NewValueInt(len(resBs)),
+			uitable.ValueFmt{
+				V:     uitable.NewValueInt(annsSize),
+				Error: annsSize >= inspectSizesAnnotationsWarnThreshold,
+			},
+			uitable.NewValueInt(origAnnSize),
+		})
+	}
+
+	ui.PrintTable(table)
+
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	cmdapp "carvel.dev/kapp/pkg/kapp/cmd/app"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectSizesView(t *testing.T) {
+	smallRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: small
+  namespace: default
+`))
+
+	largeRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: large
+  namespace: default
+  annotations:
+    kapp.k14s.io/original: '` + strings.Repeat("a", 1000) + `'
+    other: val
+data:
+  key: ` + strings.Repeat("b", 2000) + `
+`))
+
+	out := bytes.NewBufferString("")
+
+	err := cmdapp.InspectSizesView{Source: "app 'test'", Resources: []ctlres.Resource{smallRes, largeRes}}.
+		Print(ui.NewWriterUI(out, out, ui.NewNoopLogger()))
+	require.NoError(t, err)
+
+	largeBs, err := largeRes.AsCompactBytes()
+	require.NoError(t, err)
+
+	lines := strings.Split(out.String(), "\n")
+
+	require.Equal(t, "Resource sizes in app 'test'", lines[0])
+	require.Equal(t, []string{"Namespace", "Name", "Kind", "Size", "Annotations", "size", "Original", "annotation", "size"},
+		strings.Fields(lines[2]))
+
+	// Largest resource is shown first
+	require.Equal(t, []string{"default", "large", "ConfigMap", strconv.Itoa(len(largeBs)),
+		strconv.Itoa(len("kapp.k14s.io/original") + 1000 + len("other") + len("val")), strconv.Itoa(len("kapp.k14s.io/original") + 1000)},
+		strings.Fields(lines[3]))
+	require.Equal(t, []string{"^", "small", "ConfigMap"}, strings.Fields(lines[4])[:3])
+	require.Equal(t, []string{"0", "0"}, strings.Fields(lines[4])[4:])
+
+	require.Contains(t, out.String(), "Sizes are in bytes (annotations are limited to 262144 bytes in total)")
+	require.Contains(t, out.String(), "2 resources")
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestInspectPrintResourceDrift(t *testing.T) {
+	appliedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cfg
+  namespace: default
+data:
+  key: val
+`))
+
+	clusterRes := appliedRes.DeepCopy()
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{})
+
+	appliedChange, err := changeFactory.NewResourceWithHistory(clusterRes).CalculateChange(appliedRes)
+	require.NoError(t, err)
+
+	recordedRes, _, err := changeFactory.NewResourceWithHistory(clusterRes).RecordLastAppliedResource(appliedChange)
+	require.NoError(t, err)
+
+	editedRes := recordedRes.DeepCopy()
+	editedRes.UnstructuredObject()["data"] = map[string]interface{}{"key": "manually-edited-val"}
+
+	printDrift := func(res ctlres.Resource) string {
+		out := &bytes.Buffer{}
+		opts := &InspectOptions{ui: ui.NewWriterUI(out, out, ui.NewNoopLogger())}
+		require.NoError(t, opts.printResourceDrift(res))
+		return out.String()
+	}
+
+	t.Run("drifted", func(t *testing.T) {
+		out := printDrift(editedRes)
+		require.Contains(t, out, "@@ drift configmap/cfg (v1) namespace: default @@")
+		require.Contains(t, out, "manually-edited-val")
+	})
+
+	t.Run("not drifted", func(t *testing.T) {
+		require.Equal(t, "Resource 'configmap/cfg (v1) namespace: default' has not drifted since it was last applied\n",
+			printDrift(recordedRes))
+	})
+
+	t.Run("without recorded history", func(t *testing.T) {
+		require.Equal(t, "Resource 'configmap/cfg (v1) namespace: default' does not have recorded last applied copy\n",
+			printDrift(clusterRes))
+	})
+}
+
+func TestInspectAppResource(t *testing.T) {
+	appRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cfg
+  namespace: default
+  labels:
+    kapp.k14s.io/app: "1234"
+`))
+
+	otherRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  namespace: default
+  labels:
+    kapp.k14s.io/app: "5678"
+`))
+
+	identifiedResources := ctlres.NewIdentifiedResources(nil, nil,
+		existingResources{rs: []ctlres.Resource{appRes, otherRes}}, nil, logger.NewNoopLogger())
+	labelSelector := labels.SelectorFromSet(labels.Set{"kapp.k14s.io/app": "1234"})
+
+	t.Run("returns app resource", func(t *testing.T) {
+		opts := &InspectOptions{Resource: "v1/ConfigMap/default/cfg"}
+		res, err := opts.appResource(identifiedResources, labelSelector, "my-app")
+		require.NoError(t, err)
+		require.Equal(t, appRes, res)
+	})
+
+	t.Run("errors when resource belongs to another app", func(t *testing.T) {
+		opts := &InspectOptions{Resource: "v1/ConfigMap/default/other"}
+		_, err := opts.appResource(identifiedResources, labelSelector, "my-app")
+		require.EqualError(t, err, "Expected resource 'configmap/other (v1) namespace: default' to belong to app 'my-app'")
+	})
+
+	t.Run("errors when resource does not exist", func(t *testing.T) {
+		opts := &InspectOptions{Resource: "v1/ConfigMap/default/missing"}
+		_, err := opts.appResource(identifiedResources, labelSelector, "my-app")
+		require.EqualError(t, err, "Expected resource 'configmap/missing (v1) namespace: default' to exist")
+	})
+
+	t.Run("errors on invalid reference", func(t *testing.T) {
+		opts := &InspectOptions{Resource: "ConfigMap/cfg"}
+		_, err := opts.appResource(identifiedResources, labelSelector, "my-app")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "'<apiVersion>/<kind>/<namespace>/<name>' format")
+	})
+}
+
+func TestInspectShowDriftRequiresResource(t *testing.T) {
+	opts := &InspectOptions{ShowDrift: true}
+	require.EqualError(t, opts.Run(), "Expected --resource to be specified when using --show-drift")
+}
+
+// existingResources finds resources by kind, namespace and name
+type existingResources struct {
+	ctlres.Resources
+
+	rs []ctlres.Resource
+}
+
+func (r existingResources) Exists(res ctlres.Resource, _ ctlres.ExistsOpts) (ctlres.Resource, bool, error) {
+	for _, existingRes := range r.rs {
+		if existingRes.Kind() == res.Kind() && existingRes.Namespace() == res.Namespace() && existingRes.Name() == res.Name() {
+			return existingRes, true, nil
+		}
+	}
+	return nil, false, nil
+}
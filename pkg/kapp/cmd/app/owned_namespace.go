@@ -0,0 +1,195 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	ownedNamespaceAnnKey = "kapp.k14s.io/owned-namespace" // valid values: ''
+
+	// Maximum number of unexpected resources listed in an error
+	ownedNamespaceMaxShownResources = 10
+)
+
+// withOwnedNamespace adds Namespace resource for the namespace
+// that resources are deployed into (--into-ns or app namespace),
+// so that it's managed together with the rest of app resources
+// (e.g. created before and deleted after namespaced resources)
+func (o *DeployOptions) withOwnedNamespace(resources []ctlres.Resource) ([]ctlres.Resource, error) {
+	nsName := o.DeployFlags.IntoNamespace
+	if len(nsName) == 0 {
+		nsName = o.AppFlags.NamespaceFlags.Name
+	}
+
+	appNsName := o.AppFlags.AppNamespace
+	if len(appNsName) == 0 {
+		appNsName = o.AppFlags.NamespaceFlags.Name
+	}
+
+	// App record has to outlive namespace when app is deleted
+	if nsName == appNsName {
+		return nil, fmt.Errorf("Expected namespace '%s' owned via --own-namespace to differ from namespace "+
+			"storing app record (hint: use --into-ns or --app-namespace)", nsName)
+	}
+
+	for _, res := range resources {
+		if res.Kind() == "Namespace" && res.APIGroup() == "" && res.Name() == nsName {
+			return resources, nil // already explicitly provided
+		}
+	}
+
+	nsRes := ctlres.NewResourceUnstructured(unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata": map[string]interface{}{
+				"name": nsName,
+				"annotations": map[string]interface{}{
+					ownedNamespaceAnnKey: "",
+				},
+			},
+		},
+	}, ctlres.ResourceType{})
+
+	nsRes.SetOrigin("--own-namespace")
+
+	return append(resources, nsRes), nil
+}
+
+// validateOwnedNamespaceDeletes makes sure that namespaces added via --own-namespace
+// are only deleted when they do not contain resources other than ones that
+// are deleted in the same change (deleting namespace deletes all of its contents).
+func validateOwnedNamespaceDeletes(clusterChanges []*ctlcap.ClusterChange,
+	identifiedResources ctlres.IdentifiedResources) error {
+
+	var nsNames []string
+	var deletedResources []ctlres.Resource
+
+	for _, change := range clusterChanges {
+		if change.ApplyOp() != ctlcap.ClusterChangeApplyOpDelete {
+			continue
+		}
+		res := change.Resource()
+		deletedResources = append(deletedResources, res)
+
+		if res.Kind() == "Namespace" && res.APIGroup() == "" {
+			if _, found := res.Annotations()[ownedNamespaceAnnKey]; found {
+				nsNames = append(nsNames, res.Name())
+			}
+		}
+	}
+
+	if len(nsNames) == 0 {
+		return nil
+	}
+
+	nsResources, err := identifiedResources.List(labels.Everything(), nil,
+		ctlres.IdentifiedResourcesListOpts{ResourceNamespaces: nsNames})
+	if err != nil {
+		return fmt.Errorf("Listing resources in owned namespaces: %w", err)
+	}
+
+	for _, nsName := range nsNames {
+		unexpectedDescs := unexpectedOwnedNamespaceResources(nsName, nsResources, deletedResources)
+
+		if len(unexpectedDescs) > 0 {
+			if len(unexpectedDescs) > ownedNamespaceMaxShownResources {
+				unexpectedDescs = append(unexpectedDescs[:ownedNamespaceMaxShownResources],
+					fmt.Sprintf("...%d more", len(unexpectedDescs)-ownedNamespaceMaxShownResources))
+			}
+			return fmt.Errorf("Expected owned namespace '%s' (see --own-namespace) to only contain app resources "+
+				"before deleting it, but found:\n- %s", nsName, strings.Join(unexpectedDescs, "\n- "))
+		}
+	}
+
+	return nil
+}
+
+// unexpectedOwnedNamespaceResources returns descriptions of resources in namespace
+// that are neither deleted, nor created by the cluster itself, nor owned
+// (possibly transitively) only by deleted resources (e.g. Pods of deleted Deployment)
+func unexpectedOwnedNamespaceResources(nsName string, nsResources, deletedResources []ctlres.Resource) []string {
+	deletedKeys := map[string]struct{}{}
+	deletedUIDs := map[string]struct{}{}
+
+	for _, res := range deletedResources {
+		deletedKeys[ctlres.NewUniqueResourceKey(res).String()] = struct{}{}
+		if len(res.UID()) > 0 {
+			deletedUIDs[res.UID()] = struct{}{}
+		}
+	}
+
+	var remaining []ctlres.Resource
+
+	for _, res := range nsResources {
+		if res.Namespace() != nsName {
+			continue
+		}
+		if _, found := deletedKeys[ctlres.NewUniqueResourceKey(res).String()]; found {
+			continue
+		}
+		remaining = append(remaining, res)
+	}
+
+	// Owned resources are garbage collected once all of their owners are deleted;
+	// repeat until no more owners are found since ownership may be nested
+	for {
+		var notDeleted []ctlres.Resource
+		for _, res := range remaining {
+			if isOwnedOnlyByDeleted(res, deletedUIDs) {
+				deletedUIDs[res.UID()] = struct{}{}
+			} else {
+				notDeleted = append(notDeleted, res)
+			}
+		}
+		if len(notDeleted) == len(remaining) {
+			break
+		}
+		remaining = notDeleted
+	}
+
+	var unexpectedDescs []string
+
+	for _, res := range remaining {
+		if !isImplicitNamespaceResource(res) {
+			unexpectedDescs = append(unexpectedDescs, res.Description())
+		}
+	}
+
+	return unexpectedDescs
+}
+
+func isOwnedOnlyByDeleted(res ctlres.Resource, deletedUIDs map[string]struct{}) bool {
+	ownerRefs := res.OwnerRefs()
+	if len(ownerRefs) == 0 {
+		return false
+	}
+	for _, ref := range ownerRefs {
+		if _, found := deletedUIDs[string(ref.UID)]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// isImplicitNamespaceResource returns true for resources that are
+// created by the cluster itself for each namespace
+func isImplicitNamespaceResource(res ctlres.Resource) bool {
+	switch {
+	case res.Kind() == "ServiceAccount" && res.APIGroup() == "" && res.Name() == "default":
+		return true
+	case res.Kind() == "ConfigMap" && res.APIGroup() == "" && res.Name() == "kube-root-ca.crt":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnexpectedOwnedNamespaceResources(t *testing.T) {
+	deploymentRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: owned
+  uid: deployment-uid
+`))
+
+	replicaSetRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: app-1
+  namespace: owned
+  uid: replicaset-uid
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: app
+    uid: deployment-uid
+`))
+
+	podRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app-1-abc
+  namespace: owned
+  uid: pod-uid
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: ReplicaSet
+    name: app-1
+    uid: replicaset-uid
+`))
+
+	foreignOwnedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: foreign-token
+  namespace: owned
+  uid: secret-uid
+  ownerReferences:
+  - apiVersion: v1
+    kind: ConfigMap
+    name: foreign
+    uid: foreign-uid
+`))
+
+	partiallyOwnedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: shared-token
+  namespace: owned
+  uid: shared-uid
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: app
+    uid: deployment-uid
+  - apiVersion: v1
+    kind: ConfigMap
+    name: foreign
+    uid: foreign-uid
+`))
+
+	defaultSARes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: default
+  namespace: owned
+`))
+
+	otherNsRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  namespace: other
+`))
+
+	nsResources := []ctlres.Resource{deploymentRes, replicaSetRes, podRes, defaultSARes, otherNsRes}
+
+	t.Run("resources owned by deleted resources are expected", func(t *testing.T) {
+		descs := unexpectedOwnedNamespaceResources("owned", nsResources, []ctlres.Resource{deploymentRes})
+		require.Empty(t, descs)
+	})
+
+	t.Run("resources owned by resources that are not deleted are unexpected", func(t *testing.T) {
+		descs := unexpectedOwnedNamespaceResources("owned",
+			append(nsResources, foreignOwnedRes, partiallyOwnedRes), []ctlres.Resource{deploymentRes})
+		require.Equal(t, []string{foreignOwnedRes.Description(), partiallyOwnedRes.Description()}, descs)
+	})
+
+	t.Run("resources owned by kept resources are unexpected", func(t *testing.T) {
+		descs := unexpectedOwnedNamespaceResources("owned", nsResources, nil)
+		require.Equal(t, []string{deploymentRes.Description(), replicaSetRes.Description(), podRes.Description()}, descs)
+	})
+}
@@ -17,7 +17,7 @@ type ResourceTypesFlags struct {
 
 func (s *ResourceTypesFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&s.IgnoreFailingAPIServices, "dangerous-ignore-failing-api-services",
-		false, "Allow to ignore failing APIServices")
+		false, "Allow to ignore failing APIServices (resources of affected API groups are not listed, hence may not be garbage collected)")
 
 	cmd.Flags().BoolVar(&s.ScopeToFallbackAllowedNamespaces, "dangerous-scope-to-fallback-allowed-namespaces",
 		false, "Scope resource searching to fallback allowed namespaces")
@@ -0,0 +1,120 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// coOwnedSharedResources returns cluster copies of shared resources that are
+// co-owned by app but are not among existing resources since they are labeled
+// by another co-owner. Such resources are either provided again (and need to be
+// merged with their cluster copy) or were previously recorded (and need to be released).
+func coOwnedSharedResources(refs []ctlapp.SharedResourceRef, newResources, existingResources []ctlres.Resource,
+	appLabelVal string, resourceFilter ctlres.ResourceFilter, identifiedResources ctlres.IdentifiedResources) ([]ctlres.Resource, error) {
+
+	seenKeys := map[string]struct{}{}
+	for _, res := range existingResources {
+		seenKeys[ctlres.NewUniqueResourceKey(res).String()] = struct{}{}
+	}
+
+	var result []ctlres.Resource
+
+	for _, res := range newResources {
+		if _, found := res.Annotations()[ctlres.SharedAnnKey]; !found {
+			continue
+		}
+		key := ctlres.NewUniqueResourceKey(res).String()
+		if _, found := seenKeys[key]; found {
+			continue
+		}
+		seenKeys[key] = struct{}{}
+
+		clusterRes, exists, err := identifiedResources.Exists(res, ctlres.ExistsOpts{})
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			result = append(result, clusterRes)
+		}
+	}
+
+	for _, ref := range refs {
+		res := sharedResourceFromRef(ref)
+		if len(resourceFilter.Apply([]ctlres.Resource{res})) == 0 {
+			continue
+		}
+
+		key := ctlres.NewUniqueResourceKey(res).String()
+		if _, found := seenKeys[key]; found {
+			continue
+		}
+		seenKeys[key] = struct{}{}
+
+		clusterRes, exists, err := identifiedResources.Exists(res, ctlres.ExistsOpts{})
+		if err != nil {
+			return nil, err
+		}
+		// Resource may have been released already or no longer be shared
+		if exists && ctldiff.IsSharedResourceAppliedBy(clusterRes, appLabelVal) {
+			result = append(result, clusterRes)
+		}
+	}
+
+	return result, nil
+}
+
+// sharedResourceRefs returns refs to shared resources that app applies or
+// still has fields applied to (in case releasing them does not succeed).
+// Previously recorded refs excluded by resource filter are kept as is.
+func sharedResourceRefs(prevRefs []ctlapp.SharedResourceRef, newResources, existingResources []ctlres.Resource,
+	appLabelVal string, resourceFilter ctlres.ResourceFilter) []ctlapp.SharedResourceRef {
+
+	var result []ctlapp.SharedResourceRef
+	seenKeys := map[string]struct{}{}
+
+	for _, ref := range prevRefs {
+		res := sharedResourceFromRef(ref)
+		if len(resourceFilter.Apply([]ctlres.Resource{res})) == 0 {
+			seenKeys[ctlres.NewUniqueResourceKey(res).String()] = struct{}{}
+			result = append(result, ref)
+		}
+	}
+
+	for _, res := range append(append([]ctlres.Resource{}, newResources...), existingResources...) {
+		if !ctldiff.IsSharedResourceAppliedBy(res, appLabelVal) {
+			continue
+		}
+		key := ctlres.NewUniqueResourceKey(res).String()
+		if _, found := seenKeys[key]; found {
+			continue
+		}
+		seenKeys[key] = struct{}{}
+
+		result = append(result, ctlapp.SharedResourceRef{
+			APIVersion: res.APIVersion(),
+			Kind:       res.Kind(),
+			Namespace:  res.Namespace(),
+			Name:       res.Name(),
+		})
+	}
+
+	return result
+}
+
+func sharedResourceFromRef(ref ctlapp.SharedResourceRef) ctlres.Resource {
+	return ctlres.NewResourceUnstructured(unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": ref.APIVersion,
+			"kind":       ref.Kind,
+			"metadata": map[string]interface{}{
+				"name":      ref.Name,
+				"namespace": ref.Namespace,
+			},
+		},
+	}, ctlres.ResourceType{})
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"time"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+)
+
+type WaitTimingsView struct {
+	Timings []ctlcap.WaitTiming
+}
+
+func (v WaitTimingsView) Print(ui ui.UI) {
+	table := uitable.Table{
+		Title:   "Wait timings",
+		Content: "changes",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Op"),
+			uitable.NewHeader("Started"),
+			uitable.NewHeader("Duration"),
+			uitable.NewHeader("Successful"),
+		},
+
+		Notes: []string{"Changes are sorted by duration (slowest first)"},
+	}
+
+	// Rows are already sorted by duration
+	for _, timing := range v.Timings {
+		res := timing.Change.Resource()
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			cmdcore.NewValueNamespace(res.Namespace()),
+			uitable.NewValueString(res.Name()),
+			uitable.NewValueString(res.Kind()),
+			uitable.NewValueString(timing.Op()),
+			uitable.NewValueTime(timing.StartTime),
+			uitable.NewValueString(timing.Duration.Round(time.Millisecond).String()),
+			uitable.NewValueBool(timing.Successful),
+		})
+	}
+
+	ui.PrintTable(table)
+}
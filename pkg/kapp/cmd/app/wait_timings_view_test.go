@@ -0,0 +1,78 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	cmdapp "carvel.dev/kapp/pkg/kapp/cmd/app"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitTimingsView(t *testing.T) {
+	newClusterChange := func(name string) *ctlcap.ClusterChange {
+		res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + name + `
+  namespace: default
+`))
+
+		return cmdapp.NewTestChangeFactory().NewClusterChange(t, nil, res)
+	}
+
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	out := bytes.NewBufferString("")
+
+	cmdapp.WaitTimingsView{Timings: []ctlcap.WaitTiming{
+		{Change: newClusterChange("slow"), StartTime: startTime, Duration: 12345 * time.Millisecond, Successful: false},
+		{Change: newClusterChange("fast"), StartTime: startTime, Duration: 1500 * time.Microsecond, Successful: true},
+	}}.Print(ui.NewWriterUI(out, out, ui.NewNoopLogger()))
+
+	lines := strings.Split(out.String(), "\n")
+
+	require.Equal(t, "Wait timings", lines[0])
+	require.Equal(t, []string{"Namespace", "Name", "Kind", "Op", "Started", "Duration", "Successful"}, strings.Fields(lines[2]))
+
+	// Rows keep provided order (slowest first)
+	require.Equal(t, []string{"default", "slow", "ConfigMap", "create"}, strings.Fields(lines[3])[:4])
+	require.Equal(t, []string{"12.345s", "false"}, strings.Fields(lines[3])[len(strings.Fields(lines[3]))-2:])
+	require.Equal(t, []string{"^", "fast", "ConfigMap", "create"}, strings.Fields(lines[4])[:4])
+	require.Equal(t, []string{"2ms", "true"}, strings.Fields(lines[4])[len(strings.Fields(lines[4]))-2:])
+
+	require.Contains(t, out.String(), "Changes are sorted by duration (slowest first)")
+	require.Contains(t, out.String(), "2 changes")
+}
+
+func TestApplyFlagsStartWaitTimings(t *testing.T) {
+	t.Run("does not record timings by default", func(t *testing.T) {
+		applyFlags := &cmdapp.ApplyFlags{}
+
+		out := bytes.NewBufferString("")
+		applyFlags.StartWaitTimings(ui.NewWriterUI(out, out, ui.NewNoopLogger()))()
+
+		require.Nil(t, applyFlags.WaitingChangesOpts.Timings)
+		require.Empty(t, out.String())
+	})
+
+	t.Run("records and prints timings when requested", func(t *testing.T) {
+		applyFlags := &cmdapp.ApplyFlags{WaitTimings: true}
+
+		out := bytes.NewBufferString("")
+		printFunc := applyFlags.StartWaitTimings(ui.NewWriterUI(out, out, ui.NewNoopLogger()))
+
+		require.NotNil(t, applyFlags.WaitingChangesOpts.Timings)
+
+		printFunc()
+		require.Contains(t, out.String(), "Wait timings")
+	})
+}
@@ -5,6 +5,7 @@ package appchange
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -24,6 +25,7 @@ type ListOptions struct {
 
 	AppFlags  cmdapp.Flags
 	TimeFlags TimeFlags
+	Last      int
 }
 
 func NewListOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *ListOptions {
@@ -39,6 +41,7 @@ func NewListCmd(o *ListOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	}
 	o.AppFlags.Set(cmd, flagsFactory)
 	o.TimeFlags.Set(cmd)
+	cmd.Flags().IntVar(&o.Last, "last", 0, "List only given number of most recent app changes (0 means all)")
 	return cmd
 }
 
@@ -73,11 +76,43 @@ func (o *ListOptions) Run() error {
 		}
 	}
 
+	err = o.TimeFlags.applySince(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if o.Last < 0 {
+		return fmt.Errorf("Expected --last to be a non-negative number")
+	}
+	if o.Last > 0 {
+		changes = o.lastChanges(changes, o.Last)
+	}
+
 	AppChangesTable{"App changes", changes, o.TimeFlags}.Print(o.ui)
 
 	return nil
 }
 
+func (o *ListOptions) lastChanges(changes []ctlapp.Change, num int) []ctlapp.Change {
+	var result []ctlapp.Change
+
+	for _, change := range changes {
+		if o.TimeFlags.Includes(change.Meta().StartedAt) {
+			result = append(result, change)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Meta().StartedAt.After(result[j].Meta().StartedAt)
+	})
+
+	if len(result) > num {
+		result = result[:num]
+	}
+
+	return result
+}
+
 func (o *ListOptions) parseTime(input string, formats []string) (time.Time, error) {
 	for _, format := range formats {
 		t, err := time.Parse(format, input)
@@ -118,8 +153,7 @@ func (t AppChangesTable) Print(ui ui.UI) {
 	}
 
 	for _, change := range t.Changes {
-		if (!t.TimeFlags.BeforeTime.IsZero() && !change.Meta().StartedAt.Before(t.TimeFlags.BeforeTime)) ||
-			!change.Meta().StartedAt.After(t.TimeFlags.AfterTime) {
+		if !t.TimeFlags.Includes(change.Meta().StartedAt) {
 			continue
 		}
 
@@ -0,0 +1,81 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package appchange
+
+import (
+	"testing"
+	"time"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChange struct {
+	ctlapp.Change
+	name      string
+	startedAt time.Time
+}
+
+func (c fakeChange) Name() string            { return c.name }
+func (c fakeChange) Meta() ctlapp.ChangeMeta { return ctlapp.ChangeMeta{StartedAt: c.startedAt} }
+
+func TestTimeFlagsSince(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	flags := TimeFlags{Since: 24 * time.Hour}
+	require.NoError(t, flags.applySince(now))
+	require.Equal(t, now.Add(-24*time.Hour), flags.AfterTime)
+
+	require.True(t, flags.Includes(now.Add(-time.Hour)))
+	require.False(t, flags.Includes(now.Add(-25*time.Hour)))
+
+	flags = TimeFlags{}
+	require.NoError(t, flags.applySince(now))
+	require.True(t, flags.AfterTime.IsZero(), "Expected after time to not be set without --since")
+
+	flags = TimeFlags{Since: time.Hour, After: "2024-01-10"}
+	require.EqualError(t, flags.applySince(now), "Expected only one of --since or --after to be specified")
+
+	flags = TimeFlags{Since: -time.Hour}
+	require.EqualError(t, flags.applySince(now), "Expected --since to be a positive duration")
+}
+
+func TestTimeFlagsIncludes(t *testing.T) {
+	afterTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	beforeTime := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	flags := TimeFlags{AfterTime: afterTime, BeforeTime: beforeTime}
+
+	require.True(t, flags.Includes(afterTime.Add(time.Hour)))
+	require.False(t, flags.Includes(afterTime), "Expected after time to be exclusive")
+	require.False(t, flags.Includes(beforeTime), "Expected before time to be exclusive")
+	require.True(t, TimeFlags{}.Includes(beforeTime), "Expected all changes to be included without time flags")
+}
+
+func TestListLastChanges(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	changes := []ctlapp.Change{
+		fakeChange{name: "change-2", startedAt: now.Add(-2 * time.Hour)},
+		fakeChange{name: "change-4", startedAt: now},
+		fakeChange{name: "change-1", startedAt: now.Add(-3 * time.Hour)},
+		fakeChange{name: "change-3", startedAt: now.Add(-time.Hour)},
+	}
+
+	changeNames := func(changes []ctlapp.Change) []string {
+		var names []string
+		for _, change := range changes {
+			names = append(names, change.Name())
+		}
+		return names
+	}
+
+	opts := &ListOptions{}
+	require.Equal(t, []string{"change-4", "change-3"}, changeNames(opts.lastChanges(changes, 2)))
+	require.Equal(t, []string{"change-4", "change-3", "change-2", "change-1"}, changeNames(opts.lastChanges(changes, 10)))
+
+	// Time filters are applied before picking most recent changes
+	opts = &ListOptions{TimeFlags: TimeFlags{BeforeTime: now.Add(-30 * time.Minute)}}
+	require.Equal(t, []string{"change-3", "change-2"}, changeNames(opts.lastChanges(changes, 2)))
+}
@@ -4,6 +4,7 @@
 package appchange
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -15,10 +16,33 @@ type TimeFlags struct {
 	BeforeTime time.Time
 	AfterTime  time.Time
 
-	Duration string
+	Since time.Duration
 }
 
 func (t *TimeFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&t.Before, "before", "", "List app changes before given time (formats: 2022-01-10T12:15:25Z, 2022-01-10)")
 	cmd.Flags().StringVar(&t.After, "after", "", "List app changes after given time (formats: 2022-01-10T12:15:25Z, 2022-01-10)")
+	cmd.Flags().DurationVar(&t.Since, "since", 0, "List app changes started within given duration (e.g. 24h)")
+}
+
+// applySince sets after time relative to now based on --since
+func (t *TimeFlags) applySince(now time.Time) error {
+	if t.Since == 0 {
+		return nil
+	}
+	if t.After != "" {
+		return fmt.Errorf("Expected only one of --since or --after to be specified")
+	}
+	if t.Since < 0 {
+		return fmt.Errorf("Expected --since to be a positive duration")
+	}
+	t.AfterTime = now.Add(-t.Since)
+	return nil
+}
+
+func (t TimeFlags) Includes(startedAt time.Time) bool {
+	if !t.BeforeTime.IsZero() && !startedAt.Before(t.BeforeTime) {
+		return false
+	}
+	return startedAt.After(t.AfterTime)
 }
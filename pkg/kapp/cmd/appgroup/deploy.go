@@ -5,7 +5,6 @@ package appgroup
 
 import (
 	"fmt"
-	"math"
 	"os"
 	"path/filepath"
 
@@ -66,6 +65,12 @@ func (o *DeployOptions) Run() error {
 	if len(o.AppGroupFlags.Name) == 0 {
 		return fmt.Errorf("Expected group name to be non-empty")
 	}
+	if o.AppFlags.DeployFlags.Watch {
+		return fmt.Errorf("Expected --watch to not be used when deploying app group")
+	}
+	if o.AppFlags.DeployFlags.VerifyOnly {
+		return fmt.Errorf("Expected --verify-only to not be used when deploying app group")
+	}
 
 	// TODO what if app is renamed? currently it
 	// will have conflicting resources with new-named app
@@ -74,13 +79,13 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
-	var exitCode float64
+	hasNoChanges := true
 	// TODO is there some order between apps?
 	for _, appGroupApp := range updatedApps {
 		err := o.deployApp(appGroupApp)
 		if err != nil {
 			if deployErr, ok := err.(cmdapp.DeployDiffExitStatus); ok {
-				exitCode = math.Max(exitCode, float64(deployErr.ExitStatus()))
+				hasNoChanges = hasNoChanges && deployErr.HasNoChanges
 			} else {
 				return err
 			}
@@ -115,8 +120,7 @@ func (o *DeployOptions) Run() error {
 	}
 
 	if o.AppFlags.DiffFlags.Run && o.AppFlags.DiffFlags.ExitStatus {
-		var hasNoChanges = exitCode == 2
-		return cmdapp.DeployDiffExitStatus{HasNoChanges: hasNoChanges}
+		return cmdapp.NewDeployDiffExitStatus(hasNoChanges, o.AppFlags.DiffFlags)
 	}
 
 	return nil
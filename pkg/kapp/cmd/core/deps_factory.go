@@ -6,6 +6,7 @@ package core
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sync"
 
 	"github.com/cppforlife/go-cli-ui/ui"
@@ -24,6 +25,8 @@ type DepsFactory interface {
 	CoreClient() (kubernetes.Interface, error)
 	RESTMapper() (meta.RESTMapper, error)
 	ConfigureWarnings(warnings bool)
+	ConfigureNamespaceGuard(guard NamespaceGuard)
+	NamespaceGuard() NamespaceGuard
 }
 
 type DepsFactoryImpl struct {
@@ -31,7 +34,8 @@ type DepsFactoryImpl struct {
 	ui              ui.UI
 	printTargetOnce *sync.Once
 
-	Warnings bool
+	Warnings       bool
+	namespaceGuard NamespaceGuard
 }
 
 var _ DepsFactory = &DepsFactoryImpl{}
@@ -45,6 +49,8 @@ func NewDepsFactoryImpl(configFactory ConfigFactory, ui ui.UI) *DepsFactoryImpl
 
 type DynamicClientOpts struct {
 	Warnings bool
+	// WrapTransport is optional
+	WrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 func (f *DepsFactoryImpl) DynamicClient(opts DynamicClientOpts) (dynamic.Interface, error) {
@@ -62,6 +68,10 @@ func (f *DepsFactoryImpl) DynamicClient(opts DynamicClientOpts) (dynamic.Interfa
 		cpConfig.WarningHandler = rest.NoWarnings{}
 	}
 
+	if opts.WrapTransport != nil {
+		cpConfig.Wrap(opts.WrapTransport)
+	}
+
 	clientset, err := dynamic.NewForConfig(cpConfig)
 	if err != nil {
 		return nil, fmt.Errorf("Building Dynamic clientset: %w", err)
@@ -111,6 +121,14 @@ func (f *DepsFactoryImpl) ConfigureWarnings(warnings bool) {
 	f.Warnings = warnings
 }
 
+func (f *DepsFactoryImpl) ConfigureNamespaceGuard(guard NamespaceGuard) {
+	f.namespaceGuard = guard
+}
+
+func (f *DepsFactoryImpl) NamespaceGuard() NamespaceGuard {
+	return f.namespaceGuard
+}
+
 func (f *DepsFactoryImpl) printTarget(config *rest.Config) {
 	f.printTargetOnce.Do(func() {
 		nodesDesc := f.summarizeNodes(config)
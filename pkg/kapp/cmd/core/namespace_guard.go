@@ -0,0 +1,66 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// NamespaceGuard prevents changes to resources outside of allowed
+// namespaces. It's enabled only when allowed namespaces are specified.
+type NamespaceGuard struct {
+	AllowedNamespaces  []string
+	AllowClusterScoped bool
+}
+
+func (g NamespaceGuard) Enabled() bool { return len(g.AllowedNamespaces) > 0 }
+
+// Validate returns error listing all resources that are not allowed to be changed
+func (g NamespaceGuard) Validate(resources []ctlres.Resource) error {
+	if !g.Enabled() {
+		return nil
+	}
+
+	var msgs []string
+
+	for _, res := range resources {
+		switch {
+		case len(res.Namespace()) == 0:
+			if !g.AllowClusterScoped {
+				msgs = append(msgs, fmt.Sprintf("- Cluster scoped resource '%s' is not allowed (hint: use --allow-cluster-scoped)", res.Description()))
+			}
+		case !g.isAllowedNamespace(res.Namespace()):
+			msgs = append(msgs, fmt.Sprintf("- Resource '%s' is outside of allowed namespaces", res.Description()))
+		}
+	}
+
+	if len(msgs) > 0 {
+		return fmt.Errorf("Expected changed resources to be within allowed namespaces (%s):\n%s",
+			strings.Join(g.AllowedNamespaces, ", "), strings.Join(msgs, "\n"))
+	}
+
+	return nil
+}
+
+// ValidateAppNamespace returns error if app record (and its change records)
+// stored in given namespace is not allowed to be changed
+func (g NamespaceGuard) ValidateAppNamespace(ns string) error {
+	if !g.Enabled() || g.isAllowedNamespace(ns) {
+		return nil
+	}
+	return fmt.Errorf("Expected app namespace '%s' to be within allowed namespaces (%s) "+
+		"since app state is stored there (hint: use --app-namespace)", ns, strings.Join(g.AllowedNamespaces, ", "))
+}
+
+func (g NamespaceGuard) isAllowedNamespace(ns string) bool {
+	for _, allowedNs := range g.AllowedNamespaces {
+		if ns == allowedNs {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core_test
+
+import (
+	"testing"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceGuardValidate(t *testing.T) {
+	rs := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte("{apiVersion: v1, kind: ConfigMap, metadata: {name: allowed, namespace: ns1}}")),
+		ctlres.MustNewResourceFromBytes([]byte("{apiVersion: v1, kind: ConfigMap, metadata: {name: outside, namespace: ns3}}")),
+		ctlres.MustNewResourceFromBytes([]byte("{apiVersion: v1, kind: Namespace, metadata: {name: ns3}}")),
+	}
+
+	require.NoError(t, cmdcore.NamespaceGuard{}.Validate(rs), "Expected disabled guard to allow everything")
+
+	err := cmdcore.NamespaceGuard{AllowedNamespaces: []string{"ns1", "ns2"}}.Validate(rs)
+	require.EqualError(t, err, `Expected changed resources to be within allowed namespaces (ns1, ns2):
+- Resource 'configmap/outside (v1) namespace: ns3' is outside of allowed namespaces
+- Cluster scoped resource 'namespace/ns3 (v1) cluster' is not allowed (hint: use --allow-cluster-scoped)`)
+
+	err = cmdcore.NamespaceGuard{AllowedNamespaces: []string{"ns1", "ns3"}, AllowClusterScoped: true}.Validate(rs)
+	require.NoError(t, err)
+}
+
+func TestNamespaceGuardValidateAppNamespace(t *testing.T) {
+	require.NoError(t, cmdcore.NamespaceGuard{}.ValidateAppNamespace("ns1"))
+	require.NoError(t, cmdcore.NamespaceGuard{AllowedNamespaces: []string{"ns1"}}.ValidateAppNamespace("ns1"))
+
+	err := cmdcore.NamespaceGuard{AllowedNamespaces: []string{"ns1", "ns2"}}.ValidateAppNamespace("apps")
+	require.EqualError(t, err, "Expected app namespace 'apps' to be within allowed namespaces (ns1, ns2) "+
+		"since app state is stored there (hint: use --app-namespace)")
+}
@@ -29,12 +29,13 @@ type KappOptions struct {
 	configFactory cmdcore.ConfigFactory
 	depsFactory   cmdcore.DepsFactory
 
-	UIFlags         UIFlags
-	LoggerFlags     LoggerFlags
-	KubeAPIFlags    cmdcore.KubeAPIFlags
-	KubeconfigFlags cmdcore.KubeconfigFlags
-	WarningFlags    WarningFlags
-	ProfilingFlags  ProfilingFlags
+	UIFlags             UIFlags
+	LoggerFlags         LoggerFlags
+	KubeAPIFlags        cmdcore.KubeAPIFlags
+	KubeconfigFlags     cmdcore.KubeconfigFlags
+	WarningFlags        WarningFlags
+	NamespaceGuardFlags NamespaceGuardFlags
+	ProfilingFlags      ProfilingFlags
 
 	PreflightChecks *preflight.Registry
 }
@@ -49,15 +50,15 @@ func NewKappOptions(ui *ui.ConfUI, configFactory cmdcore.ConfigFactory,
 func NewDefaultKappCmd(ui *ui.ConfUI) *cobra.Command {
 	configFactory := cmdcore.NewConfigFactoryImpl()
 	depsFactory := cmdcore.NewDepsFactoryImpl(configFactory, ui)
-	preflights := defaultKappPreflightRegistry(depsFactory)
+	preflights := defaultKappPreflightRegistry(depsFactory, ui)
 	options := NewKappOptions(ui, configFactory, depsFactory, preflights)
 	flagsFactory := cmdcore.NewFlagsFactory(configFactory, depsFactory)
 	return NewKappCmd(options, flagsFactory)
 }
 
-func defaultKappPreflightRegistry(depsFactory cmdcore.DepsFactory) *preflight.Registry {
+func defaultKappPreflightRegistry(depsFactory cmdcore.DepsFactory, ui ui.UI) *preflight.Registry {
 	registry := preflight.NewRegistry(map[string]preflight.Check{
-		"PermissionValidation": permissions.NewPreflight(depsFactory, false),
+		"PermissionValidation": permissions.NewPreflight(depsFactory, ui, false),
 		"CRDUpgradeSafety":     crdupgradesafety.NewPreflight(depsFactory, false),
 	})
 
@@ -100,6 +101,7 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	o.KubeAPIFlags.Set(cmd, flagsFactory)
 	o.KubeconfigFlags.Set(cmd, flagsFactory)
 	o.WarningFlags.Set(cmd, flagsFactory)
+	o.NamespaceGuardFlags.Set(cmd, flagsFactory)
 	o.ProfilingFlags.Set(cmd, flagsFactory)
 
 	o.configFactory.ConfigurePathResolver(o.KubeconfigFlags.Path.Value)
@@ -138,7 +140,14 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	appCmd := cmdtools.NewCmd()
 	appCmd.AddCommand(cmdtools.NewInspectCmd(cmdtools.NewInspectOptions(o.ui, o.depsFactory), flagsFactory))
 	appCmd.AddCommand(cmdtools.NewDiffCmd(cmdtools.NewDiffOptions(o.ui, o.depsFactory), flagsFactory))
+	appCmd.AddCommand(cmdtools.NewValidateConfigCmd(cmdtools.NewValidateConfigOptions(o.ui, o.depsFactory), flagsFactory))
+	appCmd.AddCommand(cmdtools.NewDumpConfigCmd(cmdtools.NewDumpConfigOptions(o.ui, o.depsFactory), flagsFactory))
+	appCmd.AddCommand(cmdtools.NewRequiredPermissionsCmd(cmdtools.NewRequiredPermissionsOptions(o.ui, o.depsFactory), flagsFactory))
 	appCmd.AddCommand(cmdtools.NewListLabelsCmd(cmdtools.NewListLabelsOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	appCmd.AddCommand(cmdtools.NewOrphansCmd(cmdtools.NewOrphansOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	appCmd.AddCommand(cmdtools.NewGCVersionedCmd(cmdtools.NewGCVersionedOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	appCmd.AddCommand(cmdapp.NewCompareAppsCmd(cmdapp.NewCompareAppsOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	appCmd.AddCommand(cmdtools.NewGCPreviewCmd(cmdtools.NewGCPreviewOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(appCmd)
 
 	finishDebugLog := func(cmd *cobra.Command) {
@@ -156,6 +165,7 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		o.LoggerFlags.Configure(o.logger)
 		o.KubeAPIFlags.Configure(o.configFactory)
 		o.WarningFlags.Configure(o.depsFactory)
+		o.NamespaceGuardFlags.Configure(o.depsFactory)
 		o.ProfilingFlags.initProfiling()
 		return nil
 	})
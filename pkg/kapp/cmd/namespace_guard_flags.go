@@ -0,0 +1,24 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type NamespaceGuardFlags struct {
+	cmdcore.NamespaceGuard
+}
+
+func (f *NamespaceGuardFlags) Set(cmd *cobra.Command, _ cmdcore.FlagsFactory) {
+	cmd.PersistentFlags().StringSliceVar(&f.AllowedNamespaces, "allowed-namespaces", nil,
+		"Refuse to create, update or delete resources (including app state) outside of these namespaces (e.g. a,b)")
+	cmd.PersistentFlags().BoolVar(&f.AllowClusterScoped, "allow-cluster-scoped", false,
+		"Allow changing cluster scoped resources when --allowed-namespaces is specified")
+}
+
+func (f *NamespaceGuardFlags) Configure(depsFactory cmdcore.DepsFactory) {
+	depsFactory.ConfigureNamespaceGuard(f.NamespaceGuard)
+}
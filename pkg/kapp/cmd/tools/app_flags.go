@@ -0,0 +1,103 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"fmt"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AppFlags identify a deployed app for tools that inspect
+// or clean up its resources (mirrors app commands' flags)
+type AppFlags struct {
+	NamespaceFlags cmdcore.NamespaceFlags
+	Name           string
+	AppNamespace   string
+}
+
+func (s *AppFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	s.NamespaceFlags.Set(cmd, flagsFactory)
+
+	cmd.Flags().StringVarP(&s.Name, "app", "a", s.Name, "Set app name (or label selector) (format: name, label:key=val, !key)")
+	cmd.Flags().StringVar(&s.AppNamespace, "app-namespace", s.AppNamespace, "Set app namespace (to store app state)")
+}
+
+type appSupportObjs struct {
+	CoreClient          kubernetes.Interface
+	ResourceTypes       *ctlres.ResourceTypesImpl
+	IdentifiedResources ctlres.IdentifiedResources
+	Apps                ctlapp.Apps
+}
+
+// findApp looks up existing app and builds clients to work with its resources
+func (s *AppFlags) findApp(depsFactory cmdcore.DepsFactory, logger logger.Logger) (ctlapp.App, appSupportObjs, error) {
+	appNamespace := s.AppNamespace
+	if appNamespace == "" {
+		appNamespace = s.NamespaceFlags.Name
+	}
+
+	coreClient, err := depsFactory.CoreClient()
+	if err != nil {
+		return nil, appSupportObjs{}, err
+	}
+
+	dynamicClient, err := depsFactory.DynamicClient(cmdcore.DynamicClientOpts{Warnings: true})
+	if err != nil {
+		return nil, appSupportObjs{}, err
+	}
+
+	mutedDynamicClient, err := depsFactory.DynamicClient(cmdcore.DynamicClientOpts{Warnings: false})
+	if err != nil {
+		return nil, appSupportObjs{}, err
+	}
+
+	resTypes := ctlres.NewResourceTypesImpl(coreClient, ctlres.ResourceTypesImplOpts{}, logger)
+	resourcesImplOpts := ctlres.ResourcesImplOpts{
+		FallbackAllowedNamespaces: []string{s.NamespaceFlags.Name},
+	}
+	resources := ctlres.NewResourcesImpl(
+		resTypes, coreClient, dynamicClient, mutedDynamicClient, resourcesImplOpts, logger)
+	identifiedResources := ctlres.NewIdentifiedResources(
+		coreClient, resTypes, resources, resourcesImplOpts.FallbackAllowedNamespaces, logger)
+
+	apps := ctlapp.NewApps(appNamespace, coreClient, identifiedResources, logger)
+
+	app, err := apps.Find(s.Name)
+	if err != nil {
+		return nil, appSupportObjs{}, err
+	}
+
+	exists, notExistsMsg, err := app.Exists()
+	if err != nil {
+		return nil, appSupportObjs{}, err
+	}
+	if !exists {
+		return nil, appSupportObjs{}, fmt.Errorf("%s", notExistsMsg)
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return nil, appSupportObjs{}, err
+	}
+
+	// Resources of apps deployed without identity annotation are not transient
+	if meta.IdentityAnnotationDisabled {
+		identifiedResources = identifiedResources.WithOptionalIdentityAnnotation()
+	}
+
+	supportObjs := appSupportObjs{
+		CoreClient:          coreClient,
+		ResourceTypes:       resTypes,
+		IdentifiedResources: identifiedResources,
+		Apps:                apps,
+	}
+
+	return app, supportObjs, nil
+}
@@ -43,33 +43,76 @@ func NewDiffCmd(o *DiffOptions, _ cmdcore.FlagsFactory) *cobra.Command {
 }
 
 func (o *DiffOptions) Run() error {
-	newResources, err := o.fileResources(o.FileFlags.Files)
+	_, err := o.DiffAndPrint()
+	return err
+}
+
+// DiffAndPrint shows changes between files and files2
+// (files2 are treated as existing resources)
+func (o *DiffOptions) DiffAndPrint() (bool, error) {
+	files, err := o.FileFlags.AllFiles(o.FileSystem)
+	if err != nil {
+		return false, err
+	}
+
+	newResources, err := o.fileResources(files)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	existingResources, err := o.fileResources(o.FileFlags2.Files)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{o.DiffFlags.AnchoredDiff})
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, o.DiffFlags.ChangeOpts())
 
 	changes, err := ctldiff.NewChangeSet(existingResources, newResources, o.DiffFlags.ChangeSetOpts, changeFactory).Calculate()
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	var changeViews []ctlcap.ChangeView
+	hasNoChanges := true
 
 	for _, change := range changes {
-		changeViews = append(changeViews, DiffChangeView{change})
+		changeViews = append(changeViews, NewDiffChangeView(change))
+		if change.Op() != ctldiff.ChangeOpKeep {
+			hasNoChanges = false
+		}
 	}
 
 	// TODO support adding custom config for mask rules?
 	ctlcap.NewChangeSetView(changeViews, nil, o.DiffFlags.ChangeSetViewOpts).Print(o.ui)
 
-	return nil
+	o.printIdentityMismatches(changes)
+
+	return hasNoChanges, nil
+}
+
+// printIdentityMismatches warns about added and deleted resources that only
+// differ in namespace or API group, since they are likely meant to be the same resource
+func (o *DiffOptions) printIdentityMismatches(changes []ctldiff.Change) {
+	deletedByKindName := map[string][]ctlres.Resource{}
+
+	for _, change := range changes {
+		if change.Op() == ctldiff.ChangeOpDelete {
+			res := change.ExistingResource()
+			key := res.Kind() + "/" + res.Name()
+			deletedByKindName[key] = append(deletedByKindName[key], res)
+		}
+	}
+
+	for _, change := range changes {
+		if change.Op() != ctldiff.ChangeOpAdd {
+			continue
+		}
+		res := change.NewResource()
+		for _, deletedRes := range deletedByKindName[res.Kind()+"/"+res.Name()] {
+			o.ui.ErrorLinef("Warning: Resource '%s' does not match '%s' since namespace or API group differs",
+				res.Description(), deletedRes.Description())
+		}
+	}
 }
 
 func (o *DiffOptions) fileResources(files []string) ([]ctlres.Resource, error) {
@@ -100,6 +143,8 @@ type DiffChangeView struct {
 
 var _ ctlcap.ChangeView = DiffChangeView{}
 
+func NewDiffChangeView(change ctldiff.Change) DiffChangeView { return DiffChangeView{change} }
+
 func (v DiffChangeView) Resource() ctlres.Resource { return v.change.NewOrExistingResource() }
 
 func (v DiffChangeView) ClusterOriginalResource() ctlres.Resource {
@@ -4,6 +4,8 @@
 package tools
 
 import (
+	"strings"
+
 	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	"github.com/spf13/cobra"
@@ -14,11 +16,14 @@ type DiffFlags struct {
 	ctldiff.ChangeSetOpts
 	ctldiff.ChangeSetFilter
 
-	Run        bool
-	ExitStatus bool
-	UI         bool
+	Run                 bool
+	ExitStatus          bool
+	ExitStatusNoChanges int
+	ExitStatusChanges   int
+	UI                  bool
 
-	AnchoredDiff bool
+	AnchoredDiff        bool
+	ServerManagedFields bool
 }
 
 func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
@@ -27,20 +32,73 @@ func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
 	}
 
 	cmd.Flags().BoolVar(&s.Run, prefix+"run", false, "Show diff and exit successfully without any further action")
-	cmd.Flags().BoolVar(&s.ExitStatus, prefix+"exit-status", false, "Return specific exit status based on number of changes")
+	cmd.Flags().BoolVar(&s.ExitStatus, prefix+"exit-status", false,
+		"Return specific exit status based on number of changes (2: no changes, 3: pending changes, 1: failure)")
+	cmd.Flags().IntVar(&s.ExitStatusNoChanges, prefix+"exit-status-no-changes", 2,
+		"Exit status to return when there are no pending changes (used with --"+prefix+"exit-status)")
+	cmd.Flags().IntVar(&s.ExitStatusChanges, prefix+"exit-status-changes", 3,
+		"Exit status to return when there are pending changes (used with --"+prefix+"exit-status)")
 	cmd.Flags().BoolVar(&s.UI, prefix+"ui-alpha", false, "Start UI server to inspect changes (alpha feature)")
 
 	cmd.Flags().BoolVar(&s.Summary, prefix+"summary", true, "Show diff summary")
+	cmd.Flags().BoolVar(&s.SummaryOnly, prefix+"summary-only", false, "Show only list of changed resources and their operations (without diff details)")
 	cmd.Flags().BoolVarP(&s.Changes, prefix+"changes", "c", false, "Show changes")
 
 	cmd.Flags().IntVar(&s.Context, prefix+"context", 2, "Show number of lines around changed lines")
 	cmd.Flags().BoolVar(&s.LineNumbers, prefix+"line-numbers", true, "Show line numbers")
+	// Named the same regardless of prefix (e.g. --max-diff-lines-per-resource in both 'kapp deploy' and 'kapp tools diff')
+	cmd.Flags().IntVar(&s.MaxLines, "max-diff-lines-per-resource", 0, "Truncate diff shown for each resource after number of lines (0 for no limit)")
 	cmd.Flags().BoolVar(&s.Mask, prefix+"mask", true, "Apply masking rules")
 
 	cmd.Flags().BoolVar(&s.AgainstLastApplied, prefix+"against-last-applied", true, "Show changes against last applied copy when possible")
 
-	cmd.Flags().StringVar(&s.Filter, prefix+"filter", "", `Set changes filter (example: {"and":[{"ops":["update"]},{"existingResource":{"kinds":["Deployment"]}]})`)
+	cmd.Flags().Var(&diffFilterValue{s}, prefix+"filter", "Show only changes with specified ops in diff (A: create, M: update, D: delete) (example: AD); "+
+		`alternatively set changes filter (example: {"and":[{"ops":["update"]},{"existingResource":{"kinds":["Deployment"]}]})`)
 	cmd.Flags().BoolVar(&s.ChangesYAML, prefix+"changes-yaml", false, "Print YAML to be applied")
+	cmd.Flags().Var(&s.OpsFilter, prefix+"ops", "Show only changes with specified ops in diff (A: create, M: update, D: delete) (example: AD); does not affect what is applied")
+	cmd.Flags().MarkDeprecated(prefix+"ops", "use --"+prefix+"filter instead")
+	// Unprefixed 'sort' is already used by file flags (e.g. in 'kapp tools diff')
+	sortFlagName := prefix + "sort"
+	if len(prefix) == 0 {
+		sortFlagName = "diff-sort"
+	}
+	cmd.Flags().Var(&s.Sort, sortFlagName, "Set order of changes shown in diff (severity: deletes, then updates, then creates); defaults to calculated order")
 
 	cmd.Flags().BoolVar(&s.AnchoredDiff, prefix+"anchored", false, "Allow using anchored diff for large resources")
+	cmd.Flags().BoolVar(&s.ServerManagedFields, prefix+"server-managed-fields", false,
+		"Show fields set by the server (e.g. metadata.resourceVersion, metadata.uid) in diff")
+}
+
+func (s *DiffFlags) ChangeOpts() ctldiff.ChangeOpts {
+	return ctldiff.ChangeOpts{
+		AllowAnchoredDiff:          s.AnchoredDiff,
+		IncludeServerManagedFields: s.ServerManagedFields,
+	}
+}
+
+// diffFilterValue accepts either git style op letters (e.g. AD),
+// which only affect what is shown in diff, or a JSON changes filter,
+// which also limits what is applied.
+type diffFilterValue struct {
+	flags *DiffFlags
+}
+
+func (v *diffFilterValue) String() string {
+	if v.flags == nil {
+		return ""
+	}
+	if len(v.flags.Filter) > 0 {
+		return v.flags.Filter
+	}
+	return v.flags.OpsFilter.String()
+}
+
+func (v *diffFilterValue) Type() string { return "string" }
+
+func (v *diffFilterValue) Set(val string) error {
+	if strings.HasPrefix(strings.TrimSpace(val), "{") {
+		v.flags.Filter = val
+		return nil
+	}
+	return v.flags.OpsFilter.Set(val)
 }
@@ -0,0 +1,85 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools_test
+
+import (
+	"testing"
+
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffFlagsSortFlagName(t *testing.T) {
+	t.Run("unprefixed does not conflict with file sort flag", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		(&cmdtools.FileFlags{}).Set(cmd)
+
+		diffFlags := &cmdtools.DiffFlags{}
+		diffFlags.SetWithPrefix("", cmd)
+
+		require.NoError(t, cmd.Flags().Set("diff-sort", "severity"))
+		require.Equal(t, "severity", diffFlags.Sort.String())
+	})
+
+	t.Run("prefixed", func(t *testing.T) {
+		cmd := &cobra.Command{}
+
+		diffFlags := &cmdtools.DiffFlags{}
+		diffFlags.SetWithPrefix("diff", cmd)
+
+		require.NoError(t, cmd.Flags().Set("diff-sort", "severity"))
+		require.Equal(t, "severity", diffFlags.Sort.String())
+
+		require.Error(t, cmd.Flags().Set("diff-sort", "unknown"))
+	})
+}
+
+func TestDiffFlagsFilter(t *testing.T) {
+	newDiffFlags := func() (*cobra.Command, *cmdtools.DiffFlags) {
+		cmd := &cobra.Command{}
+		diffFlags := &cmdtools.DiffFlags{}
+		diffFlags.SetWithPrefix("diff", cmd)
+		return cmd, diffFlags
+	}
+
+	t.Run("accepts op letters", func(t *testing.T) {
+		cmd, diffFlags := newDiffFlags()
+
+		require.NoError(t, cmd.Flags().Set("diff-filter", "AM"))
+		require.Equal(t, "AM", diffFlags.OpsFilter.String())
+		require.Equal(t, "", diffFlags.Filter)
+
+		require.Error(t, cmd.Flags().Set("diff-filter", "X"))
+	})
+
+	t.Run("accepts changes filter", func(t *testing.T) {
+		cmd, diffFlags := newDiffFlags()
+
+		filter := `{"ops":["update"]}`
+		require.NoError(t, cmd.Flags().Set("diff-filter", filter))
+		require.Equal(t, filter, diffFlags.Filter)
+		require.Equal(t, "", diffFlags.OpsFilter.String())
+	})
+
+	t.Run("keeps deprecated ops alias", func(t *testing.T) {
+		cmd, diffFlags := newDiffFlags()
+
+		require.NoError(t, cmd.Flags().Set("diff-ops", "D"))
+		require.Equal(t, "D", diffFlags.OpsFilter.String())
+		require.NotEmpty(t, cmd.Flags().Lookup("diff-ops").Deprecated)
+	})
+}
+
+func TestDiffFlagsMaxLinesPerResource(t *testing.T) {
+	for _, prefix := range []string{"", "diff"} {
+		cmd := &cobra.Command{}
+
+		diffFlags := &cmdtools.DiffFlags{}
+		diffFlags.SetWithPrefix(prefix, cmd)
+
+		require.NoError(t, cmd.Flags().Set("max-diff-lines-per-resource", "10"), "prefix '%s'", prefix)
+		require.Equal(t, 10, diffFlags.MaxLines)
+	}
+}
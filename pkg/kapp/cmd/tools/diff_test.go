@@ -0,0 +1,85 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffAndPrint(t *testing.T) {
+	fsys := fstest.MapFS{
+		"prev.yml": &fstest.MapFile{Data: []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: default
+data:
+  key: old-val
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: moved
+  namespace: old-ns
+`)},
+		"new.yml": &fstest.MapFile{Data: []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: default
+data:
+  key: new-val
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: moved
+  namespace: new-ns
+`)},
+	}
+
+	diff := func(file, file2 string) (bool, string, string) {
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+
+		diffOpts := cmdtools.NewDiffOptions(ui.NewWriterUI(out, errOut, ui.NewNoopLogger()), nil)
+		diffOpts.FileFlags = cmdtools.FileFlags{Files: []string{file}}
+		diffOpts.FileFlags2 = cmdtools.FileFlags2{Files: []string{file2}}
+		diffOpts.DiffFlags = cmdtools.DiffFlags{ChangeSetViewOpts: ctlcap.ChangeSetViewOpts{Changes: true}}
+		diffOpts.FileSystem = fsys
+
+		hasNoChanges, err := diffOpts.DiffAndPrint()
+		require.NoError(t, err)
+
+		return hasNoChanges, out.String(), errOut.String()
+	}
+
+	t.Run("shows changes and warns about identity mismatches", func(t *testing.T) {
+		hasNoChanges, out, errOut := diff("new.yml", "prev.yml")
+		require.False(t, hasNoChanges)
+
+		require.Contains(t, out, "@@ update configmap/app-config (v1) namespace: default @@")
+		require.Contains(t, out, "new-val")
+		require.Contains(t, out, "@@ create configmap/moved (v1) namespace: new-ns @@")
+		require.Contains(t, out, "@@ delete configmap/moved (v1) namespace: old-ns @@")
+
+		require.Equal(t, "Warning: Resource 'configmap/moved (v1) namespace: new-ns' does not match "+
+			"'configmap/moved (v1) namespace: old-ns' since namespace or API group differs\n", errOut)
+	})
+
+	t.Run("reports no changes for same resources", func(t *testing.T) {
+		hasNoChanges, _, errOut := diff("prev.yml", "prev.yml")
+		require.True(t, hasNoChanges)
+		require.Empty(t, errOut)
+	})
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"unicode"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+var (
+	// Keys of fields that hold user provided maps hence should be printed as is
+	dumpConfigUserDataKeys = map[string]bool{"AdditionalLabels": true, "Config": true}
+)
+
+type DumpConfigOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ConfigFiles []string
+	Defaults    bool
+
+	FileSystem fs.FS
+}
+
+func NewDumpConfigOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *DumpConfigOptions {
+	return &DumpConfigOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewDumpConfigCmd(o *DumpConfigOptions, _ cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dump-config",
+		Short: "Print effective kapp config",
+		Long: `Print effective kapp config (default config followed by provided configs)
+as a single kapp.k14s.io/v1alpha1 Config without accessing a cluster.
+
+Rules are listed in the order they are evaluated by kapp.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	cmd.Flags().StringSliceVarP(&o.ConfigFiles, "config", "c", nil, "Set config file (format: /tmp/foo, https://..., -) (can repeat)")
+	cmd.Flags().BoolVar(&o.Defaults, "defaults", true, "Include default kapp config")
+	return cmd
+}
+
+func (o *DumpConfigOptions) Run() error {
+	var rs []ctlres.Resource
+
+	for _, file := range o.ConfigFiles {
+		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		if err != nil {
+			return err
+		}
+
+		for _, fileRes := range fileRs {
+			resources, err := fileRes.Resources()
+			if err != nil {
+				return err
+			}
+			rs = append(rs, resources...)
+		}
+	}
+
+	confFunc := ctlconf.NewConfFromResources
+	if o.Defaults {
+		confFunc = ctlconf.NewConfFromResourcesWithDefaults
+	}
+
+	_, conf, err := confFunc(rs)
+	if err != nil {
+		return err
+	}
+
+	configBs, err := o.configYAML(conf.Merged())
+	if err != nil {
+		return fmt.Errorf("Serializing config: %w", err)
+	}
+
+	o.ui.PrintBlock(configBs)
+
+	return nil
+}
+
+// configYAML serializes config without unset fields
+// (most of rule fields are optional pointers)
+func (o *DumpConfigOptions) configYAML(config ctlconf.Config) ([]byte, error) {
+	jsonBs, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var val interface{}
+
+	err = json.Unmarshal(jsonBs, &val)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(o.normalize(val))
+}
+
+// normalize removes null values and converts field names to lower camel case
+// (config fields without json tags are serialized with Go field names)
+func (o *DumpConfigOptions) normalize(val interface{}) interface{} {
+	switch typedVal := val.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for k, v := range typedVal {
+			switch {
+			case v == nil:
+				continue
+			case dumpConfigUserDataKeys[k]:
+				result[o.lowerCamel(k)] = v
+			default:
+				result[o.lowerCamel(k)] = o.normalize(v)
+			}
+		}
+		return result
+	case []interface{}:
+		for i, v := range typedVal {
+			typedVal[i] = o.normalize(v)
+		}
+	}
+	return val
+}
+
+// lowerCamel converts Go field name (e.g. APIGroupKindMatcher) to lower camel case (e.g. apiGroupKindMatcher)
+func (o *DumpConfigOptions) lowerCamel(key string) string {
+	runes := []rune(key)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
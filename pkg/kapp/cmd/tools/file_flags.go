@@ -4,23 +4,43 @@
 package tools
 
 import (
+	"io/fs"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"github.com/spf13/cobra"
 )
 
 type FileFlags struct {
-	Files []string
-	Sort  bool
+	Files     []string
+	FileLists []string
+	Sort      bool
 }
 
 func (s *FileFlags) Set(cmd *cobra.Command) {
-	cmd.Flags().StringSliceVarP(&s.Files, "file", "f", s.Files, "Set file (format: /tmp/foo, https://..., -) (can repeat)")
+	cmd.Flags().StringSliceVarP(&s.Files, "file", "f", s.Files, "Set file (format: /tmp/foo, https://..., kustomize://dir (requires kustomize or kubectl in PATH), -) (can repeat)")
+	cmd.Flags().StringSliceVar(&s.FileLists, "file-list", nil, "Set file that lists files one per line (format: /tmp/foo.txt) (can repeat)")
 	cmd.Flags().BoolVar(&s.Sort, "sort", true, "Sort by namespace, name, etc.")
 }
 
+// AllFiles returns files followed by files listed in file lists
+func (s *FileFlags) AllFiles(fsys fs.FS) ([]string, error) {
+	files := append([]string{}, s.Files...)
+
+	for _, fileList := range s.FileLists {
+		listedFiles, err := ctlres.NewFileList(fsys, fileList).Files()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, listedFiles...)
+	}
+
+	return files, nil
+}
+
 type FileFlags2 struct {
 	Files []string
 }
 
 func (s *FileFlags2) Set(cmd *cobra.Command) {
-	cmd.Flags().StringSliceVar(&s.Files, "file2", nil, "Set second file (format: /tmp/foo, https://..., -) (can repeat)")
+	cmd.Flags().StringSliceVar(&s.Files, "file2", nil, "Set second file (format: /tmp/foo, https://..., kustomize://dir (requires kustomize or kubectl in PATH), -) (can repeat)")
 }
@@ -0,0 +1,311 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"fmt"
+	"io/fs"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type GCPreviewOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags            AppFlags
+	FileFlags           FileFlags
+	ResourceFilterFlags ResourceFilterFlags
+
+	PrepareResourcesOpts     ctlapp.PrepareResourcesOpts
+	DefaultLabelScopingRules bool
+	DisableGKScoping         bool
+
+	FileSystem fs.FS
+}
+
+func NewGCPreviewOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *GCPreviewOptions {
+	return &GCPreviewOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewGCPreviewCmd(o *GCPreviewOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc-preview",
+		Short: "Show resources that would be deleted if provided resources were deployed",
+		Long: `Show resources that would be deleted if provided resources were deployed
+
+Changes are calculated the same way as during deploy (including ignored
+transient resources and delete strategies), but only resources that would
+be deleted are reported and nothing is applied.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Show resources of app 'app1' that are not part of config in new/
+  kapp tools gc-preview -a app1 -f new/`,
+	}
+
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.FileFlags.Set(cmd)
+	o.ResourceFilterFlags.Set(cmd)
+
+	// Only flags that affect identity of provided resources are relevant
+	cmd.Flags().StringVar(&o.PrepareResourcesOpts.IntoNamespace, "into-ns", "", "Place resources into namespace")
+	cmd.Flags().StringSliceVar(&o.PrepareResourcesOpts.MapNamespaces, "map-ns", nil, "Map resources from one namespace into another (could be specified multiple times)")
+	cmd.Flags().StringVar(&o.PrepareResourcesOpts.NamespaceFromLabel, "namespace-from-label", "",
+		"Place namespaced resources into namespace specified by the value of this label (e.g. tenant)")
+	cmd.Flags().BoolVar(&o.DefaultLabelScopingRules, "default-label-scoping-rules",
+		true, "Use default label scoping rules")
+	cmd.Flags().BoolVar(&o.DisableGKScoping, "dangerous-disable-gk-scoping",
+		false, "Disable scoping of resource searching to used GroupKinds")
+
+	return cmd
+}
+
+func (o *GCPreviewOptions) Run() error {
+	app, supportObjs, err := o.AppFlags.findApp(o.depsFactory, o.logger)
+	if err != nil {
+		return err
+	}
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	labeledResources := ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
+
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	if err != nil {
+		return err
+	}
+
+	newResources, conf, err := o.newResources(supportObjs, labeledResources)
+	if err != nil {
+		return err
+	}
+
+	if conf.IsManagedAnnotationDisabled(ctlconf.ManagedAnnotationIdentity) {
+		supportObjs.IdentifiedResources = supportObjs.IdentifiedResources.WithoutIdentityAnnotation()
+		labeledResources = ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	gksScope, err := o.gksScope(app, newResources)
+	if err != nil {
+		return err
+	}
+
+	existingResources, err := labeledResources.AllAndMatching(newResources, ctlres.AllAndMatchingOpts{
+		// Ownership conflicts are reported by deploy
+		SkipResourceOwnershipCheck:     true,
+		DisallowedResourcesByLabelKeys: []string{ctlapp.KappIsAppLabelKey},
+		IdentifiedResourcesListOpts: ctlres.IdentifiedResourcesListOpts{
+			GKsScope:           gksScope,
+			ResourceNamespaces: append(meta.LastChange.Namespaces, o.nsNames(newResources)...),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	newResources = resourceFilter.Apply(newResources)
+	existingResources = resourceFilter.Apply(existingResources)
+
+	appLabelKey, appLabelVal, err := ctlres.NewSimpleLabel(labelSelector).KV()
+	if err != nil {
+		return err
+	}
+
+	newResources, err = ctldiff.NewSharedResources(existingResources, newResources, appLabelKey, appLabelVal).Prepare()
+	if err != nil {
+		return err
+	}
+
+	deletedResources, err := o.deletedResources(existingResources, newResources, conf, supportObjs)
+	if err != nil {
+		return err
+	}
+
+	if len(deletedResources) == 0 {
+		o.ui.PrintLinef("No resources would be deleted")
+		return nil
+	}
+
+	source := fmt.Sprintf("app '%s' that would be deleted", app.Name())
+
+	InspectView{Source: source, Resources: deletedResources, Sort: true}.Print(o.ui)
+
+	// Deploy would refuse to delete these resources
+	return o.depsFactory.NamespaceGuard().Validate(deletedResources)
+}
+
+func (o *GCPreviewOptions) newResources(supportObjs appSupportObjs,
+	labeledResources *ctlres.LabeledResources) ([]ctlres.Resource, ctlconf.Conf, error) {
+
+	files, err := o.FileFlags.AllFiles(o.FileSystem)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	if len(files) == 0 {
+		return nil, ctlconf.Conf{}, fmt.Errorf("Expected at least one --file (-f) specified with a file or directory path")
+	}
+
+	var newResources []ctlres.Resource
+
+	for _, file := range files {
+		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		if err != nil {
+			return nil, ctlconf.Conf{}, err
+		}
+
+		for _, fileRes := range fileRs {
+			resources, err := fileRes.Resources()
+			if err != nil {
+				return nil, ctlconf.Conf{}, err
+			}
+
+			newResources = append(newResources, resources...)
+		}
+	}
+
+	newResources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(newResources)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	prepOpts := o.PrepareResourcesOpts
+	prepOpts.DefaultNamespace = o.AppFlags.NamespaceFlags.Name
+
+	newResources, err = ctlapp.NewPreparation(supportObjs.ResourceTypes,
+		ctlres.NewOpenAPISchema(supportObjs.CoreClient), prepOpts).PrepareResources(newResources)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	err = labeledResources.Prepare(newResources, conf.OwnershipLabelMods(),
+		conf.LabelScopingMods(o.DefaultLabelScopingRules), conf.AdditionalLabels())
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	return newResources, conf, nil
+}
+
+// deletedResources calculates changes via the same change set as deploy
+// and returns resources that would be deleted when changes are applied
+func (o *GCPreviewOptions) deletedResources(existingResources, newResources []ctlres.Resource,
+	conf ctlconf.Conf, supportObjs appSupportObjs) ([]ctlres.Resource, error) {
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), conf.DiffAgainstLastAppliedFieldExclusionMods(),
+		conf.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{}).
+		WithManagedFieldsExclusionRules(conf.DiffAgainstExistingManagedFieldsExclusionRules())
+	changeSetFactory := ctldiff.NewChangeSetFactory(ctldiff.ChangeSetOpts{}, changeFactory)
+
+	err := ctldiff.NewRenewableResources(existingResources, newResources).Prepare()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := ctldiff.NewChangeSetWithVersionedRs(
+		existingResources, newResources, conf.TemplateRules(), ctldiff.ChangeSetOpts{}, changeFactory).Calculate()
+	if err != nil {
+		return nil, err
+	}
+
+	msgsUI := cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui))
+
+	convergedResFactory := ctlcap.NewConvergedResourceFactory(conf.WaitRules(), ctlcap.ConvergedResourceFactoryOpts{})
+
+	clusterChangeFactory := ctlcap.NewClusterChangeFactory(
+		ctlcap.ClusterChangeOpts{}, supportObjs.IdentifiedResources, changeFactory, changeSetFactory,
+		convergedResFactory, msgsUI, conf.DiffMaskRules(), conf.ApplyStrategyRules(), conf.WaitTimeouts())
+
+	clusterChanges, _, err := ctlcap.NewClusterChangeSet(
+		changes, ctlcap.ClusterChangeSetOpts{}, clusterChangeFactory,
+		conf.ChangeGroupBindings(), conf.ChangeRuleBindings(), msgsUI, o.logger).Calculate()
+	if err != nil {
+		return nil, err
+	}
+
+	var deletedResources []ctlres.Resource
+
+	for _, change := range clusterChanges {
+		deletes, err := change.DeletesResource()
+		if err != nil {
+			return nil, err
+		}
+		if deletes {
+			deletedResources = append(deletedResources, change.Resource())
+		}
+	}
+
+	return deletedResources, nil
+}
+
+// gksScope limits resource searching to GroupKinds used by app and provided resources
+func (o *GCPreviewOptions) gksScope(app ctlapp.App, newResources []ctlres.Resource) ([]schema.GroupKind, error) {
+	if o.DisableGKScoping {
+		return []schema.GroupKind{}, nil
+	}
+
+	usedGKs, err := app.UsedGKs()
+	if err != nil {
+		return nil, err
+	}
+
+	// Apps without cached GKs are not scoped
+	if usedGKs == nil {
+		return []schema.GroupKind{}, nil
+	}
+
+	gksByGK := map[schema.GroupKind]struct{}{}
+	var uniqGKs []schema.GroupKind
+
+	for _, gk := range *usedGKs {
+		if _, found := gksByGK[gk]; !found {
+			gksByGK[gk] = struct{}{}
+			uniqGKs = append(uniqGKs, gk)
+		}
+	}
+
+	for _, res := range newResources {
+		gk := res.GroupKind()
+		if _, found := gksByGK[gk]; !found {
+			gksByGK[gk] = struct{}{}
+			uniqGKs = append(uniqGKs, gk)
+		}
+	}
+
+	return uniqGKs, nil
+}
+
+func (o *GCPreviewOptions) nsNames(resources []ctlres.Resource) []string {
+	uniqNames := map[string]struct{}{}
+	names := []string{}
+	for _, res := range resources {
+		ns := res.Namespace()
+		if ns == "" {
+			ns = "(cluster)"
+		}
+		if _, found := uniqNames[ns]; !found {
+			names = append(names, ns)
+			uniqNames[ns] = struct{}{}
+		}
+	}
+	return names
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"fmt"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+)
+
+type GCVersionedOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags AppFlags
+}
+
+func NewGCVersionedOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *GCVersionedOptions {
+	return &GCVersionedOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewGCVersionedCmd(o *GCVersionedOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc-versioned",
+		Short: "List versioned resources that exceed number of versions to keep",
+		Long: `List versioned resources that exceed number of versions to keep
+
+Such resources may be left behind when deploy does not complete.
+Resources are only listed unless --yes is specified.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # List versioned resources of app 'app1' that would be deleted
+  kapp tools gc-versioned -a app1
+
+  # Delete them
+  kapp tools gc-versioned -a app1 --yes`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	return cmd
+}
+
+func (o *GCVersionedOptions) Run() error {
+	app, supportObjs, err := o.AppFlags.findApp(o.depsFactory, o.logger)
+	if err != nil {
+		return err
+	}
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	existingResources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{
+		ResourceNamespaces: meta.LastChange.Namespaces})
+	if err != nil {
+		return err
+	}
+
+	orphanedResources, err := ctldiff.OrphanedVersionedResources(existingResources)
+	if err != nil {
+		return err
+	}
+
+	if len(orphanedResources) == 0 {
+		o.ui.PrintLinef("No versioned resources to delete")
+		return nil
+	}
+
+	source := fmt.Sprintf("app '%s' to delete", app.Name())
+
+	InspectView{Source: source, Resources: orphanedResources, Sort: true}.Print(o.ui)
+
+	// UI is only non-interactive when --yes is specified
+	if o.ui.IsInteractive() {
+		return nil
+	}
+
+	err = o.depsFactory.NamespaceGuard().Validate(orphanedResources)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range orphanedResources {
+		err := supportObjs.IdentifiedResources.Delete(res, ctlres.DeleteOpts{})
+		if err != nil {
+			return fmt.Errorf("Deleting %s: %w", res.Description(), err)
+		}
+	}
+
+	o.ui.PrintLinef("Deleted %d versioned resources", len(orphanedResources))
+
+	return nil
+}
@@ -53,7 +53,12 @@ func (o *InspectOptions) inspectFiles() error {
 		return err
 	}
 
-	for _, file := range o.FileFlags.Files {
+	files, err := o.FileFlags.AllFiles(o.FileSystem)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
 		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
 		if err != nil {
 			return err
@@ -0,0 +1,179 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"fmt"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type OrphansOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	Delete bool
+}
+
+func NewOrphansOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *OrphansOptions {
+	return &OrphansOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewOrphansCmd(o *OrphansOptions, _ cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "orphans",
+		Short: "List resources labeled by kapp that do not belong to any app",
+		Long: `List resources labeled by kapp that do not belong to any app
+
+Resources are considered orphaned when their app label value
+does not match any app recorded in the cluster (in any namespace),
+for example when app record was deleted without deleting its resources.
+Resources created by controllers (e.g. Pods of ReplicaSets) are not included.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	cmd.Flags().BoolVar(&o.Delete, "delete", false, "Delete orphaned resources")
+	return cmd
+}
+
+func (o *OrphansOptions) Run() error {
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient(cmdcore.DynamicClientOpts{Warnings: true})
+	if err != nil {
+		return err
+	}
+
+	mutedDynamicClient, err := o.depsFactory.DynamicClient(cmdcore.DynamicClientOpts{Warnings: false})
+	if err != nil {
+		return err
+	}
+
+	resTypes := ctlres.NewResourceTypesImpl(coreClient, ctlres.ResourceTypesImplOpts{}, o.logger)
+	resources := ctlres.NewResourcesImpl(
+		resTypes, coreClient, dynamicClient, mutedDynamicClient, ctlres.ResourcesImplOpts{}, o.logger)
+	identifiedResources := ctlres.NewIdentifiedResources(coreClient, resTypes, resources, nil, o.logger)
+
+	// Empty namespace lists apps in all namespaces
+	apps, err := ctlapp.NewApps("", coreClient, identifiedResources, o.logger).List(nil)
+	if err != nil {
+		return fmt.Errorf("Listing apps: %w", err)
+	}
+
+	labelSelector, err := labels.Parse(ctlapp.KappAppLabelKey)
+	if err != nil {
+		return err
+	}
+
+	labeledRs, err := identifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{})
+	if err != nil {
+		return fmt.Errorf("Listing labeled resources: %w", err)
+	}
+
+	orphanedRs, err := orphanedResources(apps, labeledRs)
+	if err != nil {
+		return err
+	}
+
+	o.printResources(orphanedRs)
+
+	if !o.Delete || len(orphanedRs) == 0 {
+		return nil
+	}
+
+	err = o.depsFactory.NamespaceGuard().Validate(orphanedRs)
+	if err != nil {
+		return err
+	}
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return err
+	}
+
+	for _, res := range orphanedRs {
+		err := identifiedResources.Delete(res, ctlres.DeleteOpts{})
+		if err != nil {
+			return fmt.Errorf("Deleting orphaned resource '%s': %w", res.Description(), err)
+		}
+	}
+
+	o.ui.PrintLinef("Deleted %d orphaned resources", len(orphanedRs))
+
+	return nil
+}
+
+// orphanedResources returns resources (excluding ones created by controllers)
+// whose app label value does not match any of the apps
+func orphanedResources(apps []ctlapp.App, labeledRs []ctlres.Resource) ([]ctlres.Resource, error) {
+	appLabelValues := map[string]struct{}{}
+
+	for _, app := range apps {
+		labelSelector, err := app.LabelSelector()
+		if err != nil {
+			return nil, fmt.Errorf("Getting label of app '%s' in namespace '%s': %w", app.Name(), app.Namespace(), err)
+		}
+
+		_, labelValue, err := ctlres.NewSimpleLabel(labelSelector).KV()
+		if err != nil {
+			return nil, fmt.Errorf("Getting label of app '%s' in namespace '%s': %w", app.Name(), app.Namespace(), err)
+		}
+
+		appLabelValues[labelValue] = struct{}{}
+	}
+
+	var orphanedRs []ctlres.Resource
+
+	for _, res := range labeledRs {
+		if res.Transient() {
+			continue
+		}
+		if _, found := appLabelValues[res.Labels()[ctlapp.KappAppLabelKey]]; !found {
+			orphanedRs = append(orphanedRs, res)
+		}
+	}
+
+	return orphanedRs, nil
+}
+
+func (o *OrphansOptions) printResources(rs []ctlres.Resource) {
+	table := uitable.Table{
+		Title:   "Orphaned resources",
+		Content: "resources",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("App label"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 3, Asc: true},
+			{Column: 0, Asc: true},
+			{Column: 1, Asc: true},
+		},
+	}
+
+	for _, res := range rs {
+		table.Rows = append(table.Rows, []uitable.Value{
+			cmdcore.NewValueNamespace(res.Namespace()),
+			uitable.NewValueString(res.Name()),
+			uitable.NewValueString(res.Kind()),
+			uitable.NewValueString(res.Labels()[ctlapp.KappAppLabelKey]),
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"bytes"
+	"testing"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type labeledApp struct {
+	ctlapp.App
+
+	labelValue string
+}
+
+func (a labeledApp) LabelSelector() (labels.Selector, error) {
+	return labels.Set{ctlapp.KappAppLabelKey: a.labelValue}.AsSelector(), nil
+}
+
+func TestOrphanedResources(t *testing.T) {
+	newLabeledRes := func(name, labelValue string) ctlres.Resource {
+		return ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + name + `
+  namespace: default
+  labels:
+    kapp.k14s.io/app: "` + labelValue + `"
+`))
+	}
+
+	ownedRes := newLabeledRes("owned", "100")
+	orphanedRes := newLabeledRes("orphaned", "200")
+	transientRes := newLabeledRes("transient", "200")
+	transientRes.MarkTransient(true)
+
+	apps := []ctlapp.App{labeledApp{labelValue: "100"}, labeledApp{labelValue: "300"}}
+
+	orphanedRs, err := orphanedResources(apps, []ctlres.Resource{ownedRes, orphanedRes, transientRes})
+	require.NoError(t, err)
+	require.Equal(t, []ctlres.Resource{orphanedRes}, orphanedRs)
+
+	orphanedRs, err = orphanedResources(nil, []ctlres.Resource{ownedRes})
+	require.NoError(t, err)
+	require.Equal(t, []ctlres.Resource{ownedRes}, orphanedRs, "Expected resources to be orphaned when there are no apps")
+
+	out := bytes.NewBufferString("")
+	opts := &OrphansOptions{ui: ui.NewWriterUI(out, out, ui.NewNoopLogger())}
+	opts.printResources([]ctlres.Resource{orphanedRes})
+
+	require.Contains(t, out.String(), "Orphaned resources")
+	require.Contains(t, out.String(), "default    orphaned  ConfigMap  200")
+	require.Contains(t, out.String(), "1 resources")
+}
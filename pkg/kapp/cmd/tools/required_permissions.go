@@ -0,0 +1,106 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+type RequiredPermissionsOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	FileFlags FileFlags
+	Name      string
+
+	FileSystem fs.FS
+}
+
+func NewRequiredPermissionsOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *RequiredPermissionsOptions {
+	return &RequiredPermissionsOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewRequiredPermissionsCmd(o *RequiredPermissionsOptions, _ cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "required-permissions",
+		Short: "Print RBAC role with permissions required to deploy files",
+		Long: `Print RBAC role with permissions required to deploy files
+
+Role (or ClusterRole when resources span multiple namespaces or are cluster-scoped)
+includes permissions to manage included resources, app record, and to escalate
+and bind included and referenced roles.
+
+Cluster is used to map resource kinds to resource types.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.FileFlags.Set(cmd)
+	cmd.Flags().StringVar(&o.Name, "name", "kapp-deployer", "Set role name")
+	return cmd
+}
+
+func (o *RequiredPermissionsOptions) Run() error {
+	files, err := o.FileFlags.AllFiles(o.FileSystem)
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("Expected at least one file to be specified via --file")
+	}
+
+	var rs []ctlres.Resource
+
+	for _, file := range files {
+		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		if err != nil {
+			return err
+		}
+
+		for _, fileRes := range fileRs {
+			resources, err := fileRes.Resources()
+			if err != nil {
+				return err
+			}
+			rs = append(rs, resources...)
+		}
+	}
+
+	mapper, err := o.depsFactory.RESTMapper()
+	if err != nil {
+		return err
+	}
+
+	role, err := permissions.NewRequiredPermissions(mapper).Role(o.Name, rs)
+	if err != nil {
+		return fmt.Errorf("Calculating required permissions: %w", err)
+	}
+
+	roleObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(role)
+	if err != nil {
+		return fmt.Errorf("Converting role: %w", err)
+	}
+
+	// Omit empty creation timestamp so that output is ready to be applied as is
+	unstructured.RemoveNestedField(roleObj, "metadata", "creationTimestamp")
+
+	roleBs, err := yaml.Marshal(roleObj)
+	if err != nil {
+		return fmt.Errorf("Marshaling role: %w", err)
+	}
+
+	o.ui.PrintBlock(roleBs)
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type restMapperDepsFactory struct {
+	cmdcore.DepsFactory
+
+	mapper meta.RESTMapper
+}
+
+func (f restMapperDepsFactory) RESTMapper() (meta.RESTMapper, error) { return f.mapper, nil }
+
+func TestRequiredPermissionsPrintsRole(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app.yml": &fstest.MapFile{Data: []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: app-ns
+`)},
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	out := bytes.NewBufferString("")
+
+	opts := cmdtools.NewRequiredPermissionsOptions(ui.NewWriterUI(out, out, ui.NewNoopLogger()),
+		restMapperDepsFactory{mapper: mapper})
+	opts.FileSystem = fsys
+	opts.FileFlags.Files = []string{"app.yml"}
+	opts.Name = "app-deployer"
+
+	err := opts.Run()
+	require.NoError(t, err)
+
+	require.Equal(t, `apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: app-deployer
+  namespace: app-ns
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - configmaps
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - ""
+  resources:
+  - configmaps
+  verbs:
+  - get
+  - list
+  - create
+  - update
+  - patch
+  - delete
+`, out.String())
+}
+
+func TestRequiredPermissionsRequiresFiles(t *testing.T) {
+	out := bytes.NewBufferString("")
+
+	opts := cmdtools.NewRequiredPermissionsOptions(ui.NewWriterUI(out, out, ui.NewNoopLogger()), restMapperDepsFactory{})
+
+	err := opts.Run()
+	require.EqualError(t, err, "Expected at least one file to be specified via --file")
+}
@@ -15,6 +15,7 @@ type ResourceFilterFlags struct {
 	Age string
 	Rf  ctlres.ResourceFilter
 	Bf  string
+	Ex  []string
 }
 
 func (s *ResourceFilterFlags) Set(cmd *cobra.Command) {
@@ -28,6 +29,8 @@ func (s *ResourceFilterFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().StringSliceVar(&s.Rf.KindNsNames, "filter-kind-ns-name", nil, "Set kind-namespace-name filter (example: Deployment/knative-serving/controller) (can repeat)")
 	cmd.Flags().StringSliceVar(&s.Rf.Labels, "filter-labels", nil, "Set label filter (example: x=y)")
 
+	cmd.Flags().StringArrayVar(&s.Ex, "filter-expr", nil, `Set JSONPath predicate filter (example: .metadata.labels.tier=="frontend", .spec.replicas!=0, .metadata.annotations['x/y']) (can repeat)`)
+
 	cmd.Flags().StringVar(&s.Bf, "filter", "", `Set filter (example: {"and":[{"not":{"resource":{"kinds":["foo%"]}}},{"resource":{"kinds":["!foo"]}}]})`)
 }
 
@@ -50,6 +53,15 @@ func (s *ResourceFilterFlags) ResourceFilter() (ctlres.ResourceFilter, error) {
 		rf.BoolFilter = boolFilter
 	}
 
+	for _, exprStr := range s.Ex {
+		expr, err := ctlres.NewResourceFilterExpr(exprStr)
+		if err != nil {
+			return ctlres.ResourceFilter{}, err
+		}
+
+		rf.Exprs = append(rf.Exprs, expr)
+	}
+
 	return rf, nil
 }
 
@@ -0,0 +1,189 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+const (
+	validateConfigContextLines = 2
+)
+
+var (
+	// YAML parse errors include line number relative to the document
+	yamlErrLineRegexp = regexp.MustCompile(`line (\d+):`)
+)
+
+type ValidateConfigOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	ConfigFiles []string
+
+	FileSystem fs.FS
+}
+
+type configDoc struct {
+	Bytes     []byte
+	Num       int
+	StartLine int
+}
+
+func NewValidateConfigOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *ValidateConfigOptions {
+	return &ValidateConfigOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewValidateConfigCmd(o *ValidateConfigOptions, _ cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate-config",
+		Short: "Validate kapp config files",
+		Long:  "Validate kapp config files (kapp.k14s.io/v1alpha1 Config and ConfigMaps labeled with kapp.k14s.io/config) without accessing a cluster",
+		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	cmd.Flags().StringSliceVarP(&o.ConfigFiles, "config", "c", nil, "Set config file (format: /tmp/foo, https://..., -) (can repeat)")
+	return cmd
+}
+
+func (o *ValidateConfigOptions) Run() error {
+	if len(o.ConfigFiles) == 0 {
+		return fmt.Errorf("Expected at least one config file to be specified via --config")
+	}
+
+	var numConfigs, numErrs int
+
+	for _, file := range o.ConfigFiles {
+		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		if err != nil {
+			return err
+		}
+
+		for _, fileRes := range fileRs {
+			fileNumConfigs, fileNumErrs, err := o.validateFile(fileRes)
+			if err != nil {
+				return err
+			}
+			numConfigs += fileNumConfigs
+			numErrs += fileNumErrs
+		}
+	}
+
+	if numErrs > 0 {
+		return fmt.Errorf("Validating config: Found %d error(s)", numErrs)
+	}
+
+	o.ui.PrintLinef("Succeeded validating %d config(s)", numConfigs)
+
+	return nil
+}
+
+func (o *ValidateConfigOptions) validateFile(fileRes ctlres.FileResource) (int, int, error) {
+	fileBs, err := fileRes.Bytes()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var numConfigs, numErrs int
+
+	fileLines := strings.Split(string(fileBs), "\n")
+
+	for _, doc := range o.docs(fileBs) {
+		docDesc := fmt.Sprintf("%s doc %d", fileRes.Description(), doc.Num)
+
+		rs, err := ctlres.NewResourcesFromBytes(doc.Bytes)
+		if err != nil {
+			errLine := doc.StartLine
+			if match := yamlErrLineRegexp.FindStringSubmatch(err.Error()); len(match) == 2 {
+				docLine, _ := strconv.Atoi(match[1])
+				errLine = doc.StartLine + docLine - 1
+			}
+			o.reportErr(docDesc, fileLines, errLine, fmt.Errorf("Parsing YAML: %w", err))
+			numErrs++
+			continue
+		}
+
+		for _, res := range rs {
+			_, conf, err := ctlconf.NewConfFromResources([]ctlres.Resource{res})
+			if err != nil {
+				o.reportErr(docDesc, fileLines, doc.StartLine, err)
+				numErrs++
+				continue
+			}
+			numConfigs += conf.NumConfigs()
+		}
+	}
+
+	return numConfigs, numErrs, nil
+}
+
+// docs splits file into YAML documents keeping track of where each one starts
+// (separators are matched the same way as YAML reader used for resources)
+func (o *ValidateConfigOptions) docs(fileBs []byte) []configDoc {
+	var docs []configDoc
+	var buf bytes.Buffer
+
+	startLine := 1
+	lines := strings.Split(string(fileBs), "\n")
+
+	flush := func(nextStartLine int) {
+		if len(strings.TrimSpace(buf.String())) > 0 {
+			docs = append(docs, configDoc{Bytes: append([]byte{}, buf.Bytes()...), Num: len(docs) + 1, StartLine: startLine})
+		}
+		buf.Reset()
+		startLine = nextStartLine
+	}
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, "---") && len(strings.TrimSpace(line[3:])) == 0 {
+			flush(i + 2)
+			continue
+		}
+		if buf.Len() == 0 && len(strings.TrimSpace(line)) == 0 {
+			startLine = i + 2
+			continue
+		}
+		buf.WriteString(line + "\n")
+	}
+
+	flush(0)
+
+	return docs
+}
+
+func (o *ValidateConfigOptions) reportErr(docDesc string, fileLines []string, line int, err error) {
+	o.ui.ErrorLinef("Error: %s (line %d): %s", docDesc, line, err)
+
+	from := line - validateConfigContextLines
+	if from < 1 {
+		from = 1
+	}
+	to := line + validateConfigContextLines
+	if to > len(fileLines) {
+		to = len(fileLines)
+	}
+
+	var context strings.Builder
+
+	for i := from; i <= to; i++ {
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&context, "%s %4d | %s\n", marker, i, fileLines[i-1])
+	}
+
+	o.ui.PrintBlock([]byte(context.String()))
+}
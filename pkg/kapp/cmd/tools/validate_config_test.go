@@ -0,0 +1,89 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigSucceeds(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config.yml": &fstest.MapFile{Data: []byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+waitTimeouts:
+- kind: StatefulSet
+  timeout: 15m
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kapp-config
+  labels:
+    kapp.k14s.io/config: ""
+data:
+  config.yml: |
+    apiVersion: kapp.k14s.io/v1alpha1
+    kind: Config
+    additionalLabels:
+      team: platform
+`)},
+	}
+
+	out, err := runValidateConfig(fsys, "config.yml")
+	require.NoError(t, err)
+	require.Contains(t, out, "Succeeded validating 2 config(s)")
+}
+
+func TestValidateConfigReportsErrorsWithLineContext(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config.yml": &fstest.MapFile{Data: []byte(`apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+waitTimeouts:
+- kind: StatefulSet
+  timeout: 15m
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+waitTimeouts:
+- kind: StatefulSet
+  timeout: -1m
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+rebaseRules: [
+`)},
+	}
+
+	out, err := runValidateConfig(fsys, "config.yml")
+	require.EqualError(t, err, "Validating config: Found 2 error(s)")
+
+	require.Contains(t, out, "doc 2 (line 7): ")
+	require.Contains(t, out, "Validating wait timeout 0: Expected timeout to be positive")
+	require.Contains(t, out, ">    7 | apiVersion: kapp.k14s.io/v1alpha1")
+	require.Contains(t, out, "     6 | ---")
+
+	require.Contains(t, out, "doc 3 (line 16): Parsing YAML")
+	require.NotContains(t, out, "Succeeded validating")
+}
+
+func TestValidateConfigRequiresFiles(t *testing.T) {
+	_, err := runValidateConfig(fstest.MapFS{})
+	require.EqualError(t, err, "Expected at least one config file to be specified via --config")
+}
+
+func runValidateConfig(fsys fstest.MapFS, files ...string) (string, error) {
+	out := &bytes.Buffer{}
+	opts := cmdtools.NewValidateConfigOptions(ui.NewWriterUI(out, out, ui.NewNoopLogger()), nil)
+	opts.ConfigFiles = files
+	opts.FileSystem = fsys
+	err := opts.Run()
+	return out.String(), err
+}
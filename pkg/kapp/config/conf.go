@@ -82,16 +82,45 @@ func newConfigFromConfigMapRes(res ctlres.Resource) (Config, error) {
 	return NewConfigFromResource(configRes)
 }
 
+func (c Conf) NumConfigs() int { return len(c.configs) }
+
 func (c Conf) RebaseMods() []ctlres.ResourceModWithMultiple {
 	var mods []ctlres.ResourceModWithMultiple
 	for _, config := range c.configs {
 		for _, rule := range config.RebaseRules {
 			mods = append(mods, rule.AsMods()...)
 		}
+		for _, rule := range config.PreserveFieldRules {
+			mods = append(mods, rule.AsMods()...)
+		}
+		for _, key := range config.ListMergeKeys {
+			mods = append(mods, key.AsMod())
+		}
+	}
+	// Applied last so that quantities are compared
+	// after all other rules had a chance to copy values
+	for _, config := range c.configs {
+		for _, rule := range config.NumericEquivalenceRules {
+			mods = append(mods, rule.AsMods()...)
+		}
 	}
 	return mods
 }
 
+// RebaseExternalResources returns resources (not part of the app)
+// that rebase rules copy values from
+func (c Conf) RebaseExternalResources() []RebaseRuleExternalResource {
+	var result []RebaseRuleExternalResource
+	for _, config := range c.configs {
+		for _, rule := range config.RebaseRules {
+			if rule.ExternalResource != nil {
+				result = append(result, *rule.ExternalResource)
+			}
+		}
+	}
+	return result
+}
+
 func (c Conf) DiffAgainstLastAppliedFieldExclusionMods() []ctlres.FieldRemoveMod {
 	var mods []ctlres.FieldRemoveMod
 	for _, config := range c.configs {
@@ -112,6 +141,14 @@ func (c Conf) DiffAgainstExistingFieldExclusionMods() []ctlres.FieldRemoveMod {
 	return mods
 }
 
+func (c Conf) DiffAgainstExistingManagedFieldsExclusionRules() []DiffAgainstExistingManagedFieldsExclusionRule {
+	var result []DiffAgainstExistingManagedFieldsExclusionRule
+	for _, config := range c.configs {
+		result = append(result, config.DiffAgainstExistingManagedFieldsExclusionRules...)
+	}
+	return result
+}
+
 func (c Conf) OwnershipLabelMods() func(kvs map[string]string) []ctlres.StringMapAppendMod {
 	return func(kvs map[string]string) []ctlres.StringMapAppendMod {
 		var mods []ctlres.StringMapAppendMod
@@ -171,6 +208,37 @@ func (c Conf) DiffMaskRules() []DiffMaskRule {
 	return result
 }
 
+func (c Conf) ApplyStrategyRules() []ApplyStrategyRule {
+	var result []ApplyStrategyRule
+	for _, config := range c.configs {
+		result = append(result, config.ApplyStrategyRules...)
+	}
+	return result
+}
+
+// WaitTimeouts returns per-kind wait timeouts
+// (timeouts from later configs take precedence)
+func (c Conf) WaitTimeouts() []WaitTimeout {
+	var result []WaitTimeout
+	for _, config := range c.configs {
+		result = append(result, config.WaitTimeouts...)
+	}
+	return result
+}
+
+// IsManagedAnnotationDisabled returns true if any config disables
+// specified kapp managed annotation (e.g. kapp.k14s.io/identity)
+func (c Conf) IsManagedAnnotationDisabled(key string) bool {
+	for _, config := range c.configs {
+		for _, disabledKey := range config.ManagedAnnotations.Disable {
+			if disabledKey == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (c Conf) AdditionalLabels() map[string]string {
 	result := map[string]string{}
 	for _, config := range c.configs {
@@ -185,6 +253,9 @@ func (c Conf) ChangeGroupBindings() []ChangeGroupBinding {
 	var result []ChangeGroupBinding
 	for _, config := range c.configs {
 		result = append(result, config.ChangeGroupBindings...)
+		for _, binding := range config.ChangeGroupLabelBindings {
+			result = append(result, binding.AsChangeGroupBinding())
+		}
 	}
 	return result
 }
@@ -196,3 +267,42 @@ func (c Conf) ChangeRuleBindings() []ChangeRuleBinding {
 	}
 	return result
 }
+
+// Merged returns single config that is equivalent to all configs
+// (rules are concatenated in the order configs were provided)
+func (c Conf) Merged() Config {
+	result := Config{
+		APIVersion:       configAPIVersion,
+		Kind:             configKind,
+		AdditionalLabels: c.AdditionalLabels(),
+	}
+
+	for _, config := range c.configs {
+		result.RebaseRules = append(result.RebaseRules, config.RebaseRules...)
+		result.PreserveFieldRules = append(result.PreserveFieldRules, config.PreserveFieldRules...)
+		result.WaitRules = append(result.WaitRules, config.WaitRules...)
+		result.NumericEquivalenceRules = append(result.NumericEquivalenceRules, config.NumericEquivalenceRules...)
+		result.ListMergeKeys = append(result.ListMergeKeys, config.ListMergeKeys...)
+		result.OwnershipLabelRules = append(result.OwnershipLabelRules, config.OwnershipLabelRules...)
+		result.LabelScopingRules = append(result.LabelScopingRules, config.LabelScopingRules...)
+		result.TemplateRules = append(result.TemplateRules, config.TemplateRules...)
+		result.DiffMaskRules = append(result.DiffMaskRules, config.DiffMaskRules...)
+		result.PreflightRules = append(result.PreflightRules, config.PreflightRules...)
+		result.ApplyStrategyRules = append(result.ApplyStrategyRules, config.ApplyStrategyRules...)
+		result.WaitTimeouts = append(result.WaitTimeouts, config.WaitTimeouts...)
+		result.ManagedAnnotations.Disable = append(result.ManagedAnnotations.Disable, config.ManagedAnnotations.Disable...)
+
+		result.DiffAgainstLastAppliedFieldExclusionRules = append(
+			result.DiffAgainstLastAppliedFieldExclusionRules, config.DiffAgainstLastAppliedFieldExclusionRules...)
+		result.DiffAgainstExistingFieldExclusionRules = append(
+			result.DiffAgainstExistingFieldExclusionRules, config.DiffAgainstExistingFieldExclusionRules...)
+		result.DiffAgainstExistingManagedFieldsExclusionRules = append(
+			result.DiffAgainstExistingManagedFieldsExclusionRules, config.DiffAgainstExistingManagedFieldsExclusionRules...)
+
+		result.ChangeGroupBindings = append(result.ChangeGroupBindings, config.ChangeGroupBindings...)
+		result.ChangeGroupLabelBindings = append(result.ChangeGroupLabelBindings, config.ChangeGroupLabelBindings...)
+		result.ChangeRuleBindings = append(result.ChangeRuleBindings, config.ChangeRuleBindings...)
+	}
+
+	return result
+}
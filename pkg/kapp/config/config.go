@@ -5,17 +5,40 @@ package config
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"carvel.dev/kapp/pkg/kapp/version"
 	"carvel.dev/kapp/pkg/kapp/yttresmod"
 	semver "github.com/hashicorp/go-version"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/yaml"
 )
 
 const (
 	configAPIVersion = "kapp.k14s.io/v1alpha1"
 	configKind       = "Config"
+
+	// Rebase rule type that copies existing value only when
+	// new resource does not provide one (presence of the path is checked)
+	rebaseRuleTypeCopyIfNotProvided = "copyIfNotProvided"
+	// Rebase rule source that refers to rule's external resource
+	rebaseRuleSourceExternal ctlres.FieldCopyModSource = "external"
+	// Default amount of time to wait for external resource to exist
+	rebaseRuleExternalResourceDefaultTimeout = "5m"
+
+	// ManagedAnnotationIdentity is used to determine whether resource was created
+	// by kapp (vs a controller) and which API version was used to create it
+	ManagedAnnotationIdentity = "kapp.k14s.io/identity"
+	// ManagedAnnotationOriginal (with its md5 companion) is used to diff against
+	// last applied resource instead of resource stored on the cluster
+	ManagedAnnotationOriginal = "kapp.k14s.io/original"
+)
+
+var (
+	managedAnnotationsAllowedToDisable = []string{ManagedAnnotationIdentity, ManagedAnnotationOriginal}
 )
 
 type Config struct {
@@ -24,22 +47,32 @@ type Config struct {
 
 	MinimumRequiredVersion string `json:"minimumRequiredVersion,omitempty"`
 
-	RebaseRules         []RebaseRule
-	WaitRules           []WaitRule
-	OwnershipLabelRules []OwnershipLabelRule
-	LabelScopingRules   []LabelScopingRule
-	TemplateRules       []TemplateRule
-	DiffMaskRules       []DiffMaskRule
-	PreflightRules      []PreflightRule
+	RebaseRules             []RebaseRule
+	PreserveFieldRules      []PreserveFieldRule
+	NumericEquivalenceRules []NumericEquivalenceRule
+	ListMergeKeys           []ListMergeKey
+	WaitRules               []WaitRule
+	OwnershipLabelRules     []OwnershipLabelRule
+	LabelScopingRules       []LabelScopingRule
+	TemplateRules           []TemplateRule
+	DiffMaskRules           []DiffMaskRule
+	PreflightRules          []PreflightRule
+	ApplyStrategyRules      []ApplyStrategyRule
+	WaitTimeouts            []WaitTimeout
+
+	ManagedAnnotations ManagedAnnotations `json:"managedAnnotations"`
 
 	AdditionalLabels                          map[string]string
 	DiffAgainstLastAppliedFieldExclusionRules []DiffAgainstLastAppliedFieldExclusionRule
 	DiffAgainstExistingFieldExclusionRules    []DiffAgainstExistingFieldExclusionRule
 
+	DiffAgainstExistingManagedFieldsExclusionRules []DiffAgainstExistingManagedFieldsExclusionRule
+
 	// TODO additional?
 	// TODO validations
-	ChangeGroupBindings []ChangeGroupBinding
-	ChangeRuleBindings  []ChangeRuleBinding
+	ChangeGroupBindings      []ChangeGroupBinding
+	ChangeGroupLabelBindings []ChangeGroupLabelBinding
+	ChangeRuleBindings       []ChangeRuleBinding
 }
 
 type WaitRule struct {
@@ -47,6 +80,9 @@ type WaitRule struct {
 	ConditionMatchers          []WaitRuleConditionMatcher
 	ResourceMatchers           []ResourceMatcher
 	Ytt                        *WaitRuleYtt
+	// WaitForEndpoints makes matched Services wait for at least one ready endpoint
+	// (instead of evaluating conditions)
+	WaitForEndpoints bool
 }
 
 type WaitRuleConditionMatcher struct {
@@ -79,6 +115,46 @@ type RebaseRule struct {
 	Sources []ctlres.FieldCopyModSource
 
 	Ytt *RebaseRuleYtt
+
+	ExternalResource *RebaseRuleExternalResource
+}
+
+// RebaseRuleExternalResource refers to a resource that is not part of the app
+// (e.g. secret created by a controller). Its values are available to rebase
+// rule via 'external' source once it exists (deploy waits for it up to timeout).
+type RebaseRuleExternalResource struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	// Timeout defaults to 5m
+	Timeout string
+}
+
+// PreserveFieldRule keeps existing values of fields (typically set by controllers)
+// unless they are provided by the user. It's a shorthand for rebase rule
+// of type copy with sources [new, existing].
+type PreserveFieldRule struct {
+	ResourceMatchers []ResourceMatcher
+	Paths            []ctlres.Path
+	// AnnotationKeys are globs (e.g. 'deployment.kubernetes.io/*')
+	// of annotations that are preserved unless provided
+	AnnotationKeys []string
+}
+
+// NumericEquivalenceRule ignores differences between equivalent
+// quantity representations (e.g. 1000m and 1) in fields under given paths
+type NumericEquivalenceRule struct {
+	ResourceMatchers []ResourceMatcher
+	Paths            []ctlres.Path
+}
+
+// ListMergeKey matches entries of a list under path by value
+// of a key (e.g. env vars by name) instead of by their position
+type ListMergeKey struct {
+	ResourceMatchers []ResourceMatcher
+	Path             ctlres.Path
+	Key              string
 }
 
 type RebaseRuleYtt struct {
@@ -103,6 +179,15 @@ type DiffAgainstExistingFieldExclusionRule struct {
 	Path             ctlres.Path
 }
 
+// DiffAgainstExistingManagedFieldsExclusionRule excludes fields that are
+// owned (according to metadata.managedFields) only by field managers
+// other than kapp from diff against existing resource
+type DiffAgainstExistingManagedFieldsExclusionRule struct {
+	ResourceMatchers []ResourceMatcher
+	// Field managers that are considered to be kapp (defaults to kapp's own field manager)
+	KappManagers []string `json:"kappManagers"`
+}
+
 type OwnershipLabelRule struct {
 	ResourceMatchers []ResourceMatcher
 	Path             ctlres.Path
@@ -140,12 +225,58 @@ type ChangeGroupBinding struct {
 	ResourceMatchers []ResourceMatcher
 }
 
+// ChangeGroupLabelBinding places resources that have a label into a change group
+// named after label's value (e.g. label 'tier: db' results in 'db' change group)
+type ChangeGroupLabelBinding struct {
+	LabelKey string `json:"labelKey"`
+	// NamePrefix is prepended to label's value (e.g. 'tier.example.com/')
+	NamePrefix string `json:"namePrefix"`
+}
+
+func (b ChangeGroupLabelBinding) Validate() error {
+	if len(b.LabelKey) == 0 {
+		return fmt.Errorf("Expected label key to be specified")
+	}
+	return nil
+}
+
+// AsChangeGroupBinding uses label placeholder in change group name
+// to only match resources that have the label
+func (b ChangeGroupLabelBinding) AsChangeGroupBinding() ChangeGroupBinding {
+	return ChangeGroupBinding{
+		Name: b.NamePrefix + "{label:" + b.LabelKey + "}",
+		ResourceMatchers: []ResourceMatcher{
+			{HasLabelMatcher: &HasLabelMatcher{Keys: []string{b.LabelKey}}},
+		},
+	}
+}
+
 type ChangeRuleBinding struct {
 	Rules            []string
 	IgnoreIfCyclical bool
 	ResourceMatchers []ResourceMatcher
 }
 
+// ApplyStrategyRule sets create and/or update strategies
+// for resources that do not specify them via annotations
+type ApplyStrategyRule struct {
+	ResourceMatchers []ResourceMatcher
+	CreateStrategy   string `json:"createStrategy"`
+	UpdateStrategy   string `json:"updateStrategy"`
+}
+
+// WaitTimeout sets default wait timeout for resources of a kind
+// (kapp.k14s.io/wait-timeout annotation takes precedence)
+type WaitTimeout struct {
+	Kind    string
+	Timeout string
+}
+
+// ManagedAnnotations controls which annotations kapp adds to resources
+type ManagedAnnotations struct {
+	Disable []string
+}
+
 type PreflightRule struct {
 	Name   string
 	Config map[string]any
@@ -223,12 +354,130 @@ func (c Config) Validate() error {
 		}
 	}
 
+	for i, rule := range c.PreserveFieldRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating preserve field rule %d: %w", i, err)
+		}
+	}
+
+	for i, rule := range c.NumericEquivalenceRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating numeric equivalence rule %d: %w", i, err)
+		}
+	}
+
+	for i, key := range c.ListMergeKeys {
+		err := key.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating list merge key %d: %w", i, err)
+		}
+	}
+
+	for i, binding := range c.ChangeGroupLabelBindings {
+		err := binding.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating change group label binding %d: %w", i, err)
+		}
+	}
+
+	for i, rule := range c.ApplyStrategyRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating apply strategy rule %d: %w", i, err)
+		}
+	}
+
+	for i, waitTimeout := range c.WaitTimeouts {
+		err := waitTimeout.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating wait timeout %d: %w", i, err)
+		}
+	}
+
+	err := c.ManagedAnnotations.Validate()
+	if err != nil {
+		return fmt.Errorf("Validating managed annotations: %w", err)
+	}
+
+	return c.validateResourceMatchers()
+}
+
+func (c Config) validateResourceMatchers() error {
+	type ruleMatchers struct {
+		Desc     string
+		Matchers ResourceMatchers
+	}
+
+	var allMatchers []ruleMatchers
+
+	for i, rule := range c.RebaseRules {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("rebase rule %d", i), rule.ResourceMatchers})
+	}
+	for i, rule := range c.PreserveFieldRules {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("preserve field rule %d", i), rule.ResourceMatchers})
+	}
+	for i, rule := range c.NumericEquivalenceRules {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("numeric equivalence rule %d", i), rule.ResourceMatchers})
+	}
+	for i, key := range c.ListMergeKeys {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("list merge key %d", i), key.ResourceMatchers})
+	}
+	for i, rule := range c.WaitRules {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("wait rule %d", i), rule.ResourceMatchers})
+	}
+	for i, rule := range c.OwnershipLabelRules {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("ownership label rule %d", i), rule.ResourceMatchers})
+	}
+	for i, rule := range c.LabelScopingRules {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("label scoping rule %d", i), rule.ResourceMatchers})
+	}
+	for i, rule := range c.TemplateRules {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("template rule %d", i), rule.ResourceMatchers})
+		for j, objRef := range rule.AffectedResources.ObjectReferences {
+			allMatchers = append(allMatchers, ruleMatchers{
+				fmt.Sprintf("template rule %d object reference %d", i, j), objRef.ResourceMatchers})
+		}
+	}
+	for i, rule := range c.DiffMaskRules {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("diff mask rule %d", i), rule.ResourceMatchers})
+	}
+	for i, rule := range c.DiffAgainstLastAppliedFieldExclusionRules {
+		allMatchers = append(allMatchers, ruleMatchers{
+			fmt.Sprintf("diff against last applied field exclusion rule %d", i), rule.ResourceMatchers})
+	}
+	for i, rule := range c.DiffAgainstExistingFieldExclusionRules {
+		allMatchers = append(allMatchers, ruleMatchers{
+			fmt.Sprintf("diff against existing field exclusion rule %d", i), rule.ResourceMatchers})
+	}
+	for i, rule := range c.DiffAgainstExistingManagedFieldsExclusionRules {
+		allMatchers = append(allMatchers, ruleMatchers{
+			fmt.Sprintf("diff against existing managed fields exclusion rule %d", i), rule.ResourceMatchers})
+	}
+	for i, rule := range c.ApplyStrategyRules {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("apply strategy rule %d", i), rule.ResourceMatchers})
+	}
+	for i, binding := range c.ChangeGroupBindings {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("change group binding %d", i), binding.ResourceMatchers})
+	}
+	for i, binding := range c.ChangeRuleBindings {
+		allMatchers = append(allMatchers, ruleMatchers{fmt.Sprintf("change rule binding %d", i), binding.ResourceMatchers})
+	}
+
+	for _, rm := range allMatchers {
+		err := rm.Matchers.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating %s: %w", rm.Desc, err)
+		}
+	}
+
 	return nil
 }
 
 func (r RebaseRule) Validate() error {
 	if r.Ytt != nil {
-		if len(r.Path) > 0 || len(r.Paths) > 0 || len(r.Type) > 0 || len(r.Sources) > 0 {
+		if len(r.Path) > 0 || len(r.Paths) > 0 || len(r.Type) > 0 || len(r.Sources) > 0 || r.ExternalResource != nil {
 			return fmt.Errorf("Expected only resourceMatchers specified with ytt configuration")
 		}
 		return nil
@@ -239,6 +488,142 @@ func (r RebaseRule) Validate() error {
 	if len(r.Path) == 0 && len(r.Paths) == 0 {
 		return fmt.Errorf("Expected either path or paths to be specified")
 	}
+	if r.Type == rebaseRuleTypeCopyIfNotProvided && len(r.Sources) > 0 {
+		return fmt.Errorf("Expected sources to not be specified for type %s (existing value is used when new resource does not provide it)", rebaseRuleTypeCopyIfNotProvided)
+	}
+
+	var usesExternalSource bool
+	for _, src := range r.Sources {
+		if src == rebaseRuleSourceExternal {
+			usesExternalSource = true
+		}
+	}
+
+	if r.ExternalResource == nil {
+		if usesExternalSource {
+			return fmt.Errorf("Expected externalResource to be specified when using source '%s'", rebaseRuleSourceExternal)
+		}
+		return nil
+	}
+	if r.Type != "copy" {
+		return fmt.Errorf("Expected type copy to be used with externalResource")
+	}
+	if !usesExternalSource {
+		return fmt.Errorf("Expected sources to include '%s' when externalResource is specified", rebaseRuleSourceExternal)
+	}
+	err := r.ExternalResource.Validate()
+	if err != nil {
+		return fmt.Errorf("Validating externalResource: %w", err)
+	}
+	return nil
+}
+
+func (r RebaseRuleExternalResource) Validate() error {
+	if len(r.APIVersion) == 0 || len(r.Kind) == 0 || len(r.Name) == 0 {
+		return fmt.Errorf("Expected apiVersion, kind and name to be specified")
+	}
+	_, err := r.Duration()
+	return err
+}
+
+func (r RebaseRuleExternalResource) Duration() (time.Duration, error) {
+	timeout := r.Timeout
+	if len(timeout) == 0 {
+		timeout = rebaseRuleExternalResourceDefaultTimeout
+	}
+	return WaitTimeout{Timeout: timeout}.Duration()
+}
+
+// AsResource returns resource stub that identifies external resource
+func (r RebaseRuleExternalResource) AsResource() ctlres.Resource {
+	return ctlres.NewResourceUnstructured(unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": r.APIVersion,
+			"kind":       r.Kind,
+			"metadata": map[string]interface{}{
+				"namespace": r.Namespace,
+				"name":      r.Name,
+			},
+		},
+	}, ctlres.ResourceType{})
+}
+
+func (r RebaseRuleExternalResource) Source() ctlres.FieldCopyModSource {
+	return ctlres.NewFieldCopyModSourceExternal(r.AsResource())
+}
+
+func (r PreserveFieldRule) Validate() error {
+	if len(r.Paths) == 0 && len(r.AnnotationKeys) == 0 {
+		return fmt.Errorf("Expected at least one path or annotation key to be specified")
+	}
+	for _, key := range r.AnnotationKeys {
+		if len(key) == 0 {
+			return fmt.Errorf("Expected annotation key to be non-empty")
+		}
+	}
+	return nil
+}
+
+func (r NumericEquivalenceRule) Validate() error {
+	if len(r.Paths) == 0 {
+		return fmt.Errorf("Expected at least one path to be specified")
+	}
+	return nil
+}
+
+func (k ListMergeKey) Validate() error {
+	if len(k.Key) == 0 {
+		return fmt.Errorf("Expected key to be specified")
+	}
+	if len(k.Path) == 0 {
+		return fmt.Errorf("Expected path to be specified")
+	}
+	if k.Path[len(k.Path)-1].MapKey == nil {
+		return fmt.Errorf("Expected path to end with a map key (field holding a list)")
+	}
+	return nil
+}
+
+func (r ApplyStrategyRule) Validate() error {
+	if len(r.CreateStrategy) == 0 && len(r.UpdateStrategy) == 0 {
+		return fmt.Errorf("Expected either createStrategy or updateStrategy to be specified")
+	}
+	return nil
+}
+
+func (t WaitTimeout) Validate() error {
+	if len(t.Kind) == 0 {
+		return fmt.Errorf("Expected kind to be specified")
+	}
+	_, err := t.Duration()
+	return err
+}
+
+func (t WaitTimeout) Duration() (time.Duration, error) {
+	dur, err := time.ParseDuration(t.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("Parsing timeout: %w", err)
+	}
+	if dur <= 0 {
+		return 0, fmt.Errorf("Expected timeout to be positive")
+	}
+	return dur, nil
+}
+
+func (a ManagedAnnotations) Validate() error {
+	for _, key := range a.Disable {
+		var found bool
+		for _, allowedKey := range managedAnnotationsAllowedToDisable {
+			if key == allowedKey {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("Expected annotation '%s' to be one of: %s",
+				key, strings.Join(managedAnnotationsAllowedToDisable, ", "))
+		}
+	}
 	return nil
 }
 
@@ -275,7 +660,16 @@ func (r RebaseRule) AsMods() []ctlres.ResourceModWithMultiple {
 					Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
 				},
 				Path:    path,
-				Sources: r.Sources,
+				Sources: r.sources(),
+			})
+
+		case rebaseRuleTypeCopyIfNotProvided:
+			mods = append(mods, ctlres.FieldCopyMod{
+				ResourceMatcher: ctlres.AnyMatcher{
+					Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+				},
+				Path:    path,
+				Sources: []ctlres.FieldCopyModSource{ctlres.FieldCopyModSourceNew, ctlres.FieldCopyModSourceExisting},
 			})
 
 		case "remove":
@@ -287,13 +681,88 @@ func (r RebaseRule) AsMods() []ctlres.ResourceModWithMultiple {
 			})
 
 		default:
-			panic(fmt.Sprintf("Unknown rebase rule type: %s (supported: copy, remove)", r.Type)) // TODO
+			panic(fmt.Sprintf("Unknown rebase rule type: %s (supported: copy, copyIfNotProvided, remove)", r.Type)) // TODO
 		}
 	}
 
 	return mods
 }
 
+func (r RebaseRule) sources() []ctlres.FieldCopyModSource {
+	if r.ExternalResource == nil {
+		return r.Sources
+	}
+
+	var result []ctlres.FieldCopyModSource
+
+	for _, src := range r.Sources {
+		if src == rebaseRuleSourceExternal {
+			src = r.ExternalResource.Source()
+		}
+		result = append(result, src)
+	}
+
+	return result
+}
+
+func (r PreserveFieldRule) AsMods() []ctlres.ResourceModWithMultiple {
+	var mods []ctlres.ResourceModWithMultiple
+
+	for _, path := range r.Paths {
+		mods = append(mods, ctlres.FieldCopyMod{
+			ResourceMatcher: ctlres.AnyMatcher{
+				Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+			},
+			Path:    path,
+			Sources: []ctlres.FieldCopyModSource{ctlres.FieldCopyModSourceNew, ctlres.FieldCopyModSourceExisting},
+		})
+	}
+
+	for _, key := range r.AnnotationKeys {
+		// Only '*' is special in annotation key globs
+		keyRegex := "^" + strings.ReplaceAll(regexp.QuoteMeta(key), `\*`, ".*") + "$"
+
+		mods = append(mods, ctlres.FieldCopyMod{
+			ResourceMatcher: ctlres.AnyMatcher{
+				Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+			},
+			Path: ctlres.Path{
+				ctlres.NewPathPartFromString("metadata"),
+				ctlres.NewPathPartFromString("annotations"),
+				&ctlres.PathPart{Regex: &ctlres.PathPartRegex{Regex: &keyRegex}},
+			},
+			Sources: []ctlres.FieldCopyModSource{ctlres.FieldCopyModSourceNew, ctlres.FieldCopyModSourceExisting},
+		})
+	}
+
+	return mods
+}
+
+func (r NumericEquivalenceRule) AsMods() []ctlres.ResourceModWithMultiple {
+	var mods []ctlres.ResourceModWithMultiple
+
+	for _, path := range r.Paths {
+		mods = append(mods, ctlres.QuantityEquivalenceMod{
+			ResourceMatcher: ctlres.AnyMatcher{
+				Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+			},
+			Path: path,
+		})
+	}
+
+	return mods
+}
+
+func (k ListMergeKey) AsMod() ctlres.ListMergeKeyMod {
+	return ctlres.ListMergeKeyMod{
+		ResourceMatcher: ctlres.AnyMatcher{
+			Matchers: ResourceMatchers(k.ResourceMatchers).AsResourceMatchers(),
+		},
+		Path: k.Path,
+		Key:  k.Key,
+	}
+}
+
 func (r DiffAgainstLastAppliedFieldExclusionRule) AsMod() ctlres.FieldRemoveMod {
 	return ctlres.FieldRemoveMod{
 		ResourceMatcher: ctlres.AnyMatcher{
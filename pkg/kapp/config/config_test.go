@@ -0,0 +1,778 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagedAnnotationsDisable(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+managedAnnotations:
+  disable:
+  - kapp.k14s.io/original
+`))
+
+	_, conf, err := config.NewConfFromResources([]ctlres.Resource{configRes})
+	require.NoError(t, err)
+
+	require.True(t, conf.IsManagedAnnotationDisabled(config.ManagedAnnotationOriginal))
+	require.False(t, conf.IsManagedAnnotationDisabled(config.ManagedAnnotationIdentity))
+}
+
+func TestManagedAnnotationsDisableUnknown(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+managedAnnotations:
+  disable:
+  - kapp.k14s.io/nonce
+`))
+
+	_, err := config.NewConfigFromResource(configRes)
+	require.EqualError(t, err, "Validating config: Validating managed annotations: "+
+		"Expected annotation 'kapp.k14s.io/nonce' to be one of: kapp.k14s.io/identity, kapp.k14s.io/original")
+}
+
+func TestWaitTimeouts(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+waitTimeouts:
+- kind: StatefulSet
+  timeout: 15m
+`))
+
+	_, conf, err := config.NewConfFromResources([]ctlres.Resource{configRes})
+	require.NoError(t, err)
+
+	waitTimeouts := conf.WaitTimeouts()
+	require.Len(t, waitTimeouts, 1)
+	require.Equal(t, "StatefulSet", waitTimeouts[0].Kind)
+
+	dur, err := waitTimeouts[0].Duration()
+	require.NoError(t, err)
+	require.Equal(t, 15*time.Minute, dur)
+}
+
+func TestWaitTimeoutsInvalid(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+waitTimeouts:
+- kind: StatefulSet
+  timeout: -1m
+`))
+
+	_, err := config.NewConfigFromResource(configRes)
+	require.EqualError(t, err, "Validating config: Validating wait timeout 0: Expected timeout to be positive")
+}
+
+func TestConfMerged(t *testing.T) {
+	configRes1 := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+additionalLabels:
+  key1: val1
+  key2: val2
+waitTimeouts:
+- kind: Job
+  timeout: 5m
+`))
+
+	configRes2 := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+additionalLabels:
+  key2: val2-override
+waitTimeouts:
+- kind: StatefulSet
+  timeout: 15m
+managedAnnotations:
+  disable:
+  - kapp.k14s.io/original
+`))
+
+	_, conf, err := config.NewConfFromResources([]ctlres.Resource{configRes1, configRes2})
+	require.NoError(t, err)
+
+	merged := conf.Merged()
+	require.NoError(t, merged.Validate())
+	require.Equal(t, map[string]string{"key1": "val1", "key2": "val2-override"}, merged.AdditionalLabels)
+	require.Equal(t, []config.WaitTimeout{{Kind: "Job", Timeout: "5m"}, {Kind: "StatefulSet", Timeout: "15m"}}, merged.WaitTimeouts)
+	require.Equal(t, []string{config.ManagedAnnotationOriginal}, merged.ManagedAnnotations.Disable)
+}
+
+func TestPreserveFieldRules(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preserveFieldRules:
+- paths:
+  - [spec, ports, {allIndexes: true}, nodePort]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Service}
+- paths:
+  - [spec, volumeName]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: PersistentVolumeClaim}
+`))
+
+	_, conf, err := config.NewConfFromResources([]ctlres.Resource{configRes})
+	require.NoError(t, err)
+
+	applyMods := func(newRes, existingRes ctlres.Resource) ctlres.Resource {
+		res := newRes.DeepCopy()
+		srcs := map[ctlres.FieldCopyModSource]ctlres.Resource{
+			ctlres.FieldCopyModSourceNew:      newRes,
+			ctlres.FieldCopyModSourceExisting: existingRes,
+		}
+		for _, mod := range conf.RebaseMods() {
+			require.NoError(t, mod.ApplyFromMultiple(res, srcs))
+		}
+		return res
+	}
+
+	newSvc := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  type: NodePort
+  ports:
+  - port: 80
+  - port: 443
+    nodePort: 30443
+`))
+	existingSvc := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  type: NodePort
+  ports:
+  - port: 80
+    nodePort: 30080
+  - port: 443
+    nodePort: 31443
+`))
+
+	svcBs, err := applyMods(newSvc, existingSvc).AsYAMLBytes()
+	require.NoError(t, err)
+	require.YAMLEq(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  type: NodePort
+  ports:
+  - port: 80
+    nodePort: 30080
+  - port: 443
+    nodePort: 30443
+`, string(svcBs))
+
+	newPVC := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: pvc
+spec:
+  accessModes: [ReadWriteOnce]
+`))
+	existingPVC := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: pvc
+spec:
+  accessModes: [ReadWriteOnce]
+  volumeName: pv-123
+`))
+
+	pvcBs, err := applyMods(newPVC, existingPVC).AsYAMLBytes()
+	require.NoError(t, err)
+	require.YAMLEq(t, `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: pvc
+spec:
+  accessModes: [ReadWriteOnce]
+  volumeName: pv-123
+`, string(pvcBs))
+}
+
+func TestPreserveFieldRulesWithoutPaths(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preserveFieldRules:
+- resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Service}
+`))
+
+	_, err := config.NewConfigFromResource(configRes)
+	require.EqualError(t, err, "Validating config: Validating preserve field rule 0: Expected at least one path or annotation key to be specified")
+}
+
+func TestPreserveFieldRulesWithAnnotationKeys(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preserveFieldRules:
+- annotationKeys:
+  - deployment.kubernetes.io/*
+  - "*.example.com/owner"
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: apps/v1, kind: Deployment}
+`))
+
+	_, conf, err := config.NewConfFromResources([]ctlres.Resource{configRes})
+	require.NoError(t, err)
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  annotations:
+    team.example.com/owner: provided
+`))
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  annotations:
+    deployment.kubernetes.io/revision: "3"
+    team.example.com/owner: existing
+    other.example.com/owner: existing
+    unrelated: existing
+`))
+
+	res := newRes.DeepCopy()
+	srcs := map[ctlres.FieldCopyModSource]ctlres.Resource{
+		ctlres.FieldCopyModSourceNew:      newRes,
+		ctlres.FieldCopyModSourceExisting: existingRes,
+	}
+	for _, mod := range conf.RebaseMods() {
+		require.NoError(t, mod.ApplyFromMultiple(res, srcs))
+	}
+
+	// Provided annotations are preferred over existing ones
+	require.Equal(t, map[string]string{
+		"deployment.kubernetes.io/revision": "3",
+		"team.example.com/owner":            "provided",
+		"other.example.com/owner":           "existing",
+	}, res.Annotations())
+}
+
+func TestNumericEquivalenceRules(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+numericEquivalenceRules:
+- paths:
+  - [spec, template, spec, containers, {allIndexes: true}, resources]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: apps/v1, kind: Deployment}
+`))
+
+	_, conf, err := config.NewConfFromResources([]ctlres.Resource{configRes})
+	require.NoError(t, err)
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        resources:
+          requests:
+            cpu: 1000m
+            memory: 1Gi
+          limits:
+            cpu: 2
+            memory: 2Gi
+`))
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        resources:
+          requests:
+            cpu: "1"
+            memory: 1024Mi
+          limits:
+            cpu: 1500m
+            memory: 2Gi
+`))
+
+	res := newRes.DeepCopy()
+	srcs := map[ctlres.FieldCopyModSource]ctlres.Resource{
+		ctlres.FieldCopyModSourceNew:      newRes,
+		ctlres.FieldCopyModSourceExisting: existingRes,
+	}
+	for _, mod := range conf.RebaseMods() {
+		require.NoError(t, mod.ApplyFromMultiple(res, srcs))
+	}
+
+	resBs, err := res.AsYAMLBytes()
+	require.NoError(t, err)
+
+	// Only equivalent quantities are replaced with existing representation
+	require.YAMLEq(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        resources:
+          requests:
+            cpu: "1"
+            memory: 1024Mi
+          limits:
+            cpu: 2
+            memory: 2Gi
+`, string(resBs))
+}
+
+func TestNumericEquivalenceRulesWithoutPaths(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+numericEquivalenceRules:
+- resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Pod}
+`))
+
+	_, err := config.NewConfigFromResource(configRes)
+	require.EqualError(t, err, "Validating config: Validating numeric equivalence rule 0: Expected at least one path to be specified")
+}
+
+func TestListMergeKeys(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+listMergeKeys:
+- path: [spec, template, spec, containers, {allIndexes: true}, env]
+  key: name
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: apps/v1, kind: Deployment}
+`))
+
+	_, conf, err := config.NewConfFromResources([]ctlres.Resource{configRes})
+	require.NoError(t, err)
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        env:
+        - name: C
+          value: c
+        - name: NEW
+          value: new
+        - name: A
+          value: a
+        - name: B
+          value: b2
+`))
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        env:
+        - name: A
+          value: a
+        - name: B
+          value: b
+        - name: C
+          value: c
+`))
+
+	res := newRes.DeepCopy()
+	srcs := map[ctlres.FieldCopyModSource]ctlres.Resource{
+		ctlres.FieldCopyModSourceNew:      newRes,
+		ctlres.FieldCopyModSourceExisting: existingRes,
+	}
+	for _, mod := range conf.RebaseMods() {
+		require.NoError(t, mod.ApplyFromMultiple(res, srcs))
+	}
+
+	resBs, err := res.AsYAMLBytes()
+	require.NoError(t, err)
+
+	// Matched entries follow existing order, unmatched entry keeps its position
+	require.YAMLEq(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        env:
+        - name: A
+          value: a
+        - name: NEW
+          value: new
+        - name: B
+          value: b2
+        - name: C
+          value: c
+`, string(resBs))
+}
+
+func TestListMergeKeysReorderedOnlyHasNoDiff(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+listMergeKeys:
+- path: [spec, template, spec, containers, {allIndexes: true}, env]
+  key: name
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: apps/v1, kind: Deployment}
+`))
+
+	_, conf, err := config.NewConfFromResources([]ctlres.Resource{configRes})
+	require.NoError(t, err)
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        env:
+        - {name: B, value: b}
+        - {name: A, value: a}
+`))
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        env:
+        - {name: A, value: a}
+        - {name: B, value: b}
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), nil, nil, ctldiff.ChangeOpts{})
+
+	change, err := changeFactory.NewExactChange(existingRes, newRes)
+	require.NoError(t, err)
+	require.Equal(t, ctldiff.ChangeOpKeep, change.Op())
+}
+
+func TestListMergeKeysWithoutKey(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+listMergeKeys:
+- path: [spec, template, spec, containers, {allIndexes: true}, env]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: apps/v1, kind: Deployment}
+`))
+
+	_, err := config.NewConfigFromResource(configRes)
+	require.EqualError(t, err, "Validating config: Validating list merge key 0: Expected key to be specified")
+}
+
+func TestRebaseRuleCopyIfNotProvided(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+rebaseRules:
+- path: [spec, replicas]
+  type: copyIfNotProvided
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: apps/v1, kind: Deployment}
+`))
+
+	_, conf, err := config.NewConfFromResources([]ctlres.Resource{configRes})
+	require.NoError(t, err)
+
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 5
+`))
+
+	applyMods := func(newRes ctlres.Resource) string {
+		res := newRes.DeepCopy()
+		srcs := map[ctlres.FieldCopyModSource]ctlres.Resource{
+			ctlres.FieldCopyModSourceNew:      newRes,
+			ctlres.FieldCopyModSourceExisting: existingRes,
+		}
+		for _, mod := range conf.RebaseMods() {
+			require.NoError(t, mod.ApplyFromMultiple(res, srcs))
+		}
+		resBs, err := res.AsYAMLBytes()
+		require.NoError(t, err)
+		return string(resBs)
+	}
+
+	notProvidedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec: {}
+`))
+
+	require.YAMLEq(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 5
+`, applyMods(notProvidedRes))
+
+	providedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 2
+`))
+
+	require.YAMLEq(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 2
+`, applyMods(providedRes))
+}
+
+func TestRebaseRuleCopyIfNotProvidedWithSources(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+rebaseRules:
+- path: [spec, replicas]
+  type: copyIfNotProvided
+  sources: [existing]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: apps/v1, kind: Deployment}
+`))
+
+	_, err := config.NewConfigFromResource(configRes)
+	require.EqualError(t, err, "Validating config: Validating rebase rule 0: "+
+		"Expected sources to not be specified for type copyIfNotProvided (existing value is used when new resource does not provide it)")
+}
+
+func TestRebaseRuleExternalResource(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+rebaseRules:
+- path: [data, token]
+  type: copy
+  sources: [new, external]
+  externalResource:
+    apiVersion: v1
+    kind: Secret
+    namespace: controller-ns
+    name: controller-token
+  resourceMatchers:
+  - kindNamespaceNameMatcher: {kind: Secret, namespace: app-ns, name: app-token}
+`))
+
+	_, conf, err := config.NewConfFromResources([]ctlres.Resource{configRes})
+	require.NoError(t, err)
+
+	externalRefs := conf.RebaseExternalResources()
+	require.Len(t, externalRefs, 1)
+
+	timeout, err := externalRefs[0].Duration()
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Minute, timeout)
+
+	externalRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: controller-token
+  namespace: controller-ns
+data:
+  token: dG9rZW4=
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), nil, nil, ctldiff.ChangeOpts{}).
+		WithExternalRebaseSources(map[ctlres.FieldCopyModSource]ctlres.Resource{
+			externalRefs[0].Source(): externalRes,
+		})
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-token
+  namespace: app-ns
+data: {}
+`))
+
+	// Rebase is applied even though resource does not exist yet
+	change, err := changeFactory.NewChangeAgainstLastApplied(nil, newRes)
+	require.NoError(t, err)
+
+	resBs, err := change.NewResource().AsYAMLBytes()
+	require.NoError(t, err)
+
+	require.YAMLEq(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-token
+  namespace: app-ns
+data:
+  token: dG9rZW4=
+`, string(resBs))
+}
+
+func TestRebaseRuleExternalResourceInvalid(t *testing.T) {
+	exs := []struct {
+		Rule string
+		Err  string
+	}{
+		{
+			Rule: `
+- path: [data, token]
+  type: copy
+  sources: [external]`,
+			Err: "Expected externalResource to be specified when using source 'external'",
+		},
+		{
+			Rule: `
+- path: [data, token]
+  type: copy
+  sources: [new, existing]
+  externalResource: {apiVersion: v1, kind: Secret, name: token}`,
+			Err: "Expected sources to include 'external' when externalResource is specified",
+		},
+		{
+			Rule: `
+- path: [data, token]
+  type: remove
+  externalResource: {apiVersion: v1, kind: Secret, name: token}`,
+			Err: "Expected type copy to be used with externalResource",
+		},
+		{
+			Rule: `
+- path: [data, token]
+  type: copy
+  sources: [external]
+  externalResource: {apiVersion: v1, kind: Secret}`,
+			Err: "Validating externalResource: Expected apiVersion, kind and name to be specified",
+		},
+		{
+			Rule: `
+- path: [data, token]
+  type: copy
+  sources: [external]
+  externalResource: {apiVersion: v1, kind: Secret, name: token, timeout: soon}`,
+			Err: `Validating externalResource: Parsing timeout: time: invalid duration "soon"`,
+		},
+	}
+
+	for _, ex := range exs {
+		configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+rebaseRules:` + ex.Rule))
+
+		_, err := config.NewConfigFromResource(configRes)
+		require.EqualError(t, err, "Validating config: Validating rebase rule 0: "+ex.Err)
+	}
+}
+
+func TestApplyStrategyRules(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+applyStrategyRules:
+- updateStrategy: fallback-on-replace
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: apps/v1, kind: Deployment}
+`))
+
+	_, conf, err := config.NewConfFromResources([]ctlres.Resource{configRes})
+	require.NoError(t, err)
+
+	rules := conf.ApplyStrategyRules()
+	require.Len(t, rules, 1)
+	require.Equal(t, "fallback-on-replace", rules[0].UpdateStrategy)
+	require.Empty(t, rules[0].CreateStrategy)
+}
+
+func TestApplyStrategyRulesWithoutStrategies(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+applyStrategyRules:
+- resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: apps/v1, kind: Deployment}
+`))
+
+	_, err := config.NewConfigFromResource(configRes)
+	require.EqualError(t, err, "Validating config: Validating apply strategy rule 0: "+
+		"Expected either createStrategy or updateStrategy to be specified")
+}
@@ -90,6 +90,13 @@ rebaseRules:
   - apiVersionKindMatcher: {apiVersion: apps/v1beta2, kind: Deployment}
   - apiVersionKindMatcher: {apiVersion: extensions/v1beta1, kind: Deployment}
 
+# Only resources that are applied get updated change ID (when enabled)
+- path: [metadata, annotations, "kapp.k14s.io/change-id"]
+  type: copy
+  sources: [new, existing]
+  resourceMatchers:
+  - allMatcher: {}
+
 - path: [webhooks, {allIndexes: true}, clientConfig, caBundle]
   type: copy
   sources: [new, existing]
@@ -361,6 +368,21 @@ labelScopingRules:
           matcher: *disableDefaultLabelScopingRulesAnnMatcher
       - apiVersionKindMatcher: {apiVersion: policy/v1beta1, kind: PodDisruptionBudget}
 
+numericEquivalenceRules:
+- paths:
+  - [spec, template, spec, containers, {allIndexes: true}, resources]
+  - [spec, template, spec, initContainers, {allIndexes: true}, resources]
+  resourceMatchers: *withPodTemplate
+- paths:
+  - [spec, jobTemplate, spec, template, spec, containers, {allIndexes: true}, resources]
+  - [spec, jobTemplate, spec, template, spec, initContainers, {allIndexes: true}, resources]
+  resourceMatchers: *cronJob
+- paths:
+  - [spec, containers, {allIndexes: true}, resources]
+  - [spec, initContainers, {allIndexes: true}, resources]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Pod}
+
 templateRules:
 - resourceMatchers:
   - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
@@ -583,6 +605,19 @@ changeGroupBindings:
 - name: change-groups.kapp.k14s.io/kapp-controller-packageinstall
   resourceMatchers: *packageInstallMatchers
 
+# Could be used to order resources (e.g. Ingresses) after
+# cert-manager Certificates that they depend on become ready
+- name: change-groups.kapp.k14s.io/cert-manager-certificates
+  resourceMatchers:
+  - apiGroupKindMatcher: {kind: Certificate, apiGroup: cert-manager.io}
+
+# Resources (e.g. deploy markers) that should be applied
+# only after all other changes are applied and converged
+- name: change-groups.kapp.k14s.io/apply-last
+  resourceMatchers: &applyLastMatchers
+  - hasAnnotationMatcher:
+      keys: [kapp.k14s.io/apply-last]
+
 changeRuleBindings:
 # Insert CRDs before all CRs
 - rules:
@@ -713,6 +748,16 @@ changeRuleBindings:
               - anyMatcher: {matchers: *rbacMatchers}
               - anyMatcher: {matchers: *podRelatedMatchers}
       - hasNamespaceMatcher: {}
+
+# Apply resources annotated with kapp.k14s.io/apply-last after all other changes.
+# Rules are required (not ignored if cyclical) so that conflicting rules are surfaced.
+- rules:
+  - "upsert before upserting change-groups.kapp.k14s.io/apply-last"
+  - "delete before upserting change-groups.kapp.k14s.io/apply-last"
+  resourceMatchers:
+  - notMatcher:
+      matcher:
+        anyMatcher: {matchers: *applyLastMatchers}
 `
 
 func NewDefaultConfigString() string { return defaultConfigYAML }
@@ -16,7 +16,7 @@ import (
 func TestDefaultTemplateRules(t *testing.T) {
 	_, defaultConfig, err := config.NewConfFromResourcesWithDefaults([]ctlres.Resource{})
 	require.NoError(t, err)
-	changeFactory := ctldiff.NewChangeFactory(defaultConfig.RebaseMods(), defaultConfig.DiffAgainstLastAppliedFieldExclusionMods(), defaultConfig.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{false})
+	changeFactory := ctldiff.NewChangeFactory(defaultConfig.RebaseMods(), defaultConfig.DiffAgainstLastAppliedFieldExclusionMods(), defaultConfig.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{})
 
 	testCases := []struct {
 		description  string
@@ -305,3 +305,33 @@ spec:
 		require.Equal(t, testCase.expectedDiff, diff.String())
 	}
 }
+
+func TestDefaultChangeIDRebaseRule(t *testing.T) {
+	_, defaultConfig, err := config.NewConfFromResourcesWithDefaults([]ctlres.Resource{})
+	require.NoError(t, err)
+	changeFactory := ctldiff.NewChangeFactory(defaultConfig.RebaseMods(), defaultConfig.DiffAgainstLastAppliedFieldExclusionMods(), defaultConfig.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{})
+
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  annotations:
+    kapp.k14s.io/change-id: app-change-abc
+data:
+  key: val
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  key: val
+`))
+
+	change, err := changeFactory.NewExactChange(existingRes, newRes)
+	require.NoError(t, err)
+	require.Equal(t, ctldiff.ChangeOpKeep, change.Op(), "Expected recorded change ID to not show up as a difference")
+}
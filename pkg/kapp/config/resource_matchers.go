@@ -5,6 +5,7 @@ package config
 
 import (
 	"fmt"
+	"regexp"
 
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 )
@@ -19,7 +20,9 @@ type ResourceMatcher struct {
 	APIGroupKindMatcher      *APIGroupKindMatcher
 	APIVersionKindMatcher    *APIVersionKindMatcher `json:"apiVersionKindMatcher"`
 	KindNamespaceNameMatcher *KindNamespaceNameMatcher
+	NameRegexMatcher         *NameRegexMatcher
 	HasAnnotationMatcher     *HasAnnotationMatcher
+	HasLabelMatcher          *HasLabelMatcher
 	HasNamespaceMatcher      *HasNamespaceMatcher
 	CustomResourceMatcher    *CustomResourceMatcher
 	EmptyFieldMatcher        *EmptyFieldMatcher
@@ -55,10 +58,19 @@ type KindNamespaceNameMatcher struct {
 	Name      string
 }
 
+type NameRegexMatcher struct {
+	// Regex has to match full resource name (e.g. 'app-config-[a-z0-9]{10}')
+	Regex string
+}
+
 type HasAnnotationMatcher struct {
 	Keys []string
 }
 
+type HasLabelMatcher struct {
+	Keys []string
+}
+
 type HasNamespaceMatcher struct {
 	Names []string
 }
@@ -77,6 +89,36 @@ func (ms ResourceMatchers) AsResourceMatchers() []ctlres.ResourceMatcher {
 	return result
 }
 
+func (ms ResourceMatchers) Validate() error {
+	for i, matcher := range ms {
+		err := matcher.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating resource matcher %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (m ResourceMatcher) Validate() error {
+	switch {
+	case m.AnyMatcher != nil:
+		return ResourceMatchers(m.AnyMatcher.Matchers).Validate()
+
+	case m.AndMatcher != nil:
+		return ResourceMatchers(m.AndMatcher.Matchers).Validate()
+
+	case m.NotMatcher != nil:
+		return m.NotMatcher.Matcher.Validate()
+
+	case m.NameRegexMatcher != nil:
+		_, err := m.NameRegexMatcher.compile()
+		return err
+
+	default:
+		return nil
+	}
+}
+
 func (m ResourceMatcher) AsResourceMatcher() ctlres.ResourceMatcher {
 	switch {
 	case m.AllMatcher != nil:
@@ -104,6 +146,13 @@ func (m ResourceMatcher) AsResourceMatcher() ctlres.ResourceMatcher {
 			Name:      m.KindNamespaceNameMatcher.Name,
 		}
 
+	case m.NameRegexMatcher != nil:
+		re, err := m.NameRegexMatcher.compile()
+		if err != nil {
+			panic(err.Error()) // validated when config is loaded
+		}
+		return ctlres.NameRegexMatcher{Regexp: re}
+
 	case m.APIGroupKindMatcher != nil:
 		return ctlres.APIGroupKindMatcher{
 			APIGroup: m.APIGroupKindMatcher.APIGroup,
@@ -121,6 +170,11 @@ func (m ResourceMatcher) AsResourceMatcher() ctlres.ResourceMatcher {
 			Keys: m.HasAnnotationMatcher.Keys,
 		}
 
+	case m.HasLabelMatcher != nil:
+		return ctlres.HasLabelMatcher{
+			Keys: m.HasLabelMatcher.Keys,
+		}
+
 	case m.HasNamespaceMatcher != nil:
 		return ctlres.HasNamespaceMatcher{
 			Names: m.HasNamespaceMatcher.Names,
@@ -136,3 +190,14 @@ func (m ResourceMatcher) AsResourceMatcher() ctlres.ResourceMatcher {
 		panic(fmt.Sprintf("Unknown resource matcher specified: %#v", m))
 	}
 }
+
+func (m NameRegexMatcher) compile() (*regexp.Regexp, error) {
+	if len(m.Regex) == 0 {
+		return nil, fmt.Errorf("Expected nameRegexMatcher to specify non-empty regex")
+	}
+	re, err := regexp.Compile("^(?:" + m.Regex + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("Compiling nameRegexMatcher regex '%s': %w", m.Regex, err)
+	}
+	return re, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/config"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameRegexMatcher(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+diffMaskRules:
+- path: [data]
+  resourceMatchers:
+  - nameRegexMatcher:
+      regex: "app-config-[a-z0-9]{5}"
+`))
+
+	cfg, err := config.NewConfigFromResource(configRes)
+	require.NoError(t, err)
+
+	matcher := ctlres.AnyMatcher{
+		Matchers: config.ResourceMatchers(cfg.DiffMaskRules[0].ResourceMatchers).AsResourceMatchers(),
+	}
+
+	for name, expected := range map[string]bool{
+		"app-config-ab12c":       true,
+		"app-config-ab12c-extra": false,
+		"other-app-config-ab12c": false,
+		"app-config":             false,
+	} {
+		res := ctlres.MustNewResourceFromBytes([]byte(`{"kind": "ConfigMap", "metadata": {"name": "` + name + `"}}`))
+		require.Equal(t, expected, matcher.Matches(res), "Unexpected match result for %s", name)
+	}
+}
+
+func TestNameRegexMatcherInvalidRegex(t *testing.T) {
+	configRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+changeGroupBindings:
+- name: example.com/group
+  resourceMatchers:
+  - notMatcher:
+      matcher:
+        nameRegexMatcher:
+          regex: "app-(config"
+`))
+
+	_, err := config.NewConfigFromResource(configRes)
+	require.EqualError(t, err, "Validating config: Validating change group binding 0: "+
+		"Validating resource matcher 0: Compiling nameRegexMatcher regex 'app-(config': "+
+		"error parsing regexp: missing closing ): `^(?:app-(config)$`")
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+const (
+	kappMetadataKeyPrefix = "kapp.k14s.io/"
+)
+
+// AdoptedResources keeps contents of existing resources that are adopted
+// by the app for the first time, only adding kapp's labels and annotations
+// (e.g. app label), so that provided contents are applied by subsequent deploy
+type AdoptedResources struct {
+	adoptedRs, newRs []ctlres.Resource
+}
+
+func NewAdoptedResources(adoptedRs, newRs []ctlres.Resource) AdoptedResources {
+	return AdoptedResources{adoptedRs: adoptedRs, newRs: newRs}
+}
+
+func (d AdoptedResources) Prepare() ([]ctlres.Resource, error) {
+	adoptedRsMap := existingResourcesMap(d.adoptedRs)
+
+	var result []ctlres.Resource
+
+	for _, res := range d.newRs {
+		adoptedRes, found := adoptedRsMap[ctlres.NewUniqueResourceKey(res).String()]
+		if !found {
+			result = append(result, res)
+			continue
+		}
+
+		keptRes, err := d.keepExisting(adoptedRes, res)
+		if err != nil {
+			return nil, fmt.Errorf("Keeping existing resource '%s': %w", res.Description(), err)
+		}
+
+		result = append(result, keptRes)
+	}
+
+	return result, nil
+}
+
+func (d AdoptedResources) keepExisting(adoptedRes, newRes ctlres.Resource) (ctlres.Resource, error) {
+	res, err := NewResourceWithoutServerManagedFields(adoptedRes).Resource()
+	if err != nil {
+		return nil, err
+	}
+
+	mods := []ctlres.ResourceMod{
+		ctlres.FieldRemoveMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings([]string{"status"}),
+		},
+	}
+
+	for field, kvs := range map[string]map[string]string{"labels": newRes.Labels(), "annotations": newRes.Annotations()} {
+		kappKVs := d.kappKVs(kvs)
+		if len(kappKVs) > 0 {
+			mods = append(mods, ctlres.StringMapAppendMod{
+				ResourceMatcher: ctlres.AllMatcher{},
+				Path:            ctlres.NewPathFromStrings([]string{"metadata", field}),
+				KVs:             kappKVs,
+			})
+		}
+	}
+
+	for _, mod := range mods {
+		err := mod.Apply(res)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res.SetOrigin(newRes.Origin())
+
+	return res, nil
+}
+
+func (AdoptedResources) kappKVs(kvs map[string]string) map[string]string {
+	result := map[string]string{}
+	for key, val := range kvs {
+		if strings.HasPrefix(key, kappMetadataKeyPrefix) {
+			result[key] = val
+		}
+	}
+	return result
+}
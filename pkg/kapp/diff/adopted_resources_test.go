@@ -0,0 +1,76 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdoptedResourcesKeepExistingContents(t *testing.T) {
+	adoptedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: adopted
+  namespace: default
+  uid: abc
+  resourceVersion: "123"
+  labels:
+    existing: label
+data:
+  key: existing-val
+`))
+
+	newAdoptedRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: adopted
+  namespace: default
+  labels:
+    kapp.k14s.io/app: "1"
+    new: label
+  annotations:
+    kapp.k14s.io/change-group: group
+data:
+  key: new-val
+`))
+
+	otherRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  namespace: default
+data:
+  key: new-val
+`))
+
+	result, err := ctldiff.NewAdoptedResources([]ctlres.Resource{adoptedRes}, []ctlres.Resource{newAdoptedRes, otherRes}).Prepare()
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+
+	resultBs, err := result[0].AsYAMLBytes()
+	require.NoError(t, err)
+
+	require.Equal(t, `apiVersion: v1
+data:
+  key: existing-val
+kind: ConfigMap
+metadata:
+  annotations:
+    kapp.k14s.io/change-group: group
+  labels:
+    existing: label
+    kapp.k14s.io/app: "1"
+  name: adopted
+  namespace: default
+`, string(resultBs))
+
+	require.Equal(t, otherRes, result[1])
+}
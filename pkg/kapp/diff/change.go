@@ -8,6 +8,8 @@ import (
 	"github.com/cppforlife/go-patch/patch"
 )
 
+// ChangeOp describes what needs to happen to a resource
+// to go from its existing state to its new state
 type ChangeOp string
 
 const (
@@ -19,6 +21,13 @@ const (
 	ChangeOpNoop   ChangeOp = "noop"
 )
 
+// AllChangeOps lists all possible change operations
+var AllChangeOps = []ChangeOp{ChangeOpAdd, ChangeOpDelete, ChangeOpUpdate,
+	ChangeOpKeep, ChangeOpExists, ChangeOpNoop}
+
+// Change represents a calculated difference between existing
+// and new version of a resource. It could be used by library
+// consumers to build their own change previews.
 type Change interface {
 	NewOrExistingResource() ctlres.Resource
 	NewResource() ctlres.Resource
@@ -29,6 +38,14 @@ type Change interface {
 	Op() ChangeOp
 	ConfigurableTextDiff() *ConfigurableTextDiff
 	OpsDiff() OpsDiff
+	// Patch returns YAML encoded go-patch operations that transform
+	// existing resource into new resource. It is nil when there are
+	// no changes or when either of resources is not present
+	// (e.g. for add and delete changes).
+	Patch() ([]byte, error)
+	// FieldChanges returns changed fields between existing and new resource
+	// (e.g. to check if any container image changed)
+	FieldChanges() []FieldChange
 
 	IsIgnored() bool
 }
@@ -101,6 +118,12 @@ func (d *ChangeImpl) op() ChangeOp {
 		}
 	}
 
+	// Paused resources are neither updated nor deleted
+	// (even if they are no longer part of the app)
+	if d.existingRes != nil && d.isPaused() {
+		return ChangeOpKeep
+	}
+
 	if d.existingRes == nil {
 		if d.newResHasExistsAnnotation() {
 			return ChangeOpExists
@@ -131,7 +154,8 @@ func (d *ChangeImpl) isIgnoredTransient() bool {
 func (d *ChangeImpl) ConfigurableTextDiff() *ConfigurableTextDiff {
 	// diff is called very often, so memoize
 	if d.configurableTextDiff == nil {
-		d.configurableTextDiff = NewConfigurableTextDiff(d.existingRes, d.newRes, d.IsIgnored(), d.opts)
+		ignored := d.IsIgnored() || (d.existingRes != nil && d.isPaused())
+		d.configurableTextDiff = NewConfigurableTextDiff(d.existingRes, d.newRes, ignored, d.opts)
 	}
 	return d.configurableTextDiff
 }
@@ -147,11 +171,40 @@ func (d *ChangeImpl) OpsDiff() OpsDiff {
 	return *d.opsDiff
 }
 
+func (d *ChangeImpl) Patch() ([]byte, error) {
+	if d.existingRes == nil || d.newRes == nil {
+		return nil, nil
+	}
+	opsDiff := d.OpsDiff()
+	if !opsDiff.HasChanges() {
+		return nil, nil
+	}
+	return opsDiff.AsBytes()
+}
+
+func (d *ChangeImpl) FieldChanges() []FieldChange {
+	return NewFieldChanges(d.existingRes, d.newRes)
+}
+
 func (d *ChangeImpl) calculateOpsDiff() OpsDiff {
 	return OpsDiff(patch.Diff{Left: d.existingRes.UnstructuredObject(), Right: d.newRes.UnstructuredObject()}.Calculate())
 }
 
 func (d *ChangeImpl) newResHasExistsAnnotation() bool {
-	_, hasExistsAnnotation := d.newRes.Annotations()[ctlres.ExistsAnnKey]
-	return hasExistsAnnotation
+	return ctlres.IsExistsOnly(d.newRes)
+}
+
+// isPaused is based on the annotation applied to the cluster so that
+// newly added annotation is applied (together with other changes) before
+// resource becomes paused, and removed annotation unpauses resource.
+// Resource that is no longer part of the app stays paused.
+func (d *ChangeImpl) isPaused() bool {
+	if _, paused := d.existingRes.Annotations()[ctlres.PauseAnnKey]; !paused {
+		return false
+	}
+	if d.newRes == nil {
+		return true
+	}
+	_, stillPaused := d.newRes.Annotations()[ctlres.PauseAnnKey]
+	return stillPaused
 }
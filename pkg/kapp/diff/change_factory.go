@@ -4,6 +4,7 @@
 package diff
 
 import (
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 )
 
@@ -11,17 +12,49 @@ type ChangeFactory struct {
 	rebaseMods                               []ctlres.ResourceModWithMultiple
 	diffAgainstLastAppliedFieldExclusionMods []ctlres.FieldRemoveMod
 	diffAgainstExistingFieldExclusionRules   []ctlres.FieldRemoveMod
+	managedFieldsExclusionRules              []ctlconf.DiffAgainstExistingManagedFieldsExclusionRule
+	externalRebaseSources                    map[ctlres.FieldCopyModSource]ctlres.Resource
+	rebaseLog                                *RebaseLog
 	opts                                     ChangeOpts
 }
 
 type ChangeOpts struct {
 	AllowAnchoredDiff bool
+	// IncludeServerManagedFields shows fields such as metadata.resourceVersion
+	// in text diffs (they are excluded by default)
+	IncludeServerManagedFields bool
 }
 
 func NewChangeFactory(rebaseMods []ctlres.ResourceModWithMultiple,
 	diffAgainstLastAppliedFieldExclusionMods []ctlres.FieldRemoveMod, diffAgainstExistingFieldExclusionRules []ctlres.FieldRemoveMod, opts ChangeOpts) ChangeFactory {
 
-	return ChangeFactory{rebaseMods, diffAgainstLastAppliedFieldExclusionMods, diffAgainstExistingFieldExclusionRules, opts}
+	return ChangeFactory{
+		rebaseMods:                               rebaseMods,
+		diffAgainstLastAppliedFieldExclusionMods: diffAgainstLastAppliedFieldExclusionMods,
+		diffAgainstExistingFieldExclusionRules:   diffAgainstExistingFieldExclusionRules,
+		opts:                                     opts,
+	}
+}
+
+// WithManagedFieldsExclusionRules returns change factory that excludes fields
+// owned by non-kapp field managers from existing resources when diffing
+func (f ChangeFactory) WithManagedFieldsExclusionRules(rules []ctlconf.DiffAgainstExistingManagedFieldsExclusionRule) ChangeFactory {
+	f.managedFieldsExclusionRules = rules
+	return f
+}
+
+// WithExternalRebaseSources returns change factory that makes
+// external resources available to rebase rules
+func (f ChangeFactory) WithExternalRebaseSources(sources map[ctlres.FieldCopyModSource]ctlres.Resource) ChangeFactory {
+	f.externalRebaseSources = sources
+	return f
+}
+
+// WithRebaseLog returns change factory that records
+// rebase mods applied to new resources into provided log
+func (f ChangeFactory) WithRebaseLog(log *RebaseLog) ChangeFactory {
+	f.rebaseLog = log
+	return f
 }
 
 func (f ChangeFactory) NewChangeAgainstLastApplied(existingRes, newRes ctlres.Resource) (Change, error) {
@@ -35,7 +68,7 @@ func (f ChangeFactory) NewChangeAgainstLastApplied(existingRes, newRes ctlres.Re
 		// diffing against resource that is actually stored on cluster.
 		lastAppliedRes := f.NewResourceWithHistory(existingRes).LastAppliedResource()
 		if lastAppliedRes != nil {
-			rebasedLastAppliedRes, err := NewRebasedResource(existingResForRebasing, lastAppliedRes, f.rebaseMods).Resource()
+			rebasedLastAppliedRes, err := NewRebasedResource(existingResForRebasing, lastAppliedRes, f.rebaseMods).WithExternalSources(f.externalRebaseSources).Resource()
 			if err != nil {
 				return nil, err
 			}
@@ -59,11 +92,20 @@ func (f ChangeFactory) NewChangeAgainstLastApplied(existingRes, newRes ctlres.Re
 		newRes = historylessNewRes
 	}
 
-	rebasedNewRes, err := NewRebasedResource(existingResForRebasing, newRes, f.rebaseMods).Resource()
+	rebasedNewRes, err := NewRebasedResource(existingResForRebasing, newRes, f.rebaseMods).
+		WithExternalSources(f.externalRebaseSources).WithLog(f.rebaseLog).Resource()
 	if err != nil {
 		return nil, err
 	}
 
+	if len(f.managedFieldsExclusionRules) > 0 {
+		existingRes, err = NewManagedFieldsExcludedResource(
+			existingRes, existingResForRebasing, rebasedNewRes, f.managedFieldsExclusionRules).Resource()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return NewChange(existingRes, rebasedNewRes, newRes, existingResForRebasing, f.opts), nil
 }
 
@@ -86,7 +128,8 @@ func (f ChangeFactory) NewExactChange(existingRes, newRes ctlres.Resource) (Chan
 		newRes = historylessNewRes
 	}
 
-	rebasedNewRes, err := NewRebasedResource(existingRes, newRes, f.rebaseMods).Resource()
+	rebasedNewRes, err := NewRebasedResource(existingRes, newRes, f.rebaseMods).
+		WithExternalSources(f.externalRebaseSources).WithLog(f.rebaseLog).Resource()
 	if err != nil {
 		return nil, err
 	}
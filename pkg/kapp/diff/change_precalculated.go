@@ -62,4 +62,15 @@ func (d *ChangePrecalculated) ConfigurableTextDiff() *ConfigurableTextDiff {
 }
 func (d *ChangePrecalculated) OpsDiff() OpsDiff { return d.opsDiff }
 
+func (d *ChangePrecalculated) Patch() ([]byte, error) {
+	if len(d.opsDiff) == 0 {
+		return nil, nil
+	}
+	return d.opsDiff.AsBytes()
+}
+
+func (d *ChangePrecalculated) FieldChanges() []FieldChange {
+	return NewFieldChanges(d.existingRes, d.newRes)
+}
+
 func (d *ChangePrecalculated) IsIgnored() bool { return false }
@@ -48,7 +48,7 @@ metadata:
 		},
 	}
 
-	changeFactory := ctldiff.NewChangeFactory(mods, nil, nil, ctldiff.ChangeOpts{false})
+	changeFactory := ctldiff.NewChangeFactory(mods, nil, nil, ctldiff.ChangeOpts{})
 	changeSet := ctldiff.NewChangeSet([]ctlres.Resource{existingRes}, []ctlres.Resource{newRes},
 		ctldiff.ChangeSetOpts{}, changeFactory)
 
@@ -106,7 +106,7 @@ metadata:
 		},
 	}
 
-	changeFactory := ctldiff.NewChangeFactory(mods, nil, nil, ctldiff.ChangeOpts{false})
+	changeFactory := ctldiff.NewChangeFactory(mods, nil, nil, ctldiff.ChangeOpts{})
 	changeSet := ctldiff.NewChangeSet([]ctlres.Resource{existingRes}, []ctlres.Resource{newRes},
 		ctldiff.ChangeSetOpts{}, changeFactory)
 
@@ -174,7 +174,7 @@ metadata:
 		},
 	}
 
-	changeFactory := ctldiff.NewChangeFactory(rebaseMods, ignoreFieldsMods, nil, ctldiff.ChangeOpts{false})
+	changeFactory := ctldiff.NewChangeFactory(rebaseMods, ignoreFieldsMods, nil, ctldiff.ChangeOpts{})
 	changeSet := ctldiff.NewChangeSet([]ctlres.Resource{existingRes}, []ctlres.Resource{newRes},
 		ctldiff.ChangeSetOpts{AgainstLastApplied: true}, changeFactory)
 
@@ -246,7 +246,7 @@ metadata:
 		},
 	}
 
-	changeFactory := ctldiff.NewChangeFactory(rebaseMods, ignoreFieldsMods, nil, ctldiff.ChangeOpts{false})
+	changeFactory := ctldiff.NewChangeFactory(rebaseMods, ignoreFieldsMods, nil, ctldiff.ChangeOpts{})
 	changeSet := ctldiff.NewChangeSet([]ctlres.Resource{existingRes}, []ctlres.Resource{newRes},
 		ctldiff.ChangeSetOpts{AgainstLastApplied: true}, changeFactory)
 
@@ -304,7 +304,7 @@ metadata:
 		},
 	}
 
-	changeFactory := ctldiff.NewChangeFactory(mods, nil, nil, ctldiff.ChangeOpts{false})
+	changeFactory := ctldiff.NewChangeFactory(mods, nil, nil, ctldiff.ChangeOpts{})
 	changeSet := ctldiff.NewChangeSet([]ctlres.Resource{existingRes}, []ctlres.Resource{newRes},
 		ctldiff.ChangeSetOpts{}, changeFactory)
 
@@ -323,3 +323,326 @@ metadata:
 
 	require.Equal(t, expectedDiff, actualDiff, "Expected diff to match")
 }
+
+func TestChangeSet_PausedResources(t *testing.T) {
+	newConfigMap := func(name string, paused bool, val string) ctlres.Resource {
+		anns := "{}"
+		if paused {
+			anns = `{kapp.k14s.io/pause: ""}`
+		}
+		return ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: ` + name + `
+  annotations: ` + anns + `
+data:
+  key: ` + val + `
+`))
+	}
+
+	existingRs := []ctlres.Resource{
+		newConfigMap("paused-updated", true, "old-val"),
+		newConfigMap("paused-removed", true, "old-val"),
+		newConfigMap("newly-paused", false, "old-val"),
+		newConfigMap("unpaused", true, "old-val"),
+	}
+	newRs := []ctlres.Resource{
+		newConfigMap("paused-updated", true, "new-val"),
+		newConfigMap("newly-paused", true, "new-val"),
+		newConfigMap("unpaused", false, "old-val"),
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{AllowAnchoredDiff: false})
+	changeSet := ctldiff.NewChangeSet(existingRs, newRs, ctldiff.ChangeSetOpts{}, changeFactory)
+
+	changes, err := changeSet.Calculate()
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+
+	changesByName := map[string]ctldiff.Change{}
+	for _, change := range changes {
+		changesByName[change.NewOrExistingResource().Name()] = change
+	}
+
+	require.Equal(t, ctldiff.ChangeOpKeep, changesByName["paused-updated"].Op(), "Expected paused resource to be kept")
+
+	require.Equal(t, ctldiff.ChangeOpKeep, changesByName["paused-removed"].Op(), "Expected removed paused resource to be kept")
+	require.False(t, changesByName["paused-removed"].ConfigurableTextDiff().Full().HasChanges(),
+		"Expected removed paused resource to show no diff")
+
+	// Annotation has to be applied first for resource to become paused
+	require.Equal(t, ctldiff.ChangeOpUpdate, changesByName["newly-paused"].Op(), "Expected newly paused resource to be updated")
+	require.Contains(t, changesByName["newly-paused"].ConfigurableTextDiff().Full().MinimalString(), "kapp.k14s.io/pause")
+
+	require.Equal(t, ctldiff.ChangeOpUpdate, changesByName["unpaused"].Op(), "Expected unpaused resource to be updated")
+}
+
+func TestChangeSet_YAMLAnchorsAndAliases(t *testing.T) {
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels: &labels
+    app: app
+spec:
+  selector:
+    matchLabels: *labels
+  template:
+    metadata:
+      labels: *labels
+    spec:
+      containers:
+      - &container
+        name: app
+        image: app:1
+        env: &env
+        - name: key
+          value: val
+      - <<: *container
+        name: sidecar
+        env: *env
+`))
+
+	// Resources returned by API server have anchors expanded
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {"name": "app", "labels": {"app": "app"}},
+  "spec": {
+    "selector": {"matchLabels": {"app": "app"}},
+    "template": {
+      "metadata": {"labels": {"app": "app"}},
+      "spec": {
+        "containers": [
+          {"name": "app", "image": "app:1", "env": [{"name": "key", "value": "val"}]},
+          {"name": "sidecar", "image": "app:1", "env": [{"name": "key", "value": "val"}]}
+        ]
+      }
+    }
+  }
+}`))
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{AllowAnchoredDiff: false})
+	changeSet := ctldiff.NewChangeSet([]ctlres.Resource{existingRes}, []ctlres.Resource{newRes},
+		ctldiff.ChangeSetOpts{}, changeFactory)
+
+	changes, err := changeSet.Calculate()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, ctldiff.ChangeOpKeep, changes[0].Op())
+	require.False(t, changes[0].ConfigurableTextDiff().Full().HasChanges())
+
+	// Aliased nodes must not share underlying objects
+	// so that modifying one location does not affect others
+	labels := newRes.UnstructuredObject()["metadata"].(map[string]interface{})["labels"].(map[string]interface{})
+	labels["extra"] = "1"
+
+	selectorLabels := newRes.UnstructuredObject()["spec"].(map[string]interface{})["selector"].(map[string]interface{})["matchLabels"]
+	require.Equal(t, map[string]interface{}{"app": "app"}, selectorLabels)
+}
+
+func TestChangeSet_Patch(t *testing.T) {
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: my-res
+data:
+  key: new-val
+`))
+
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: my-res
+data:
+  key: old-val
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{AllowAnchoredDiff: false})
+	changeSet := ctldiff.NewChangeSet([]ctlres.Resource{existingRes}, []ctlres.Resource{newRes},
+		ctldiff.ChangeSetOpts{}, changeFactory)
+
+	changes, err := changeSet.Calculate()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, ctldiff.ChangeOpUpdate, changes[0].Op())
+
+	patchBytes, err := changes[0].Patch()
+	require.NoError(t, err)
+
+	expectedPatch := `- type: test
+  path: /data/key
+  value: old-val
+- type: replace
+  path: /data/key
+  value: new-val
+`
+	require.Equal(t, expectedPatch, string(patchBytes))
+}
+
+func TestResourceWithHistory_DriftedChange(t *testing.T) {
+	appliedRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: my-res
+  annotations:
+    kapp.k14s.io/identity: my-id
+data:
+  key: val
+`))
+
+	clusterRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: my-res
+  uid: my-uid
+  annotations:
+    kapp.k14s.io/identity: my-id
+data:
+  key: val
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{AllowAnchoredDiff: false})
+
+	appliedChange, err := changeFactory.NewResourceWithHistory(clusterRes).CalculateChange(appliedRes)
+	require.NoError(t, err)
+
+	recordedRes, _, err := changeFactory.NewResourceWithHistory(clusterRes).RecordLastAppliedResource(appliedChange)
+	require.NoError(t, err)
+
+	_, drifted := changeFactory.NewResourceWithHistory(recordedRes).DriftedChange()
+	require.False(t, drifted)
+
+	editedRes := recordedRes.DeepCopy()
+	editedRes.UnstructuredObject()["data"] = map[string]interface{}{"key": "manually-edited-val"}
+
+	driftedChange, drifted := changeFactory.NewResourceWithHistory(editedRes).DriftedChange()
+	require.True(t, drifted)
+	require.Contains(t, driftedChange.OpsDiff().MinimalString(), "manually-edited-val")
+
+	_, drifted = changeFactory.NewResourceWithHistory(appliedRes).DriftedChange()
+	require.False(t, drifted, "Expected resource without recorded history to not be drifted")
+}
+
+func TestResourceWithHistory_DriftedChangeWithoutAnnotations(t *testing.T) {
+	// Resource without annotations (e.g. when identity annotation is disabled)
+	appliedRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: my-res
+data:
+  key: val
+`))
+
+	clusterRes := appliedRes.DeepCopy()
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{AllowAnchoredDiff: false})
+
+	require.False(t, changeFactory.NewResourceWithHistory(clusterRes).HasLastApplied())
+
+	appliedChange, err := changeFactory.NewResourceWithHistory(clusterRes).CalculateChange(appliedRes)
+	require.NoError(t, err)
+
+	recordedRes, _, err := changeFactory.NewResourceWithHistory(clusterRes).RecordLastAppliedResource(appliedChange)
+	require.NoError(t, err)
+
+	recordedResWithHistory := changeFactory.NewResourceWithHistory(recordedRes)
+	require.True(t, recordedResWithHistory.HasLastApplied())
+	require.NotNil(t, recordedResWithHistory.LastAppliedResource())
+
+	_, drifted := recordedResWithHistory.DriftedChange()
+	require.False(t, drifted, "Expected empty annotations left after removing history to not be considered drift")
+}
+
+func TestChangeSet_ServerManagedFields(t *testing.T) {
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: my-res
+data:
+  key: val
+`))
+
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: my-res
+  resourceVersion: "123"
+  uid: 9e3c5b1a-0c7d-4b8f-9f8a-3b1d2c4e5f60
+  generation: 2
+  creationTimestamp: "2024-01-01T00:00:00Z"
+  managedFields:
+  - manager: kapp
+data:
+  key: val
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{})
+	changeSet := ctldiff.NewChangeSet([]ctlres.Resource{existingRes}, []ctlres.Resource{newRes},
+		ctldiff.ChangeSetOpts{}, changeFactory)
+
+	changes, err := changeSet.Calculate()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, ctldiff.ChangeOpKeep, changes[0].Op(), "Expected server managed fields to be excluded from diff")
+
+	changeFactory = ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{IncludeServerManagedFields: true})
+	changeSet = ctldiff.NewChangeSet([]ctlres.Resource{existingRes}, []ctlres.Resource{newRes},
+		ctldiff.ChangeSetOpts{}, changeFactory)
+
+	changes, err = changeSet.Calculate()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, ctldiff.ChangeOpUpdate, changes[0].Op())
+
+	actualDiff := changes[0].ConfigurableTextDiff().Full().FullString()
+	require.Contains(t, actualDiff, "resourceVersion")
+	require.Contains(t, actualDiff, "managedFields")
+}
+
+func TestChangeSet_ExternalResources(t *testing.T) {
+	newRes1 := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: external-missing
+  annotations:
+    kapp.k14s.io/external: ""
+`))
+
+	newRes2 := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: external-present
+  annotations:
+    kapp.k14s.io/external: ""
+data:
+  key: new-val
+`))
+
+	existingRes2 := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: external-present
+data:
+  key: old-val
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{AllowAnchoredDiff: false})
+	changeSet := ctldiff.NewChangeSet([]ctlres.Resource{existingRes2}, []ctlres.Resource{newRes1, newRes2},
+		ctldiff.ChangeSetOpts{}, changeFactory)
+
+	changes, err := changeSet.Calculate()
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	opsByName := map[string]ctldiff.ChangeOp{}
+	for _, change := range changes {
+		opsByName[change.NewOrExistingResource().Name()] = change.Op()
+	}
+
+	require.Equal(t, map[string]ctldiff.ChangeOp{
+		"external-missing": ctldiff.ChangeOpExists,
+		"external-present": ctldiff.ChangeOpKeep,
+	}, opsByName)
+}
@@ -4,6 +4,7 @@
 package diff
 
 import (
+	"fmt"
 	"testing"
 
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
@@ -164,3 +165,35 @@ func checkChangeDiff(t *testing.T, change Change, expectedDiff string) {
 
 	require.Equal(t, expectedDiff, actualDiffString, "Expected diff to match")
 }
+
+func TestOrphanedVersionedResources(t *testing.T) {
+	var existingRs []ctlres.Resource
+
+	for i := 1; i <= 4; i++ {
+		existingRs = append(existingRs, ctlres.MustNewResourceFromBytes([]byte(fmt.Sprintf(`
+kind: ConfigMap
+metadata:
+  name: cfg-ver-%d
+  annotations:
+    kapp.k14s.io/versioned: ""
+    kapp.k14s.io/num-versions: "2"
+`, i))))
+	}
+
+	existingRs = append(existingRs, ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: other-ver-1
+  annotations:
+    kapp.k14s.io/versioned: ""
+`)))
+
+	orphanedRs, err := OrphanedVersionedResources(existingRs)
+	require.NoError(t, err)
+
+	var orphanedNames []string
+	for _, res := range orphanedRs {
+		orphanedNames = append(orphanedNames, res.Name())
+	}
+	require.Equal(t, []string{"cfg-ver-1", "cfg-ver-2"}, orphanedNames)
+}
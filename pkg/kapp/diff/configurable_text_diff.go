@@ -57,6 +57,11 @@ func (d ConfigurableTextDiff) calculate(existingRes, newRes ctlres.Resource) Tex
 	existingLines := []string{}
 	newLines := []string{}
 
+	if !d.opts.IncludeServerManagedFields {
+		existingRes = d.withoutServerManagedFields(existingRes)
+		newRes = d.withoutServerManagedFields(newRes)
+	}
+
 	if existingRes != nil {
 		existingBytes, err := existingRes.AsYAMLBytes()
 		if err != nil {
@@ -77,3 +82,14 @@ func (d ConfigurableTextDiff) calculate(existingRes, newRes ctlres.Resource) Tex
 
 	return NewTextDiff(existingLines, newLines, d.opts.AllowAnchoredDiff)
 }
+
+func (d ConfigurableTextDiff) withoutServerManagedFields(res ctlres.Resource) ctlres.Resource {
+	if res == nil {
+		return nil
+	}
+	res, err := NewResourceWithoutServerManagedFields(res).Resource()
+	if err != nil {
+		panic("removing server managed fields") // TODO panic
+	}
+	return res
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+type FieldChangeOp string
+
+const (
+	FieldChangeOpAdd     FieldChangeOp = "add"
+	FieldChangeOpRemove  FieldChangeOp = "remove"
+	FieldChangeOpReplace FieldChangeOp = "replace"
+	// FieldChangeOpReorder indicates that items of an array were reordered;
+	// old and new values contain item keys in their order
+	FieldChangeOpReorder FieldChangeOp = "reorder"
+)
+
+// FieldChange describes single changed field (values are not masked).
+// Path uses dots for map keys and brackets for array items,
+// e.g. spec.template.spec.containers[name=app].image or spec.args[1].
+type FieldChange struct {
+	Path     string
+	Op       FieldChangeOp
+	OldValue interface{}
+	NewValue interface{}
+}
+
+// NewFieldChanges returns changed fields between existing and new resource.
+// Arrays of maps that have unique 'name' keys (e.g. containers, env, ports)
+// are compared by name so that reordered items are not reported as changed.
+func NewFieldChanges(existingRes, newRes ctlres.Resource) []FieldChange {
+	var existingObj, newObj interface{} = map[string]interface{}{}, map[string]interface{}{}
+
+	if existingRes != nil {
+		existingObj = existingRes.UnstructuredObject()
+	}
+	if newRes != nil {
+		newObj = newRes.UnstructuredObject()
+	}
+
+	var changes []FieldChange
+	fieldChangesWalker{&changes}.walk("", existingObj, newObj)
+	return changes
+}
+
+type fieldChangesWalker struct {
+	changes *[]FieldChange
+}
+
+func (w fieldChangesWalker) walk(path string, oldVal, newVal interface{}) {
+	switch typedOld := oldVal.(type) {
+	case map[string]interface{}:
+		if typedNew, ok := newVal.(map[string]interface{}); ok {
+			w.walkMap(path, typedOld, typedNew)
+			return
+		}
+
+	case []interface{}:
+		if typedNew, ok := newVal.([]interface{}); ok {
+			w.walkArray(path, typedOld, typedNew)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldVal, newVal) {
+		w.add(FieldChange{Path: path, Op: FieldChangeOpReplace, OldValue: oldVal, NewValue: newVal})
+	}
+}
+
+func (w fieldChangesWalker) walkMap(path string, oldMap, newMap map[string]interface{}) {
+	keysMap := map[string]struct{}{}
+	for key := range oldMap {
+		keysMap[key] = struct{}{}
+	}
+	for key := range newMap {
+		keysMap[key] = struct{}{}
+	}
+
+	var keys []string
+	for key := range keysMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := key
+		if len(path) > 0 {
+			keyPath = path + "." + key
+		}
+
+		oldVal, oldFound := oldMap[key]
+		newVal, newFound := newMap[key]
+
+		switch {
+		case !oldFound:
+			w.add(FieldChange{Path: keyPath, Op: FieldChangeOpAdd, NewValue: newVal})
+		case !newFound:
+			w.add(FieldChange{Path: keyPath, Op: FieldChangeOpRemove, OldValue: oldVal})
+		default:
+			w.walk(keyPath, oldVal, newVal)
+		}
+	}
+}
+
+func (w fieldChangesWalker) walkArray(path string, oldArr, newArr []interface{}) {
+	oldNames, oldByName := w.itemsByName(oldArr)
+	newNames, newByName := w.itemsByName(newArr)
+
+	if oldByName == nil || newByName == nil {
+		w.walkArrayByIndex(path, oldArr, newArr)
+		return
+	}
+
+	var commonOldNames, commonNewNames []interface{}
+
+	for _, name := range oldNames {
+		itemPath := fmt.Sprintf("%s[name=%s]", path, name)
+		if newItem, found := newByName[name]; found {
+			commonOldNames = append(commonOldNames, name)
+			w.walk(itemPath, oldByName[name], newItem)
+		} else {
+			w.add(FieldChange{Path: itemPath, Op: FieldChangeOpRemove, OldValue: oldByName[name]})
+		}
+	}
+
+	for _, name := range newNames {
+		if _, found := oldByName[name]; found {
+			commonNewNames = append(commonNewNames, name)
+		} else {
+			w.add(FieldChange{Path: fmt.Sprintf("%s[name=%s]", path, name), Op: FieldChangeOpAdd, NewValue: newByName[name]})
+		}
+	}
+
+	if !reflect.DeepEqual(commonOldNames, commonNewNames) {
+		w.add(FieldChange{Path: path, Op: FieldChangeOpReorder, OldValue: commonOldNames, NewValue: commonNewNames})
+	}
+}
+
+func (w fieldChangesWalker) walkArrayByIndex(path string, oldArr, newArr []interface{}) {
+	for i := 0; i < len(oldArr) || i < len(newArr); i++ {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+
+		switch {
+		case i >= len(oldArr):
+			w.add(FieldChange{Path: itemPath, Op: FieldChangeOpAdd, NewValue: newArr[i]})
+		case i >= len(newArr):
+			w.add(FieldChange{Path: itemPath, Op: FieldChangeOpRemove, OldValue: oldArr[i]})
+		default:
+			w.walk(itemPath, oldArr[i], newArr[i])
+		}
+	}
+}
+
+// itemsByName returns nil map if not all items are maps with unique string names
+func (fieldChangesWalker) itemsByName(arr []interface{}) ([]string, map[string]interface{}) {
+	if len(arr) == 0 {
+		return nil, map[string]interface{}{}
+	}
+
+	var names []string
+	byName := map[string]interface{}{}
+
+	for _, item := range arr {
+		typedItem, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		name, ok := typedItem["name"].(string)
+		if !ok {
+			return nil, nil
+		}
+		if _, found := byName[name]; found {
+			return nil, nil
+		}
+		names = append(names, name)
+		byName[name] = item
+	}
+
+	return names, byName
+}
+
+func (w fieldChangesWalker) add(change FieldChange) {
+	*w.changes = append(*w.changes, change)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeFieldChanges(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    removed: "true"
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+        args: [a, b]
+      - name: sidecar
+        image: sidecar:v1
+      - name: removed
+        image: removed:v1
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 2
+  paused: false
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        image: sidecar:v1
+      - name: app
+        image: app:v2
+        args: [a, c, d]
+      - name: added
+        image: added:v1
+`))
+
+	change := ctldiff.NewChange(existingRes, newRes, nil, nil, ctldiff.ChangeOpts{})
+
+	require.Equal(t, []ctldiff.FieldChange{
+		{Path: "metadata.labels", Op: ctldiff.FieldChangeOpRemove, OldValue: map[string]interface{}{"removed": "true"}},
+		{Path: "spec.paused", Op: ctldiff.FieldChangeOpAdd, NewValue: false},
+		{Path: "spec.replicas", Op: ctldiff.FieldChangeOpReplace, OldValue: float64(1), NewValue: float64(2)},
+		{Path: "spec.template.spec.containers[name=app].args[1]", Op: ctldiff.FieldChangeOpReplace, OldValue: "b", NewValue: "c"},
+		{Path: "spec.template.spec.containers[name=app].args[2]", Op: ctldiff.FieldChangeOpAdd, NewValue: "d"},
+		{Path: "spec.template.spec.containers[name=app].image", Op: ctldiff.FieldChangeOpReplace, OldValue: "app:v1", NewValue: "app:v2"},
+		{Path: "spec.template.spec.containers[name=removed]", Op: ctldiff.FieldChangeOpRemove,
+			OldValue: map[string]interface{}{"name": "removed", "image": "removed:v1"}},
+		{Path: "spec.template.spec.containers[name=added]", Op: ctldiff.FieldChangeOpAdd,
+			NewValue: map[string]interface{}{"name": "added", "image": "added:v1"}},
+		{Path: "spec.template.spec.containers", Op: ctldiff.FieldChangeOpReorder,
+			OldValue: []interface{}{"app", "sidecar"}, NewValue: []interface{}{"sidecar", "app"}},
+	}, change.FieldChanges())
+}
+
+func TestChangeFieldChangesForAddedResource(t *testing.T) {
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`))
+
+	change := ctldiff.NewChange(nil, newRes, nil, nil, ctldiff.ChangeOpts{})
+
+	require.Equal(t, []ctldiff.FieldChange{
+		{Path: "apiVersion", Op: ctldiff.FieldChangeOpAdd, NewValue: "v1"},
+		{Path: "kind", Op: ctldiff.FieldChangeOpAdd, NewValue: "ConfigMap"},
+		{Path: "metadata", Op: ctldiff.FieldChangeOpAdd, NewValue: map[string]interface{}{"name": "cm"}},
+	}, change.FieldChanges())
+}
@@ -0,0 +1,263 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// ManagedFieldsExcludedResource removes fields from existing resource
+// that are owned by non-kapp field managers, unless they are also
+// specified in new resource, so that fields written by controllers
+// do not show up in the diff.
+type ManagedFieldsExcludedResource struct {
+	existingRes ctlres.Resource
+	clusterRes  ctlres.Resource
+	newRes      ctlres.Resource
+	rules       []ctlconf.DiffAgainstExistingManagedFieldsExclusionRule
+}
+
+// NewManagedFieldsExcludedResource takes cluster resource separately from
+// existing resource since existing resource may be last applied resource
+// which does not carry managed fields.
+func NewManagedFieldsExcludedResource(existingRes, clusterRes, newRes ctlres.Resource,
+	rules []ctlconf.DiffAgainstExistingManagedFieldsExclusionRule) ManagedFieldsExcludedResource {
+
+	return ManagedFieldsExcludedResource{existingRes, clusterRes, newRes, rules}
+}
+
+func (r ManagedFieldsExcludedResource) Resource() (ctlres.Resource, error) {
+	if r.existingRes == nil || r.clusterRes == nil {
+		return r.existingRes, nil
+	}
+
+	res := r.existingRes.DeepCopy()
+
+	for _, rule := range r.rules {
+		matcher := ctlres.AnyMatcher{
+			Matchers: ctlconf.ResourceMatchers(rule.ResourceMatchers).AsResourceMatchers(),
+		}
+		if !matcher.Matches(r.clusterRes) {
+			continue
+		}
+
+		paths, err := r.excludedPaths(rule)
+		if err != nil {
+			return nil, fmt.Errorf("Calculating fields owned by non-kapp managers for resource '%s': %w",
+				r.clusterRes.Description(), err)
+		}
+
+		var newObj map[string]interface{}
+		if r.newRes != nil {
+			newObj = r.newRes.UnstructuredObject()
+		}
+
+		paths.Iterate(func(path fieldpath.Path) {
+			if _, found := r.find(newObj, path); found {
+				return // Leave field in place since it's going to be applied by kapp
+			}
+			r.remove(res.UnstructuredObject(), newObj, path)
+		})
+	}
+
+	return res, nil
+}
+
+func (r ManagedFieldsExcludedResource) excludedPaths(rule ctlconf.DiffAgainstExistingManagedFieldsExclusionRule) (*fieldpath.Set, error) {
+	kappManagers := rule.KappManagers
+	if len(kappManagers) == 0 {
+		kappManagers = []string{r.defaultKappManager()}
+	}
+
+	kappOwned := &fieldpath.Set{}
+	otherOwned := &fieldpath.Set{}
+
+	entries := (&unstructured.Unstructured{Object: r.clusterRes.UnstructuredObject()}).GetManagedFields()
+
+	for _, entry := range entries {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+
+		set := &fieldpath.Set{}
+
+		err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw))
+		if err != nil {
+			return nil, fmt.Errorf("Parsing fields of manager '%s': %w", entry.Manager, err)
+		}
+
+		if r.isKappManager(entry.Manager, kappManagers) {
+			kappOwned = kappOwned.Union(set)
+		} else {
+			otherOwned = otherOwned.Union(set)
+		}
+	}
+
+	otherOnlyOwned := otherOwned.Difference(kappOwned)
+	otherOnlyOwnedLeaves := otherOnlyOwned.Leaves()
+
+	// List items added by other managers are excluded as a whole
+	// (instead of their individual fields) since they are looked up
+	// by key fields which may otherwise be removed first
+	result := &fieldpath.Set{}
+
+	otherOnlyOwned.Iterate(func(path fieldpath.Path) {
+		last := path[len(path)-1]
+		switch {
+		case last.FieldName == nil:
+			result.Insert(path)
+		case otherOnlyOwnedLeaves.Has(path) && !r.isInExcludedListItem(result, path):
+			result.Insert(path)
+		}
+	})
+
+	return result, nil
+}
+
+func (ManagedFieldsExcludedResource) isInExcludedListItem(excluded *fieldpath.Set, path fieldpath.Path) bool {
+	for i := len(path) - 1; i > 0; i-- {
+		if path[i-1].FieldName == nil && excluded.Has(path[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultKappManager matches field manager name that API server
+// derives from default user agent (kapp does not set field manager explicitly)
+func (ManagedFieldsExcludedResource) defaultKappManager() string {
+	return strings.Split(rest.DefaultKubernetesUserAgent(), "/")[0]
+}
+
+func (ManagedFieldsExcludedResource) isKappManager(manager string, kappManagers []string) bool {
+	for _, kappManager := range kappManagers {
+		if manager == kappManager {
+			return true
+		}
+	}
+	return false
+}
+
+func (r ManagedFieldsExcludedResource) find(obj interface{}, path fieldpath.Path) (interface{}, bool) {
+	for _, pe := range path {
+		var found bool
+		obj, _, found = r.child(obj, pe)
+		if !found {
+			return nil, false
+		}
+	}
+	return obj, true
+}
+
+// remove deletes field at the path and then its parent maps that became empty
+// (as long as they are not present in new resource) to avoid showing them in the diff
+func (r ManagedFieldsExcludedResource) remove(obj, newObj map[string]interface{}, path fieldpath.Path) {
+	r.removeField(obj, path)
+
+	for parentPath := path[:len(path)-1]; len(parentPath) > 0; parentPath = parentPath[:len(parentPath)-1] {
+		parent, found := r.find(obj, parentPath)
+		if typedParent, ok := parent.(map[string]interface{}); !found || !ok || len(typedParent) > 0 {
+			return
+		}
+		if _, found := r.find(newObj, parentPath); found {
+			return
+		}
+		r.removeField(obj, parentPath)
+	}
+}
+
+func (r ManagedFieldsExcludedResource) removeField(obj map[string]interface{}, path fieldpath.Path) {
+	if len(path) < 1 {
+		return
+	}
+
+	parent, found := r.find(obj, path[:len(path)-1])
+	if !found {
+		return
+	}
+
+	last := path[len(path)-1]
+
+	switch typedParent := parent.(type) {
+	case map[string]interface{}:
+		if last.FieldName != nil {
+			delete(typedParent, *last.FieldName)
+		}
+
+	case []interface{}:
+		_, idx, found := r.child(typedParent, last)
+		if !found || len(path) < 2 {
+			return
+		}
+		// Lists are referenced by their parent, hence update via grandparent
+		grandparent, _ := r.find(obj, path[:len(path)-2])
+		if typedGrandparent, ok := grandparent.(map[string]interface{}); ok && path[len(path)-2].FieldName != nil {
+			typedGrandparent[*path[len(path)-2].FieldName] = append(typedParent[:idx:idx], typedParent[idx+1:]...)
+		}
+	}
+}
+
+func (ManagedFieldsExcludedResource) child(obj interface{}, pe fieldpath.PathElement) (interface{}, int, bool) {
+	switch {
+	case pe.FieldName != nil:
+		typedObj, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil, 0, false
+		}
+		val, found := typedObj[*pe.FieldName]
+		return val, 0, found
+
+	case pe.Key != nil:
+		typedObj, ok := obj.([]interface{})
+		if !ok {
+			return nil, 0, false
+		}
+	itemsLoop:
+		for i, item := range typedObj {
+			typedItem, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, field := range *pe.Key {
+				fieldVal, found := typedItem[field.Name]
+				if !found || !value.Equals(value.NewValueInterface(fieldVal), field.Value) {
+					continue itemsLoop
+				}
+			}
+			return item, i, true
+		}
+		return nil, 0, false
+
+	case pe.Value != nil:
+		typedObj, ok := obj.([]interface{})
+		if !ok {
+			return nil, 0, false
+		}
+		for i, item := range typedObj {
+			if value.Equals(value.NewValueInterface(item), *pe.Value) {
+				return item, i, true
+			}
+		}
+		return nil, 0, false
+
+	case pe.Index != nil:
+		typedObj, ok := obj.([]interface{})
+		if !ok || *pe.Index < 0 || *pe.Index >= len(typedObj) {
+			return nil, 0, false
+		}
+		return typedObj[*pe.Index], *pe.Index, true
+
+	default:
+		return nil, 0, false
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagedFieldsExcludedResource(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+  annotations:
+    controller-ann: val
+  managedFields:
+  - manager: kapp
+    operation: Update
+    fieldsType: FieldsV1
+    fieldsV1:
+      f:spec:
+        f:replicas: {}
+        f:template:
+          f:spec:
+            f:containers:
+              k:{"name":"app"}:
+                .: {}
+                f:image: {}
+                f:name: {}
+  - manager: controller
+    operation: Update
+    fieldsType: FieldsV1
+    fieldsV1:
+      f:metadata:
+        f:annotations:
+          .: {}
+          f:controller-ann: {}
+      f:spec:
+        f:replicas: {}
+        f:template:
+          f:spec:
+            f:containers:
+              k:{"name":"app"}:
+                f:imagePullPolicy: {}
+              k:{"name":"sidecar"}:
+                .: {}
+                f:image: {}
+                f:name: {}
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+        imagePullPolicy: Always
+      - name: sidecar
+        image: sidecar:1
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+`))
+
+	rules := []ctlconf.DiffAgainstExistingManagedFieldsExclusionRule{{
+		ResourceMatchers: []ctlconf.ResourceMatcher{{AllMatcher: &ctlconf.AllMatcher{}}},
+		KappManagers:     []string{"kapp"},
+	}}
+
+	res, err := ctldiff.NewManagedFieldsExcludedResource(existingRes, existingRes, newRes, rules).Resource()
+	require.NoError(t, err)
+
+	res, err = ctldiff.NewResourceWithoutHistory(res, []ctlres.FieldRemoveMod{{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"metadata", "managedFields"}),
+	}}).Resource()
+	require.NoError(t, err)
+
+	resBs, err := res.AsYAMLBytes()
+	require.NoError(t, err)
+
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - image: app:1
+        name: app
+`
+	require.Equal(t, expected, string(resBs))
+}
+
+func TestManagedFieldsExcludedResource_NotMatching(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  managedFields:
+  - manager: controller
+    operation: Update
+    fieldsType: FieldsV1
+    fieldsV1:
+      f:data:
+        f:key: {}
+data:
+  key: val
+`))
+
+	rules := []ctlconf.DiffAgainstExistingManagedFieldsExclusionRule{{
+		ResourceMatchers: []ctlconf.ResourceMatcher{{
+			KindNamespaceNameMatcher: &ctlconf.KindNamespaceNameMatcher{Kind: "Secret"},
+		}},
+	}}
+
+	res, err := ctldiff.NewManagedFieldsExcludedResource(existingRes, existingRes, nil, rules).Resource()
+	require.NoError(t, err)
+	require.Equal(t, "val", res.UnstructuredObject()["data"].(map[string]interface{})["key"])
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// NameSuffixedResources appends suffix to names of namespaced resources
+// (e.g. to deploy same set of resources side by side for blue/green)
+// and updates references to renamed resources based on template rules.
+type NameSuffixedResources struct {
+	resources     []ctlres.Resource
+	templateRules []ctlconf.TemplateRule
+}
+
+func NewNameSuffixedResources(resources []ctlres.Resource,
+	templateRules []ctlconf.TemplateRule) NameSuffixedResources {
+
+	return NameSuffixedResources{resources, templateRules}
+}
+
+// Apply renames resources in place and returns warnings
+// for references that could not be resolved via template rules
+func (d NameSuffixedResources) Apply(suffix string) ([]string, error) {
+	var renamedRs []ctlres.Resource
+	oldNames := map[ctlres.Resource]string{}
+
+	for _, res := range d.resources {
+		// Names of cluster scoped resources (e.g. CRDs) are typically significant
+		if len(res.Namespace()) == 0 {
+			continue
+		}
+		oldNames[res] = res.Name()
+		res.SetName(res.Name() + "-" + suffix)
+		renamedRs = append(renamedRs, res)
+	}
+
+	for _, res := range renamedRs {
+		verRes := VersionedResource{res: res, allRules: d.templateRules}
+
+		rules, err := verRes.matchingRules()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rule := range rules {
+			for _, affectedObjRef := range rule.AffectedResources.ObjectReferences {
+				mod := ctlres.ObjectRefSetMod{
+					ResourceMatcher: ctlres.AnyMatcher{
+						Matchers: ctlconf.ResourceMatchers(affectedObjRef.ResourceMatchers).AsResourceMatchers(),
+					},
+					Path:            affectedObjRef.Path,
+					ReplacementFunc: d.buildObjRefReplacementFunc(res, oldNames[res], affectedObjRef),
+				}
+
+				for _, affectedRes := range d.resources {
+					err := mod.Apply(affectedRes)
+					if err != nil {
+						return nil, fmt.Errorf("Updating references to renamed resource '%s': %w", res.Description(), err)
+					}
+				}
+			}
+		}
+	}
+
+	return d.unresolvedRefWarnings(renamedRs, oldNames), nil
+}
+
+func (d NameSuffixedResources) buildObjRefReplacementFunc(res ctlres.Resource, oldName string,
+	affectedObjRef ctlconf.TemplateAffectedObjRef) func(map[string]interface{}) error {
+
+	nameKey := affectedObjRef.NameKey
+	if len(nameKey) == 0 {
+		nameKey = "name"
+	}
+
+	return func(typedObj map[string]interface{}) error {
+		if typedObj[nameKey] != oldName {
+			return nil
+		}
+		if ns, ok := typedObj["namespace"].(string); ok && len(ns) > 0 && ns != res.Namespace() {
+			return nil
+		}
+		if kind, ok := typedObj["kind"].(string); ok && len(kind) > 0 && kind != res.Kind() {
+			return nil
+		}
+		typedObj[nameKey] = res.Name()
+		return nil
+	}
+}
+
+// unresolvedRefWarnings finds name-like fields (e.g. serviceName, secretRef.name)
+// that still point to old names of renamed resources
+func (d NameSuffixedResources) unresolvedRefWarnings(renamedRs []ctlres.Resource,
+	oldNames map[ctlres.Resource]string) []string {
+
+	oldNamesByNs := map[string]map[string]struct{}{}
+
+	for _, res := range renamedRs {
+		if oldNamesByNs[res.Namespace()] == nil {
+			oldNamesByNs[res.Namespace()] = map[string]struct{}{}
+		}
+		oldNamesByNs[res.Namespace()][oldNames[res]] = struct{}{}
+	}
+
+	var warnings []string
+
+	for _, res := range d.resources {
+		names := oldNamesByNs[res.Namespace()]
+		if len(names) == 0 {
+			continue
+		}
+
+		var paths []string
+		d.findNameRefs(res.UnstructuredObject(), "", "", names, &paths)
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			warnings = append(warnings, fmt.Sprintf(
+				"Could not resolve reference in resource '%s' at '%s' to renamed resource "+
+					"(consider adding template rule to update it)", res.Description(), path))
+		}
+	}
+
+	return warnings
+}
+
+func (d NameSuffixedResources) findNameRefs(val interface{}, path, parentKey string,
+	names map[string]struct{}, paths *[]string) {
+
+	switch typedVal := val.(type) {
+	case map[string]interface{}:
+		_, hasKind := typedVal["kind"]
+		isObjRef := hasKind || strings.HasSuffix(parentKey, "Ref")
+
+		for key, subVal := range typedVal {
+			if len(path) == 0 && key == "metadata" {
+				continue
+			}
+
+			subPath := key
+			if len(path) > 0 {
+				subPath = path + "." + key
+			}
+
+			if strVal, ok := subVal.(string); ok && (strings.HasSuffix(key, "Name") || (key == "name" && isObjRef)) {
+				if _, found := names[strVal]; found {
+					*paths = append(*paths, subPath)
+				}
+				continue
+			}
+
+			d.findNameRefs(subVal, subPath, key, names, paths)
+		}
+
+	case []interface{}:
+		for i, subVal := range typedVal {
+			d.findNameRefs(subVal, fmt.Sprintf("%s[%d]", path, i), parentKey, names, paths)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameSuffixedResources(t *testing.T) {
+	configMap := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: default
+`))
+
+	deployment := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  template:
+    spec:
+      serviceAccountName: app-sa
+      volumes:
+      - name: config
+        configMap:
+          name: app-config
+      - name: other-config
+        configMap:
+          name: external-config
+`))
+
+	serviceAccount := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app-sa
+  namespace: default
+`))
+
+	clusterRole := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: app-role
+`))
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	require.NoError(t, err)
+
+	rs := []ctlres.Resource{configMap, deployment, serviceAccount, clusterRole}
+
+	warnings, err := ctldiff.NewNameSuffixedResources(rs, conf.TemplateRules()).Apply("v2")
+	require.NoError(t, err)
+
+	require.Equal(t, "app-config-v2", configMap.Name())
+	require.Equal(t, "app-v2", deployment.Name())
+	require.Equal(t, "app-sa-v2", serviceAccount.Name())
+	require.Equal(t, "app-role", clusterRole.Name(), "Expected cluster scoped resource to not be renamed")
+
+	volumes := deployment.UnstructuredObject()["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["volumes"].([]interface{})
+	require.Equal(t, "app-config-v2", volumes[0].(map[string]interface{})["configMap"].(map[string]interface{})["name"])
+	require.Equal(t, "external-config", volumes[1].(map[string]interface{})["configMap"].(map[string]interface{})["name"])
+
+	require.Equal(t, []string{
+		"Could not resolve reference in resource 'deployment/app-v2 (apps/v1) namespace: default' at " +
+			"'spec.template.spec.serviceAccountName' to renamed resource (consider adding template rule to update it)",
+	}, warnings)
+}
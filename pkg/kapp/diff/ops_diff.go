@@ -22,15 +22,26 @@ func (l OpsDiff) MinimalMD5() string {
 func (l OpsDiff) FullString() string { return "" }
 
 func (l OpsDiff) MinimalString() string {
+	bs, err := l.AsBytes()
+	if err != nil {
+		panic(err.Error()) // TODO panic
+	}
+
+	return string(bs)
+}
+
+// AsBytes returns YAML encoded go-patch operations
+// (same format as used by ytt/bosh ops files)
+func (l OpsDiff) AsBytes() ([]byte, error) {
 	opsDefs, err := patch.NewOpDefinitionsFromOps(patch.Ops(l))
 	if err != nil {
-		panic("building opdefs") // TODO panic
+		return nil, fmt.Errorf("Building op definitions: %w", err)
 	}
 
 	bs, err := yaml.Marshal(opsDefs)
 	if err != nil {
-		panic("yamling opsdiff") // TODO panic
+		return nil, fmt.Errorf("Marshaling op definitions: %w", err)
 	}
 
-	return string(bs)
+	return bs, nil
 }
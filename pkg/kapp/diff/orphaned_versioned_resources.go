@@ -0,0 +1,36 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"sort"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// OrphanedVersionedResources returns older versions of versioned resources
+// that exceed number of versions to keep (based on the latest version).
+// Such resources are typically left behind when deploy is interrupted.
+func OrphanedVersionedResources(existingRs []ctlres.Resource) ([]ctlres.Resource, error) {
+	var result []ctlres.Resource
+
+	existingVersionedRs := existingVersionedResources(existingRs)
+
+	for _, rs := range newGroupedVersionedResources(existingVersionedRs.Versioned) {
+		numToKeep, err := ChangeSetWithVersionedRs{}.numOfResourcesToKeep(rs[len(rs)-1])
+		if err != nil {
+			return nil, err
+		}
+		if numToKeep > len(rs) {
+			numToKeep = len(rs)
+		}
+		result = append(result, rs[0:len(rs)-numToKeep]...)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Description() < result[j].Description()
+	})
+
+	return result, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// RebaseLog records rebase mods that changed new resources
+// to help explain why applied resource differs from provided one
+type RebaseLog struct {
+	entries     map[string][]string
+	entriesLock sync.Mutex
+}
+
+func NewRebaseLog() *RebaseLog {
+	return &RebaseLog{entries: map[string][]string{}}
+}
+
+func (l *RebaseLog) record(res ctlres.Resource, mod ctlres.ResourceModWithMultiple) {
+	l.entriesLock.Lock()
+	defer l.entriesLock.Unlock()
+
+	desc := res.Description()
+	modDesc := l.modDescription(mod)
+
+	// Same resource may be rebased multiple times (e.g. when calculating changes for versioned resources)
+	for _, existingModDesc := range l.entries[desc] {
+		if existingModDesc == modDesc {
+			return
+		}
+	}
+
+	l.entries[desc] = append(l.entries[desc], modDesc)
+}
+
+// Entries returns applied mods descriptions keyed by resource description
+func (l *RebaseLog) Entries() map[string][]string {
+	l.entriesLock.Lock()
+	defer l.entriesLock.Unlock()
+
+	result := map[string][]string{}
+	for desc, mods := range l.entries {
+		result[desc] = append([]string{}, mods...)
+	}
+	return result
+}
+
+func (l *RebaseLog) String() string {
+	entries := l.Entries()
+
+	var descs []string
+	for desc := range entries {
+		descs = append(descs, desc)
+	}
+	sort.Strings(descs)
+
+	var lines []string
+	for _, desc := range descs {
+		lines = append(lines, desc)
+		for _, mod := range entries[desc] {
+			lines = append(lines, "  - "+mod)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (*RebaseLog) modDescription(mod ctlres.ResourceModWithMultiple) string {
+	switch typedMod := mod.(type) {
+	case ctlres.FieldCopyMod:
+		var srcs []string
+		for _, src := range typedMod.Sources {
+			srcs = append(srcs, string(src))
+		}
+		return fmt.Sprintf("copied '%s' (sources: %s)", typedMod.Path.AsString(), strings.Join(srcs, ", "))
+	case ctlres.FieldRemoveMod:
+		return fmt.Sprintf("removed '%s'", typedMod.Path.AsString())
+	case ctlres.QuantityEquivalenceMod:
+		return fmt.Sprintf("kept equivalent quantities under '%s'", typedMod.Path.AsString())
+	case ctlres.ListMergeKeyMod:
+		return fmt.Sprintf("reordered '%s' by key '%s'", typedMod.Path.AsString(), typedMod.Key)
+	default:
+		return fmt.Sprintf("applied %T", mod)
+	}
+}
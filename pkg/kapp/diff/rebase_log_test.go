@@ -0,0 +1,74 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebaseLog(t *testing.T) {
+	mods := []ctlres.ResourceModWithMultiple{
+		ctlres.FieldCopyMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings([]string{"spec", "replicas"}),
+			Sources:         []ctlres.FieldCopyModSource{ctlres.FieldCopyModSourceNew, ctlres.FieldCopyModSourceExisting},
+		},
+		// Does not change resource since field is not present
+		ctlres.FieldCopyMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings([]string{"spec", "missing"}),
+			Sources:         []ctlres.FieldCopyModSource{ctlres.FieldCopyModSourceExisting},
+		},
+		ctlres.FieldRemoveMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings([]string{"spec", "paused"}),
+		},
+	}
+
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  replicas: 3
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  paused: false
+`))
+
+	rebaseLog := ctldiff.NewRebaseLog()
+
+	changeFactory := ctldiff.NewChangeFactory(mods, nil, nil, ctldiff.ChangeOpts{}).WithRebaseLog(rebaseLog)
+
+	_, err := changeFactory.NewExactChange(existingRes, newRes)
+	require.NoError(t, err)
+
+	// Recording same rebase again does not duplicate entries
+	_, err = changeFactory.NewExactChange(existingRes, newRes)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string][]string{
+		"deployment/app (apps/v1) namespace: default": {
+			"copied 'spec,replicas' (sources: new, existing)",
+			"removed 'spec,paused'",
+		},
+	}, rebaseLog.Entries())
+
+	require.Equal(t, `deployment/app (apps/v1) namespace: default
+  - copied 'spec,replicas' (sources: new, existing)
+  - removed 'spec,paused'`, rebaseLog.String())
+}
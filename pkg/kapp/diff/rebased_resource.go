@@ -12,6 +12,8 @@ import (
 type RebasedResource struct {
 	existingRes, newRes ctlres.Resource
 	mods                []ctlres.ResourceModWithMultiple
+	externalSources     map[ctlres.FieldCopyModSource]ctlres.Resource
+	log                 *RebaseLog
 }
 
 func NewRebasedResource(existingRes, newRes ctlres.Resource, mods []ctlres.ResourceModWithMultiple) RebasedResource {
@@ -22,6 +24,19 @@ func NewRebasedResource(existingRes, newRes ctlres.Resource, mods []ctlres.Resou
 	return RebasedResource{existingRes: existingRes, newRes: newRes, mods: mods}
 }
 
+// WithExternalSources returns rebased resource that makes external resources
+// (e.g. referenced by rebase rules via externalResource) available to mods
+func (r RebasedResource) WithExternalSources(externalSources map[ctlres.FieldCopyModSource]ctlres.Resource) RebasedResource {
+	r.externalSources = externalSources
+	return r
+}
+
+// WithLog returns rebased resource that records mods that changed it
+func (r RebasedResource) WithLog(log *RebaseLog) RebasedResource {
+	r.log = log
+	return r
+}
+
 func (r RebasedResource) Resource() (ctlres.Resource, error) {
 	if r.newRes == nil {
 		return nil, nil // nothing to rebase
@@ -30,26 +45,47 @@ func (r RebasedResource) Resource() (ctlres.Resource, error) {
 	result := r.newRes.DeepCopy()
 	resultDesc := result.Description() // capture since resource could change
 
-	if r.existingRes == nil {
-		return result, nil // all done rebasing
-	}
-
 	for _, t := range r.mods {
+		// Resources that do not exist yet are only rebased
+		// by mods that copy values from external resources
+		if r.existingRes == nil && !r.hasExternalSources(t) {
+			continue
+		}
+
 		if t.IsResourceMatching(result) {
 			// copy newRes and existingRes as they may be modified in place
 			resSources := map[ctlres.FieldCopyModSource]ctlres.Resource{
-				ctlres.FieldCopyModSourceNew:      r.newRes,
-				ctlres.FieldCopyModSourceExisting: r.existingRes,
+				ctlres.FieldCopyModSourceNew: r.newRes,
 				// Might be useful for more advanced rebase rules like ytt-based
 				ctlres.FieldCopyModSource("_current"): result,
 			}
+			if r.existingRes != nil {
+				resSources[ctlres.FieldCopyModSourceExisting] = r.existingRes
+			}
+			for src, res := range r.externalSources {
+				resSources[src] = res
+			}
+
+			var beforeRes ctlres.Resource
+			if r.log != nil {
+				beforeRes = result.DeepCopy()
+			}
 
 			err := t.ApplyFromMultiple(result, resSources)
 			if err != nil {
 				return nil, fmt.Errorf("Applying rebase rule to %s: %w", resultDesc, err)
 			}
+
+			if r.log != nil && !beforeRes.Equal(result) {
+				r.log.record(r.newRes, t)
+			}
 		}
 	}
 
 	return result, nil
 }
+
+func (r RebasedResource) hasExternalSources(mod ctlres.ResourceModWithMultiple) bool {
+	fieldCopyMod, ok := mod.(ctlres.FieldCopyMod)
+	return ok && fieldCopyMod.HasExternalSources()
+}
@@ -63,6 +63,27 @@ func (r ResourceWithHistory) LastAppliedResource() ctlres.Resource {
 	return nil
 }
 
+// HasLastApplied returns true if resource has recorded "last applied" resource
+func (r ResourceWithHistory) HasLastApplied() bool {
+	return len(r.resource.Annotations()[appliedResAnnKey]) > 0 &&
+		len(r.resource.Annotations()[appliedResDiffMD5AnnKey]) > 0
+}
+
+// DriftedChange returns change from resource as it's currently stored on the cluster
+// to the "last applied" resource iff resource was modified since it was last applied
+// (e.g. manually edited). Resources without recorded history are not considered drifted.
+func (r ResourceWithHistory) DriftedChange() (Change, bool) {
+	recalculatedLastAppliedChanges, expectedDiffMD5, _ := r.recalculateLastAppliedChange()
+
+	for _, recalculatedLastAppliedChange := range recalculatedLastAppliedChanges {
+		if recalculatedLastAppliedChange.OpsDiff().MinimalMD5() != expectedDiffMD5 {
+			return recalculatedLastAppliedChange, true
+		}
+	}
+
+	return nil, false
+}
+
 func (r ResourceWithHistory) AllowsRecordingLastApplied() bool {
 	_, found := r.resource.Annotations()[disableOriginalAnnKey]
 	return !found
@@ -165,6 +186,20 @@ func (r ResourceWithHistory) newExactHistorylessChange(existingRes, newRes ctlre
 		return nil, err
 	}
 
+	// Removing history annotations may leave empty annotations on cluster resource
+	// which should not be considered different from resource without annotations
+	for _, res := range []ctlres.Resource{existingRes, newRes} {
+		if len(res.Annotations()) == 0 {
+			err := ctlres.FieldRemoveMod{
+				ResourceMatcher: ctlres.AllMatcher{},
+				Path:            ctlres.NewPathFromStrings([]string{"metadata", "annotations"}),
+			}.Apply(res)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return r.changeFactory.NewExactChange(existingRes, newRes)
 }
 
@@ -0,0 +1,58 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+var (
+	// ServerManagedMetadataFields are set by the server on every resource
+	// and are not meaningful when comparing resource contents
+	ServerManagedMetadataFields = []string{
+		"resourceVersion",
+		"uid",
+		"generation",
+		"creationTimestamp",
+		"managedFields",
+		"selfLink",
+	}
+)
+
+type ResourceWithoutServerManagedFields struct {
+	res ctlres.Resource
+}
+
+func NewResourceWithoutServerManagedFields(res ctlres.Resource) ResourceWithoutServerManagedFields {
+	if res == nil {
+		panic("Expected res be non-nil")
+	}
+	return ResourceWithoutServerManagedFields{res}
+}
+
+func (r ResourceWithoutServerManagedFields) Resource() (ctlres.Resource, error) {
+	res := r.res.DeepCopy()
+
+	for _, mod := range r.removeMods() {
+		err := mod.Apply(res)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func (ResourceWithoutServerManagedFields) removeMods() []ctlres.FieldRemoveMod {
+	var mods []ctlres.FieldRemoveMod
+
+	for _, field := range ServerManagedMetadataFields {
+		mods = append(mods, ctlres.FieldRemoveMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings([]string{"metadata", field}),
+		})
+	}
+
+	return mods
+}
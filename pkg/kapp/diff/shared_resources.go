@@ -0,0 +1,354 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+const (
+	// Suffixed with app label value of the co-owner
+	sharedResAppliedAnnKeyPrefix = "kapp.k14s.io/shared-applied."
+
+	// Kubernetes limits total size of resource annotations (keys and values)
+	sharedResAnnsMaxSize = 256 * 1024
+)
+
+var (
+	// Fields that are set by the server and should not be carried over from cluster copy
+	sharedResServerSetFieldPaths = [][]string{
+		{"metadata", "uid"},
+		{"metadata", "resourceVersion"},
+		{"metadata", "generation"},
+		{"metadata", "creationTimestamp"},
+		{"metadata", "managedFields"},
+		{"status"},
+	}
+)
+
+// SharedResources allows multiple apps to co-own resources annotated
+// with kapp.k14s.io/shared. Each app only manages fields it applied:
+// fields applied by other apps are retained and fields that app
+// stopped applying are removed. Fields applied by each app are recorded
+// in a per-app annotation on the resource. Lists are managed as a whole
+// (i.e. last app to apply a list owns all of its items).
+type SharedResources struct {
+	existingRs, newRs        []ctlres.Resource
+	appLabelKey, appLabelVal string
+}
+
+func NewSharedResources(existingRs, newRs []ctlres.Resource, appLabelKey, appLabelVal string) SharedResources {
+	return SharedResources{existingRs: existingRs, newRs: newRs, appLabelKey: appLabelKey, appLabelVal: appLabelVal}
+}
+
+// Prepare returns new resources where shared resources are merged with their
+// cluster copies. Shared resources that are no longer part of this app but are
+// co-owned by other apps are included as well so that they are released
+// (handed over to one of other co-owners) instead of being deleted.
+func (d SharedResources) Prepare() ([]ctlres.Resource, error) {
+	exResourcesMap := existingResourcesMap(d.existingRs)
+	newResourcesMap := map[string]struct{}{}
+
+	var result []ctlres.Resource
+
+	for _, res := range d.newRs {
+		resKey := ctlres.NewUniqueResourceKey(res).String()
+		newResourcesMap[resKey] = struct{}{}
+
+		if _, found := res.Annotations()[ctlres.SharedAnnKey]; !found {
+			result = append(result, res)
+			continue
+		}
+
+		mergedRes, err := d.merge(exResourcesMap[resKey], res)
+		if err != nil {
+			return nil, fmt.Errorf("Merging shared resource '%s': %w", res.Description(), err)
+		}
+
+		result = append(result, mergedRes)
+	}
+
+	for _, exRes := range d.existingRs {
+		if _, found := newResourcesMap[ctlres.NewUniqueResourceKey(exRes).String()]; found {
+			continue
+		}
+
+		releasedRes, released, err := d.release(exRes)
+		if err != nil {
+			return nil, fmt.Errorf("Releasing shared resource '%s': %w", exRes.Description(), err)
+		}
+		if released {
+			result = append(result, releasedRes)
+		}
+	}
+
+	return result, nil
+}
+
+func (d SharedResources) merge(exRes, newRes ctlres.Resource) (ctlres.Resource, error) {
+	appliedBs, err := newRes.AsCompactBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if exRes == nil {
+		resultRes := newRes.DeepCopy()
+		return resultRes, d.setApplied(resultRes, map[string]string{d.appliedAnnKey(d.appLabelVal): string(appliedBs)})
+	}
+
+	lastApplied, err := d.lastApplied(exRes, d.appLabelVal)
+	if err != nil {
+		return nil, err
+	}
+
+	resultRes, err := d.clusterCopy(exRes)
+	if err != nil {
+		return nil, err
+	}
+
+	resultObj := resultRes.UnstructuredObject()
+	d.removeStaleFields(resultObj, lastApplied, newRes.DeepCopy().UnstructuredObject())
+
+	err = d.overlayOtherOwnersFields(resultObj, exRes)
+	if err != nil {
+		return nil, err
+	}
+
+	d.overlayFields(resultObj, newRes.DeepCopy().UnstructuredObject())
+
+	resultRes, err = ctlres.NewResourceFromBytes(d.mustMarshal(resultObj))
+	if err != nil {
+		return nil, err
+	}
+
+	return resultRes, d.setApplied(resultRes, map[string]string{d.appliedAnnKey(d.appLabelVal): string(appliedBs)})
+}
+
+// IsSharedResourceAppliedBy returns true if shared resource
+// has fields applied by app with given app label value
+func IsSharedResourceAppliedBy(res ctlres.Resource, appLabelVal string) bool {
+	if _, found := res.Annotations()[ctlres.SharedAnnKey]; !found {
+		return false
+	}
+	_, found := res.Annotations()[SharedResources{}.appliedAnnKey(appLabelVal)]
+	return found
+}
+
+// release removes fields applied by this app. If resource is labeled
+// with this app, it's handed over to another co-owner (if there is one);
+// otherwise resource is owned by another app and is always kept.
+func (d SharedResources) release(exRes ctlres.Resource) (ctlres.Resource, bool, error) {
+	if _, found := exRes.Annotations()[ctlres.SharedAnnKey]; !found {
+		return nil, false, nil
+	}
+
+	isLabeled := exRes.Labels()[d.appLabelKey] == d.appLabelVal
+
+	otherOwners := d.otherOwners(exRes)
+	if len(otherOwners) == 0 && isLabeled {
+		return nil, false, nil
+	}
+
+	lastApplied, err := d.lastApplied(exRes, d.appLabelVal)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resultRes, err := d.clusterCopy(exRes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resultObj := resultRes.UnstructuredObject()
+	d.removeStaleFields(resultObj, lastApplied, map[string]interface{}{})
+
+	err = d.overlayOtherOwnersFields(resultObj, exRes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Removal of stale fields may have removed identifying information
+	resultObj["apiVersion"] = exRes.APIVersion()
+	resultObj["kind"] = exRes.Kind()
+
+	resultRes, err = ctlres.NewResourceFromBytes(d.mustMarshal(resultObj))
+	if err != nil {
+		return nil, false, err
+	}
+
+	resultRes.SetName(exRes.Name())
+	resultRes.SetNamespace(exRes.Namespace())
+
+	err = ctlres.FieldRemoveMod{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"metadata", "annotations", d.appliedAnnKey(d.appLabelVal)}),
+	}.Apply(resultRes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if isLabeled {
+		err = ctlres.StringMapAppendMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings([]string{"metadata", "labels"}),
+			KVs:             map[string]string{d.appLabelKey: otherOwners[0]},
+		}.Apply(resultRes)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return resultRes, true, nil
+}
+
+func (d SharedResources) clusterCopy(exRes ctlres.Resource) (ctlres.Resource, error) {
+	// Last applied resource is recorded after apply, hence should not be carried over
+	resultRes, err := NewResourceWithoutHistory(exRes, nil).Resource()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range sharedResServerSetFieldPaths {
+		err := ctlres.FieldRemoveMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings(path),
+		}.Apply(resultRes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resultRes, nil
+}
+
+func (d SharedResources) lastApplied(exRes ctlres.Resource, owner string) (map[string]interface{}, error) {
+	val, found := exRes.Annotations()[d.appliedAnnKey(owner)]
+	if !found {
+		return map[string]interface{}{}, nil
+	}
+
+	var result map[string]interface{}
+
+	err := json.Unmarshal([]byte(val), &result)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling applied fields of '%s': %w", owner, err)
+	}
+
+	return result, nil
+}
+
+// removeStaleFields removes fields that were previously applied but are not applied anymore.
+// Maps are not removed as a whole since they may contain fields applied by other co-owners.
+func (d SharedResources) removeStaleFields(obj, lastApplied, applied map[string]interface{}) {
+	for key, lastVal := range lastApplied {
+		typedLastVal, lastIsMap := lastVal.(map[string]interface{})
+		typedObjVal, objIsMap := obj[key].(map[string]interface{})
+
+		val, found := applied[key]
+		if !found {
+			if lastIsMap && objIsMap {
+				d.removeStaleFields(typedObjVal, typedLastVal, map[string]interface{}{})
+				if len(typedObjVal) == 0 {
+					delete(obj, key)
+				}
+			} else {
+				delete(obj, key)
+			}
+			continue
+		}
+
+		typedVal, isMap := val.(map[string]interface{})
+		if lastIsMap && isMap && objIsMap {
+			d.removeStaleFields(typedObjVal, typedLastVal, typedVal)
+		}
+	}
+}
+
+// overlayOtherOwnersFields restores fields applied by other co-owners
+// in case they were removed as stale fields of this app
+func (d SharedResources) overlayOtherOwnersFields(obj map[string]interface{}, exRes ctlres.Resource) error {
+	for _, owner := range d.otherOwners(exRes) {
+		applied, err := d.lastApplied(exRes, owner)
+		if err != nil {
+			return err
+		}
+		d.overlayFields(obj, applied)
+	}
+	return nil
+}
+
+func (d SharedResources) otherOwners(exRes ctlres.Resource) []string {
+	var result []string
+
+	for key := range exRes.Annotations() {
+		if strings.HasPrefix(key, sharedResAppliedAnnKeyPrefix) {
+			owner := strings.TrimPrefix(key, sharedResAppliedAnnKeyPrefix)
+			if owner != d.appLabelVal {
+				result = append(result, owner)
+			}
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func (d SharedResources) overlayFields(obj, applied map[string]interface{}) {
+	for key, val := range applied {
+		typedVal, isMap := val.(map[string]interface{})
+		typedObjVal, objIsMap := obj[key].(map[string]interface{})
+
+		if isMap && objIsMap {
+			d.overlayFields(typedObjVal, typedVal)
+			continue
+		}
+
+		obj[key] = val
+	}
+}
+
+// setApplied records applied fields in annotations. Since applied fields
+// of each co-owner are recorded in full, annotations may exceed size limit
+// for large resources, hence fail with a clear error instead of an API error.
+func (d SharedResources) setApplied(res ctlres.Resource, kvs map[string]string) error {
+	err := ctlres.StringMapAppendMod{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"metadata", "annotations"}),
+		KVs:             kvs,
+	}.Apply(res)
+	if err != nil {
+		return err
+	}
+
+	var annsSize int
+	for key, val := range res.Annotations() {
+		annsSize += len(key) + len(val)
+	}
+
+	if annsSize > sharedResAnnsMaxSize {
+		return fmt.Errorf("Expected annotations (including fields applied by each co-owner "+
+			"recorded in '%s*' annotations) to not exceed %d bytes in total, but were %d bytes "+
+			"(shared resource may be too large to be co-owned)", sharedResAppliedAnnKeyPrefix, sharedResAnnsMaxSize, annsSize)
+	}
+
+	return nil
+}
+
+func (d SharedResources) appliedAnnKey(owner string) string {
+	return sharedResAppliedAnnKeyPrefix + owner
+}
+
+func (d SharedResources) mustMarshal(obj map[string]interface{}) []byte {
+	bs, err := json.Marshal(obj)
+	if err != nil {
+		panic(fmt.Sprintf("Marshaling shared resource: %s", err))
+	}
+	return bs
+}
@@ -0,0 +1,205 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedResources_MergeRetainsFieldsOfOtherApps(t *testing.T) {
+	appARes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+  namespace: default
+  labels:
+    kapp.k14s.io/app: app-a
+  annotations:
+    kapp.k14s.io/shared: ""
+    team-a: owned
+`))
+
+	rs, err := ctldiff.NewSharedResources(nil, []ctlres.Resource{appARes}, "kapp.k14s.io/app", "app-a").Prepare()
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+
+	// App B applies its own annotation to the same resource
+	appBRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+  namespace: default
+  labels:
+    kapp.k14s.io/app: app-b
+  annotations:
+    kapp.k14s.io/shared: ""
+    team-b: owned
+`))
+
+	rs, err = ctldiff.NewSharedResources(rs, []ctlres.Resource{appBRes}, "kapp.k14s.io/app", "app-b").Prepare()
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+
+	anns := rs[0].Annotations()
+	require.Equal(t, "owned", anns["team-a"])
+	require.Equal(t, "owned", anns["team-b"])
+	require.Contains(t, anns, "kapp.k14s.io/shared-applied.app-a")
+	require.Contains(t, anns, "kapp.k14s.io/shared-applied.app-b")
+	require.Equal(t, "app-b", rs[0].Labels()["kapp.k14s.io/app"])
+
+	// App B stops applying its annotation
+	appBRes = ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+  namespace: default
+  labels:
+    kapp.k14s.io/app: app-b
+  annotations:
+    kapp.k14s.io/shared: ""
+`))
+
+	rs, err = ctldiff.NewSharedResources(rs, []ctlres.Resource{appBRes}, "kapp.k14s.io/app", "app-b").Prepare()
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+
+	require.Equal(t, "owned", rs[0].Annotations()["team-a"])
+	require.NotContains(t, rs[0].Annotations(), "team-b")
+
+	// App B no longer includes resource, hence it's released to app A
+	rs, err = ctldiff.NewSharedResources(rs, nil, "kapp.k14s.io/app", "app-b").Prepare()
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+
+	require.Equal(t, "shared", rs[0].Name())
+	require.Equal(t, "default", rs[0].Namespace())
+	require.Equal(t, "app-a", rs[0].Labels()["kapp.k14s.io/app"])
+	require.Equal(t, "owned", rs[0].Annotations()["team-a"])
+	require.NotContains(t, rs[0].Annotations(), "kapp.k14s.io/shared-applied.app-b")
+	require.Contains(t, rs[0].Annotations(), "kapp.k14s.io/shared-applied.app-a")
+}
+
+func TestSharedResources_NotReleasedWithoutOtherApps(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+  namespace: default
+  labels:
+    kapp.k14s.io/app: app-a
+  annotations:
+    kapp.k14s.io/shared: ""
+`))
+
+	rs, err := ctldiff.NewSharedResources(nil, []ctlres.Resource{res}, "kapp.k14s.io/app", "app-a").Prepare()
+	require.NoError(t, err)
+
+	rs, err = ctldiff.NewSharedResources(rs, nil, "kapp.k14s.io/app", "app-a").Prepare()
+	require.NoError(t, err)
+	require.Len(t, rs, 0, "Expected resource to be deleted")
+}
+
+func TestSharedResources_ReleasedByAppNotHoldingLabel(t *testing.T) {
+	appARes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+  namespace: default
+  labels:
+    kapp.k14s.io/app: app-a
+  annotations:
+    kapp.k14s.io/shared: ""
+    team-a: owned
+`))
+
+	appBRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+  namespace: default
+  labels:
+    kapp.k14s.io/app: app-b
+  annotations:
+    kapp.k14s.io/shared: ""
+    team-b: owned
+`))
+
+	rs, err := ctldiff.NewSharedResources(nil, []ctlres.Resource{appBRes}, "kapp.k14s.io/app", "app-b").Prepare()
+	require.NoError(t, err)
+
+	// App A applies last, hence resource is labeled with app A
+	rs, err = ctldiff.NewSharedResources(rs, []ctlres.Resource{appARes}, "kapp.k14s.io/app", "app-a").Prepare()
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+	require.Equal(t, "app-a", rs[0].Labels()["kapp.k14s.io/app"])
+	require.True(t, ctldiff.IsSharedResourceAppliedBy(rs[0], "app-b"))
+
+	// App B no longer includes resource
+	rs, err = ctldiff.NewSharedResources(rs, nil, "kapp.k14s.io/app", "app-b").Prepare()
+	require.NoError(t, err)
+	require.Len(t, rs, 1, "Expected resource to be kept for app A")
+
+	require.Equal(t, "app-a", rs[0].Labels()["kapp.k14s.io/app"])
+	require.Equal(t, "owned", rs[0].Annotations()["team-a"])
+	require.NotContains(t, rs[0].Annotations(), "team-b")
+	require.NotContains(t, rs[0].Annotations(), "kapp.k14s.io/shared-applied.app-b")
+	require.False(t, ctldiff.IsSharedResourceAppliedBy(rs[0], "app-b"))
+	require.True(t, ctldiff.IsSharedResourceAppliedBy(rs[0], "app-a"))
+}
+
+func TestSharedResources_ReleasedByLastOwnerNotHoldingLabel(t *testing.T) {
+	// Resource is labeled with app that no longer applies it
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+  namespace: default
+  labels:
+    kapp.k14s.io/app: app-a
+  annotations:
+    kapp.k14s.io/shared: ""
+    kapp.k14s.io/shared-applied.app-b: '{"metadata":{"annotations":{"team-b":"owned"}}}'
+    team-b: owned
+`))
+
+	rs, err := ctldiff.NewSharedResources([]ctlres.Resource{res}, nil, "kapp.k14s.io/app", "app-b").Prepare()
+	require.NoError(t, err)
+	require.Len(t, rs, 1, "Expected resource to not be deleted by app that is not labeled on it")
+
+	require.Equal(t, "app-a", rs[0].Labels()["kapp.k14s.io/app"])
+	require.NotContains(t, rs[0].Annotations(), "team-b")
+	require.NotContains(t, rs[0].Annotations(), "kapp.k14s.io/shared-applied.app-b")
+}
+
+func TestSharedResources_AppliedFieldsExceedAnnotationsSizeLimit(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+  namespace: default
+  annotations:
+    kapp.k14s.io/shared: ""
+data:
+  key: ` + strings.Repeat("a", 300*1024) + `
+`))
+
+	_, err := ctldiff.NewSharedResources(nil, []ctlres.Resource{res}, "kapp.k14s.io/app", "app-a").Prepare()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Merging shared resource 'configmap/shared (v1) namespace: default'")
+	require.Contains(t, err.Error(), "Expected annotations (including fields applied by each co-owner "+
+		"recorded in 'kapp.k14s.io/shared-applied.*' annotations) to not exceed 262144 bytes in total")
+}
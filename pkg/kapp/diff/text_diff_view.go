@@ -16,6 +16,7 @@ type TextDiffViewOpts struct {
 	Context     int // number of lines to show around changed lines; <0 for all
 	LineNumbers bool
 	Mask        bool
+	MaxLines    int // number of lines to show before truncating; 0 for no limit
 }
 
 type TextDiffView struct {
@@ -88,6 +89,11 @@ func (v TextDiffView) String() string {
 		}
 	}
 
+	if v.opts.MaxLines > 0 && len(lines) > v.opts.MaxLines {
+		numTruncated := len(lines) - v.opts.MaxLines
+		lines = append(lines[:v.opts.MaxLines], fmt.Sprintf("  ... %d more lines", numTruncated))
+	}
+
 	return strings.Join(lines, "\n") + "\n"
 }
 
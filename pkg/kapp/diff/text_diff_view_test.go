@@ -0,0 +1,53 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextDiffView_MaxLines(t *testing.T) {
+	color.NoColor = true
+
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  key1: val1
+  key2: val2
+  key3: val3
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  key1: new-val1
+  key2: new-val2
+  key3: new-val3
+`))
+
+	textDiff := ctldiff.NewConfigurableTextDiff(existingRes, newRes, false, ctldiff.ChangeOpts{})
+
+	opts := ctldiff.TextDiffViewOpts{Context: 2, LineNumbers: true, MaxLines: 3}
+	actual := ctldiff.NewTextDiffView(textDiff, nil, opts).String()
+
+	expected := `  0,  0   data:
+  1     -   key1: val1
+  2     -   key2: val2
+  ... 6 more lines
+`
+	require.Equal(t, expected, actual)
+
+	opts.MaxLines = 0
+	actual = ctldiff.NewTextDiffView(textDiff, nil, opts).String()
+	require.Contains(t, actual, "+   key3: new-val3")
+}
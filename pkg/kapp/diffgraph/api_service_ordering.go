@@ -0,0 +1,105 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffgraph
+
+import (
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	apiServiceMatcher = ctlres.APIGroupKindMatcher{APIGroup: "apiregistration.k8s.io", Kind: "APIService"}
+
+	// Rules are only used to explain why changes wait for each other
+	apiServiceUpsertRule = ChangeRule{
+		Action:           ChangeRuleActionUpsert,
+		Order:            ChangeRuleOrderAfter,
+		TargetAction:     ChangeRuleTargetActionUpserting,
+		TargetGroup:      ChangeGroup{inferredChangeGroupPrefix + "api-services"},
+		IgnoreIfCyclical: true,
+	}
+	apiServiceDeleteRule = ChangeRule{
+		Action:           ChangeRuleActionDelete,
+		Order:            ChangeRuleOrderBefore,
+		TargetAction:     ChangeRuleTargetActionDeleting,
+		TargetGroup:      ChangeGroup{inferredChangeGroupPrefix + "api-services"},
+		IgnoreIfCyclical: true,
+	}
+)
+
+// addAPIServiceEdges makes resources served by an aggregated API
+// (registered via APIService backed by a service) wait for that APIService
+// to be upserted (and become available), and makes APIService deletion
+// wait for deletion of resources it serves, since API server is not able
+// to handle requests for those resources without it. Similar to optional
+// change rules, edges that would introduce a cycle are not added.
+func (g *ChangeGraph) addAPIServiceEdges() {
+	apiServiceChanges := map[schema.GroupVersion]map[ActualChangeOp]*Change{}
+
+	for _, change := range g.changes {
+		gv, found := g.servedGroupVersion(change.Change.Resource())
+		if !found {
+			continue
+		}
+		if _, found := apiServiceChanges[gv]; !found {
+			apiServiceChanges[gv] = map[ActualChangeOp]*Change{}
+		}
+		apiServiceChanges[gv][change.Change.Op()] = change
+	}
+
+	if len(apiServiceChanges) == 0 {
+		return
+	}
+
+	for _, change := range g.changes {
+		changesByOp, found := apiServiceChanges[change.Change.Resource().GroupVersion()]
+		if !found {
+			continue
+		}
+
+		switch change.Change.Op() {
+		case ActualChangeOpUpsert:
+			if apiServiceChange, found := changesByOp[ActualChangeOpUpsert]; found {
+				g.addOptionalWaitingFor(change, apiServiceChange, apiServiceUpsertRule)
+			}
+		case ActualChangeOpDelete:
+			if apiServiceChange, found := changesByOp[ActualChangeOpDelete]; found {
+				g.addOptionalWaitingFor(apiServiceChange, change, apiServiceDeleteRule)
+			}
+		}
+	}
+}
+
+// servedGroupVersion returns group and version of API served
+// by an APIService if it's backed by a service (i.e. not built-in API)
+func (*ChangeGraph) servedGroupVersion(res ctlres.Resource) (schema.GroupVersion, bool) {
+	if !apiServiceMatcher.Matches(res) {
+		return schema.GroupVersion{}, false
+	}
+
+	spec, ok := res.UnstructuredObject()["spec"].(map[string]interface{})
+	if !ok {
+		return schema.GroupVersion{}, false
+	}
+	if service, ok := spec["service"].(map[string]interface{}); !ok || len(service) == 0 {
+		return schema.GroupVersion{}, false
+	}
+
+	group, _ := spec["group"].(string)
+	version, _ := spec["version"].(string)
+
+	if len(group) == 0 || len(version) == 0 {
+		return schema.GroupVersion{}, false
+	}
+
+	return schema.GroupVersion{Group: group, Version: version}, true
+}
+
+func (*ChangeGraph) addOptionalWaitingFor(change, waitingForChange *Change, rule ChangeRule) {
+	if change == waitingForChange || change.IsDirectlyWaitingFor(waitingForChange) ||
+		waitingForChange.IsTransitivelyWaitingFor(change) {
+		return
+	}
+	change.addWaitingFor(waitingForChange, rule)
+}
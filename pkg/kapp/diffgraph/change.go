@@ -41,6 +41,9 @@ type Change struct {
 
 	groups *[]ChangeGroup
 	rules  *[]ChangeRule
+
+	// Rules that caused this change to wait for other changes
+	waitingForRules map[*Change][]ChangeRule
 }
 
 type Changes []*Change
@@ -49,6 +52,15 @@ func (c *Change) Description() string {
 	return fmt.Sprintf("(%s) %s", c.Change.Op(), c.Change.Resource().Description())
 }
 
+func (c *Change) addWaitingFor(change *Change, rule ChangeRule) {
+	c.WaitingFor = append(c.WaitingFor, change)
+
+	if c.waitingForRules == nil {
+		c.waitingForRules = map[*Change][]ChangeRule{}
+	}
+	c.waitingForRules[change] = append(c.waitingForRules[change], rule)
+}
+
 func (c *Change) IsDirectlyWaitingFor(changeToFind *Change) bool {
 	for _, change := range c.WaitingFor {
 		if change == changeToFind {
@@ -72,6 +72,10 @@ func NewChangeGraph(changes []ActualChange,
 		return graph, fmt.Errorf("Change graph: Calculating optional deps: %w", err)
 	}
 
+	graph.addOwnerDeleteEdges()
+	graph.addAPIServiceEdges()
+	graph.addImmutableConfigEdges()
+
 	graph.dedup()
 
 	// Double check cycles again
@@ -120,14 +124,14 @@ func (g *ChangeGraph) buildEdges(allowRule func(ChangeRule) bool,
 		case sr.ChangeRule.Order == ChangeRuleOrderAfter:
 			for _, matchedChange := range matchedChanges {
 				if allowChange(sr.Change, matchedChange) {
-					sr.Change.WaitingFor = append(sr.Change.WaitingFor, matchedChange)
+					sr.Change.addWaitingFor(matchedChange, sr.ChangeRule)
 				}
 			}
 
 		case sr.ChangeRule.Order == ChangeRuleOrderBefore:
 			for _, matchedChange := range matchedChanges {
 				if allowChange(matchedChange, sr.Change) {
-					matchedChange.WaitingFor = append(matchedChange.WaitingFor, sr.Change)
+					matchedChange.addWaitingFor(sr.Change, sr.ChangeRule)
 				}
 			}
 
@@ -284,7 +288,7 @@ func (g *ChangeGraph) checkCycles() error {
 	for len(unmarked) > 0 {
 		nodeN := unmarked[0]
 		unmarked = unmarked[1:]
-		err := g.checkCyclesVisit(nodeN, markedTemp, markedPerm)
+		err := g.checkCyclesVisit(nodeN, nil, markedTemp, markedPerm)
 		if err != nil {
 			return fmt.Errorf("Detected cycle while ordering changes: [%s] %w",
 				nodeN.Change.Resource().Description(), err)
@@ -294,17 +298,18 @@ func (g *ChangeGraph) checkCycles() error {
 	return nil
 }
 
-func (g *ChangeGraph) checkCyclesVisit(nodeN *Change, markedTemp, markedPerm map[*Change]struct{}) error {
+func (g *ChangeGraph) checkCyclesVisit(nodeN *Change, path []*Change, markedTemp, markedPerm map[*Change]struct{}) error {
 	if _, found := markedPerm[nodeN]; found {
 		return nil
 	}
 	if _, found := markedTemp[nodeN]; found {
-		return fmt.Errorf("(found repeated: %s)", nodeN.Change.Resource().Description())
+		return fmt.Errorf("(found repeated: %s)%s",
+			nodeN.Change.Resource().Description(), g.cycleRulesDesc(append(path, nodeN)))
 	}
 	markedTemp[nodeN] = struct{}{}
 
 	for _, nodeM := range nodeN.WaitingFor {
-		err := g.checkCyclesVisit(nodeM, markedTemp, markedPerm)
+		err := g.checkCyclesVisit(nodeM, append(path, nodeN), markedTemp, markedPerm)
 		if err != nil {
 			return fmt.Errorf("-> [%s] %w", nodeM.Change.Resource().Description(), err)
 		}
@@ -314,3 +319,41 @@ func (g *ChangeGraph) checkCyclesVisit(nodeN *Change, markedTemp, markedPerm map
 	markedPerm[nodeN] = struct{}{}
 	return nil
 }
+
+// cycleRulesDesc describes change rules that connect changes forming a cycle
+// (path ends with a change that was already seen earlier in the path)
+func (g *ChangeGraph) cycleRulesDesc(path []*Change) string {
+	repeated := path[len(path)-1]
+	start := 0
+
+	for i, change := range path {
+		if change == repeated {
+			start = i
+			break
+		}
+	}
+
+	var lines []string
+
+	for i := start; i < len(path)-1; i++ {
+		change, waitingForChange := path[i], path[i+1]
+		seenRules := map[string]struct{}{}
+
+		for _, rule := range change.waitingForRules[waitingForChange] {
+			ruleDesc := rule.String()
+			if _, found := seenRules[ruleDesc]; found {
+				continue
+			}
+			seenRules[ruleDesc] = struct{}{}
+
+			lines = append(lines, fmt.Sprintf("  - %s waits for %s (change rule: %s)",
+				change.Change.Resource().Description(), waitingForChange.Change.Resource().Description(), ruleDesc))
+		}
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return "\nCycle is formed by:\n" + strings.Join(lines, "\n")
+}
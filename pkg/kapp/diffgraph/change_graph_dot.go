@@ -0,0 +1,89 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffgraph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrintDotStr returns graph in Graphviz DOT format. Edges point from
+// a change to changes that wait for it (i.e. in the order of application)
+// and are labeled with change rules that caused them. Edges caused by
+// inferred ordering are dashed.
+func (g *ChangeGraph) PrintDotStr() (string, error) {
+	ids := map[*Change]string{}
+
+	idFunc := func(change *Change) string {
+		if _, found := ids[change]; !found {
+			ids[change] = fmt.Sprintf("c%d", len(ids))
+		}
+		return ids[change]
+	}
+
+	var nodes, edges []string
+
+	for _, change := range g.changes {
+		label, err := g.dotNodeLabel(change)
+		if err != nil {
+			return "", err
+		}
+		nodes = append(nodes, fmt.Sprintf("  %s [label=%s];", idFunc(change), g.dotQuote(label)))
+	}
+
+	for _, change := range g.changes {
+		for _, waitingForChange := range change.WaitingFor {
+			var ruleDescs []string
+			inferred := len(change.waitingForRules[waitingForChange]) > 0
+
+			for _, rule := range change.waitingForRules[waitingForChange] {
+				ruleDescs = append(ruleDescs, rule.String())
+				if !strings.HasPrefix(rule.TargetGroup.Name, inferredChangeGroupPrefix) {
+					inferred = false
+				}
+			}
+
+			attrs := []string{"label=" + g.dotQuote(strings.Join(ruleDescs, "\n"))}
+			if inferred {
+				attrs = append(attrs, "style=dashed")
+			}
+
+			edges = append(edges, fmt.Sprintf("  %s -> %s [%s];",
+				idFunc(waitingForChange), idFunc(change), strings.Join(attrs, ", ")))
+		}
+	}
+
+	lines := []string{"digraph changes {", "  rankdir=LR;", "  node [shape=box];"}
+	lines = append(lines, nodes...)
+	lines = append(lines, edges...)
+	lines = append(lines, "}")
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+func (g *ChangeGraph) dotNodeLabel(change *Change) (string, error) {
+	groups, err := change.Groups()
+	if err != nil {
+		return "", err
+	}
+
+	label := change.Description()
+
+	if len(groups) > 0 {
+		var groupNames []string
+		for _, group := range groups {
+			groupNames = append(groupNames, group.Name)
+		}
+		label += "\ngroups: " + strings.Join(groupNames, ", ")
+	}
+
+	return label, nil
+}
+
+func (g *ChangeGraph) dotQuote(str string) string {
+	str = strings.ReplaceAll(str, `\`, `\\`)
+	str = strings.ReplaceAll(str, `"`, `\"`)
+	str = strings.ReplaceAll(str, "\n", `\n`)
+	return `"` + str + `"`
+}
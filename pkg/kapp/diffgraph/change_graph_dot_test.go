@@ -0,0 +1,43 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffgraph_test
+
+import (
+	"testing"
+
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeGraphPrintDotStr(t *testing.T) {
+	configYAML := `
+kind: Job
+metadata:
+  name: migrations
+  annotations:
+    kapp.k14s.io/change-group: "apps.big.co/db-migrations"
+---
+kind: Deployment
+metadata:
+  name: app
+  annotations:
+    kapp.k14s.io/change-rule: "upsert after upserting apps.big.co/db-migrations"
+`
+
+	graph, err := buildChangeGraph(configYAML, ctldgraph.ActualChangeOpUpsert, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	output, err := graph.PrintDotStr()
+	require.NoError(t, err)
+
+	expectedOutput := `digraph changes {
+  rankdir=LR;
+  node [shape=box];
+  c0 [label="(upsert) job/migrations () cluster\ngroups: apps.big.co/db-migrations"];
+  c1 [label="(upsert) deployment/app () cluster"];
+  c0 -> c1 [label="upsert after upserting apps.big.co/db-migrations"];
+}
+`
+	require.Equal(t, expectedOutput, output)
+}
@@ -145,3 +145,67 @@ changeRuleBindings:
 
 	require.Equal(t, expectedOutput, output)
 }
+
+func TestChangeGraphWithChangeGroupLabelBindings(t *testing.T) {
+	configYAML := `
+kind: StatefulSet
+apiVersion: apps/v1
+metadata:
+  name: postgres
+  namespace: app1
+  labels:
+    tier: db
+---
+kind: Deployment
+apiVersion: apps/v1
+metadata:
+  name: api
+  namespace: app1
+  labels:
+    tier: backend
+---
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: app-config
+  namespace: app1
+`
+
+	confYAML := `
+kind: Config
+apiVersion: kapp.k14s.io/v1alpha1
+
+changeGroupLabelBindings:
+- labelKey: tier
+  namePrefix: tier.test.kapp.k14s.io/
+
+changeRuleBindings:
+- rules:
+  - "upsert after upserting tier.test.kapp.k14s.io/db"
+  resourceMatchers:
+  - apiVersionKindMatcher: {kind: Deployment, apiVersion: apps/v1}
+`
+
+	_, conf, err := ctlconf.NewConfFromResources([]ctlres.Resource{ctlres.MustNewResourceFromBytes([]byte(confYAML))})
+	require.NoErrorf(t, err, "Expected parsing conf to succeed")
+
+	opts := buildGraphOpts{
+		resourcesBs:         configYAML,
+		op:                  ctldgraph.ActualChangeOpUpsert,
+		changeGroupBindings: conf.ChangeGroupBindings(),
+		changeRuleBindings:  conf.ChangeRuleBindings(),
+	}
+
+	graph, err := buildChangeGraphWithOpts(opts, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	output := strings.TrimSpace(graph.PrintStr())
+	expectedOutput := strings.TrimSpace(`
+(upsert) statefulset/postgres (apps/v1) namespace: app1
+(upsert) deployment/api (apps/v1) namespace: app1
+  (upsert) statefulset/postgres (apps/v1) namespace: app1
+(upsert) configmap/app-config (v1) namespace: app1
+`)
+
+	require.Equal(t, expectedOutput, output)
+}
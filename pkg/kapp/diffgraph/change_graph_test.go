@@ -331,7 +331,10 @@ metadata:
 	_, err := buildChangeGraph(circularDep1YAML, ctldgraph.ActualChangeOpUpsert, t)
 	require.Error(t, err, "Expected graph to fail building")
 
-	expectedErr := "Detected cycle while ordering changes: [job/job1 () cluster] -> [job/job2 () cluster] -> [job/job1 () cluster] (found repeated: job/job1 () cluster)"
+	expectedErr := "Detected cycle while ordering changes: [job/job1 () cluster] -> [job/job2 () cluster] -> [job/job1 () cluster] (found repeated: job/job1 () cluster)\n" +
+		"Cycle is formed by:\n" +
+		"  - job/job1 () cluster waits for job/job2 () cluster (change rule: upsert before upserting apps.big.co/job1)\n" +
+		"  - job/job2 () cluster waits for job/job1 () cluster (change rule: upsert before upserting apps.big.co/job2)"
 	require.EqualError(t, err, expectedErr, "Expected to detect cycle")
 }
 
@@ -362,7 +365,11 @@ metadata:
 	_, err := buildChangeGraph(circularDep1YAML, ctldgraph.ActualChangeOpUpsert, t)
 	require.Error(t, err, "Expected graph to fail building")
 
-	expectedErr := "Detected cycle while ordering changes: [job/job1 () cluster] -> [job/job3 () cluster] -> [job/job2 () cluster] -> [job/job1 () cluster] (found repeated: job/job1 () cluster)"
+	expectedErr := "Detected cycle while ordering changes: [job/job1 () cluster] -> [job/job3 () cluster] -> [job/job2 () cluster] -> [job/job1 () cluster] (found repeated: job/job1 () cluster)\n" +
+		"Cycle is formed by:\n" +
+		"  - job/job1 () cluster waits for job/job3 () cluster (change rule: upsert after upserting apps.big.co/job3)\n" +
+		"  - job/job3 () cluster waits for job/job2 () cluster (change rule: upsert after upserting apps.big.co/job2)\n" +
+		"  - job/job2 () cluster waits for job/job1 () cluster (change rule: upsert after upserting apps.big.co/job1)"
 	require.EqualError(t, err, expectedErr, "Expected to detect cycle")
 }
 
@@ -386,7 +393,10 @@ metadata:
 	_, err := buildChangeGraph(circularDep1YAML, ctldgraph.ActualChangeOpUpsert, t)
 	require.Error(t, err, "Expected graph to fail building")
 
-	expectedErr := "Detected cycle while ordering changes: [job/job1 () cluster] -> [job/job2 () cluster] -> [job/job1 () cluster] (found repeated: job/job1 () cluster)"
+	expectedErr := "Detected cycle while ordering changes: [job/job1 () cluster] -> [job/job2 () cluster] -> [job/job1 () cluster] (found repeated: job/job1 () cluster)\n" +
+		"Cycle is formed by:\n" +
+		"  - job/job1 () cluster waits for job/job2 () cluster (change rule: upsert after upserting apps.big.co/job2)\n" +
+		"  - job/job2 () cluster waits for job/job1 () cluster (change rule: upsert after upserting apps.big.co/job1)"
 	require.EqualError(t, err, expectedErr, "Expected to detect cycle")
 }
 
@@ -416,7 +426,10 @@ metadata:
 	_, err := buildChangeGraph(circularDep1YAML, ctldgraph.ActualChangeOpUpsert, t)
 	require.Error(t, err, "Expected graph to fail building")
 
-	expectedErr := "Detected cycle while ordering changes: [job/job3 () cluster] -> [job/job1 () cluster] -> [job/job2 () cluster] -> [job/job1 () cluster] (found repeated: job/job1 () cluster)"
+	expectedErr := "Detected cycle while ordering changes: [job/job3 () cluster] -> [job/job1 () cluster] -> [job/job2 () cluster] -> [job/job1 () cluster] (found repeated: job/job1 () cluster)\n" +
+		"Cycle is formed by:\n" +
+		"  - job/job1 () cluster waits for job/job2 () cluster (change rule: upsert after upserting apps.big.co/job2)\n" +
+		"  - job/job2 () cluster waits for job/job1 () cluster (change rule: upsert after upserting apps.big.co/job1)"
 	require.EqualError(t, err, expectedErr, "Expected to detect cycle")
 }
 
@@ -433,7 +446,65 @@ metadata:
 	_, err := buildChangeGraph(circularDep2YAML, ctldgraph.ActualChangeOpUpsert, t)
 	require.Error(t, err, "Expected graph to fail building")
 
-	expectedErr := "Detected cycle while ordering changes: [job/job1 () cluster] -> [job/job1 () cluster] (found repeated: job/job1 () cluster)"
+	expectedErr := "Detected cycle while ordering changes: [job/job1 () cluster] -> [job/job1 () cluster] (found repeated: job/job1 () cluster)\n" +
+		"Cycle is formed by:\n" +
+		"  - job/job1 () cluster waits for job/job1 () cluster (change rule: upsert before upserting apps.big.co/job1)"
+	require.EqualError(t, err, expectedErr, "Expected to detect cycle")
+}
+
+func TestChangeGraphCircularFromRuleBindings(t *testing.T) {
+	resourcesYAML := `
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: config
+---
+kind: Secret
+apiVersion: v1
+metadata:
+  name: secret
+`
+
+	confYAML := `
+kind: Config
+apiVersion: kapp.k14s.io/v1alpha1
+
+changeGroupBindings:
+- name: apps.big.co/configs
+  resourceMatchers:
+  - apiVersionKindMatcher: {kind: ConfigMap, apiVersion: v1}
+- name: apps.big.co/secrets
+  resourceMatchers:
+  - apiVersionKindMatcher: {kind: Secret, apiVersion: v1}
+
+changeRuleBindings:
+- rules:
+  - "upsert after upserting apps.big.co/secrets"
+  resourceMatchers:
+  - apiVersionKindMatcher: {kind: ConfigMap, apiVersion: v1}
+- rules:
+  - "upsert before upserting apps.big.co/secrets"
+  resourceMatchers:
+  - apiVersionKindMatcher: {kind: ConfigMap, apiVersion: v1}
+`
+
+	_, conf, err := ctlconf.NewConfFromResources([]ctlres.Resource{ctlres.MustNewResourceFromBytes([]byte(confYAML))})
+	require.NoErrorf(t, err, "Expected parsing conf to succeed")
+
+	opts := buildGraphOpts{
+		resourcesBs:         resourcesYAML,
+		op:                  ctldgraph.ActualChangeOpUpsert,
+		changeGroupBindings: conf.ChangeGroupBindings(),
+		changeRuleBindings:  conf.ChangeRuleBindings(),
+	}
+
+	_, err = buildChangeGraphWithOpts(opts, t)
+	require.Error(t, err, "Expected graph to fail building")
+
+	expectedErr := "Detected cycle while ordering changes: [configmap/config (v1) cluster] -> [secret/secret (v1) cluster] -> [configmap/config (v1) cluster] (found repeated: configmap/config (v1) cluster)\n" +
+		"Cycle is formed by:\n" +
+		"  - configmap/config (v1) cluster waits for secret/secret (v1) cluster (change rule: upsert after upserting apps.big.co/secrets)\n" +
+		"  - secret/secret (v1) cluster waits for configmap/config (v1) cluster (change rule: upsert before upserting apps.big.co/secrets)"
 	require.EqualError(t, err, expectedErr, "Expected to detect cycle")
 }
 
@@ -545,6 +616,68 @@ metadata:
 	require.Equal(t, expectedOutput, output)
 }
 
+func TestChangeGraphWithApplyLastAnnotation(t *testing.T) {
+	configYAML := `
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: deploy-marker
+  namespace: app
+  annotations:
+    kapp.k14s.io/apply-last: ""
+---
+kind: Deployment
+apiVersion: apps/v1
+metadata:
+  name: app
+  namespace: app
+---
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: app-config
+  namespace: app
+---
+kind: Secret
+apiVersion: v1
+metadata:
+  name: second-marker
+  namespace: app
+  annotations:
+    kapp.k14s.io/apply-last: ""
+`
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	require.NoError(t, err, "Expected parsing conf defaults to succeed")
+
+	opts := buildGraphOpts{
+		resourcesBs:         configYAML,
+		op:                  ctldgraph.ActualChangeOpUpsert,
+		changeGroupBindings: conf.ChangeGroupBindings(),
+		changeRuleBindings:  conf.ChangeRuleBindings(),
+	}
+
+	graph, err := buildChangeGraphWithOpts(opts, t)
+	require.NoError(t, err, "Expected graph to build")
+
+	output := strings.TrimSpace(graph.PrintStr())
+	expectedOutput := strings.TrimSpace(`
+(upsert) configmap/deploy-marker (v1) namespace: app
+  (upsert) deployment/app (apps/v1) namespace: app
+    (upsert) configmap/app-config (v1) namespace: app
+  (upsert) configmap/app-config (v1) namespace: app
+(upsert) deployment/app (apps/v1) namespace: app
+  (upsert) configmap/app-config (v1) namespace: app
+(upsert) configmap/app-config (v1) namespace: app
+(upsert) secret/second-marker (v1) namespace: app
+  (upsert) deployment/app (apps/v1) namespace: app
+    (upsert) configmap/app-config (v1) namespace: app
+  (upsert) configmap/app-config (v1) namespace: app
+`)
+
+	require.Equal(t, expectedOutput, output)
+}
+
 func TestGraphOrderWithClusterRoleAndClusterRoleBinding(t *testing.T) {
 	configYAML := `
 ---
@@ -836,6 +969,222 @@ type: kubernetes.io/service-account-token
 	require.Equal(t, expectedOutput, output)
 }
 
+func TestChangeGraphWithOwnedDeletes(t *testing.T) {
+	configYAML := `
+apiVersion: apps.co/v1
+kind: Cluster
+metadata:
+  name: db
+  namespace: default
+  uid: cluster-uid
+---
+apiVersion: apps.co/v1
+kind: Replica
+metadata:
+  name: db-0
+  namespace: default
+  uid: replica-uid
+  ownerReferences:
+  - apiVersion: apps.co/v1
+    kind: Cluster
+    name: db
+    uid: cluster-uid
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: db-0-config
+  namespace: default
+  uid: config-uid
+  ownerReferences:
+  - apiVersion: apps.co/v1
+    kind: Replica
+    name: db-0
+    uid: replica-uid
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: owned-by-not-deleted
+  namespace: default
+  uid: other-config-uid
+  ownerReferences:
+  - apiVersion: apps.co/v1
+    kind: Cluster
+    name: other
+    uid: other-cluster-uid
+`
+
+	graph, err := buildChangeGraph(configYAML, ctldgraph.ActualChangeOpDelete, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	output := strings.TrimSpace(graph.PrintStr())
+	expectedOutput := strings.TrimSpace(`
+(delete) cluster/db (apps.co/v1) namespace: default
+  (delete) replica/db-0 (apps.co/v1) namespace: default
+    (delete) configmap/db-0-config (v1) namespace: default
+(delete) replica/db-0 (apps.co/v1) namespace: default
+  (delete) configmap/db-0-config (v1) namespace: default
+(delete) configmap/db-0-config (v1) namespace: default
+(delete) configmap/owned-by-not-deleted (v1) namespace: default
+`)
+
+	require.Equal(t, expectedOutput, output)
+
+	// Ownership is not considered for upserts
+	graph, err = buildChangeGraph(configYAML, ctldgraph.ActualChangeOpUpsert, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	for _, change := range graph.All() {
+		require.Empty(t, change.WaitingFor)
+	}
+}
+
+func TestChangeGraphWithAPIServices(t *testing.T) {
+	configYAML := `
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1alpha1.wardle.example.com
+spec:
+  group: wardle.example.com
+  version: v1alpha1
+  service:
+    name: api
+    namespace: wardle
+---
+apiVersion: wardle.example.com/v1alpha1
+kind: Flunder
+metadata:
+  name: my-flunder
+  namespace: default
+---
+apiVersion: wardle.example.com/v1beta1
+kind: Flunder
+metadata:
+  name: other-version-flunder
+  namespace: default
+---
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1.apps
+spec:
+  group: apps
+  version: v1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+`
+
+	graph, err := buildChangeGraph(configYAML, ctldgraph.ActualChangeOpUpsert, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	output := strings.TrimSpace(graph.PrintStr())
+	expectedOutput := strings.TrimSpace(`
+(upsert) apiservice/v1alpha1.wardle.example.com (apiregistration.k8s.io/v1) cluster
+(upsert) flunder/my-flunder (wardle.example.com/v1alpha1) namespace: default
+  (upsert) apiservice/v1alpha1.wardle.example.com (apiregistration.k8s.io/v1) cluster
+(upsert) flunder/other-version-flunder (wardle.example.com/v1beta1) namespace: default
+(upsert) apiservice/v1.apps (apiregistration.k8s.io/v1) cluster
+(upsert) deployment/app (apps/v1) namespace: default
+`)
+
+	require.Equal(t, expectedOutput, output)
+
+	graph, err = buildChangeGraph(configYAML, ctldgraph.ActualChangeOpDelete, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	output = strings.TrimSpace(graph.PrintStr())
+	expectedOutput = strings.TrimSpace(`
+(delete) apiservice/v1alpha1.wardle.example.com (apiregistration.k8s.io/v1) cluster
+  (delete) flunder/my-flunder (wardle.example.com/v1alpha1) namespace: default
+(delete) flunder/my-flunder (wardle.example.com/v1alpha1) namespace: default
+(delete) flunder/other-version-flunder (wardle.example.com/v1beta1) namespace: default
+(delete) apiservice/v1.apps (apiregistration.k8s.io/v1) cluster
+(delete) deployment/app (apps/v1) namespace: default
+`)
+
+	require.Equal(t, expectedOutput, output)
+}
+
+func TestChangeGraphWithImmutableConfigs(t *testing.T) {
+	configYAML := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: immutable-config
+        - configMapRef:
+            name: mutable-config
+      volumes:
+      - name: creds
+        secret:
+          secretName: immutable-secret
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: immutable-config
+  namespace: default
+immutable: true
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: mutable-config
+  namespace: default
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: immutable-secret
+  namespace: default
+immutable: true
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: immutable-secret
+  namespace: other
+immutable: true
+`
+
+	graph, err := buildChangeGraph(configYAML, ctldgraph.ActualChangeOpUpsert, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	output := strings.TrimSpace(graph.PrintStr())
+	expectedOutput := strings.TrimSpace(`
+(upsert) deployment/app (apps/v1) namespace: default
+  (upsert) configmap/immutable-config (v1) namespace: default
+  (upsert) secret/immutable-secret (v1) namespace: default
+(upsert) configmap/immutable-config (v1) namespace: default
+(upsert) configmap/mutable-config (v1) namespace: default
+(upsert) secret/immutable-secret (v1) namespace: default
+(upsert) secret/immutable-secret (v1) namespace: other
+`)
+
+	require.Equal(t, expectedOutput, output)
+
+	graph, err = buildChangeGraph(configYAML, ctldgraph.ActualChangeOpDelete, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	for _, change := range graph.All() {
+		require.Empty(t, change.WaitingFor, "Expected deletes to not be ordered")
+	}
+}
+
 func buildChangeGraph(resourcesBs string, op ctldgraph.ActualChangeOp, t *testing.T) (*ctldgraph.ChangeGraph, error) {
 	return buildChangeGraphWithOpts(buildGraphOpts{resourcesBs: resourcesBs, op: op}, t)
 }
@@ -6,6 +6,7 @@ package diffgraph
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	ctlcrd "carvel.dev/kapp/pkg/kapp/resourcesmisc"
@@ -24,6 +25,11 @@ var (
 	placeholderMatcher = regexp.MustCompile("{.+?}")
 )
 
+const (
+	// Label placeholder has format {label:label-key}
+	labelPlaceholderPrefix = "{label:"
+)
+
 // Placeholders have the format {placeholder-name}
 // Other patterns like ${placeholder-name} are commonly used by other operators/tools
 func (c ChangeGroupName) AsString() (string, error) {
@@ -51,6 +57,15 @@ func (c ChangeGroupName) AsString() (string, error) {
 	}
 
 	replaced := placeholderMatcher.ReplaceAllStringFunc(c.name, func(placeholder string) string {
+		if strings.HasPrefix(placeholder, labelPlaceholderPrefix) {
+			labelKey := strings.TrimSuffix(strings.TrimPrefix(placeholder, labelPlaceholderPrefix), "}")
+			value := c.resource.Labels()[labelKey]
+			if value == "" {
+				err = fmt.Errorf("Placeholder %s does not have a value for target resource (hint: resource does not have label '%s')", placeholder, labelKey)
+			}
+			return value
+		}
+
 		value, found := values[placeholder]
 		if !found {
 			err = fmt.Errorf("Expected placeholder to be one of these: %s but was %s", c.placeholders(values), placeholder)
@@ -68,5 +83,6 @@ func (c ChangeGroupName) placeholders(values map[string]string) (placeholders []
 	for k := range values {
 		placeholders = append(placeholders, k)
 	}
+	placeholders = append(placeholders, labelPlaceholderPrefix+"label-key}")
 	return placeholders
 }
@@ -74,3 +74,8 @@ func (r ChangeRule) Validate() error {
 	}
 	return nil
 }
+
+// String returns rule in annotation format (e.g. upsert after upserting apps.big.co/db)
+func (r ChangeRule) String() string {
+	return fmt.Sprintf("%s %s %s %s", r.Action, r.Order, r.TargetAction, r.TargetGroup.Name)
+}
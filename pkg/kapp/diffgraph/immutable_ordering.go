@@ -0,0 +1,81 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffgraph
+
+import (
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+var (
+	immutableConfigMatcher = ctlres.AnyMatcher{Matchers: []ctlres.ResourceMatcher{
+		ctlres.APIVersionKindMatcher{APIVersion: "v1", Kind: "Secret"},
+		ctlres.APIVersionKindMatcher{APIVersion: "v1", Kind: "ConfigMap"},
+	}}
+
+	// Rule is only used to explain why workload waits for immutable config
+	immutableConfigUpsertRule = ChangeRule{
+		Action:           ChangeRuleActionUpsert,
+		Order:            ChangeRuleOrderAfter,
+		TargetAction:     ChangeRuleTargetActionUpserting,
+		TargetGroup:      ChangeGroup{inferredChangeGroupPrefix + "immutable-configs"},
+		IgnoreIfCyclical: true,
+	}
+)
+
+// addImmutableConfigEdges makes workloads wait for upserts of immutable
+// Secrets and ConfigMaps that they reference within the same namespace.
+// Since immutable resources may be deleted and recreated when their
+// content changes, workloads should only roll out once new content is present.
+// Similar to optional change rules, edges that would introduce a cycle are not added.
+func (g *ChangeGraph) addImmutableConfigEdges() {
+	configChanges := map[string]*Change{}
+
+	for _, change := range g.changes {
+		res := change.Change.Resource()
+		if change.Change.Op() != ActualChangeOpUpsert || !g.isImmutableConfig(res) {
+			continue
+		}
+		configChanges[g.immutableConfigKey(res.Kind(), res.Namespace(), res.Name())] = change
+	}
+
+	if len(configChanges) == 0 {
+		return
+	}
+
+	for _, change := range g.changes {
+		if change.Change.Op() != ActualChangeOpUpsert {
+			continue
+		}
+
+		res := change.Change.Resource()
+
+		cmNames, secretNames, err := InferredOrdering{}.podSpecReferences(res)
+		if err != nil {
+			continue // Leave validation of unexpected contents to API server
+		}
+
+		for _, name := range cmNames {
+			if configChange, found := configChanges[g.immutableConfigKey("ConfigMap", res.Namespace(), name)]; found {
+				g.addOptionalWaitingFor(change, configChange, immutableConfigUpsertRule)
+			}
+		}
+		for _, name := range secretNames {
+			if configChange, found := configChanges[g.immutableConfigKey("Secret", res.Namespace(), name)]; found {
+				g.addOptionalWaitingFor(change, configChange, immutableConfigUpsertRule)
+			}
+		}
+	}
+}
+
+func (*ChangeGraph) isImmutableConfig(res ctlres.Resource) bool {
+	if !immutableConfigMatcher.Matches(res) {
+		return false
+	}
+	immutable, _ := res.UnstructuredObject()["immutable"].(bool)
+	return immutable
+}
+
+func (*ChangeGraph) immutableConfigKey(kind, namespace, name string) string {
+	return InferredOrdering{}.key("", kind, namespace, name)
+}
@@ -0,0 +1,310 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffgraph
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlcrd "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	inferredChangeGroupPrefix = "inferred.change-groups.kapp.k14s.io/"
+)
+
+var (
+	inferredChangeGroupInvalidCharsRegexp = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+	// Paths to pod spec within workload resources (keyed by kind)
+	inferredOrderingPodSpecPaths = map[string][]string{
+		"Pod":                   {"spec"},
+		"Deployment":            {"spec", "template", "spec"},
+		"StatefulSet":           {"spec", "template", "spec"},
+		"DaemonSet":             {"spec", "template", "spec"},
+		"ReplicaSet":            {"spec", "template", "spec"},
+		"ReplicationController": {"spec", "template", "spec"},
+		"Job":                   {"spec", "template", "spec"},
+		"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+	}
+)
+
+// InferredOrdering analyzes references between resources within a set
+// and synthesizes change groups and rules so that referenced resources
+// are upserted before resources that reference them. Inferred dependencies:
+//   - Namespace before resources within that namespace
+//   - CustomResourceDefinition before custom resources of its group and kind
+//   - ServiceAccount before RoleBindings and ClusterRoleBindings that list it as a subject
+//   - ConfigMap and Secret before workloads (Pods, Deployments, StatefulSets, DaemonSets,
+//     ReplicaSets, ReplicationControllers, Jobs, CronJobs) that reference them
+//     via volumes, projected volumes, env, envFrom or imagePullSecrets
+//
+// Only resources that are part of the set are considered.
+type InferredOrdering struct {
+	resources []ctlres.Resource
+}
+
+type InferredDependency struct {
+	Resource  ctlres.Resource
+	DependsOn ctlres.Resource
+}
+
+func NewInferredOrdering(resources []ctlres.Resource) InferredOrdering {
+	return InferredOrdering{resources}
+}
+
+func (o InferredOrdering) Dependencies() ([]InferredDependency, error) {
+	var result []InferredDependency
+
+	byKey := map[string]ctlres.Resource{}
+	crdsByGroupKind := map[string]ctlres.Resource{}
+
+	for _, res := range o.resources {
+		byKey[o.key(res.APIGroup(), res.Kind(), res.Namespace(), res.Name())] = res
+
+		if crd := ctlcrd.NewAPIExtensionsVxCRD(res); crd != nil {
+			crdGroup, err := crd.Group()
+			if err != nil {
+				return nil, err
+			}
+			crdKind, err := crd.Kind()
+			if err != nil {
+				return nil, err
+			}
+			crdsByGroupKind[crdGroup+"/"+crdKind] = res
+		}
+	}
+
+	for _, res := range o.resources {
+		seen := map[ctlres.Resource]struct{}{}
+
+		addDep := func(dependsOn ctlres.Resource, found bool) {
+			if !found || dependsOn == res {
+				return
+			}
+			if _, found := seen[dependsOn]; found {
+				return
+			}
+			seen[dependsOn] = struct{}{}
+			result = append(result, InferredDependency{Resource: res, DependsOn: dependsOn})
+		}
+
+		if len(res.Namespace()) > 0 {
+			addDep(o.find(byKey, "", "Namespace", "", res.Namespace()))
+		}
+
+		crd, found := crdsByGroupKind[res.APIGroup()+"/"+res.Kind()]
+		addDep(crd, found)
+
+		subjects, err := o.serviceAccountSubjects(res)
+		if err != nil {
+			return nil, err
+		}
+		for _, subject := range subjects {
+			addDep(o.find(byKey, "", "ServiceAccount", subject.Namespace, subject.Name))
+		}
+
+		cmNames, secretNames, err := o.podSpecReferences(res)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range cmNames {
+			addDep(o.find(byKey, "", "ConfigMap", res.Namespace(), name))
+		}
+		for _, name := range secretNames {
+			addDep(o.find(byKey, "", "Secret", res.Namespace(), name))
+		}
+	}
+
+	return result, nil
+}
+
+// Bindings returns change groups for referenced resources and
+// change rules that order referencing resources after them
+func (o InferredOrdering) Bindings() ([]ctlconf.ChangeGroupBinding, []ctlconf.ChangeRuleBinding, error) {
+	deps, err := o.Dependencies()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var groupBindings []ctlconf.ChangeGroupBinding
+	var ruleBindings []ctlconf.ChangeRuleBinding
+
+	groupNames := map[ctlres.Resource]string{}
+	ruleBindingIdxs := map[ctlres.Resource]int{}
+
+	for _, dep := range deps {
+		groupName, found := groupNames[dep.DependsOn]
+		if !found {
+			groupName = o.changeGroupName(dep.DependsOn)
+			groupNames[dep.DependsOn] = groupName
+			groupBindings = append(groupBindings, ctlconf.ChangeGroupBinding{
+				Name:             groupName,
+				ResourceMatchers: o.resourceMatchers(dep.DependsOn),
+			})
+		}
+
+		rule := "upsert after upserting " + groupName
+
+		idx, found := ruleBindingIdxs[dep.Resource]
+		if !found {
+			ruleBindingIdxs[dep.Resource] = len(ruleBindings)
+			ruleBindings = append(ruleBindings, ctlconf.ChangeRuleBinding{
+				Rules: []string{rule},
+				// Inferred rules should not prevent deploys when
+				// they conflict with explicitly configured rules
+				IgnoreIfCyclical: true,
+				ResourceMatchers: o.resourceMatchers(dep.Resource),
+			})
+		} else {
+			ruleBindings[idx].Rules = append(ruleBindings[idx].Rules, rule)
+		}
+	}
+
+	return groupBindings, ruleBindings, nil
+}
+
+func (InferredOrdering) serviceAccountSubjects(res ctlres.Resource) ([]rbacv1.Subject, error) {
+	if res.APIGroup() != rbacv1.GroupName || (res.Kind() != "RoleBinding" && res.Kind() != "ClusterRoleBinding") {
+		return nil, nil
+	}
+
+	var binding rbacv1.ClusterRoleBinding // RoleBinding has same subjects structure
+
+	err := res.AsUncheckedTypedObj(&binding)
+	if err != nil {
+		return nil, fmt.Errorf("Converting resource '%s' to binding: %w", res.Description(), err)
+	}
+
+	var result []rbacv1.Subject
+
+	for _, subject := range binding.Subjects {
+		if subject.Kind != rbacv1.ServiceAccountKind {
+			continue
+		}
+		if len(subject.Namespace) == 0 {
+			subject.Namespace = res.Namespace()
+		}
+		result = append(result, subject)
+	}
+
+	return result, nil
+}
+
+func (InferredOrdering) podSpecReferences(res ctlres.Resource) ([]string, []string, error) {
+	path, found := inferredOrderingPodSpecPaths[res.Kind()]
+	if !found {
+		return nil, nil, nil
+	}
+
+	podSpecObj, found, err := unstructured.NestedMap(res.UnstructuredObject(), path...)
+	if err != nil || !found {
+		return nil, nil, nil // Leave validation of unexpected contents to API server
+	}
+
+	var podSpec corev1.PodSpec
+
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(podSpecObj, &podSpec)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Converting resource '%s' pod spec: %w", res.Description(), err)
+	}
+
+	var cmNames, secretNames []string
+
+	addCM := func(name string) {
+		if len(name) > 0 {
+			cmNames = append(cmNames, name)
+		}
+	}
+	addSecret := func(name string) {
+		if len(name) > 0 {
+			secretNames = append(secretNames, name)
+		}
+	}
+
+	for _, vol := range podSpec.Volumes {
+		if vol.ConfigMap != nil {
+			addCM(vol.ConfigMap.Name)
+		}
+		if vol.Secret != nil {
+			addSecret(vol.Secret.SecretName)
+		}
+		if vol.Projected != nil {
+			for _, source := range vol.Projected.Sources {
+				if source.ConfigMap != nil {
+					addCM(source.ConfigMap.Name)
+				}
+				if source.Secret != nil {
+					addSecret(source.Secret.Name)
+				}
+			}
+		}
+	}
+
+	for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				addCM(envFrom.ConfigMapRef.Name)
+			}
+			if envFrom.SecretRef != nil {
+				addSecret(envFrom.SecretRef.Name)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				addCM(env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				addSecret(env.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+	}
+
+	for _, secretRef := range podSpec.ImagePullSecrets {
+		addSecret(secretRef.Name)
+	}
+
+	return cmNames, secretNames, nil
+}
+
+func (o InferredOrdering) find(byKey map[string]ctlres.Resource, apiGroup, kind, namespace, name string) (ctlres.Resource, bool) {
+	res, found := byKey[o.key(apiGroup, kind, namespace, name)]
+	return res, found
+}
+
+func (InferredOrdering) key(apiGroup, kind, namespace, name string) string {
+	return strings.Join([]string{apiGroup, kind, namespace, name}, "/")
+}
+
+func (InferredOrdering) changeGroupName(res ctlres.Resource) string {
+	pieces := []string{strings.ToLower(res.Kind())}
+	if len(res.Namespace()) > 0 {
+		pieces = append(pieces, res.Namespace())
+	}
+	pieces = append(pieces, res.Name())
+
+	name := inferredChangeGroupInvalidCharsRegexp.ReplaceAllString(strings.Join(pieces, "."), "-")
+
+	return inferredChangeGroupPrefix + strings.Trim(name, "-_.")
+}
+
+func (InferredOrdering) resourceMatchers(res ctlres.Resource) []ctlconf.ResourceMatcher {
+	return []ctlconf.ResourceMatcher{{
+		KindNamespaceNameMatcher: &ctlconf.KindNamespaceNameMatcher{
+			Kind:      res.Kind(),
+			Namespace: res.Namespace(),
+			Name:      res.Name(),
+		},
+	}}
+}
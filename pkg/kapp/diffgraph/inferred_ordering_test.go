@@ -0,0 +1,150 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffgraph_test
+
+import (
+	"strings"
+	"testing"
+
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferredOrdering(t *testing.T) {
+	configYAML := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: ns1
+spec:
+  template:
+    spec:
+      imagePullSecrets:
+      - name: pull-secret
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: app-config
+      volumes:
+      - name: certs
+        secret:
+          secretName: app-certs
+      - name: unrelated
+        configMap:
+          name: not-in-set
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: ns1
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-certs
+  namespace: ns1
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: pull-secret
+  namespace: ns1
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: system:app
+subjects:
+- kind: ServiceAccount
+  name: app
+  namespace: ns1
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+  namespace: ns1
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ns1
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+  scope: Cluster
+`
+
+	rs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(configYAML))).Resources()
+	require.NoError(t, err)
+
+	groupBindings, ruleBindings, err := ctldgraph.NewInferredOrdering(rs).Bindings()
+	require.NoError(t, err)
+
+	var groupNames []string
+	for _, binding := range groupBindings {
+		groupNames = append(groupNames, binding.Name)
+	}
+
+	require.Equal(t, []string{
+		"inferred.change-groups.kapp.k14s.io/namespace.ns1",
+		"inferred.change-groups.kapp.k14s.io/configmap.ns1.app-config",
+		"inferred.change-groups.kapp.k14s.io/secret.ns1.app-certs",
+		"inferred.change-groups.kapp.k14s.io/secret.ns1.pull-secret",
+		"inferred.change-groups.kapp.k14s.io/serviceaccount.ns1.app",
+		"inferred.change-groups.kapp.k14s.io/customresourcedefinition.widgets.example.com",
+	}, groupNames)
+
+	graph, err := buildChangeGraphWithOpts(buildGraphOpts{
+		resources:           rs,
+		op:                  ctldgraph.ActualChangeOpUpsert,
+		changeGroupBindings: groupBindings,
+		changeRuleBindings:  ruleBindings,
+	}, t)
+	require.NoError(t, err)
+
+	output := strings.TrimSpace(graph.PrintStr())
+	expectedOutput := strings.TrimSpace(`
+(upsert) deployment/app (apps/v1) namespace: ns1
+  (upsert) namespace/ns1 (v1) cluster
+  (upsert) configmap/app-config (v1) namespace: ns1
+    (upsert) namespace/ns1 (v1) cluster
+  (upsert) secret/app-certs (v1) namespace: ns1
+    (upsert) namespace/ns1 (v1) cluster
+  (upsert) secret/pull-secret (v1) namespace: ns1
+    (upsert) namespace/ns1 (v1) cluster
+(upsert) configmap/app-config (v1) namespace: ns1
+  (upsert) namespace/ns1 (v1) cluster
+(upsert) secret/app-certs (v1) namespace: ns1
+  (upsert) namespace/ns1 (v1) cluster
+(upsert) secret/pull-secret (v1) namespace: ns1
+  (upsert) namespace/ns1 (v1) cluster
+(upsert) clusterrolebinding/system:app (rbac.authorization.k8s.io/v1) cluster
+  (upsert) serviceaccount/app (v1) namespace: ns1
+    (upsert) namespace/ns1 (v1) cluster
+(upsert) serviceaccount/app (v1) namespace: ns1
+  (upsert) namespace/ns1 (v1) cluster
+(upsert) namespace/ns1 (v1) cluster
+(upsert) widget/widget (example.com/v1) cluster
+  (upsert) customresourcedefinition/widgets.example.com (apiextensions.k8s.io/v1) cluster
+(upsert) customresourcedefinition/widgets.example.com (apiextensions.k8s.io/v1) cluster
+`)
+
+	require.Equal(t, expectedOutput, output)
+}
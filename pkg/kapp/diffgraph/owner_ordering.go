@@ -0,0 +1,48 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffgraph
+
+var (
+	// Rule is only used to explain why owner waits for its owned resource
+	ownerDeleteRule = ChangeRule{
+		Action:           ChangeRuleActionDelete,
+		Order:            ChangeRuleOrderAfter,
+		TargetAction:     ChangeRuleTargetActionDeleting,
+		TargetGroup:      ChangeGroup{inferredChangeGroupPrefix + "owned-resources"},
+		IgnoreIfCyclical: true,
+	}
+)
+
+// addOwnerDeleteEdges makes deletion of a resource wait for deletion
+// of resources that it owns (via ownerReferences) when both are deleted
+// together (e.g. during GC of resources no longer in the app). Otherwise
+// owned resources may get garbage collected by the cluster before kapp
+// gets to delete them, or may be left behind when owner is deleted with
+// orphan propagation. Since waiting is transitive, resources that are
+// deeper in ownership hierarchy are deleted first. Similar to optional
+// change rules, edges that would introduce a cycle are not added.
+func (g *ChangeGraph) addOwnerDeleteEdges() {
+	deletesByUID := map[string]*Change{}
+
+	for _, change := range g.changes {
+		if change.Change.Op() != ActualChangeOpDelete {
+			continue
+		}
+		if uid := change.Change.Resource().UID(); len(uid) > 0 {
+			deletesByUID[uid] = change
+		}
+	}
+
+	for _, ownedChange := range g.changes {
+		if ownedChange.Change.Op() != ActualChangeOpDelete {
+			continue
+		}
+
+		for _, ref := range ownedChange.Change.Resource().OwnerRefs() {
+			if ownerChange, found := deletesByUID[string(ref.UID)]; found {
+				g.addOptionalWaitingFor(ownerChange, ownedChange, ownerDeleteRule)
+			}
+		}
+	}
+}
@@ -52,7 +52,7 @@ func (l PodContainerLog) Tail(ui ui.UI, cancelCh chan struct{}) error {
 	for {
 		err := l.StartTail(ui, cancelCh)
 		if err == io.EOF {
-			if l.opts.Follow {
+			if l.opts.Follow && !(l.opts.EndWithPod && l.podCompleted()) {
 				ui.BeginLinef("%s# container stopped '%s' logs\n", linePrefix, l.tag)
 				// Making it 1sec instead of 500ms as some times for initContainers, it fetches the older stream if we go by 500ms.
 				time.Sleep(1 * time.Second)
@@ -161,11 +161,24 @@ func (l PodContainerLog) obtainStream(ui ui.UI, linePrefix string, cancelCh chan
 	}
 }
 
+func (l PodContainerLog) podCompleted() bool {
+	pod, err := l.podsClient.Get(context.TODO(), l.pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
 func (l PodContainerLog) readyToGetLogs() bool {
 	pod, err := l.podsClient.Get(context.TODO(), l.pod.Name, metav1.GetOptions{})
 	if err != nil {
 		return false
 	}
+	// Logs of terminated containers are still available, but they
+	// are only fetched once Pod completes (otherwise container may restart)
+	if l.opts.EndWithPod && (pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed) {
+		return true
+	}
 	containerStatuses := pod.Status.ContainerStatuses
 	initContainerStatuses := pod.Status.InitContainerStatuses
 	for _, containerStatus := range containerStatuses {
@@ -0,0 +1,61 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func TestPodContainerLogEndWithPod(t *testing.T) {
+	failedPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-pod"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "main",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+			}},
+		},
+	}
+
+	podsClient := podClient{pod: failedPod}
+
+	t.Run("gets logs of terminated containers once Pod completes", func(t *testing.T) {
+		log := NewPodContainerLog(failedPod, "main", podsClient, "job-pod > main", PodLogOpts{Follow: true, EndWithPod: true})
+		require.True(t, log.readyToGetLogs())
+		require.True(t, log.podCompleted())
+	})
+
+	t.Run("waits for running containers by default", func(t *testing.T) {
+		log := NewPodContainerLog(failedPod, "main", podsClient, "job-pod > main", PodLogOpts{Follow: true})
+		require.False(t, log.readyToGetLogs())
+	})
+
+	t.Run("does not consider running Pod completed", func(t *testing.T) {
+		runningPod := failedPod
+		runningPod.Status.Phase = corev1.PodRunning
+
+		log := NewPodContainerLog(runningPod, "main", podClient{pod: runningPod}, "job-pod > main", PodLogOpts{Follow: true, EndWithPod: true})
+		require.False(t, log.readyToGetLogs())
+		require.False(t, log.podCompleted())
+	})
+}
+
+// podClient always returns the same Pod
+type podClient struct {
+	typedcorev1.PodInterface
+
+	pod corev1.Pod
+}
+
+func (c podClient) Get(context.Context, string, metav1.GetOptions) (*corev1.Pod, error) {
+	pod := c.pod
+	return &pod, nil
+}
@@ -18,6 +18,10 @@ type PodLogOpts struct {
 	ContainerNames []string
 	ContainerTag   bool
 	LinePrefix     string
+
+	// EndWithPod includes containers of completed Pods and
+	// stops following logs once Pod completes
+	EndWithPod bool
 }
 
 type PodLog struct {
@@ -0,0 +1,45 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"time"
+)
+
+const (
+	changesAppliedMetricName      = "kapp_changes_applied_total"
+	changeApplyRetriesMetricName  = "kapp_change_apply_retries_total"
+	changeWaitDurationsMetricName = "kapp_change_wait_duration_seconds"
+)
+
+// ApplyMetrics records activity of apply and wait loops
+type ApplyMetrics struct {
+	registry *Registry
+}
+
+func NewApplyMetrics(registry *Registry) ApplyMetrics {
+	return ApplyMetrics{registry}
+}
+
+func (m ApplyMetrics) ChangeApplied(op string, successful bool) {
+	m.registry.AddCounter(changesAppliedMetricName, "Number of applied changes",
+		map[string]string{"op": op, "result": m.result(successful)}, 1)
+}
+
+func (m ApplyMetrics) ChangeApplyRetried(op string) {
+	m.registry.AddCounter(changeApplyRetriesMetricName, "Number of change apply retries due to retryable errors",
+		map[string]string{"op": op}, 1)
+}
+
+func (m ApplyMetrics) ChangeWaited(op string, duration time.Duration, successful bool) {
+	m.registry.ObserveSummary(changeWaitDurationsMetricName, "Duration of waiting for changes to converge",
+		map[string]string{"op": op, "result": m.result(successful)}, duration.Seconds())
+}
+
+func (ApplyMetrics) result(successful bool) string {
+	if successful {
+		return "success"
+	}
+	return "failure"
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	metricTypeCounter = "counter"
+	metricTypeSummary = "summary"
+)
+
+// Registry keeps track of counters and summaries and exposes them
+// in Prometheus text exposition format. Metrics are created lazily
+// when they are first recorded.
+type Registry struct {
+	metrics map[string]*metric
+	lock    sync.Mutex
+}
+
+type metric struct {
+	name    string
+	help    string
+	typ     string
+	samples map[string]*sample
+}
+
+type sample struct {
+	labels string
+	value  float64
+	count  uint64
+}
+
+var _ http.Handler = &Registry{}
+
+func NewRegistry() *Registry {
+	return &Registry{metrics: map[string]*metric{}}
+}
+
+func (r *Registry) AddCounter(name, help string, labels map[string]string, val float64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.sample(name, help, metricTypeCounter, labels).value += val
+}
+
+func (r *Registry) ObserveSummary(name, help string, labels map[string]string, val float64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s := r.sample(name, help, metricTypeSummary, labels)
+	s.value += val
+	s.count++
+}
+
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var out strings.Builder
+
+	for _, name := range r.sortedNames() {
+		m := r.metrics[name]
+
+		fmt.Fprintf(&out, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(&out, "# TYPE %s %s\n", m.name, m.typ)
+
+		for _, s := range m.sortedSamples() {
+			switch m.typ {
+			case metricTypeCounter:
+				fmt.Fprintf(&out, "%s%s %g\n", m.name, s.labels, s.value)
+			case metricTypeSummary:
+				fmt.Fprintf(&out, "%s_sum%s %g\n", m.name, s.labels, s.value)
+				fmt.Fprintf(&out, "%s_count%s %d\n", m.name, s.labels, s.count)
+			default:
+				panic(fmt.Sprintf("Unknown metric type: %s", m.typ))
+			}
+		}
+	}
+
+	n, err := io.WriteString(w, out.String())
+	return int64(n), err
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.WriteTo(w)
+}
+
+func (r *Registry) sample(name, help, typ string, labels map[string]string) *sample {
+	m, found := r.metrics[name]
+	if !found {
+		m = &metric{name: name, help: help, typ: typ, samples: map[string]*sample{}}
+		r.metrics[name] = m
+	}
+
+	if m.typ != typ {
+		panic(fmt.Sprintf("Expected metric '%s' to be of type %s, but was %s", name, m.typ, typ))
+	}
+
+	labelsStr := r.labelsString(labels)
+
+	s, found := m.samples[labelsStr]
+	if !found {
+		s = &sample{labels: labelsStr}
+		m.samples[labelsStr] = s
+	}
+
+	return s
+}
+
+func (r *Registry) labelsString(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var keys []string
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		val := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[key])
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, key, val))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (r *Registry) sortedNames() []string {
+	var names []string
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *metric) sortedSamples() []*sample {
+	var samples []*sample
+	for _, s := range m.samples {
+		samples = append(samples, s)
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].labels < samples[j].labels })
+	return samples
+}
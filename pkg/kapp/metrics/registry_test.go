@@ -0,0 +1,44 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	ctlmetrics "carvel.dev/kapp/pkg/kapp/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyMetrics(t *testing.T) {
+	registry := ctlmetrics.NewRegistry()
+	metrics := ctlmetrics.NewApplyMetrics(registry)
+
+	metrics.ChangeApplied("update", true)
+	metrics.ChangeApplied("update", true)
+	metrics.ChangeApplied("add", false)
+	metrics.ChangeApplyRetried("add")
+	metrics.ChangeWaited("update", 1500*time.Millisecond, true)
+	metrics.ChangeWaited("update", 500*time.Millisecond, true)
+
+	var out strings.Builder
+
+	_, err := registry.WriteTo(&out)
+	require.NoError(t, err)
+
+	expectedOut := `# HELP kapp_change_apply_retries_total Number of change apply retries due to retryable errors
+# TYPE kapp_change_apply_retries_total counter
+kapp_change_apply_retries_total{op="add"} 1
+# HELP kapp_change_wait_duration_seconds Duration of waiting for changes to converge
+# TYPE kapp_change_wait_duration_seconds summary
+kapp_change_wait_duration_seconds_sum{op="update",result="success"} 2
+kapp_change_wait_duration_seconds_count{op="update",result="success"} 2
+# HELP kapp_changes_applied_total Number of applied changes
+# TYPE kapp_changes_applied_total counter
+kapp_changes_applied_total{op="add",result="failure"} 1
+kapp_changes_applied_total{op="update",result="success"} 2
+`
+	require.Equal(t, expectedOut, out.String())
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Server exposes registry metrics over HTTP at /metrics
+type Server struct {
+	server *http.Server
+}
+
+func NewServer(addr string, registry *Registry) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry)
+
+	return &Server{server: &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}}
+}
+
+// Start returns once server is listening (or failed to start listening)
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("Listening for metrics on '%s': %w", s.server.Addr, err)
+	}
+
+	go s.server.Serve(listener)
+
+	return nil
+}
+
+func (s *Server) Stop() error {
+	return s.server.Close()
+}
@@ -24,6 +24,12 @@ type BindingValidator struct {
 	ssarClient authv1client.SelfSubjectAccessReviewInterface
 	rbacClient rbacv1client.RbacV1Interface
 	mapper     meta.RESTMapper
+
+	skipEscalationCheck        bool
+	skippedEscalationCheckFunc func(ctlres.Resource)
+
+	escalationExemptions        []ctlres.ResourceMatcher
+	exemptedEscalationCheckFunc func(ctlres.Resource)
 }
 
 var _ Validator = (*BindingValidator)(nil)
@@ -36,6 +42,23 @@ func NewBindingValidator(ssarClient authv1client.SelfSubjectAccessReviewInterfac
 	}
 }
 
+// DangerouslySkipEscalationCheck makes validator only check permissions
+// to create or update bindings without verifying that referenced (Cluster)Role
+// does not grant more permissions than user already has. skippedFunc
+// is called for each binding that was not checked.
+func (bv *BindingValidator) DangerouslySkipEscalationCheck(skippedFunc func(ctlres.Resource)) {
+	bv.skipEscalationCheck = true
+	bv.skippedEscalationCheckFunc = skippedFunc
+}
+
+// ExemptFromEscalationCheck makes validator skip escalation check
+// only for bindings matched by one of provided matchers. exemptedFunc
+// is called for each binding that was exempted.
+func (bv *BindingValidator) ExemptFromEscalationCheck(matchers []ctlres.ResourceMatcher, exemptedFunc func(ctlres.Resource)) {
+	bv.escalationExemptions = matchers
+	bv.exemptedEscalationCheckFunc = exemptedFunc
+}
+
 func (bv *BindingValidator) Validate(ctx context.Context, res ctlres.Resource, verb string) error {
 	mapping, err := bv.mapper.RESTMapping(res.GroupKind(), res.GroupVersion().Version)
 	if err != nil {
@@ -75,6 +98,20 @@ func (bv *BindingValidator) Validate(ctx context.Context, res ctlres.Resource, v
 			return err
 		}
 
+		if bv.skipEscalationCheck {
+			if bv.skippedEscalationCheckFunc != nil {
+				bv.skippedEscalationCheckFunc(res)
+			}
+			return nil
+		}
+
+		if bv.isExemptFromEscalationCheck(res) {
+			if bv.exemptedEscalationCheckFunc != nil {
+				bv.exemptedEscalationCheckFunc(res)
+			}
+			return nil
+		}
+
 		// If user doesn't have "bind" permissions then they can
 		// only create (Cluster)RolesBindings where the referenced (Cluster)Role
 		// contains permissions that they already have.
@@ -128,3 +165,12 @@ func (bv *BindingValidator) Validate(ctx context.Context, res ctlres.Resource, v
 
 	return nil
 }
+
+func (bv *BindingValidator) isExemptFromEscalationCheck(res ctlres.Resource) bool {
+	for _, matcher := range bv.escalationExemptions {
+		if matcher.Matches(res) {
+			return true
+		}
+	}
+	return false
+}
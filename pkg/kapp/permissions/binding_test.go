@@ -0,0 +1,109 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"context"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
+)
+
+func TestBindingValidatorEscalationExemptions(t *testing.T) {
+	// User can create bindings but does not have "bind" permission
+	// nor permissions granted by referenced role (reading secrets)
+	ssarClient := &deniedVerbsSSARClient{denied: map[string]struct{}{"bind": {}, "get": {}}}
+	rbacClient := &fakeRbacClient{roleRules: []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+	}}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("RoleBinding"), meta.RESTScopeNamespace)
+
+	trustedBinding := newRoleBinding("trusted")
+	untrustedBinding := newRoleBinding("untrusted")
+
+	var exemptedRs []ctlres.Resource
+
+	validator := permissions.NewBindingValidator(ssarClient, rbacClient, mapper)
+	validator.ExemptFromEscalationCheck([]ctlres.ResourceMatcher{
+		ctlres.HasNamespaceMatcher{Names: []string{"trusted"}},
+	}, func(res ctlres.Resource) { exemptedRs = append(exemptedRs, res) })
+
+	err := validator.Validate(context.Background(), trustedBinding, "create")
+	require.NoError(t, err)
+	require.Equal(t, []ctlres.Resource{trustedBinding}, exemptedRs)
+
+	err = validator.Validate(context.Background(), untrustedBinding, "create")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `potential privilege escalation, not permitted to "create" rbac.authorization.k8s.io/v1, Kind=RoleBinding`)
+	require.Len(t, exemptedRs, 1, "Expected only matched bindings to be exempted")
+
+	t.Run("still checks permission to create binding", func(t *testing.T) {
+		ssarClient.denied["create"] = struct{}{}
+		defer delete(ssarClient.denied, "create")
+
+		err := validator.Validate(context.Background(), trustedBinding, "create")
+		require.Error(t, err)
+		require.Len(t, exemptedRs, 1)
+	})
+}
+
+func newRoleBinding(ns string) ctlres.Resource {
+	return ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: secret-reader
+  namespace: ` + ns + `
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: secret-reader
+subjects:
+- kind: ServiceAccount
+  name: app
+  namespace: ` + ns + `
+`))
+}
+
+type deniedVerbsSSARClient struct {
+	authv1client.SelfSubjectAccessReviewInterface
+
+	denied map[string]struct{}
+}
+
+func (c *deniedVerbsSSARClient) Create(_ context.Context, ssar *authv1.SelfSubjectAccessReview, _ metav1.CreateOptions) (*authv1.SelfSubjectAccessReview, error) {
+	_, denied := c.denied[ssar.Spec.ResourceAttributes.Verb]
+	ssar.Status.Allowed = !denied
+	return ssar, nil
+}
+
+type fakeRbacClient struct {
+	rbacv1client.RbacV1Interface
+
+	roleRules []rbacv1.PolicyRule
+}
+
+func (c *fakeRbacClient) Roles(string) rbacv1client.RoleInterface {
+	return fakeRoles{rules: c.roleRules}
+}
+
+type fakeRoles struct {
+	rbacv1client.RoleInterface
+
+	rules []rbacv1.PolicyRule
+}
+
+func (r fakeRoles) Get(_ context.Context, name string, _ metav1.GetOptions) (*rbacv1.Role, error) {
+	return &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: name}, Rules: r.rules}, nil
+}
@@ -5,13 +5,22 @@ package permissions
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
 	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
 	"carvel.dev/kapp/pkg/kapp/preflight"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/pflag"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
 )
 
 // Preflight is an implementation of preflight.Check
@@ -19,16 +28,50 @@ import (
 // as a preflight check
 type Preflight struct {
 	depsFactory cmdcore.DepsFactory
+	ui          ui.UI
 	enabled     bool
+
+	dangerousSkipBindingEscalationCheck bool
+	debugPermissions                    bool
+	maxConcurrentSSAR                   int
+	verbs                               []string
+	escalationExemptions                []ctlres.ResourceMatcher
+}
+
+var (
+	// Verbs that are checked for resources kapp would create, update or delete
+	preflightVerbs = []string{"create", "update", "delete"}
+)
+
+// PreflightConfig is configuration provided via
+// preflightRules in kapp Config for PermissionValidation check
+type PreflightConfig struct {
+	// EscalationExemptions matches (Cluster)RoleBindings
+	// that are not checked for privilege escalation
+	EscalationExemptions []ctlconf.ResourceMatcher `json:"escalationExemptions"`
 }
 
-func NewPreflight(depsFactory cmdcore.DepsFactory, enabled bool) preflight.Check {
+func NewPreflight(depsFactory cmdcore.DepsFactory, ui ui.UI, enabled bool) preflight.Check {
 	return &Preflight{
 		depsFactory: depsFactory,
+		ui:          ui,
 		enabled:     enabled,
 	}
 }
 
+var _ preflight.CheckWithFlags = &Preflight{}
+
+func (p *Preflight) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&p.dangerousSkipBindingEscalationCheck, "dangerous-skip-binding-escalation-check", false,
+		"Skip checking that (Cluster)RoleBindings do not grant more permissions than user has during PermissionValidation preflight check")
+	flags.BoolVar(&p.debugPermissions, "debug-permissions", false,
+		"Show each access review made during PermissionValidation preflight check together with its result")
+	flags.IntVar(&p.maxConcurrentSSAR, "max-concurrent-ssar", 10,
+		"Maximum number of concurrent access reviews made during PermissionValidation preflight check")
+	flags.StringSliceVar(&p.verbs, "preflight-verbs", preflightVerbs,
+		"Verbs to check during PermissionValidation preflight check (e.g. create when resources are never updated or deleted)")
+}
+
 func (p *Preflight) Enabled() bool {
 	return p.enabled
 }
@@ -37,11 +80,45 @@ func (p *Preflight) SetEnabled(enabled bool) {
 	p.enabled = enabled
 }
 
-func (p *Preflight) SetConfig(_ preflight.CheckConfig) error {
+func (p *Preflight) SetConfig(cfg preflight.CheckConfig) error {
+	p.escalationExemptions = nil
+
+	if len(cfg) == 0 {
+		return nil
+	}
+
+	bs, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	var conf PreflightConfig
+
+	err = json.Unmarshal(bs, &conf)
+	if err != nil {
+		return fmt.Errorf("Unmarshaling PermissionValidation config: %w", err)
+	}
+
+	err = ctlconf.ResourceMatchers(conf.EscalationExemptions).Validate()
+	if err != nil {
+		return fmt.Errorf("Validating escalationExemptions: %w", err)
+	}
+
+	p.escalationExemptions = ctlconf.ResourceMatchers(conf.EscalationExemptions).AsResourceMatchers()
+
 	return nil
 }
 
 func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph) error {
+	if p.maxConcurrentSSAR < 1 {
+		return fmt.Errorf("Expected --max-concurrent-ssar to be >= 1, but was %d", p.maxConcurrentSSAR)
+	}
+
+	verbs, err := p.checkedVerbs()
+	if err != nil {
+		return err
+	}
+
 	client, err := p.depsFactory.CoreClient()
 	if err != nil {
 		return err
@@ -52,9 +129,27 @@ func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph)
 		return err
 	}
 
-	roleValidator := NewRoleValidator(client.AuthorizationV1().SelfSubjectAccessReviews(), mapper)
-	bindingValidator := NewBindingValidator(client.AuthorizationV1().SelfSubjectAccessReviews(), client.RbacV1(), mapper)
-	basicValidator := NewBasicValidator(client.AuthorizationV1().SelfSubjectAccessReviews(), mapper)
+	var ssarClient authv1client.SelfSubjectAccessReviewInterface = NewLimitedSSARClient(
+		client.AuthorizationV1().SelfSubjectAccessReviews(), p.maxConcurrentSSAR)
+	if p.debugPermissions {
+		ssarClient = NewDebugSSARClient(ssarClient, func(line string) {
+			p.ui.PrintLinef("permissions: %s", line)
+		})
+	}
+
+	roleValidator := NewRoleValidator(ssarClient, mapper)
+	bindingValidator := NewBindingValidator(ssarClient, client.RbacV1(), mapper)
+	if p.dangerousSkipBindingEscalationCheck {
+		bindingValidator.DangerouslySkipEscalationCheck(func(res ctlres.Resource) {
+			p.ui.ErrorLinef("Warning: Skipped privilege escalation check for %s (--dangerous-skip-binding-escalation-check)", res.Description())
+		})
+	}
+	if len(p.escalationExemptions) > 0 {
+		bindingValidator.ExemptFromEscalationCheck(p.escalationExemptions, func(res ctlres.Resource) {
+			p.ui.ErrorLinef("Skipped privilege escalation check for %s (matched escalationExemptions in PermissionValidation config)", res.Description())
+		})
+	}
+	basicValidator := NewBasicValidator(ssarClient, mapper)
 
 	validator := NewCompositeValidator(basicValidator, map[schema.GroupVersionKind]Validator{
 		rbacv1.SchemeGroupVersion.WithKind("Role"):               roleValidator,
@@ -63,26 +158,26 @@ func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph)
 		rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"): bindingValidator,
 	})
 
-	errorSet := []error{}
-	for _, change := range changeGraph.All() {
-		switch change.Change.Op() {
-		case ctldgraph.ActualChangeOpDelete:
-			err = validator.Validate(ctx, change.Change.Resource(), "delete")
-			if err != nil {
-				errorSet = append(errorSet, err)
-			}
-		case ctldgraph.ActualChangeOpUpsert:
-			// Check both create and update permissions
-			err = validator.Validate(ctx, change.Change.Resource(), "create")
-			if err != nil {
-				errorSet = append(errorSet, err)
-			}
+	changes := changeGraph.All()
 
-			err = validator.Validate(ctx, change.Change.Resource(), "update")
-			if err != nil {
-				errorSet = append(errorSet, err)
-			}
-		}
+	// Changes are validated concurrently (bounded by number of
+	// concurrent access reviews); errors are kept in changes order
+	changeErrs := make([][]error, len(changes))
+	var wg sync.WaitGroup
+
+	for i, change := range changes {
+		wg.Add(1)
+		go func(i int, change *ctldgraph.Change) {
+			defer wg.Done()
+			changeErrs[i] = p.validateChange(ctx, validator, change, verbs)
+		}(i, change)
+	}
+
+	wg.Wait()
+
+	errorSet := []error{}
+	for _, errs := range changeErrs {
+		errorSet = append(errorSet, errs...)
 	}
 
 	if len(errorSet) > 0 {
@@ -91,3 +186,56 @@ func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph)
 
 	return nil
 }
+
+func (*Preflight) validateChange(ctx context.Context, validator Validator,
+	change *ctldgraph.Change, verbs map[string]struct{}) []error {
+
+	var changeVerbs []string
+
+	switch change.Change.Op() {
+	case ctldgraph.ActualChangeOpDelete:
+		changeVerbs = []string{"delete"}
+	case ctldgraph.ActualChangeOpUpsert:
+		// Check both create and update permissions
+		changeVerbs = []string{"create", "update"}
+	}
+
+	var errs []error
+
+	for _, verb := range changeVerbs {
+		if _, found := verbs[verb]; !found {
+			continue
+		}
+		err := validator.Validate(ctx, change.Change.Resource(), verb)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func (p *Preflight) checkedVerbs() (map[string]struct{}, error) {
+	if len(p.verbs) == 0 {
+		return nil, fmt.Errorf("Expected --preflight-verbs to include at least one verb (one of: %s)", strings.Join(preflightVerbs, ", "))
+	}
+
+	verbs := map[string]struct{}{}
+
+	for _, verb := range p.verbs {
+		var known bool
+		for _, knownVerb := range preflightVerbs {
+			if verb == knownVerb {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("Expected --preflight-verbs to only include known verbs (one of: %s), but was '%s'",
+				strings.Join(preflightVerbs, ", "), verb)
+		}
+		verbs[verb] = struct{}{}
+	}
+
+	return verbs, nil
+}
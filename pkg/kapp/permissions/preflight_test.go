@@ -0,0 +1,109 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"context"
+	"testing"
+
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightSetConfigEscalationExemptions(t *testing.T) {
+	p := &Preflight{}
+
+	err := p.SetConfig(map[string]any{
+		"escalationExemptions": []any{
+			map[string]any{"hasNamespaceMatcher": map[string]any{"names": []any{"trusted"}}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ctlres.ResourceMatcher{ctlres.HasNamespaceMatcher{Names: []string{"trusted"}}}, p.escalationExemptions)
+
+	err = p.SetConfig(nil)
+	require.NoError(t, err)
+	require.Empty(t, p.escalationExemptions, "Expected exemptions to be reset")
+
+	err = p.SetConfig(map[string]any{
+		"escalationExemptions": []any{
+			map[string]any{"nameRegexMatcher": map[string]any{"regex": "("}},
+		},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Validating escalationExemptions: Validating resource matcher 0:")
+
+	err = p.SetConfig(map[string]any{"escalationExemptions": "invalid"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Unmarshaling PermissionValidation config:")
+}
+
+func TestPreflightVerbs(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cfg
+  namespace: default
+`))
+
+	upsertChange := &ctldgraph.Change{Change: preflightActualChange{res, ctldgraph.ActualChangeOpUpsert}}
+	deleteChange := &ctldgraph.Change{Change: preflightActualChange{res, ctldgraph.ActualChangeOpDelete}}
+
+	validatedVerbs := func(t *testing.T, args ...string) []string {
+		p := &Preflight{}
+
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		p.AddFlags(flags)
+		require.NoError(t, flags.Parse(args))
+
+		verbs, err := p.checkedVerbs()
+		require.NoError(t, err)
+
+		validator := &verbsRecordingValidator{}
+		for _, change := range []*ctldgraph.Change{upsertChange, deleteChange} {
+			require.Empty(t, p.validateChange(context.Background(), validator, change, verbs))
+		}
+		return validator.verbs
+	}
+
+	t.Run("checks all verbs by default", func(t *testing.T) {
+		require.Equal(t, []string{"create", "update", "delete"}, validatedVerbs(t))
+	})
+
+	t.Run("checks only specified verbs", func(t *testing.T) {
+		require.Equal(t, []string{"create"}, validatedVerbs(t, "--preflight-verbs=create"))
+		require.Equal(t, []string{"update", "delete"}, validatedVerbs(t, "--preflight-verbs=delete,update"))
+	})
+
+	t.Run("rejects unknown verbs", func(t *testing.T) {
+		_, err := (&Preflight{verbs: []string{"create", "patch"}}).checkedVerbs()
+		require.EqualError(t, err, "Expected --preflight-verbs to only include known verbs (one of: create, update, delete), but was 'patch'")
+	})
+
+	t.Run("rejects empty verbs", func(t *testing.T) {
+		_, err := (&Preflight{}).checkedVerbs()
+		require.EqualError(t, err, "Expected --preflight-verbs to include at least one verb (one of: create, update, delete)")
+	})
+}
+
+type preflightActualChange struct {
+	res ctlres.Resource
+	op  ctldgraph.ActualChangeOp
+}
+
+func (c preflightActualChange) Resource() ctlres.Resource    { return c.res }
+func (c preflightActualChange) Op() ctldgraph.ActualChangeOp { return c.op }
+
+// verbsRecordingValidator allows everything and records validated verbs
+type verbsRecordingValidator struct {
+	verbs []string
+}
+
+func (v *verbsRecordingValidator) Validate(_ context.Context, _ ctlres.Resource, verb string) error {
+	v.verbs = append(v.verbs, verb)
+	return nil
+}
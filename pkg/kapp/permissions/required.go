@@ -0,0 +1,248 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"fmt"
+	"sort"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// Verbs kapp uses when deploying and deleting resources
+	requiredResourceVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+	// Verbs kapp uses to keep track of app record (stored in a ConfigMap)
+	requiredAppRecordVerbs = []string{"get", "list", "create", "update", "patch", "delete"}
+)
+
+// RequiredPermissions calculates RBAC rules that are necessary
+// to deploy a set of resources with kapp
+type RequiredPermissions struct {
+	mapper meta.RESTMapper
+}
+
+type requiredResource struct {
+	GroupResource schema.GroupResource
+	Namespaced    bool
+}
+
+func NewRequiredPermissions(mapper meta.RESTMapper) RequiredPermissions {
+	return RequiredPermissions{mapper: mapper}
+}
+
+// Rules returns policy rules for given resources. Since creating
+// (Cluster)Roles and (Cluster)RoleBindings is subject to privilege
+// escalation checks, rules that allow "escalate" on included roles
+// and "bind" on referenced roles are added as well; otherwise deployer
+// would have to already hold all permissions granted by those roles.
+// Returned namespace is non-empty when all resources belong to
+// a single namespace, hence could be deployed with a Role.
+func (p RequiredPermissions) Rules(rs []ctlres.Resource) ([]rbacv1.PolicyRule, string, error) {
+	crdResources, err := p.crdResources(rs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	namespaced := true
+	namespaces := map[string]struct{}{}
+	resourcesByGroup := map[string]map[string]struct{}{}
+	escalateRoles := map[schema.GroupResource]map[string]struct{}{}
+	bindRoles := map[schema.GroupResource]map[string]struct{}{}
+
+	addName := func(m map[schema.GroupResource]map[string]struct{}, gr schema.GroupResource, name string) {
+		if _, found := m[gr]; !found {
+			m[gr] = map[string]struct{}{}
+		}
+		m[gr][name] = struct{}{}
+	}
+
+	for _, res := range rs {
+		reqRes, err := p.resource(res, crdResources)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if reqRes.Namespaced {
+			namespaces[res.Namespace()] = struct{}{}
+		} else {
+			namespaced = false
+		}
+
+		if _, found := resourcesByGroup[reqRes.GroupResource.Group]; !found {
+			resourcesByGroup[reqRes.GroupResource.Group] = map[string]struct{}{}
+		}
+		resourcesByGroup[reqRes.GroupResource.Group][reqRes.GroupResource.Resource] = struct{}{}
+
+		if res.APIGroup() != rbacv1.GroupName {
+			continue
+		}
+
+		switch res.Kind() {
+		case "Role", "ClusterRole":
+			addName(escalateRoles, reqRes.GroupResource, res.Name())
+
+		case "RoleBinding", "ClusterRoleBinding":
+			var binding rbacv1.RoleBinding // ClusterRoleBinding has same roleRef structure
+
+			err := res.AsUncheckedTypedObj(&binding)
+			if err != nil {
+				return nil, "", fmt.Errorf("Converting resource '%s' to binding: %w", res.Description(), err)
+			}
+
+			switch binding.RoleRef.Kind {
+			case "Role":
+				addName(bindRoles, rbacv1.Resource("roles"), binding.RoleRef.Name)
+			case "ClusterRole":
+				addName(bindRoles, rbacv1.Resource("clusterroles"), binding.RoleRef.Name)
+				if res.Kind() == "ClusterRoleBinding" {
+					namespaced = false
+				}
+			}
+		}
+	}
+
+	var rules []rbacv1.PolicyRule
+
+	var groups []string
+	for group := range resourcesByGroup {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{group},
+			Resources: p.sortedKeys(resourcesByGroup[group]),
+			Verbs:     requiredResourceVerbs,
+		})
+	}
+
+	rules = append(rules, rbacv1.PolicyRule{
+		APIGroups: []string{corev1.GroupName},
+		Resources: []string{"configmaps"},
+		Verbs:     requiredAppRecordVerbs,
+	})
+
+	rules = append(rules, p.namedRules(escalateRoles, "escalate")...)
+	rules = append(rules, p.namedRules(bindRoles, "bind")...)
+
+	var namespace string
+	if namespaced && len(namespaces) == 1 {
+		namespace = p.sortedKeys(namespaces)[0]
+	}
+
+	return rules, namespace, nil
+}
+
+// Role returns a Role (if possible) or a ClusterRole that contains required rules
+func (p RequiredPermissions) Role(name string, rs []ctlres.Resource) (runtime.Object, error) {
+	rules, namespace, err := p.Rules(rs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(namespace) > 0 {
+		return &rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Rules:      rules,
+		}, nil
+	}
+
+	return &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Rules:      rules,
+	}, nil
+}
+
+func (p RequiredPermissions) resource(res ctlres.Resource, crdResources map[schema.GroupKind]requiredResource) (requiredResource, error) {
+	// Prefer CRDs included in the set as they may not be installed yet
+	if reqRes, found := crdResources[res.GroupKind()]; found {
+		return reqRes, nil
+	}
+
+	mapping, err := p.mapper.RESTMapping(res.GroupKind(), res.GroupVersion().Version)
+	if err != nil {
+		return requiredResource{}, fmt.Errorf("Mapping resource '%s': %w", res.Description(), err)
+	}
+
+	return requiredResource{
+		GroupResource: mapping.Resource.GroupResource(),
+		Namespaced:    mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+	}, nil
+}
+
+func (RequiredPermissions) crdResources(rs []ctlres.Resource) (map[schema.GroupKind]requiredResource, error) {
+	result := map[schema.GroupKind]requiredResource{}
+
+	for _, res := range rs {
+		crd := ctlresm.NewAPIExtensionsVxCRD(res)
+		if crd == nil {
+			continue
+		}
+
+		group, err := crd.Group()
+		if err != nil {
+			return nil, err
+		}
+		kind, err := crd.Kind()
+		if err != nil {
+			return nil, err
+		}
+		plural, err := crd.Plural()
+		if err != nil {
+			return nil, err
+		}
+		namespaced, err := crd.Namespaced()
+		if err != nil {
+			return nil, err
+		}
+
+		result[schema.GroupKind{Group: group, Kind: kind}] = requiredResource{
+			GroupResource: schema.GroupResource{Group: group, Resource: plural},
+			Namespaced:    namespaced,
+		}
+	}
+
+	return result, nil
+}
+
+func (p RequiredPermissions) namedRules(namesByResource map[schema.GroupResource]map[string]struct{}, verb string) []rbacv1.PolicyRule {
+	var grs []schema.GroupResource
+	for gr := range namesByResource {
+		grs = append(grs, gr)
+	}
+	sort.Slice(grs, func(i, j int) bool { return grs[i].String() < grs[j].String() })
+
+	var rules []rbacv1.PolicyRule
+
+	for _, gr := range grs {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{gr.Group},
+			Resources:     []string{gr.Resource},
+			ResourceNames: p.sortedKeys(namesByResource[gr]),
+			Verbs:         []string{verb},
+		})
+	}
+
+	return rules
+}
+
+func (RequiredPermissions) sortedKeys(m map[string]struct{}) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	expectedResourceVerbs  = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+	expectedAppRecordRules = rbacv1.PolicyRule{
+		APIGroups: []string{""},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"get", "list", "create", "update", "patch", "delete"},
+	}
+)
+
+func TestRequiredPermissionsNamespacedRole(t *testing.T) {
+	rs := mustNewRequiredPermissionsResources(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: app-ns
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: app-ns
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: app-role
+  namespace: app-ns
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: app-binding
+  namespace: app-ns
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: app-role
+`)
+
+	role, err := permissions.NewRequiredPermissions(newRequiredPermissionsMapper()).Role("deployer", rs)
+	require.NoError(t, err)
+
+	require.Equal(t, &rbacv1.Role{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+		ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "app-ns"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: expectedResourceVerbs},
+			{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: expectedResourceVerbs},
+			{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"rolebindings", "roles"}, Verbs: expectedResourceVerbs},
+			expectedAppRecordRules,
+			{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"roles"}, ResourceNames: []string{"app-role"}, Verbs: []string{"escalate"}},
+			{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"roles"}, ResourceNames: []string{"app-role"}, Verbs: []string{"bind"}},
+		},
+	}, role)
+}
+
+func TestRequiredPermissionsClusterRole(t *testing.T) {
+	t.Run("resources in multiple namespaces", func(t *testing.T) {
+		rs := mustNewRequiredPermissionsResources(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: ns1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: ns2
+`)
+
+		role, err := permissions.NewRequiredPermissions(newRequiredPermissionsMapper()).Role("deployer", rs)
+		require.NoError(t, err)
+		require.IsType(t, &rbacv1.ClusterRole{}, role)
+		require.Equal(t, "deployer", role.(*rbacv1.ClusterRole).Name)
+	})
+
+	t.Run("role binding referencing cluster role", func(t *testing.T) {
+		rs := mustNewRequiredPermissionsResources(t, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: app-binding
+  namespace: app-ns
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: view
+`)
+
+		rules, namespace, err := permissions.NewRequiredPermissions(newRequiredPermissionsMapper()).Rules(rs)
+		require.NoError(t, err)
+		require.Equal(t, "app-ns", namespace, "Expected binding in a namespace to only require a Role")
+		require.Contains(t, rules, rbacv1.PolicyRule{APIGroups: []string{"rbac.authorization.k8s.io"},
+			Resources: []string{"clusterroles"}, ResourceNames: []string{"view"}, Verbs: []string{"bind"}})
+	})
+
+	t.Run("cluster role binding and cluster role", func(t *testing.T) {
+		rs := mustNewRequiredPermissionsResources(t, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: app-cluster-role
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: app-binding
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: app-cluster-role
+`)
+
+		role, err := permissions.NewRequiredPermissions(newRequiredPermissionsMapper()).Role("deployer", rs)
+		require.NoError(t, err)
+
+		require.Equal(t, &rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: "deployer"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterrolebindings", "clusterroles"}, Verbs: expectedResourceVerbs},
+				expectedAppRecordRules,
+				{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}, ResourceNames: []string{"app-cluster-role"}, Verbs: []string{"escalate"}},
+				{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}, ResourceNames: []string{"app-cluster-role"}, Verbs: []string{"bind"}},
+			},
+		}, role)
+	})
+}
+
+func TestRequiredPermissionsIncludedCRDs(t *testing.T) {
+	rs := mustNewRequiredPermissionsResources(t, `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: app-ns
+`)
+
+	rules, namespace, err := permissions.NewRequiredPermissions(newRequiredPermissionsMapper()).Rules(rs)
+	require.NoError(t, err)
+	require.Empty(t, namespace, "Expected cluster-scoped CRD to require a ClusterRole")
+
+	require.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{"apiextensions.k8s.io"}, Resources: []string{"customresourcedefinitions"}, Verbs: expectedResourceVerbs},
+		{APIGroups: []string{"example.com"}, Resources: []string{"widgets"}, Verbs: expectedResourceVerbs},
+		expectedAppRecordRules,
+	}, rules)
+}
+
+func TestRequiredPermissionsUnknownKind(t *testing.T) {
+	rs := mustNewRequiredPermissionsResources(t, `
+apiVersion: example.com/v1
+kind: Gadget
+metadata:
+  name: gadget
+  namespace: app-ns
+`)
+
+	_, _, err := permissions.NewRequiredPermissions(newRequiredPermissionsMapper()).Rules(rs)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Mapping resource 'gadget/gadget (example.com/v1) namespace: app-ns'")
+}
+
+func newRequiredPermissionsMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("Role"), meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("RoleBinding"), meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"), meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1",
+		Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+	return mapper
+}
+
+func mustNewRequiredPermissionsResources(t *testing.T, data string) []ctlres.Resource {
+	rs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(data))).Resources()
+	require.NoError(t, err)
+	return rs
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// DebugSSARClient records every SelfSubjectAccessReview submitted
+// by validators (including ones made for each subrule of a role)
+// together with its result. Each review is reported as a single line
+// of key=value pairs so that output can be easily grepped.
+type DebugSSARClient struct {
+	authv1client.SelfSubjectAccessReviewInterface
+	logFunc func(string)
+	logLock *sync.Mutex
+}
+
+var _ authv1client.SelfSubjectAccessReviewInterface = DebugSSARClient{}
+
+func NewDebugSSARClient(ssarClient authv1client.SelfSubjectAccessReviewInterface, logFunc func(string)) DebugSSARClient {
+	return DebugSSARClient{ssarClient, logFunc, &sync.Mutex{}}
+}
+
+func (c DebugSSARClient) Create(ctx context.Context, ssar *authv1.SelfSubjectAccessReview, opts metav1.CreateOptions) (*authv1.SelfSubjectAccessReview, error) {
+	retSsar, err := c.SelfSubjectAccessReviewInterface.Create(ctx, ssar, opts)
+
+	pairs := []string{"ssar"}
+	if attrs := ssar.Spec.ResourceAttributes; attrs != nil {
+		pairs = append(pairs,
+			c.pair("verb", attrs.Verb),
+			c.pair("group", attrs.Group),
+			c.pair("version", attrs.Version),
+			c.pair("resource", attrs.Resource),
+			c.pair("subresource", attrs.Subresource),
+			c.pair("namespace", attrs.Namespace),
+			c.pair("name", attrs.Name),
+		)
+	}
+
+	switch {
+	case err != nil:
+		pairs = append(pairs, c.pair("result", "error"), c.pair("error", err.Error()))
+	case retSsar == nil:
+		pairs = append(pairs, c.pair("result", "error"), c.pair("error", "returned SelfSubjectAccessReview is nil"))
+	case retSsar.Status.EvaluationError != "":
+		pairs = append(pairs, c.pair("result", "error"), c.pair("error", retSsar.Status.EvaluationError))
+	case retSsar.Status.Allowed:
+		pairs = append(pairs, c.pair("result", "allowed"))
+	default:
+		pairs = append(pairs, c.pair("result", "denied"))
+		if len(retSsar.Status.Reason) > 0 {
+			pairs = append(pairs, c.pair("reason", retSsar.Status.Reason))
+		}
+	}
+
+	// Reviews may be made concurrently
+	c.logLock.Lock()
+	c.logFunc(strings.Join(pairs, " "))
+	c.logLock.Unlock()
+
+	return retSsar, err
+}
+
+func (DebugSSARClient) pair(key, val string) string {
+	// Quote values only when necessary to keep common output terse
+	if len(val) == 0 || strings.ContainsAny(val, " \t\n\"=") {
+		val = strconv.Quote(val)
+	}
+	return fmt.Sprintf("%s=%s", key, val)
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+func TestDebugSSARClient(t *testing.T) {
+	var lines []string
+
+	ssarClient := permissions.NewDebugSSARClient(&deniedVerbsSSARClient{denied: map[string]struct{}{"delete": {}}},
+		func(line string) { lines = append(lines, line) })
+
+	newSSAR := func(verb string) *authv1.SelfSubjectAccessReview {
+		return &authv1.SelfSubjectAccessReview{Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Verb: verb, Group: "apps", Version: "v1", Resource: "deployments", Namespace: "default", Name: "app",
+			},
+		}}
+	}
+
+	ssar, err := ssarClient.Create(context.Background(), newSSAR("create"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.True(t, ssar.Status.Allowed, "Expected result to be passed through")
+
+	ssar, err = ssarClient.Create(context.Background(), newSSAR("delete"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.False(t, ssar.Status.Allowed)
+
+	require.Equal(t, []string{
+		`ssar verb=create group=apps version=v1 resource=deployments subresource="" namespace=default name=app result=allowed`,
+		`ssar verb=delete group=apps version=v1 resource=deployments subresource="" namespace=default name=app result=denied`,
+	}, lines)
+}
+
+func TestDebugSSARClientErrors(t *testing.T) {
+	var lines []string
+
+	ssarClient := permissions.NewDebugSSARClient(erroringSSARClient{}, func(line string) { lines = append(lines, line) })
+
+	_, err := ssarClient.Create(context.Background(), &authv1.SelfSubjectAccessReview{Spec: authv1.SelfSubjectAccessReviewSpec{
+		ResourceAttributes: &authv1.ResourceAttributes{Verb: "get", Version: "v1", Resource: "secrets"},
+	}}, metav1.CreateOptions{})
+	require.EqualError(t, err, "connection refused")
+
+	require.Equal(t, []string{`ssar verb=get group="" version=v1 resource=secrets subresource="" namespace="" name="" ` +
+		`result=error error="connection refused"`}, lines)
+}
+
+type erroringSSARClient struct {
+	authv1client.SelfSubjectAccessReviewInterface
+}
+
+func (erroringSSARClient) Create(context.Context, *authv1.SelfSubjectAccessReview, metav1.CreateOptions) (*authv1.SelfSubjectAccessReview, error) {
+	return nil, fmt.Errorf("connection refused")
+}
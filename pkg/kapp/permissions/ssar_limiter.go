@@ -0,0 +1,35 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"context"
+
+	"carvel.dev/kapp/pkg/kapp/util"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// LimitedSSARClient bounds number of SelfSubjectAccessReviews
+// that are in flight at the same time. Validators that share
+// the same client are limited together, so that validating many
+// (Cluster)Roles and (Cluster)RoleBindings does not overwhelm API server.
+type LimitedSSARClient struct {
+	authv1client.SelfSubjectAccessReviewInterface
+	throttle util.Throttle
+}
+
+var _ authv1client.SelfSubjectAccessReviewInterface = LimitedSSARClient{}
+
+func NewLimitedSSARClient(ssarClient authv1client.SelfSubjectAccessReviewInterface, maxConcurrent int) LimitedSSARClient {
+	return LimitedSSARClient{ssarClient, util.NewThrottle(maxConcurrent)}
+}
+
+func (c LimitedSSARClient) Create(ctx context.Context, ssar *authv1.SelfSubjectAccessReview, opts metav1.CreateOptions) (*authv1.SelfSubjectAccessReview, error) {
+	c.throttle.Take()
+	defer c.throttle.Done()
+
+	return c.SelfSubjectAccessReviewInterface.Create(ctx, ssar, opts)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+func TestLimitedSSARClientBoundsConcurrentReviews(t *testing.T) {
+	fakeClient := &fakeSSARClient{}
+	ssarClient := permissions.NewLimitedSSARClient(fakeClient, 3)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	// Validators share the same client hence the same limit
+	validators := []permissions.Validator{
+		permissions.NewBasicValidator(ssarClient, mapper),
+		permissions.NewRoleValidator(ssarClient, mapper),
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		res := ctlres.MustNewResourceFromBytes([]byte(fmt.Sprintf(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-%d
+  namespace: default
+`, i)))
+
+		for _, validator := range validators {
+			wg.Add(1)
+			go func(validator permissions.Validator) {
+				defer wg.Done()
+				require.NoError(t, validator.Validate(context.Background(), res, "delete"))
+			}(validator)
+		}
+	}
+
+	wg.Wait()
+
+	require.Equal(t, int64(40), fakeClient.total.Load())
+	require.Equal(t, int64(3), fakeClient.maxInFlight.Load())
+}
+
+type fakeSSARClient struct {
+	authv1client.SelfSubjectAccessReviewInterface
+
+	inFlight    atomic.Int64
+	maxInFlight atomic.Int64
+	total       atomic.Int64
+}
+
+func (c *fakeSSARClient) Create(_ context.Context, ssar *authv1.SelfSubjectAccessReview, _ metav1.CreateOptions) (*authv1.SelfSubjectAccessReview, error) {
+	inFlight := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+
+	for {
+		maxInFlight := c.maxInFlight.Load()
+		if inFlight <= maxInFlight || c.maxInFlight.CompareAndSwap(maxInFlight, inFlight) {
+			break
+		}
+	}
+
+	c.total.Add(1)
+
+	// Give other reviews a chance to pile up
+	time.Sleep(10 * time.Millisecond)
+
+	ssar.Status.Allowed = true
+	return ssar, nil
+}
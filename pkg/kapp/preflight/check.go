@@ -7,6 +7,7 @@ import (
 	"context"
 
 	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"github.com/spf13/pflag"
 )
 
 type CheckFunc func(context.Context, *ctldgraph.ChangeGraph, CheckConfig) error
@@ -20,6 +21,12 @@ type Check interface {
 	Run(context.Context, *ctldgraph.ChangeGraph) error
 }
 
+// CheckWithFlags is optionally implemented by checks
+// that are configurable via command line flags
+type CheckWithFlags interface {
+	AddFlags(*pflag.FlagSet)
+}
+
 type checkImpl struct {
 	enabled   bool
 	checkFunc CheckFunc
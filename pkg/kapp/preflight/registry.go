@@ -6,6 +6,7 @@ package preflight
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"carvel.dev/kapp/pkg/kapp/config"
@@ -94,6 +95,14 @@ func (c *Registry) AddFlags(flags *pflag.FlagSet) {
 		knownChecks = append(knownChecks, name)
 	}
 	flags.Var(c, preflightFlag, fmt.Sprintf("preflight checks to run. Available preflight checks are [%s]", strings.Join(knownChecks, ",")))
+
+	sort.Strings(knownChecks)
+
+	for _, name := range knownChecks {
+		if check, ok := c.known[name].(CheckWithFlags); ok {
+			check.AddFlags(flags)
+		}
+	}
 }
 
 // AddCheck adds a new preflight check to the registry.
@@ -10,6 +10,7 @@ import (
 	ctlconf "carvel.dev/kapp/pkg/kapp/config"
 	"carvel.dev/kapp/pkg/kapp/diffgraph"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
 )
 
@@ -333,3 +334,29 @@ preflightRules:
 		})
 	}
 }
+
+type checkWithFlags struct {
+	Check
+	value bool
+}
+
+func (c *checkWithFlags) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&c.value, "some-check-flag", false, "")
+}
+
+func TestRegistryAddFlags(t *testing.T) {
+	check := &checkWithFlags{Check: NewCheck(func(_ context.Context, _ *diffgraph.ChangeGraph, _ CheckConfig) error {
+		return nil
+	}, nil, false)}
+
+	registry := NewRegistry(map[string]Check{"someCheck": check})
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	registry.AddFlags(flags)
+
+	err := flags.Parse([]string{"--preflight", "someCheck", "--some-check-flag"})
+	require.NoError(t, err)
+
+	require.True(t, check.Enabled())
+	require.True(t, check.value)
+}
@@ -0,0 +1,302 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CRDSchemas validates custom resources against schemas of CRDs
+// that are provided together with them (i.e. before API server knows about them).
+// Only commonly used subset of OpenAPI v3 validations is checked
+// (types, required fields, enums, patterns, lengths and bounds).
+type CRDSchemas struct {
+	schemasByGVK map[schema.GroupVersionKind]*apiextv1.JSONSchemaProps
+}
+
+func NewCRDSchemas(resources []Resource) (CRDSchemas, error) {
+	schemas := CRDSchemas{schemasByGVK: map[schema.GroupVersionKind]*apiextv1.JSONSchemaProps{}}
+
+	crdMatcher := APIVersionKindMatcher{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"}
+
+	for _, res := range resources {
+		if !crdMatcher.Matches(res) {
+			continue
+		}
+
+		var crd apiextv1.CustomResourceDefinition
+
+		err := res.AsUncheckedTypedObj(&crd)
+		if err != nil {
+			return CRDSchemas{}, fmt.Errorf("Reading CRD '%s': %w", res.Description(), err)
+		}
+
+		for _, ver := range crd.Spec.Versions {
+			if ver.Schema == nil || ver.Schema.OpenAPIV3Schema == nil {
+				continue
+			}
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: ver.Name, Kind: crd.Spec.Names.Kind}
+			schemas.schemasByGVK[gvk] = ver.Schema.OpenAPIV3Schema
+		}
+	}
+
+	return schemas, nil
+}
+
+// InvalidFields returns descriptions of fields (e.g. 'spec.replicas: Invalid value...')
+// that do not match schema. Resources without provided CRD are not checked.
+func (s CRDSchemas) InvalidFields(res Resource, includeUnknownFields bool) []string {
+	resSchema, found := s.schemasByGVK[res.GroupVersion().WithKind(res.Kind())]
+	if !found {
+		return nil
+	}
+
+	v := crdSchemaValidator{includeUnknownFields: includeUnknownFields}
+	v.validateObject(resSchema, res.UnstructuredObject(), "", true)
+
+	sort.Strings(v.errs)
+	return v.errs
+}
+
+type crdSchemaValidator struct {
+	includeUnknownFields bool
+	errs                 []string
+}
+
+func (v *crdSchemaValidator) addErr(path, msg string, args ...interface{}) {
+	if len(path) == 0 {
+		path = "<root>"
+	}
+	v.errs = append(v.errs, path+": "+fmt.Sprintf(msg, args...))
+}
+
+func (v *crdSchemaValidator) validate(sch *apiextv1.JSONSchemaProps, val interface{}, path string) {
+	if val == nil {
+		if !sch.Nullable && len(sch.Type) > 0 {
+			v.addErr(path, "Invalid value: null (expected %s)", sch.Type)
+		}
+		return
+	}
+
+	if sch.XIntOrString {
+		switch val.(type) {
+		case string, int64, int, float64:
+		default:
+			v.addErr(path, "Invalid value: expected integer or string, got %s", v.typeName(val))
+		}
+		return
+	}
+
+	if len(sch.Enum) > 0 {
+		v.validateEnum(sch.Enum, val, path)
+	}
+
+	switch sch.Type {
+	case "object":
+		typedVal, ok := val.(map[string]interface{})
+		if !ok {
+			v.addErr(path, "Invalid value: expected object, got %s", v.typeName(val))
+			return
+		}
+		v.validateObject(sch, typedVal, path, false)
+
+	case "array":
+		typedVal, ok := val.([]interface{})
+		if !ok {
+			v.addErr(path, "Invalid value: expected array, got %s", v.typeName(val))
+			return
+		}
+		if sch.MinItems != nil && int64(len(typedVal)) < *sch.MinItems {
+			v.addErr(path, "Invalid value: should have at least %d items", *sch.MinItems)
+		}
+		if sch.MaxItems != nil && int64(len(typedVal)) > *sch.MaxItems {
+			v.addErr(path, "Invalid value: should have at most %d items", *sch.MaxItems)
+		}
+		if sch.Items != nil && sch.Items.Schema != nil {
+			for i, item := range typedVal {
+				v.validate(sch.Items.Schema, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+
+	case "string":
+		typedVal, ok := val.(string)
+		if !ok {
+			v.addErr(path, "Invalid value: expected string, got %s", v.typeName(val))
+			return
+		}
+		length := int64(len([]rune(typedVal)))
+		if sch.MinLength != nil && length < *sch.MinLength {
+			v.addErr(path, "Invalid value: should be at least %d chars long", *sch.MinLength)
+		}
+		if sch.MaxLength != nil && length > *sch.MaxLength {
+			v.addErr(path, "Invalid value: should be at most %d chars long", *sch.MaxLength)
+		}
+		if len(sch.Pattern) > 0 {
+			re, err := regexp.Compile(sch.Pattern)
+			if err == nil && !re.MatchString(typedVal) {
+				v.addErr(path, "Invalid value: should match '%s'", sch.Pattern)
+			}
+		}
+
+	case "integer", "number":
+		num, ok := v.number(val)
+		if !ok {
+			v.addErr(path, "Invalid value: expected %s, got %s", sch.Type, v.typeName(val))
+			return
+		}
+		if sch.Type == "integer" && num != math.Trunc(num) {
+			v.addErr(path, "Invalid value: expected integer, got number")
+			return
+		}
+		v.validateBounds(sch, num, path)
+
+	case "boolean":
+		if _, ok := val.(bool); !ok {
+			v.addErr(path, "Invalid value: expected boolean, got %s", v.typeName(val))
+		}
+	}
+}
+
+func (v *crdSchemaValidator) validateObject(sch *apiextv1.JSONSchemaProps, val map[string]interface{}, path string, isRoot bool) {
+	for _, key := range sch.Required {
+		if _, found := val[key]; !found {
+			v.addErr(v.fieldPath(path, key), "Required value")
+		}
+	}
+
+	preserveUnknown := sch.XPreserveUnknownFields != nil && *sch.XPreserveUnknownFields
+
+	var keys []string
+	for key := range val {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := v.fieldPath(path, key)
+
+		if propSch, found := sch.Properties[key]; found {
+			propSch := propSch
+			v.validate(&propSch, val[key], fieldPath)
+			continue
+		}
+
+		if sch.AdditionalProperties != nil {
+			if sch.AdditionalProperties.Schema != nil {
+				v.validate(sch.AdditionalProperties.Schema, val[key], fieldPath)
+			}
+			continue
+		}
+
+		// Type information fields are validated by API server itself
+		if (isRoot || sch.XEmbeddedResource) && (key == "apiVersion" || key == "kind" || key == "metadata") {
+			continue
+		}
+
+		if v.includeUnknownFields && !preserveUnknown {
+			v.addErr(fieldPath, "Unknown field")
+		}
+	}
+}
+
+func (v *crdSchemaValidator) validateEnum(enum []apiextv1.JSON, val interface{}, path string) {
+	var allowedVals []string
+
+	for _, allowed := range enum {
+		var allowedVal interface{}
+		if err := json.Unmarshal(allowed.Raw, &allowedVal); err != nil {
+			return // do not report on invalid schema
+		}
+		if v.equal(allowedVal, val) {
+			return
+		}
+		allowedVals = append(allowedVals, string(allowed.Raw))
+	}
+
+	v.addErr(path, "Unsupported value: %s (supported values: %s)", v.jsonString(val), strings.Join(allowedVals, ", "))
+}
+
+func (v *crdSchemaValidator) validateBounds(sch *apiextv1.JSONSchemaProps, num float64, path string) {
+	if sch.Minimum != nil {
+		if num < *sch.Minimum || (sch.ExclusiveMinimum && num == *sch.Minimum) {
+			v.addErr(path, "Invalid value: should be greater than %s %v", v.boundDesc(sch.ExclusiveMinimum), *sch.Minimum)
+		}
+	}
+	if sch.Maximum != nil {
+		if num > *sch.Maximum || (sch.ExclusiveMaximum && num == *sch.Maximum) {
+			v.addErr(path, "Invalid value: should be less than %s %v", v.boundDesc(sch.ExclusiveMaximum), *sch.Maximum)
+		}
+	}
+}
+
+func (*crdSchemaValidator) boundDesc(exclusive bool) string {
+	if exclusive {
+		return "(exclusive)"
+	}
+	return "or equal to"
+}
+
+func (v *crdSchemaValidator) equal(a, b interface{}) bool {
+	// Numbers may be represented differently (e.g. int64 vs float64)
+	aNum, aIsNum := v.number(a)
+	bNum, bIsNum := v.number(b)
+	if aIsNum && bIsNum {
+		return aNum == bNum
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func (*crdSchemaValidator) number(val interface{}) (float64, bool) {
+	switch typedVal := val.(type) {
+	case int64:
+		return float64(typedVal), true
+	case int:
+		return float64(typedVal), true
+	case float64:
+		return typedVal, true
+	default:
+		return 0, false
+	}
+}
+
+func (*crdSchemaValidator) typeName(val interface{}) string {
+	switch val.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int64, int, float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", val)
+	}
+}
+
+func (*crdSchemaValidator) jsonString(val interface{}) string {
+	bs, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(bs)
+}
+
+func (*crdSchemaValidator) fieldPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
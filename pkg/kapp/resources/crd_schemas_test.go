@@ -0,0 +1,112 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRDSchemasInvalidFields(t *testing.T) {
+	crd := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            required: [size]
+            properties:
+              size:
+                type: integer
+                minimum: 1
+              color:
+                type: string
+                enum: [red, blue]
+              name:
+                type: string
+                pattern: '^[a-z]+$'
+              ports:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    port:
+                      type: integer
+              port:
+                x-kubernetes-int-or-string: true
+              extra:
+                type: object
+                x-kubernetes-preserve-unknown-fields: true
+`))
+
+	validWidget := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: valid
+spec:
+  size: 3
+  color: red
+  name: abc
+  ports:
+  - port: 80
+  port: http
+  extra:
+    anything: true
+`))
+
+	invalidWidget := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: invalid
+spec:
+  color: green
+  name: ABC
+  ports:
+  - port: "80"
+  colour: red
+`))
+
+	otherVersionWidget := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v2
+kind: Widget
+metadata:
+  name: other
+spec:
+  size: invalid
+`))
+
+	schemas, err := ctlres.NewCRDSchemas([]ctlres.Resource{crd, validWidget, invalidWidget})
+	require.NoError(t, err)
+
+	require.Empty(t, schemas.InvalidFields(validWidget, true))
+	require.Empty(t, schemas.InvalidFields(otherVersionWidget, true))
+
+	require.Equal(t, []string{
+		`spec.color: Unsupported value: "green" (supported values: "red", "blue")`,
+		`spec.name: Invalid value: should match '^[a-z]+$'`,
+		`spec.ports[0].port: Invalid value: expected integer, got string`,
+		`spec.size: Required value`,
+	}, schemas.InvalidFields(invalidWidget, false))
+
+	require.Contains(t, schemas.InvalidFields(invalidWidget, true), `spec.colour: Unknown field`)
+}
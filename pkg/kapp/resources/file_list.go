@@ -0,0 +1,57 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// FileList is a file that lists files (paths or URLs) one per line.
+// Empty lines and lines starting with '#' are ignored. Relative paths
+// are resolved against directory of the list file.
+type FileList struct {
+	fsys fs.FS
+	path string
+}
+
+func NewFileList(fsys fs.FS, path string) FileList {
+	return FileList{fsys: fsys, path: path}
+}
+
+func (l FileList) Files() ([]string, error) {
+	listBs, err := NewLocalFileSource(l.fsys, l.path).Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("Reading file list '%s': %w", l.path, err)
+	}
+
+	var files []string
+
+	for _, line := range strings.Split(string(listBs), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		files = append(files, l.resolve(line))
+	}
+
+	return files, nil
+}
+
+func (l FileList) resolve(file string) string {
+	switch {
+	case file == "-":
+		return file
+	case strings.HasPrefix(file, kustomizeFilePrefix):
+		return kustomizeFilePrefix + l.resolve(strings.TrimPrefix(file, kustomizeFilePrefix))
+	case strings.Contains(file, "://"):
+		return file
+	case filepath.IsAbs(file):
+		return file
+	default:
+		return filepath.Join(filepath.Dir(l.path), file)
+	}
+}
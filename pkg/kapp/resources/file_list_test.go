@@ -0,0 +1,37 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileList(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/files.txt": &fstest.MapFile{Data: []byte(`
+# base resources
+base.yml
+  https://example.com/crds.yml
+
+/abs/path.yml
+kustomize://overlays/prod
+`)},
+	}
+
+	files, err := ctlres.NewFileList(fsys, "config/files.txt").Files()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"config/base.yml",
+		"https://example.com/crds.yml",
+		"/abs/path.yml",
+		"kustomize://config/overlays/prod",
+	}, files)
+
+	_, err = ctlres.NewFileList(fsys, "config/missing.txt").Files()
+	require.ErrorContains(t, err, "Reading file list 'config/missing.txt'")
+}
@@ -14,6 +14,7 @@ import (
 
 var (
 	fileResourcesAllowedExts = []string{".json", ".yaml", ".yml"} // matches kubectl
+	kustomizeFilePrefix      = "kustomize://"
 )
 
 type FileResource struct {
@@ -22,7 +23,8 @@ type FileResource struct {
 
 // NewFileResources inspects file and returns a slice of FileResource objects. If file is "-", a FileResource for STDIN
 // is returned. If it is prefixed with either http:// or https://, a FileResource that supports an HTTP transport is
-// returned. If file is a directory, one FileResource object is returned for each file in the directory with an allowed
+// returned. If it is prefixed with kustomize://, a FileResource that builds kustomization directory is
+// returned (requires kustomize or kubectl binary in PATH). If file is a directory, one FileResource object is returned for each file in the directory with an allowed
 // extension (.json, .yml, .yaml). If file is not a directory, a FileResource object is returned for that one file. If
 // fsys is nil, NewFileResources uses the OS's file system. Otherwise, it uses the passed in file system.
 func NewFileResources(fsys fs.FS, file string) ([]FileResource, error) {
@@ -35,6 +37,9 @@ func NewFileResources(fsys fs.FS, file string) ([]FileResource, error) {
 	case strings.HasPrefix(file, "http://") || strings.HasPrefix(file, "https://"):
 		fileRs = append(fileRs, NewFileResource(NewHTTPFileSource(file)))
 
+	case strings.HasPrefix(file, kustomizeFilePrefix):
+		fileRs = append(fileRs, NewFileResource(NewKustomizeSource(fsys, strings.TrimPrefix(file, kustomizeFilePrefix))))
+
 	default:
 		dir, err := isDir(fsys, file)
 		if err != nil {
@@ -79,9 +84,25 @@ func NewFileResources(fsys fs.FS, file string) ([]FileResource, error) {
 	return fileRs, nil
 }
 
+// LocalPath returns path on the local file system that file is read from
+// (e.g. kustomization directory for kustomize:// files). Stdin and HTTP files are not local.
+func LocalPath(file string) (string, bool) {
+	switch {
+	case file == "-":
+		return "", false
+	case strings.HasPrefix(file, kustomizeFilePrefix):
+		return strings.TrimPrefix(file, kustomizeFilePrefix), true
+	case strings.Contains(file, "://"):
+		return "", false
+	default:
+		return file, true
+	}
+}
+
 func NewFileResource(fileSrc FileSource) FileResource { return FileResource{fileSrc} }
 
-func (r FileResource) Description() string { return r.fileSrc.Description() }
+func (r FileResource) Description() string    { return r.fileSrc.Description() }
+func (r FileResource) Bytes() ([]byte, error) { return r.fileSrc.Bytes() }
 
 func (r FileResource) Resources() ([]Resource, error) {
 	docs, err := NewYAMLFile(r.fileSrc).Docs()
@@ -94,7 +115,7 @@ func (r FileResource) Resources() ([]Resource, error) {
 	for i, doc := range docs {
 		rs, err := NewResourcesFromBytes(doc)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("Parsing %s doc %d: %w", r.fileSrc.Description(), i+1, err)
 		}
 
 		for _, res := range rs {
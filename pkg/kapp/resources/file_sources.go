@@ -4,11 +4,15 @@
 package resources
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/fs"
 	"net/http"
 	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
 )
 
 type FileSource interface {
@@ -93,3 +97,119 @@ func (s HTTPFileSource) Bytes() ([]byte, error) {
 
 	return result, nil
 }
+
+var (
+	kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+)
+
+// KustomizeSource builds kustomization directory via kustomize CLI
+// (or kubectl if kustomize is not available) and returns its output.
+// Kustomize (sigs.k8s.io/kustomize/api/krusty) is not vendored into kapp,
+// hence one of these binaries has to be found in PATH. Since build happens
+// in a separate process, contents of fsys (when provided) are copied into
+// a temporary directory so that build reads the same files kapp does.
+type KustomizeSource struct {
+	fsys fs.FS
+	dir  string
+
+	// LookPath and Command are swapped in tests
+	LookPath func(string) (string, error)
+	Command  func(string, ...string) *exec.Cmd
+}
+
+var _ FileSource = KustomizeSource{}
+
+func NewKustomizeSource(fsys fs.FS, dir string) KustomizeSource {
+	return KustomizeSource{fsys: fsys, dir: dir, LookPath: exec.LookPath, Command: exec.Command}
+}
+
+func (s KustomizeSource) Description() string {
+	return fmt.Sprintf("kustomization '%s'", s.dir)
+}
+
+func (s KustomizeSource) Bytes() ([]byte, error) {
+	err := s.checkKustomization()
+	if err != nil {
+		return nil, err
+	}
+
+	var name string
+	var args []string
+
+	if _, err := s.LookPath("kustomize"); err == nil {
+		name, args = "kustomize", []string{"build"}
+	} else if _, err := s.LookPath("kubectl"); err == nil {
+		name, args = "kubectl", []string{"kustomize"}
+	} else {
+		return nil, fmt.Errorf("Building kustomization '%s': Expected kustomize or kubectl to be found in PATH", s.dir)
+	}
+
+	dir := s.dir
+
+	if s.fsys != nil {
+		tmpDir, err := os.MkdirTemp("", "kapp-kustomize")
+		if err != nil {
+			return nil, fmt.Errorf("Building kustomization '%s': %w", s.dir, err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		err = s.copyFS(tmpDir)
+		if err != nil {
+			return nil, fmt.Errorf("Building kustomization '%s': %w", s.dir, err)
+		}
+
+		dir = filepath.Join(tmpDir, filepath.FromSlash(s.dir))
+	}
+
+	cmd := s.Command(name, append(args, dir)...)
+
+	var stdoutBs, stderrBs bytes.Buffer
+
+	cmd.Stdout = &stdoutBs
+	cmd.Stderr = &stderrBs
+
+	err = cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("Building kustomization '%s': %w (stderr: %s)", s.dir, err, stderrBs.String())
+	}
+
+	return stdoutBs.Bytes(), nil
+}
+
+// copyFS copies all files from fsys since kustomization
+// may refer to files outside of its directory (e.g. ../base)
+func (s KustomizeSource) copyFS(dstDir string) error {
+	return fs.WalkDir(s.fsys, ".", func(srcPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		dstPath := filepath.Join(dstDir, filepath.FromSlash(srcPath))
+
+		if d.IsDir() {
+			return os.MkdirAll(dstPath, 0700)
+		}
+
+		bs, err := fs.ReadFile(s.fsys, srcPath)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(dstPath, bs, 0600)
+	})
+}
+
+func (s KustomizeSource) checkKustomization() error {
+	for _, name := range kustomizationFileNames {
+		var err error
+		if s.fsys == nil {
+			_, err = os.Stat(filepath.Join(s.dir, name))
+		} else {
+			_, err = fs.Stat(s.fsys, path.Join(s.dir, name))
+		}
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("Expected directory '%s' to be a kustomization (containing one of: %v)", s.dir, kustomizationFileNames)
+}
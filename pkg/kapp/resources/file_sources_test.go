@@ -8,7 +8,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os/exec"
+	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"github.com/stretchr/testify/require"
@@ -70,6 +73,113 @@ func TestHTTPFileSources(t *testing.T) {
 	require.EqualError(t, err, fmt.Sprintf("Requesting URL '%s': %s", url, status))
 }
 
+func TestKustomizeSourceNotKustomization(t *testing.T) {
+	dir := t.TempDir()
+
+	fileRs, err := ctlres.NewFileResources(nil, "kustomize://"+dir)
+	require.NoError(t, err)
+	require.Len(t, fileRs, 1)
+	require.Equal(t, fmt.Sprintf("kustomization '%s'", dir), fileRs[0].Description())
+
+	_, err = fileRs[0].Bytes()
+	require.EqualError(t, err, fmt.Sprintf("Expected directory '%s' to be a kustomization "+
+		"(containing one of: [kustomization.yaml kustomization.yml Kustomization])", dir))
+}
+
+func TestKustomizeSourceWithFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"overlays/prod/kustomization.yml": &fstest.MapFile{Data: []byte("resources: [../../base]\n")},
+		"base/config.yml":                 &fstest.MapFile{Data: []byte("kind: ConfigMap\n")},
+	}
+
+	// Print command instead of running it
+	echoCommand := func(name string, args ...string) *exec.Cmd {
+		args[len(args)-1] = filepath.Base(args[len(args)-1])
+		return exec.Command("echo", append([]string{name}, args...)...)
+	}
+	lookPathFor := func(found ...string) func(string) (string, error) {
+		return func(file string) (string, error) {
+			for _, name := range found {
+				if name == file {
+					return "/bin/" + file, nil
+				}
+			}
+			return "", exec.ErrNotFound
+		}
+	}
+
+	t.Run("prefers kustomize binary", func(t *testing.T) {
+		src := ctlres.NewKustomizeSource(fsys, "overlays/prod")
+		src.LookPath = lookPathFor("kustomize", "kubectl")
+		src.Command = echoCommand
+
+		output, err := src.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, "kustomize build prod\n", string(output))
+	})
+
+	t.Run("falls back to kubectl binary", func(t *testing.T) {
+		src := ctlres.NewKustomizeSource(fsys, "overlays/prod")
+		src.LookPath = lookPathFor("kubectl")
+		src.Command = echoCommand
+
+		output, err := src.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, "kubectl kustomize prod\n", string(output))
+	})
+
+	t.Run("builds files from fs", func(t *testing.T) {
+		src := ctlres.NewKustomizeSource(fsys, "overlays/prod")
+		src.LookPath = lookPathFor("kustomize")
+		// Print files referenced by kustomization instead of building it
+		src.Command = func(_ string, args ...string) *exec.Cmd {
+			return exec.Command("cat", filepath.Join(args[len(args)-1], "kustomization.yml"),
+				filepath.Join(args[len(args)-1], "../../base/config.yml"))
+		}
+
+		output, err := src.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, "resources: [../../base]\nkind: ConfigMap\n", string(output))
+	})
+
+	t.Run("fails without binaries", func(t *testing.T) {
+		src := ctlres.NewKustomizeSource(fsys, "overlays/prod")
+		src.LookPath = lookPathFor()
+		src.Command = echoCommand
+
+		_, err := src.Bytes()
+		require.EqualError(t, err, "Building kustomization 'overlays/prod': Expected kustomize or kubectl to be found in PATH")
+	})
+
+	t.Run("checks kustomization within fs", func(t *testing.T) {
+		src := ctlres.NewKustomizeSource(fsys, "base")
+		src.LookPath = lookPathFor("kustomize")
+		src.Command = echoCommand
+
+		_, err := src.Bytes()
+		require.EqualError(t, err, "Expected directory 'base' to be a kustomization "+
+			"(containing one of: [kustomization.yaml kustomization.yml Kustomization])")
+	})
+}
+
+func TestLocalPath(t *testing.T) {
+	for file, expectedPath := range map[string]string{
+		"/tmp/config.yml":            "/tmp/config.yml",
+		"config/":                    "config/",
+		"kustomize://overlays/prod":  "overlays/prod",
+		"kustomize:///tmp/overlays/": "/tmp/overlays/",
+	} {
+		path, local := ctlres.LocalPath(file)
+		require.True(t, local, "Expected file %s to be local", file)
+		require.Equal(t, expectedPath, path)
+	}
+
+	for _, file := range []string{"-", "https://example.com/config.yml", "http://example.com/config.yml"} {
+		_, local := ctlres.LocalPath(file)
+		require.False(t, local, "Expected file %s to not be local", file)
+	}
+}
+
 // NewTestClient returns *http.Client with Transport replaced to avoid making real calls
 func NewTestClient(fn RoundTripFunc) *http.Client {
 	return &http.Client{
@@ -0,0 +1,44 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+)
+
+const (
+	helmManagedByLabelKey      = "app.kubernetes.io/managed-by"
+	helmManagedByLabelVal      = "Helm"
+	helmReleaseNameAnnKey      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnKey = "meta.helm.sh/release-namespace"
+)
+
+// HelmRelease describes Helm release that tracks a resource
+// based on ownership metadata that Helm (v3) adds to resources
+type HelmRelease struct {
+	res Resource
+}
+
+func NewHelmRelease(res Resource) HelmRelease { return HelmRelease{res} }
+
+func (r HelmRelease) IsManaged() bool {
+	if r.res.Labels()[helmManagedByLabelKey] == helmManagedByLabelVal {
+		return true
+	}
+	_, found := r.res.Annotations()[helmReleaseNameAnnKey]
+	return found
+}
+
+func (r HelmRelease) Description() string {
+	anns := r.res.Annotations()
+	name, ns := anns[helmReleaseNameAnnKey], anns[helmReleaseNamespaceAnnKey]
+	switch {
+	case len(name) == 0:
+		return "unknown Helm release"
+	case len(ns) == 0:
+		return fmt.Sprintf("Helm release '%s'", name)
+	default:
+		return fmt.Sprintf("Helm release '%s/%s'", ns, name)
+	}
+}
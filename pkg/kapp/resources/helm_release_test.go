@@ -0,0 +1,50 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelmRelease(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: cm
+  labels:
+    app.kubernetes.io/managed-by: Helm
+  annotations:
+    meta.helm.sh/release-name: my-release
+    meta.helm.sh/release-namespace: my-ns
+`))
+
+	release := ctlres.NewHelmRelease(res)
+	require.True(t, release.IsManaged())
+	require.Equal(t, "Helm release 'my-ns/my-release'", release.Description())
+
+	res = ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: cm
+  labels:
+    app.kubernetes.io/managed-by: Helm
+`))
+
+	release = ctlres.NewHelmRelease(res)
+	require.True(t, release.IsManaged())
+	require.Equal(t, "unknown Helm release", release.Description())
+
+	res = ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: cm
+  labels:
+    app.kubernetes.io/managed-by: kapp
+`))
+
+	require.False(t, ctlres.NewHelmRelease(res).IsManaged())
+}
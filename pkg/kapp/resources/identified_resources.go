@@ -11,19 +11,56 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	ChangeIDAnnKey = "kapp.k14s.io/change-id"
+)
+
 type IdentifiedResources struct {
 	coreClient                kubernetes.Interface
 	resourceTypes             ResourceTypes
 	resources                 Resources
 	fallbackAllowedNamespaces []string
 	logger                    logger.Logger
+
+	identityAnnotationDisabled bool
+	identityAnnotationOptional bool
+	changeIDFunc               func() string
 }
 
 func NewIdentifiedResources(coreClient kubernetes.Interface, resourceTypes ResourceTypes,
 	resources Resources, fallbackAllowedNamespaces []string, logger logger.Logger) IdentifiedResources {
 
-	return IdentifiedResources{coreClient, resourceTypes, resources,
-		fallbackAllowedNamespaces, logger.NewPrefixed("IdentifiedResources")}
+	return IdentifiedResources{coreClient: coreClient, resourceTypes: resourceTypes, resources: resources,
+		fallbackAllowedNamespaces: fallbackAllowedNamespaces, logger: logger.NewPrefixed("IdentifiedResources")}
+}
+
+// WithoutIdentityAnnotation returns a copy that does not record identity annotation
+// on created or updated resources. Since identity annotation is used to tell apart
+// resources created by kapp from ones created by controllers (e.g. ReplicaSets),
+// resources without owner references are assumed to be created by kapp.
+// In addition, when resource is returned in multiple API versions,
+// version cannot be matched to the one used during deploy.
+func (r IdentifiedResources) WithoutIdentityAnnotation() IdentifiedResources {
+	r.identityAnnotationDisabled = true
+	r.identityAnnotationOptional = true
+	return r
+}
+
+// WithOptionalIdentityAnnotation returns a copy that still records identity
+// annotation but treats listed resources without it (and without owner references)
+// as created by kapp. It's used for apps that were deployed with identity annotation
+// disabled at some point, so that their resources are not mistaken for transient ones.
+func (r IdentifiedResources) WithOptionalIdentityAnnotation() IdentifiedResources {
+	r.identityAnnotationOptional = true
+	return r
+}
+
+// WithChangeIDAnnotation returns a copy that records app change ID (if known
+// at the time resource is created or updated) onto resources so that
+// their state could be correlated with a specific deploy
+func (r IdentifiedResources) WithChangeIDAnnotation(changeIDFunc func() string) IdentifiedResources {
+	r.changeIDFunc = changeIDFunc
+	return r
 }
 
 func (r IdentifiedResources) Create(resource Resource) (Resource, error) {
@@ -31,7 +68,12 @@ func (r IdentifiedResources) Create(resource Resource) (Resource, error) {
 
 	resource = resource.DeepCopy()
 
-	err := NewIdentityAnnotation(resource).AddMod().Apply(resource)
+	err := r.addIdentityAnnotation(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.addChangeIDAnnotation(resource)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +96,12 @@ func (r IdentifiedResources) Update(resource Resource) (Resource, error) {
 
 	resource = resource.DeepCopy()
 
-	err := NewIdentityAnnotation(resource).AddMod().Apply(resource)
+	err := r.addIdentityAnnotation(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.addChangeIDAnnotation(resource)
 	if err != nil {
 		return nil, err
 	}
@@ -72,14 +119,69 @@ func (r IdentifiedResources) Update(resource Resource) (Resource, error) {
 	return resource, nil
 }
 
+func (r IdentifiedResources) CreateDryRun(resource Resource) (Resource, error) {
+	return r.dryRun(resource, r.resources.CreateDryRun)
+}
+
+func (r IdentifiedResources) UpdateDryRun(resource Resource) (Resource, error) {
+	return r.dryRun(resource, r.resources.UpdateDryRun)
+}
+
+// dryRun includes identity annotation in the request, same as actual
+// create or update would, so that it does not show up as a difference
+func (r IdentifiedResources) dryRun(resource Resource, doFunc func(Resource) (Resource, error)) (Resource, error) {
+	defer r.logger.DebugFunc(fmt.Sprintf("DryRun(%s)", resource.Description())).Finish()
+
+	resource = resource.DeepCopy()
+
+	err := r.addIdentityAnnotation(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err = doFunc(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	err = NewIdentityAnnotation(resource).RemoveMod().Apply(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+func (r IdentifiedResources) addIdentityAnnotation(resource Resource) error {
+	if r.identityAnnotationDisabled {
+		return nil
+	}
+	return NewIdentityAnnotation(resource).AddMod().Apply(resource)
+}
+
+func (r IdentifiedResources) addChangeIDAnnotation(resource Resource) error {
+	if r.changeIDFunc == nil {
+		return nil
+	}
+	changeID := r.changeIDFunc()
+	if len(changeID) == 0 {
+		return nil
+	}
+	return StringMapAppendMod{
+		ResourceMatcher: AllMatcher{},
+		Path:            NewPathFromStrings([]string{"metadata", "annotations"}),
+		KVs:             map[string]string{ChangeIDAnnKey: changeID},
+	}.Apply(resource)
+}
+
 func (r IdentifiedResources) Patch(resource Resource, patchType types.PatchType, data []byte) (Resource, error) {
 	defer r.logger.DebugFunc(fmt.Sprintf("Patch(%s)", resource.Description())).Finish()
 	return r.resources.Patch(resource, patchType, data)
 }
 
-func (r IdentifiedResources) Delete(resource Resource) error {
+func (r IdentifiedResources) Delete(resource Resource, opts DeleteOpts) error {
 	defer r.logger.DebugFunc(fmt.Sprintf("Delete(%s)", resource.Description())).Finish()
-	return r.resources.Delete(resource)
+	return r.resources.Delete(resource, opts)
 }
 
 func (r IdentifiedResources) Get(resource Resource) (Resource, error) {
@@ -0,0 +1,86 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingResources keeps copies of resources sent to the server
+type recordingResources struct {
+	ctlres.Resources
+
+	sent []ctlres.Resource
+}
+
+func (r *recordingResources) Create(res ctlres.Resource) (ctlres.Resource, error) {
+	r.sent = append(r.sent, res.DeepCopy())
+	return res, nil
+}
+
+func (r *recordingResources) Update(res ctlres.Resource) (ctlres.Resource, error) {
+	r.sent = append(r.sent, res.DeepCopy())
+	return res, nil
+}
+
+func TestIdentifiedResourcesChangeIDAnnotation(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: default
+`))
+
+	t.Run("records change ID on created and updated resources", func(t *testing.T) {
+		resources := &recordingResources{}
+
+		var changeID string
+
+		identifiedResources := ctlres.NewIdentifiedResources(nil, nil, resources, nil, logger.NewNoopLogger()).
+			WithChangeIDAnnotation(func() string { return changeID })
+
+		// Change ID is only known once app change begins
+		changeID = "app-change-abc"
+
+		createdRes, err := identifiedResources.Create(res)
+		require.NoError(t, err)
+		require.Equal(t, "app-change-abc", createdRes.Annotations()[ctlres.ChangeIDAnnKey])
+
+		_, err = identifiedResources.Update(res)
+		require.NoError(t, err)
+
+		require.Len(t, resources.sent, 2)
+		for _, sentRes := range resources.sent {
+			require.Equal(t, "app-change-abc", sentRes.Annotations()[ctlres.ChangeIDAnnKey])
+		}
+
+		require.NotContains(t, res.Annotations(), ctlres.ChangeIDAnnKey, "Expected original resource to not be modified")
+	})
+
+	t.Run("does not record empty change ID", func(t *testing.T) {
+		resources := &recordingResources{}
+
+		identifiedResources := ctlres.NewIdentifiedResources(nil, nil, resources, nil, logger.NewNoopLogger()).
+			WithChangeIDAnnotation(func() string { return "" })
+
+		_, err := identifiedResources.Create(res)
+		require.NoError(t, err)
+		require.NotContains(t, resources.sent[0].Annotations(), ctlres.ChangeIDAnnKey)
+	})
+
+	t.Run("does not record change ID unless enabled", func(t *testing.T) {
+		resources := &recordingResources{}
+
+		identifiedResources := ctlres.NewIdentifiedResources(nil, nil, resources, nil, logger.NewNoopLogger())
+
+		_, err := identifiedResources.Update(res)
+		require.NoError(t, err)
+		require.NotContains(t, resources.sent[0].Annotations(), ctlres.ChangeIDAnnKey)
+	})
+}
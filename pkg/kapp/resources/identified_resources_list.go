@@ -76,7 +76,7 @@ func (r IdentifiedResources) List(labelSelector labels.Selector, resRefs []Resou
 
 	// Mark resources that were not created by kapp as transient
 	for i, res := range resources {
-		if !NewIdentityAnnotation(res).Valid() {
+		if !r.isCreatedByKapp(res) {
 			res.MarkTransient(true)
 			resources[i] = res
 		}
@@ -85,6 +85,13 @@ func (r IdentifiedResources) List(labelSelector labels.Selector, resRefs []Resou
 	return r.pickPreferredVersions(resources)
 }
 
+func (r IdentifiedResources) isCreatedByKapp(res Resource) bool {
+	if NewIdentityAnnotation(res).Valid() {
+		return true
+	}
+	return r.identityAnnotationOptional && len(res.OwnerRefs()) == 0
+}
+
 func (r IdentifiedResources) pickPreferredVersions(resources []Resource) ([]Resource, error) {
 	var result []Resource
 
@@ -56,7 +56,7 @@ metadata:
 
 	return []ctlres.Resource{antreaRes, deploymentRes}, nil
 }
-func (r *FakeResources) Delete(ctlres.Resource) error { return nil }
+func (r *FakeResources) Delete(ctlres.Resource, ctlres.DeleteOpts) error { return nil }
 func (r *FakeResources) Exists(ctlres.Resource, ctlres.ExistsOpts) (ctlres.Resource, bool, error) {
 	return nil, true, nil
 }
@@ -66,6 +66,12 @@ func (r *FakeResources) Patch(ctlres.Resource, types.PatchType, []byte) (ctlres.
 }
 func (r *FakeResources) Update(ctlres.Resource) (ctlres.Resource, error) { return nil, nil }
 func (r *FakeResources) Create(ctlres.Resource) (ctlres.Resource, error) { return nil, nil }
+func (r *FakeResources) CreateDryRun(ctlres.Resource) (ctlres.Resource, error) {
+	return nil, nil
+}
+func (r *FakeResources) UpdateDryRun(ctlres.Resource) (ctlres.Resource, error) {
+	return nil, nil
+}
 
 type FakeResourceTypes struct{}
 
@@ -76,3 +82,71 @@ func (r *FakeResourceTypes) Find(ctlres.Resource) (ctlres.ResourceType, error) {
 	return ctlres.ResourceType{}, nil
 }
 func (r *FakeResourceTypes) CanIgnoreFailingGroupVersion(schema.GroupVersion) bool { return true }
+
+func TestIdentifiedResourcesListWithOptionalIdentityAnnotation(t *testing.T) {
+	labeledBs := `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cfg
+  namespace: default
+  uid: cfg-uid
+  labels:
+    some-label: "value"
+`
+	ownedBs := `---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+  namespace: default
+  uid: pod-uid
+  labels:
+    some-label: "value"
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: ReplicaSet
+    name: rs
+    uid: rs-uid
+`
+	fakeResources := &fakeListedResources{FakeResources: FakeResources{t}, resources: []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(labeledBs)),
+		ctlres.MustNewResourceFromBytes([]byte(ownedBs)),
+	}}
+
+	identifiedResources := ctlres.NewIdentifiedResources(nil, &FakeResourceTypes{}, fakeResources, []string{}, logger.NewUILogger(ui.NewNoopUI()))
+	sel := labels.Set(map[string]string{"some-label": "value"}).AsSelector()
+
+	transientByName := func(identifiedResources ctlres.IdentifiedResources) map[string]bool {
+		resources, err := identifiedResources.List(sel, nil, ctlres.IdentifiedResourcesListOpts{})
+		require.NoError(t, err)
+
+		result := map[string]bool{}
+		for _, res := range resources {
+			result[res.Name()] = res.Transient()
+		}
+		return result
+	}
+
+	// Resources without identity annotation are not considered to be created by kapp
+	require.Equal(t, map[string]bool{"cfg": true, "pod": true}, transientByName(identifiedResources))
+
+	// Resources with owners are still considered to be created by controllers
+	require.Equal(t, map[string]bool{"cfg": false, "pod": true},
+		transientByName(identifiedResources.WithOptionalIdentityAnnotation()))
+	require.Equal(t, map[string]bool{"cfg": false, "pod": true},
+		transientByName(identifiedResources.WithoutIdentityAnnotation()))
+}
+
+type fakeListedResources struct {
+	FakeResources
+	resources []ctlres.Resource
+}
+
+func (r *fakeListedResources) All([]ctlres.ResourceType, ctlres.AllOpts) ([]ctlres.Resource, error) {
+	var result []ctlres.Resource
+	for _, res := range r.resources {
+		result = append(result, res.DeepCopy())
+	}
+	return result, nil
+}
@@ -15,7 +15,11 @@ import (
 )
 
 func (r IdentifiedResources) PodResources(labelSelector labels.Selector, resourceNamespaces []string) UniquePodWatcher {
-	return UniquePodWatcher{labelSelector, uniqAndValidNamespaces(append(r.fallbackAllowedNamespaces, resourceNamespaces...)), r.coreClient}
+	return UniquePodWatcher{
+		labelSelector:             labelSelector,
+		fallbackAllowedNamespaces: uniqAndValidNamespaces(append(r.fallbackAllowedNamespaces, resourceNamespaces...)),
+		coreClient:                r.coreClient,
+	}
 }
 
 type PodWatcherI interface {
@@ -26,10 +30,20 @@ type UniquePodWatcher struct {
 	labelSelector             labels.Selector
 	fallbackAllowedNamespaces []string
 	coreClient                kubernetes.Interface
+
+	matcherFunc func(*corev1.Pod) bool
 }
 
 var _ PodWatcherI = UniquePodWatcher{}
 
+// WithMatcher only sends Pods once they match. Unlike FilteringPodWatcher,
+// matching is done before duplicates are removed, hence Pods that
+// start matching later (e.g. once they fail) are still sent.
+func (w UniquePodWatcher) WithMatcher(matcherFunc func(*corev1.Pod) bool) UniquePodWatcher {
+	w.matcherFunc = matcherFunc
+	return w
+}
+
 func (w UniquePodWatcher) Watch(podsToWatchCh chan corev1.Pod, cancelCh chan struct{}) error {
 	nonUniquePodsToWatchCh := make(chan corev1.Pod)
 
@@ -71,6 +85,10 @@ func (w UniquePodWatcher) Watch(podsToWatchCh chan corev1.Pod, cancelCh chan str
 	watchedPods := map[string]struct{}{}
 
 	for pod := range nonUniquePodsToWatchCh {
+		if w.matcherFunc != nil && !w.matcherFunc(&pod) {
+			continue
+		}
+
 		podUID := string(pod.UID)
 		if _, found := watchedPods[podUID]; found {
 			continue
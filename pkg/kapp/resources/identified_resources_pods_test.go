@@ -0,0 +1,109 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func TestUniquePodWatcherWithMatcher(t *testing.T) {
+	newPod := func(phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", UID: "pod-uid"}, Status: corev1.PodStatus{Phase: phase}}
+	}
+
+	podEvents := watch.NewFakeWithChanSize(4, false)
+	podEvents.Add(newPod(corev1.PodPending))
+	podEvents.Modify(newPod(corev1.PodRunning))
+	podEvents.Modify(newPod(corev1.PodFailed))
+	podEvents.Modify(newPod(corev1.PodFailed))
+
+	coreClient := &podEventsCoreClient{pods: &podEventsClient{watchers: []watch.Interface{podEvents}}}
+
+	podWatcher := ctlres.NewIdentifiedResources(coreClient, nil, nil, nil, logger.NewNoopLogger()).
+		PodResources(labels.Everything(), nil).
+		WithMatcher(func(pod *corev1.Pod) bool { return pod.Status.Phase == corev1.PodFailed })
+
+	podsCh := make(chan corev1.Pod)
+	cancelCh := make(chan struct{})
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- podWatcher.Watch(podsCh, cancelCh) }()
+
+	select {
+	case pod := <-podsCh:
+		require.Equal(t, corev1.PodFailed, pod.Status.Phase, "Expected Pod to be sent once it starts matching")
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "Expected Pod to be sent")
+	}
+
+	close(cancelCh)
+
+	for {
+		select {
+		case pod := <-podsCh:
+			require.FailNow(t, "Expected Pod to be sent only once", "Received: %s", pod.Status.Phase)
+		case err := <-errCh:
+			require.NoError(t, err)
+			return
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "Expected watching to stop")
+		}
+	}
+}
+
+type podEventsCoreClient struct {
+	kubernetes.Interface
+
+	pods *podEventsClient
+}
+
+func (c *podEventsCoreClient) CoreV1() typedcorev1.CoreV1Interface {
+	return podEventsCoreV1{pods: c.pods}
+}
+
+type podEventsCoreV1 struct {
+	typedcorev1.CoreV1Interface
+
+	pods *podEventsClient
+}
+
+func (c podEventsCoreV1) Pods(string) typedcorev1.PodInterface { return c.pods }
+
+// podEventsClient returns prepared watchers in order
+// (and watchers without events once they run out)
+type podEventsClient struct {
+	typedcorev1.PodInterface
+
+	lock     sync.Mutex
+	watchers []watch.Interface
+}
+
+func (c *podEventsClient) List(context.Context, metav1.ListOptions) (*corev1.PodList, error) {
+	return &corev1.PodList{}, nil
+}
+
+func (c *podEventsClient) Watch(context.Context, metav1.ListOptions) (watch.Interface, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.watchers) == 0 {
+		return watch.NewFake(), nil
+	}
+	watcher := c.watchers[0]
+	c.watchers = c.watchers[1:]
+	return watcher, nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPath evaluates subset of JSONPath (as used by CRD printer columns):
+// field access (.a.b), indexes ([0], [*]) and equality filters ([?(@.type=="Ready")])
+type JSONPath struct {
+	steps []jsonPathStep
+}
+
+type jsonPathStep struct {
+	Field string
+
+	Index    int
+	IsIndex  bool
+	AllIndex bool
+
+	FilterPath  *JSONPath
+	FilterValue string
+}
+
+func NewJSONPath(path string) (JSONPath, error) {
+	path = strings.TrimSpace(path)
+	path = strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+
+	var steps []jsonPathStep
+
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			end := strings.IndexAny(path[1:], ".[")
+			if end == -1 {
+				end = len(path) - 1
+			}
+			field := path[1 : end+1]
+			if len(field) == 0 {
+				return JSONPath{}, fmt.Errorf("Expected field name in path")
+			}
+			steps = append(steps, jsonPathStep{Field: field})
+			path = path[end+1:]
+
+		case '[':
+			end := strings.Index(path, "]")
+			if end == -1 {
+				return JSONPath{}, fmt.Errorf("Expected closing bracket in path")
+			}
+			step, err := newJSONPathBracketStep(path[1:end])
+			if err != nil {
+				return JSONPath{}, err
+			}
+			steps = append(steps, step)
+			path = path[end+1:]
+
+		default:
+			return JSONPath{}, fmt.Errorf("Unexpected character '%c' in path", path[0])
+		}
+	}
+
+	if len(steps) == 0 {
+		return JSONPath{}, fmt.Errorf("Expected non-empty path")
+	}
+
+	return JSONPath{steps}, nil
+}
+
+func newJSONPathBracketStep(expr string) (jsonPathStep, error) {
+	switch {
+	case expr == "*":
+		return jsonPathStep{AllIndex: true}, nil
+
+	case strings.HasPrefix(expr, "?(@") && strings.HasSuffix(expr, ")"):
+		pieces := strings.SplitN(expr[3:len(expr)-1], "==", 2)
+		if len(pieces) != 2 {
+			return jsonPathStep{}, fmt.Errorf("Expected filter to be an equality check")
+		}
+		filterPath, err := NewJSONPath(strings.TrimSpace(pieces[0]))
+		if err != nil {
+			return jsonPathStep{}, err
+		}
+		filterValue, err := strconv.Unquote(strings.ReplaceAll(strings.TrimSpace(pieces[1]), "'", `"`))
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("Expected filter value to be quoted")
+		}
+		return jsonPathStep{
+			FilterPath:  &filterPath,
+			FilterValue: filterValue,
+		}, nil
+
+	case strings.HasPrefix(expr, "'") || strings.HasPrefix(expr, `"`):
+		field, err := strconv.Unquote(strings.ReplaceAll(expr, "'", `"`))
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("Expected field to be quoted")
+		}
+		return jsonPathStep{Field: field}, nil
+
+	default:
+		idx, err := strconv.Atoi(expr)
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("Expected index to be an integer")
+		}
+		return jsonPathStep{Index: idx, IsIndex: true}, nil
+	}
+}
+
+// Eval returns all values found at path
+func (p JSONPath) Eval(obj interface{}) []interface{} {
+	current := []interface{}{obj}
+
+	for _, step := range p.steps {
+		var next []interface{}
+
+		for _, val := range current {
+			next = append(next, step.eval(val)...)
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+func (s jsonPathStep) eval(obj interface{}) []interface{} {
+	switch {
+	case len(s.Field) > 0:
+		typedObj, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		val, found := typedObj[s.Field]
+		if !found {
+			return nil
+		}
+		return []interface{}{val}
+
+	default:
+		typedObj, ok := obj.([]interface{})
+		if !ok {
+			return nil
+		}
+
+		switch {
+		case s.IsIndex:
+			idx := s.Index
+			if idx < 0 {
+				idx += len(typedObj)
+			}
+			if idx < 0 || idx >= len(typedObj) {
+				return nil
+			}
+			return []interface{}{typedObj[idx]}
+
+		case s.AllIndex:
+			return typedObj
+
+		default:
+			var result []interface{}
+			for _, item := range typedObj {
+				for _, val := range s.FilterPath.Eval(item) {
+					if fmt.Sprintf("%v", val) == s.FilterValue {
+						result = append(result, item)
+						break
+					}
+				}
+			}
+			return result
+		}
+	}
+}
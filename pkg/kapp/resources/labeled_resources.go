@@ -16,8 +16,26 @@ import (
 const (
 	ExistsAnnKey = "kapp.k14s.io/exists" // Value is ignored
 	NoopAnnKey   = "kapp.k14s.io/noop"   // value is ignored
+	PauseAnnKey  = "kapp.k14s.io/pause"  // value is ignored
+	SharedAnnKey = "kapp.k14s.io/shared" // value is ignored
+
+	// ExternalAnnKey marks resources that are managed elsewhere. Similar to
+	// exists annotation, kapp only waits for such resources to exist and never
+	// creates, updates or deletes them. Since they are never applied they do not
+	// become part of the app (e.g. they are not garbage collected once removed
+	// from provided resources), though rebase rules could still copy values
+	// from them via externalResource.
+	ExternalAnnKey = "kapp.k14s.io/external" // value is ignored
 )
 
+// IsExistsOnly indicates that kapp should only check
+// for resource's existence instead of managing it
+func IsExistsOnly(res Resource) bool {
+	_, hasExistsAnnotation := res.Annotations()[ExistsAnnKey]
+	_, hasExternalAnnotation := res.Annotations()[ExternalAnnKey]
+	return hasExistsAnnotation || hasExternalAnnotation
+}
+
 type OwnershipLabelModsFunc func(kvs map[string]string) []StringMapAppendMod
 type LabelScopingModsFunc func(kvs map[string]string) []StringMapAppendMod
 
@@ -97,6 +115,9 @@ type AllAndMatchingOpts struct {
 
 	DisallowedResourcesByLabelKeys []string
 	LabelErrorResolutionFunc       func(string, string) string
+	// AdoptedResourcesCheckFunc is called with existing non-labeled
+	// resources that are about to be adopted by the app
+	AdoptedResourcesCheckFunc func([]Resource) error
 
 	IdentifiedResourcesListOpts IdentifiedResourcesListOpts
 }
@@ -141,6 +162,16 @@ func (a *LabeledResources) AllAndMatching(newResources []Resource, opts AllAndMa
 		}
 	}
 
+	if opts.AdoptedResourcesCheckFunc != nil && len(nonLabeledResources) > 0 {
+		resourcesForCheck := a.resourcesForOwnershipCheck(newResources, nonLabeledResources)
+		if len(resourcesForCheck) > 0 {
+			err := opts.AdoptedResourcesCheckFunc(resourcesForCheck)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	resources = append(resources, nonLabeledResources...)
 
 	err = a.checkDisallowedLabels(resources, opts.DisallowedResourcesByLabelKeys)
@@ -157,9 +188,9 @@ func (a *LabeledResources) resourcesForOwnershipCheck(newResources []Resource, n
 	resourcesToBeSkipped := map[string]bool{}
 
 	for _, res := range newResources {
-		_, hasExistsAnnotation := res.Annotations()[ExistsAnnKey]
 		_, hasNoopAnnotation := res.Annotations()[NoopAnnKey]
-		if hasExistsAnnotation || hasNoopAnnotation {
+		_, hasSharedAnnotation := res.Annotations()[SharedAnnKey]
+		if IsExistsOnly(res) || hasNoopAnnotation || hasSharedAnnotation {
 			resourcesToBeSkipped[NewUniqueResourceKey(res).String()] = true
 		}
 	}
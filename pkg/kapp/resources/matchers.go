@@ -3,6 +3,10 @@
 
 package resources
 
+import (
+	"regexp"
+)
+
 type ResourceMatcher interface {
 	Matches(Resource) bool
 }
@@ -39,6 +43,17 @@ func (m KindNamespaceNameMatcher) Matches(res Resource) bool {
 	return res.Kind() == m.Kind && res.Namespace() == m.Namespace && res.Name() == m.Name
 }
 
+// NameRegexMatcher matches resources whose name fully matches regular expression
+type NameRegexMatcher struct {
+	Regexp *regexp.Regexp
+}
+
+var _ ResourceMatcher = NameRegexMatcher{}
+
+func (m NameRegexMatcher) Matches(res Resource) bool {
+	return m.Regexp.MatchString(res.Name())
+}
+
 type AllMatcher struct{}
 
 var _ ResourceMatcher = AllMatcher{}
@@ -101,6 +116,22 @@ func (m HasAnnotationMatcher) Matches(res Resource) bool {
 	return true
 }
 
+type HasLabelMatcher struct {
+	Keys []string
+}
+
+var _ ResourceMatcher = HasLabelMatcher{}
+
+func (m HasLabelMatcher) Matches(res Resource) bool {
+	labels := res.Labels()
+	for _, key := range m.Keys {
+		if _, found := labels[key]; !found {
+			return false
+		}
+	}
+	return true
+}
+
 type HasNamespaceMatcher struct {
 	Names []string
 }
@@ -6,6 +6,7 @@ package resources
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 type FieldCopyModSource string
@@ -13,8 +14,20 @@ type FieldCopyModSource string
 const (
 	FieldCopyModSourceNew      FieldCopyModSource = "new"
 	FieldCopyModSourceExisting                    = "existing"
+
+	fieldCopyModSourceExternalPrefix = "external:"
 )
 
+// NewFieldCopyModSourceExternal returns source that refers to
+// a resource that is not part of the app (e.g. secret created by a controller)
+func NewFieldCopyModSourceExternal(res Resource) FieldCopyModSource {
+	return FieldCopyModSource(fieldCopyModSourceExternalPrefix + NewUniqueResourceKey(res).String())
+}
+
+func (s FieldCopyModSource) IsExternal() bool {
+	return strings.HasPrefix(string(s), fieldCopyModSourceExternalPrefix)
+}
+
 type FieldCopyMod struct {
 	ResourceMatcher ResourceMatcher
 	Path            Path
@@ -30,6 +43,17 @@ func (t FieldCopyMod) IsResourceMatching(res Resource) bool {
 	return true
 }
 
+// HasExternalSources indicates whether values may be copied
+// from resources that are not part of the app
+func (t FieldCopyMod) HasExternalSources() bool {
+	for _, src := range t.Sources {
+		if src.IsExternal() {
+			return true
+		}
+	}
+	return false
+}
+
 func (t FieldCopyMod) ApplyFromMultiple(res Resource, srcs map[FieldCopyModSource]Resource) error {
 	for _, src := range t.Sources {
 		source, found := srcs[src]
@@ -0,0 +1,192 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+)
+
+// ListMergeKeyMod reorders entries of a list under path to follow
+// order of existing entries with the same key value (e.g. env vars by name),
+// so that lists that only differ in ordering do not produce a diff
+type ListMergeKeyMod struct {
+	ResourceMatcher ResourceMatcher
+	Path            Path
+	Key             string
+}
+
+var _ ResourceModWithMultiple = ListMergeKeyMod{}
+
+func (t ListMergeKeyMod) IsResourceMatching(res Resource) bool {
+	if res == nil || !t.ResourceMatcher.Matches(res) {
+		return false
+	}
+	return true
+}
+
+func (t ListMergeKeyMod) ApplyFromMultiple(res Resource, srcs map[FieldCopyModSource]Resource) error {
+	existingRes, found := srcs[FieldCopyModSourceExisting]
+	if !found || existingRes == nil {
+		return nil
+	}
+
+	err := t.apply(res.unstructured().Object, existingRes.unstructured().Object, t.Path)
+	if err != nil {
+		return fmt.Errorf("ListMergeKeyMod for path '%s' on resource '%s': %w", t.Path.AsString(), res.Description(), err)
+	}
+	return nil
+}
+
+func (t ListMergeKeyMod) apply(obj interface{}, existingObj interface{}, path Path) error {
+	for i, part := range path {
+		switch {
+		case part.MapKey != nil:
+			typedObj, ok := obj.(map[string]interface{})
+			if !ok {
+				return nil // nothing to reorder
+			}
+			typedExistingObj, ok := existingObj.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+
+			if len(path) == i+1 {
+				list, ok := typedObj[*part.MapKey].([]interface{})
+				if !ok {
+					return nil
+				}
+				existingList, ok := typedExistingObj[*part.MapKey].([]interface{})
+				if !ok {
+					return nil
+				}
+				t.reorder(list, existingList)
+				return nil
+			}
+
+			obj = typedObj[*part.MapKey]
+			existingObj = typedExistingObj[*part.MapKey]
+
+		case part.ArrayIndex != nil:
+			typedObj, ok := obj.([]interface{})
+			if !ok {
+				return nil
+			}
+			typedExistingObj, ok := existingObj.([]interface{})
+			if !ok {
+				return nil
+			}
+
+			switch {
+			case part.ArrayIndex.All != nil:
+				for objI := range typedObj {
+					if objI >= len(typedExistingObj) {
+						break
+					}
+					err := t.apply(typedObj[objI], typedExistingObj[objI], path[i+1:])
+					if err != nil {
+						return err
+					}
+				}
+				return nil // dealt with children, get out
+
+			case part.ArrayIndex.Index != nil:
+				objI := *part.ArrayIndex.Index
+				if objI >= len(typedObj) || objI >= len(typedExistingObj) {
+					return nil
+				}
+				return t.apply(typedObj[objI], typedExistingObj[objI], path[i+1:])
+
+			default:
+				panic(fmt.Sprintf("Unknown array index: %#v", part.ArrayIndex))
+			}
+
+		case part.Regex != nil:
+			if part.Regex.Regex == nil {
+				panic("Regex should be non nil")
+			}
+			matchedKeys, err := matchRegexWithSrcObj(*part.Regex.Regex, obj)
+			if err != nil {
+				return err
+			}
+			for _, key := range matchedKeys {
+				newPath := append(Path{&PathPart{MapKey: &key}}, path[i+1:]...)
+				err := t.apply(obj, existingObj, newPath)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+
+		default:
+			panic(fmt.Sprintf("Unexpected path part: %#v", part))
+		}
+	}
+
+	return fmt.Errorf("Expected path to end with a map key")
+}
+
+// reorder places new entries that are also found in existing list
+// into slots occupied by such entries, following existing order.
+// Entries without a match stay in their positions. Lists with entries
+// missing a key or with duplicate keys are left as is.
+func (t ListMergeKeyMod) reorder(list, existingList []interface{}) {
+	keys, ok := t.keys(list)
+	if !ok {
+		return
+	}
+	existingKeys, ok := t.keys(existingList)
+	if !ok {
+		return
+	}
+
+	itemsByKey := map[string]interface{}{}
+	for i, key := range keys {
+		itemsByKey[key] = list[i]
+	}
+
+	var matchedItems []interface{}
+	for _, key := range existingKeys {
+		if item, found := itemsByKey[key]; found {
+			matchedItems = append(matchedItems, item)
+		}
+	}
+
+	existingKeySet := map[string]struct{}{}
+	for _, key := range existingKeys {
+		existingKeySet[key] = struct{}{}
+	}
+
+	var matchedIdx int
+	for i, key := range keys {
+		if _, found := existingKeySet[key]; found {
+			list[i] = matchedItems[matchedIdx]
+			matchedIdx++
+		}
+	}
+}
+
+func (t ListMergeKeyMod) keys(list []interface{}) ([]string, bool) {
+	var keys []string
+	seen := map[string]struct{}{}
+
+	for _, item := range list {
+		typedItem, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		val, found := typedItem[t.Key]
+		if !found {
+			return nil, false
+		}
+		// Format values so that numbers compare equal regardless of representation
+		key := fmt.Sprintf("%v", val)
+		if _, found := seen[key]; found {
+			return nil, false
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+
+	return keys, true
+}
@@ -27,6 +27,7 @@ type PathPart struct {
 }
 
 var _ json.Unmarshaler = &PathPart{}
+var _ json.Marshaler = PathPart{}
 
 type PathPartArrayIndex struct {
 	Index *int
@@ -125,6 +126,21 @@ func (p *PathPart) AsString() string {
 	}
 }
 
+// MarshalJSON uses same format as UnmarshalJSON so that
+// paths could be serialized back into config
+func (p PathPart) MarshalJSON() ([]byte, error) {
+	switch {
+	case p.MapKey != nil:
+		return json.Marshal(*p.MapKey)
+	case p.Regex != nil:
+		return json.Marshal(p.Regex)
+	case p.ArrayIndex != nil:
+		return json.Marshal(p.ArrayIndex)
+	default:
+		return nil, fmt.Errorf("Unknown path part")
+	}
+}
+
 func (p *PathPart) UnmarshalJSON(data []byte) error {
 	var str string
 	var idx PathPartArrayIndex
@@ -0,0 +1,214 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// QuantityEquivalenceMod keeps existing values under path when they represent
+// the same quantity as new values (e.g. 1000m and 1, or 1Gi and 1024Mi),
+// since controllers may store quantities in a normalized form
+type QuantityEquivalenceMod struct {
+	ResourceMatcher ResourceMatcher
+	Path            Path
+}
+
+var _ ResourceModWithMultiple = QuantityEquivalenceMod{}
+
+func (t QuantityEquivalenceMod) IsResourceMatching(res Resource) bool {
+	if res == nil || !t.ResourceMatcher.Matches(res) {
+		return false
+	}
+	return true
+}
+
+func (t QuantityEquivalenceMod) ApplyFromMultiple(res Resource, srcs map[FieldCopyModSource]Resource) error {
+	existingRes, found := srcs[FieldCopyModSourceExisting]
+	if !found || existingRes == nil {
+		return nil
+	}
+
+	err := t.apply(res.unstructured().Object, existingRes.unstructured().Object, t.Path)
+	if err != nil {
+		return fmt.Errorf("QuantityEquivalenceMod for path '%s' on resource '%s': %w", t.Path.AsString(), res.Description(), err)
+	}
+	return nil
+}
+
+func (t QuantityEquivalenceMod) apply(obj interface{}, existingObj interface{}, path Path) error {
+	for i, part := range path {
+		switch {
+		case part.MapKey != nil:
+			typedObj, ok := obj.(map[string]interface{})
+			if !ok {
+				return nil // nothing to compare
+			}
+			typedExistingObj, ok := existingObj.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+
+			if len(path) == i+1 {
+				t.normalizeMapKey(typedObj, typedExistingObj, *part.MapKey)
+				return nil
+			}
+
+			obj = typedObj[*part.MapKey]
+			existingObj = typedExistingObj[*part.MapKey]
+
+		case part.ArrayIndex != nil:
+			typedObj, ok := obj.([]interface{})
+			if !ok {
+				return nil
+			}
+			typedExistingObj, ok := existingObj.([]interface{})
+			if !ok {
+				return nil
+			}
+
+			switch {
+			case part.ArrayIndex.All != nil:
+				for objI := range typedObj {
+					if objI >= len(typedExistingObj) {
+						break
+					}
+					err := t.applyToArrayItem(typedObj, typedExistingObj, objI, path[i+1:])
+					if err != nil {
+						return err
+					}
+				}
+				return nil // dealt with children, get out
+
+			case part.ArrayIndex.Index != nil:
+				objI := *part.ArrayIndex.Index
+				if objI >= len(typedObj) || objI >= len(typedExistingObj) {
+					return nil
+				}
+				return t.applyToArrayItem(typedObj, typedExistingObj, objI, path[i+1:])
+
+			default:
+				panic(fmt.Sprintf("Unknown array index: %#v", part.ArrayIndex))
+			}
+
+		case part.Regex != nil:
+			if part.Regex.Regex == nil {
+				panic("Regex should be non nil")
+			}
+			matchedKeys, err := matchRegexWithSrcObj(*part.Regex.Regex, obj)
+			if err != nil {
+				return err
+			}
+			for _, key := range matchedKeys {
+				newPath := append(Path{&PathPart{MapKey: &key}}, path[i+1:]...)
+				err := t.apply(obj, existingObj, newPath)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+
+		default:
+			panic(fmt.Sprintf("Unexpected path part: %#v", part))
+		}
+	}
+
+	// Empty path refers to the whole object
+	t.normalizeChildren(obj, existingObj)
+	return nil
+}
+
+func (t QuantityEquivalenceMod) applyToArrayItem(objs, existingObjs []interface{}, idx int, path Path) error {
+	if len(path) == 0 {
+		if val, ok := equivalentQuantity(objs[idx], existingObjs[idx]); ok {
+			objs[idx] = val
+			return nil
+		}
+		t.normalizeChildren(objs[idx], existingObjs[idx])
+		return nil
+	}
+	return t.apply(objs[idx], existingObjs[idx], path)
+}
+
+func (t QuantityEquivalenceMod) normalizeMapKey(obj, existingObj map[string]interface{}, key string) {
+	val, found := obj[key]
+	if !found {
+		return
+	}
+	existingVal, found := existingObj[key]
+	if !found {
+		return
+	}
+	if eqVal, ok := equivalentQuantity(val, existingVal); ok {
+		obj[key] = eqVal
+		return
+	}
+	t.normalizeChildren(val, existingVal)
+}
+
+// normalizeChildren descends into maps and arrays (e.g. container resources)
+// so that a path may point at a parent of multiple quantity fields
+func (t QuantityEquivalenceMod) normalizeChildren(obj, existingObj interface{}) {
+	switch typedObj := obj.(type) {
+	case map[string]interface{}:
+		typedExistingObj, ok := existingObj.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key := range typedObj {
+			t.normalizeMapKey(typedObj, typedExistingObj, key)
+		}
+
+	case []interface{}:
+		typedExistingObj, ok := existingObj.([]interface{})
+		if !ok || len(typedObj) != len(typedExistingObj) {
+			return
+		}
+		for idx := range typedObj {
+			_ = t.applyToArrayItem(typedObj, typedExistingObj, idx, nil)
+		}
+	}
+}
+
+// equivalentQuantity returns existing value if it's equal
+// to new value when both are interpreted as quantities
+func equivalentQuantity(val, existingVal interface{}) (interface{}, bool) {
+	if reflect.DeepEqual(val, existingVal) {
+		return nil, false
+	}
+	q, ok := parseQuantity(val)
+	if !ok {
+		return nil, false
+	}
+	existingQ, ok := parseQuantity(existingVal)
+	if !ok {
+		return nil, false
+	}
+	if q.Cmp(existingQ) != 0 {
+		return nil, false
+	}
+	return existingVal, true
+}
+
+func parseQuantity(val interface{}) (resource.Quantity, bool) {
+	var str string
+
+	switch typedVal := val.(type) {
+	case string:
+		str = typedVal
+	case int, int32, int64, float64:
+		str = fmt.Sprintf("%v", typedVal)
+	default:
+		return resource.Quantity{}, false
+	}
+
+	q, err := resource.ParseQuantity(str)
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+	return q, true
+}
@@ -0,0 +1,182 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	openapi_v2 "github.com/google/gnostic-models/openapiv2"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kube-openapi/pkg/util/proto"
+)
+
+const (
+	openAPIGVKExtKey                   = "x-kubernetes-group-version-kind"
+	openAPIPreserveUnknownFieldsExtKey = "x-kubernetes-preserve-unknown-fields"
+)
+
+// OpenAPISchema finds fields in resources that are not
+// described by the server's OpenAPI schema (e.g. typos).
+type OpenAPISchema struct {
+	coreClient kubernetes.Interface
+
+	fetchOnce    *sync.Once
+	fetchErr     error
+	schemasByGVK map[schema.GroupVersionKind]proto.Schema
+}
+
+func NewOpenAPISchema(coreClient kubernetes.Interface) *OpenAPISchema {
+	return &OpenAPISchema{coreClient: coreClient, fetchOnce: &sync.Once{}}
+}
+
+// UnknownFields returns paths (e.g. spec.template.spec.containers[0].imagePullPolcy)
+// of fields that are not known to the server. Resources without
+// a published schema (e.g. CRs of CRDs not yet created) are not checked.
+func (s *OpenAPISchema) UnknownFields(res Resource) ([]string, error) {
+	s.fetchOnce.Do(func() { s.fetchErr = s.fetch() })
+	if s.fetchErr != nil {
+		return nil, s.fetchErr
+	}
+
+	resSchema, found := s.schemasByGVK[res.GroupVersion().WithKind(res.Kind())]
+	if !found {
+		return nil, nil
+	}
+
+	var paths []string
+	s.visit(resSchema, res.UnstructuredObject(), "", &paths)
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+func (s *OpenAPISchema) fetch() error {
+	doc, err := s.coreClient.Discovery().OpenAPISchema()
+	if err != nil {
+		return fmt.Errorf("Fetching OpenAPI schema: %w", err)
+	}
+
+	return s.load(doc)
+}
+
+func (s *OpenAPISchema) load(doc *openapi_v2.Document) error {
+	models, err := proto.NewOpenAPIData(doc)
+	if err != nil {
+		return fmt.Errorf("Parsing OpenAPI schema: %w", err)
+	}
+
+	s.schemasByGVK = map[schema.GroupVersionKind]proto.Schema{}
+
+	for _, name := range models.ListModels() {
+		model := models.LookupModel(name)
+		if model == nil {
+			continue
+		}
+		for _, gvk := range s.gvksFromExtensions(model.GetExtensions()) {
+			s.schemasByGVK[gvk] = model
+		}
+	}
+
+	return nil
+}
+
+func (s *OpenAPISchema) gvksFromExtensions(exts map[string]interface{}) []schema.GroupVersionKind {
+	var result []schema.GroupVersionKind
+
+	gvks, ok := exts[openAPIGVKExtKey].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, gvk := range gvks {
+		gvkStrs := map[string]string{}
+
+		switch typedGVK := gvk.(type) {
+		case map[interface{}]interface{}:
+			for k, v := range typedGVK {
+				kStr, _ := k.(string)
+				gvkStrs[kStr], _ = v.(string)
+			}
+		case map[string]interface{}:
+			for k, v := range typedGVK {
+				gvkStrs[k], _ = v.(string)
+			}
+		default:
+			continue
+		}
+
+		result = append(result, schema.GroupVersionKind{
+			Group:   gvkStrs["group"],
+			Version: gvkStrs["version"],
+			Kind:    gvkStrs["kind"],
+		})
+	}
+
+	return result
+}
+
+func (s *OpenAPISchema) visit(sch proto.Schema, val interface{}, path string, unknownPaths *[]string) {
+	if preserve, ok := sch.GetExtensions()[openAPIPreserveUnknownFieldsExtKey].(bool); ok && preserve {
+		return
+	}
+
+	switch typedSch := sch.(type) {
+	case *proto.Ref:
+		s.visit(typedSch.SubSchema(), val, path, unknownPaths)
+
+	case *proto.Kind:
+		typedVal, ok := val.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		var keys []string
+		for key := range typedVal {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fieldPath := s.fieldPath(path, key)
+
+			fieldSch, found := typedSch.Fields[key]
+			if !found {
+				*unknownPaths = append(*unknownPaths, fieldPath)
+				continue
+			}
+			s.visit(fieldSch, typedVal[key], fieldPath, unknownPaths)
+		}
+
+	case *proto.Map:
+		typedVal, ok := val.(map[string]interface{})
+		if !ok || typedSch.SubType == nil {
+			return
+		}
+		for key, subVal := range typedVal {
+			s.visit(typedSch.SubType, subVal, s.fieldPath(path, key), unknownPaths)
+		}
+
+	case *proto.Array:
+		typedVal, ok := val.([]interface{})
+		if !ok || typedSch.SubType == nil {
+			return
+		}
+		for i, subVal := range typedVal {
+			s.visit(typedSch.SubType, subVal, fmt.Sprintf("%s[%d]", path, i), unknownPaths)
+		}
+
+	default:
+		// Primitives and arbitrary values do not have fields
+	}
+}
+
+func (*OpenAPISchema) fieldPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"sync"
+	"testing"
+
+	openapi_v2 "github.com/google/gnostic-models/openapiv2"
+	"github.com/stretchr/testify/require"
+)
+
+const openAPISchemaTestDoc = `
+swagger: "2.0"
+info: {title: Kubernetes, version: v1.30.0}
+paths: {}
+definitions:
+  io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta:
+    type: object
+    properties:
+      name: {type: string}
+      namespace: {type: string}
+      labels:
+        type: object
+        additionalProperties: {type: string}
+  io.k8s.api.core.v1.ConfigMap:
+    type: object
+    x-kubernetes-group-version-kind:
+    - {group: "", kind: ConfigMap, version: v1}
+    properties:
+      apiVersion: {type: string}
+      kind: {type: string}
+      metadata: {$ref: "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"}
+      data:
+        type: object
+        additionalProperties: {type: string}
+  io.k8s.api.core.v1.Container:
+    type: object
+    properties:
+      name: {type: string}
+      imagePullPolicy: {type: string}
+  io.k8s.api.core.v1.Pod:
+    type: object
+    x-kubernetes-group-version-kind:
+    - {group: "", kind: Pod, version: v1}
+    properties:
+      apiVersion: {type: string}
+      kind: {type: string}
+      metadata: {$ref: "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"}
+      spec:
+        type: object
+        properties:
+          containers:
+            type: array
+            items: {$ref: "#/definitions/io.k8s.api.core.v1.Container"}
+          extra:
+            type: object
+            x-kubernetes-preserve-unknown-fields: true
+`
+
+func TestOpenAPISchemaUnknownFields(t *testing.T) {
+	doc, err := openapi_v2.ParseDocument([]byte(openAPISchemaTestDoc))
+	require.NoError(t, err)
+
+	s := &OpenAPISchema{fetchOnce: &sync.Once{}}
+	s.fetchOnce.Do(func() { s.fetchErr = s.load(doc) })
+	require.NoError(t, s.fetchErr)
+
+	t.Run("known fields", func(t *testing.T) {
+		paths, err := s.UnknownFields(MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  labels: {key: val}
+data:
+  key: val
+`)))
+		require.NoError(t, err)
+		require.Empty(t, paths)
+	})
+
+	t.Run("unknown fields within nested objects, maps and arrays", func(t *testing.T) {
+		paths, err := s.UnknownFields(MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+  nmespace: typo
+spec:
+  containers:
+  - name: app
+    imagePullPolcy: Always
+  - name: sidecar
+  extra:
+    anything: {goes: here}
+  hostNetwrok: true
+`)))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"metadata.nmespace",
+			"spec.containers[0].imagePullPolcy",
+			"spec.hostNetwrok",
+		}, paths)
+	})
+
+	t.Run("resources without schema are not checked", func(t *testing.T) {
+		paths, err := s.UnknownFields(MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Custom
+metadata:
+  name: cr
+spec:
+  anything: true
+`)))
+		require.NoError(t, err)
+		require.Empty(t, paths)
+	})
+}
@@ -75,7 +75,9 @@ func (w PodWatcher) watch(podsToWatchCh chan corev1.Pod, cancelCh chan struct{})
 			}
 
 			switch e.Type {
-			case watch.Added:
+			// Modified events let consumers notice Pods changing state
+			// (e.g. starting to fail); UniquePodWatcher removes duplicates
+			case watch.Added, watch.Modified:
 				podsToWatchCh <- *pod
 			}
 
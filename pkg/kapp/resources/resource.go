@@ -81,6 +81,9 @@ func NewResourceUnstructured(un unstructured.Unstructured, resType ResourceType)
 	return &ResourceImpl{un: un, resType: resType}
 }
 
+// NewResourceFromBytes parses YAML or JSON. YAML is converted to JSON before
+// decoding hence anchors, aliases and merge keys are fully expanded (without sharing
+// underlying objects) which makes resources comparable to ones returned by API server.
 func NewResourceFromBytes(data []byte) (*ResourceImpl, error) {
 	var content map[string]interface{}
 
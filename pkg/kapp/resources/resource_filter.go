@@ -24,7 +24,8 @@ type ResourceFilter struct {
 	KindNsNames    []string
 	Labels         []string
 
-	BoolFilter *BoolFilter `json:"-"`
+	BoolFilter *BoolFilter           `json:"-"`
+	Exprs      []*ResourceFilterExpr `json:"-"`
 }
 
 func (f ResourceFilter) Apply(resources []Resource) []Resource {
@@ -40,6 +41,12 @@ func (f ResourceFilter) Apply(resources []Resource) []Resource {
 }
 
 func (f ResourceFilter) Matches(resource Resource) bool {
+	for _, expr := range f.Exprs {
+		if !expr.Matches(resource) {
+			return false
+		}
+	}
+
 	if f.BoolFilter != nil {
 		return f.BoolFilter.Matches(resource)
 	}
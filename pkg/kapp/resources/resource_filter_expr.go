@@ -0,0 +1,119 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ResourceFilterExpr matches resources based on a JSONPath predicate:
+//   - '<path>' matches when path resolves to a value other than null, false or empty string
+//   - '<path> == <value>' matches when any value found at path equals given value
+//   - '<path> != <value>' matches when no value found at path equals given value
+//
+// Value may be quoted (e.g. "frontend") or bare (e.g. 3, true).
+// Example: .metadata.labels['app.kubernetes.io/part-of'] == "frontend"
+type ResourceFilterExpr struct {
+	path     JSONPath
+	op       string
+	value    string
+	original string
+}
+
+const (
+	resourceFilterExprOpEq  = "=="
+	resourceFilterExprOpNeq = "!="
+)
+
+func NewResourceFilterExpr(expr string) (*ResourceFilterExpr, error) {
+	pathStr, op, value := strings.TrimSpace(expr), "", ""
+
+	if idx := resourceFilterExprOpIndex(pathStr); idx >= 0 {
+		op = pathStr[idx : idx+2]
+		value = strings.TrimSpace(pathStr[idx+2:])
+		pathStr = strings.TrimSpace(pathStr[:idx])
+
+		if len(value) == 0 {
+			return nil, fmt.Errorf("Expected filter expression '%s' to specify value after '%s'", expr, op)
+		}
+		if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+			if len(value) < 2 || value[0] != value[len(value)-1] {
+				return nil, fmt.Errorf("Expected filter expression '%s' to have properly quoted value", expr)
+			}
+			unquotedVal, err := strconv.Unquote(`"` + value[1:len(value)-1] + `"`)
+			if err != nil {
+				return nil, fmt.Errorf("Expected filter expression '%s' to have properly quoted value", expr)
+			}
+			value = unquotedVal
+		}
+	}
+
+	path, err := NewJSONPath(pathStr)
+	if err != nil {
+		return nil, fmt.Errorf("Parsing filter expression '%s': %w", expr, err)
+	}
+
+	return &ResourceFilterExpr{path: path, op: op, value: value, original: expr}, nil
+}
+
+func (e ResourceFilterExpr) Matches(resource Resource) bool {
+	vals := e.path.Eval(resource.UnstructuredObject())
+
+	switch e.op {
+	case resourceFilterExprOpEq:
+		return e.anyEqual(vals)
+
+	case resourceFilterExprOpNeq:
+		return !e.anyEqual(vals)
+
+	default:
+		for _, val := range vals {
+			if val != nil && val != false && val != "" {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (e ResourceFilterExpr) String() string { return e.original }
+
+func (e ResourceFilterExpr) anyEqual(vals []interface{}) bool {
+	for _, val := range vals {
+		if val != nil && fmt.Sprintf("%v", val) == e.value {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceFilterExprOpIndex finds comparison operator that is not
+// part of the path itself (e.g. inside [?(@.type=="Ready")] filter)
+func resourceFilterExprOpIndex(expr string) int {
+	var depth int
+	var quote byte
+
+	for i := 0; i < len(expr)-1; i++ {
+		switch {
+		case quote != 0:
+			if expr[i] == quote {
+				quote = 0
+			}
+		case expr[i] == '"' || expr[i] == '\'':
+			quote = expr[i]
+		case expr[i] == '[':
+			depth++
+		case expr[i] == ']':
+			depth--
+		case depth == 0:
+			if op := expr[i : i+2]; op == resourceFilterExprOpEq || op == resourceFilterExprOpNeq {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceFilterExpr(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    tier: frontend
+    app.kubernetes.io/part-of: shop
+  annotations:
+    empty: ""
+spec:
+  replicas: 3
+  paused: false
+status:
+  conditions:
+  - type: Available
+    status: "True"
+  - type: Progressing
+    status: "False"
+`))
+
+	exs := []struct {
+		Expr     string
+		Expected bool
+	}{
+		{`.metadata.labels.tier == "frontend"`, true},
+		{`.metadata.labels.tier=="backend"`, false},
+		{`{.metadata.labels.tier} != 'backend'`, true},
+		{`.metadata.labels['app.kubernetes.io/part-of'] == "shop"`, true},
+		{`.metadata.labels.missing != "x"`, true},
+		{`.metadata.labels.missing == "x"`, false},
+		{`.spec.replicas == 3`, true},
+		{`.spec.replicas != 3`, false},
+		{`.metadata.labels.tier`, true},
+		{`.metadata.labels.missing`, false},
+		{`.metadata.annotations.empty`, false},
+		{`.spec.paused`, false},
+		{`.status.conditions[?(@.type=="Available")].status == "True"`, true},
+		{`.status.conditions[?(@.type=="Progressing")].status == "True"`, false},
+		{`.status.conditions[*].type == "Progressing"`, true},
+	}
+
+	for _, ex := range exs {
+		expr, err := ctlres.NewResourceFilterExpr(ex.Expr)
+		require.NoError(t, err, ex.Expr)
+		require.Equal(t, ex.Expected, expr.Matches(res), ex.Expr)
+
+		filter := ctlres.ResourceFilter{Exprs: []*ctlres.ResourceFilterExpr{expr}}
+		require.Equal(t, ex.Expected, len(filter.Apply([]ctlres.Resource{res})) == 1, ex.Expr)
+	}
+}
+
+func TestResourceFilterExprInvalid(t *testing.T) {
+	exs := []struct {
+		Expr string
+		Err  string
+	}{
+		{`metadata.name`, "Parsing filter expression 'metadata.name': Unexpected character 'm' in path"},
+		{`.metadata.name ==`, "Expected filter expression '.metadata.name ==' to specify value after '=='"},
+		{`.metadata.name == "web`, `Expected filter expression '.metadata.name == "web' to have properly quoted value`},
+		{`.status.conditions[0`, "Parsing filter expression '.status.conditions[0': Expected closing bracket in path"},
+		{``, "Parsing filter expression '': Expected non-empty path"},
+	}
+
+	for _, ex := range exs {
+		_, err := ctlres.NewResourceFilterExpr(ex.Expr)
+		require.EqualError(t, err, ex.Err)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// NewResourceFromRef returns resource stub that identifies resource
+// specified in '<apiVersion>/<kind>/<namespace>/<name>' format
+// (e.g. apps/v1/Deployment/default/app). Namespace is left
+// empty for cluster scoped resources (e.g. v1/Namespace//app).
+func NewResourceFromRef(ref string) (Resource, error) {
+	pieces := strings.Split(ref, "/")
+	if len(pieces) != 4 && len(pieces) != 5 {
+		return nil, fmt.Errorf("Expected resource reference '%s' to be in "+
+			"'<apiVersion>/<kind>/<namespace>/<name>' format", ref)
+	}
+
+	last := len(pieces) - 1
+	apiVersion := strings.Join(pieces[:last-2], "/")
+	kind, namespace, name := pieces[last-2], pieces[last-1], pieces[last]
+
+	if len(kind) == 0 || len(name) == 0 {
+		return nil, fmt.Errorf("Expected resource reference '%s' to specify kind and name", ref)
+	}
+
+	return NewResourceUnstructured(unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"namespace": namespace,
+				"name":      name,
+			},
+		},
+	}, ResourceType{}), nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResourceFromRef(t *testing.T) {
+	res, err := ctlres.NewResourceFromRef("apps/v1/Deployment/default/app")
+	require.NoError(t, err)
+	require.Equal(t, "deployment/app (apps/v1) namespace: default", res.Description())
+
+	res, err = ctlres.NewResourceFromRef("v1/ConfigMap/default/cfg")
+	require.NoError(t, err)
+	require.Equal(t, "configmap/cfg (v1) namespace: default", res.Description())
+
+	res, err = ctlres.NewResourceFromRef("apiextensions.k8s.io/v1/CustomResourceDefinition//foos.example.com")
+	require.NoError(t, err)
+	require.Equal(t, "customresourcedefinition/foos.example.com (apiextensions.k8s.io/v1) cluster", res.Description())
+}
+
+func TestNewResourceFromRefInvalid(t *testing.T) {
+	for _, ref := range []string{"", "v1/ConfigMap/cfg", "a/b/v1/ConfigMap/ns/cfg", "v1//default/cfg", "v1/ConfigMap/default/"} {
+		_, err := ctlres.NewResourceFromRef(ref)
+		require.Error(t, err, "Expected error for ref '%s'", ref)
+	}
+}
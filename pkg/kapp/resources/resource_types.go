@@ -5,10 +5,12 @@ package resources
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"carvel.dev/kapp/pkg/kapp/logger"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
@@ -29,6 +31,7 @@ type ResourceTypesImplOpts struct {
 type ResourceTypesImpl struct {
 	coreClient kubernetes.Interface
 	opts       ResourceTypesImplOpts
+	logger     logger.Logger
 
 	memoizedResTypes     *[]ResourceType
 	memoizedResTypesLock sync.RWMutex
@@ -41,8 +44,8 @@ type ResourceType struct {
 	metav1.APIResource
 }
 
-func NewResourceTypesImpl(coreClient kubernetes.Interface, opts ResourceTypesImplOpts) *ResourceTypesImpl {
-	return &ResourceTypesImpl{coreClient: coreClient, opts: opts}
+func NewResourceTypesImpl(coreClient kubernetes.Interface, opts ResourceTypesImplOpts, logger logger.Logger) *ResourceTypesImpl {
+	return &ResourceTypesImpl{coreClient: coreClient, opts: opts, logger: logger.NewPrefixed("ResourceTypes")}
 }
 
 func (g *ResourceTypesImpl) All(ignoreCachedResTypes bool) ([]ResourceType, error) {
@@ -122,6 +125,7 @@ func (g *ResourceTypesImpl) serverResources() ([]*metav1.APIResourceList, error)
 			return serverResources, nil
 		} else if typedLastErr, ok := lastErr.(*discovery.ErrGroupDiscoveryFailed); ok {
 			if len(serverResources) > 0 && g.canIgnoreFailingGroupVersions(typedLastErr.Groups) {
+				g.warnSkippedGroupVersions(typedLastErr.Groups)
 				return serverResources, nil
 			}
 			// Even local services may not be Available immediately, so retry
@@ -133,6 +137,21 @@ func (g *ResourceTypesImpl) serverResources() ([]*metav1.APIResourceList, error)
 	return nil, lastErr
 }
 
+// warnSkippedGroupVersions makes it visible that resources of failing
+// group versions are neither diffed nor garbage collected
+func (g *ResourceTypesImpl) warnSkippedGroupVersions(groupVers map[schema.GroupVersion]error) {
+	var descs []string
+	for groupVer, err := range groupVers {
+		descs = append(descs, fmt.Sprintf("%s (%s)", groupVer.String(), err))
+	}
+	sort.Strings(descs)
+
+	for _, desc := range descs {
+		g.logger.Info("Warning: Skipped failing API group version %s; "+
+			"its resources may not be included in diff or garbage collected", desc)
+	}
+}
+
 func (g *ResourceTypesImpl) memoizedAll() ([]ResourceType, error) {
 	g.memoizedResTypesLock.RLock()
 
@@ -0,0 +1,63 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+)
+
+type failingGroupsCoreClient struct {
+	kubernetes.Interface
+	discovery failingGroupsDiscovery
+}
+
+func (c failingGroupsCoreClient) Discovery() discovery.DiscoveryInterface { return c.discovery }
+
+type failingGroupsDiscovery struct {
+	discovery.DiscoveryInterface
+	failedGroups map[schema.GroupVersion]error
+}
+
+func (d failingGroupsDiscovery) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	resLists := []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap", Namespaced: true}},
+	}}
+	return nil, resLists, &discovery.ErrGroupDiscoveryFailed{Groups: d.failedGroups}
+}
+
+func TestResourceTypesWarnsAboutSkippedGroupVersions(t *testing.T) {
+	coreClient := failingGroupsCoreClient{discovery: failingGroupsDiscovery{
+		failedGroups: map[schema.GroupVersion]error{
+			{Group: "metrics.k8s.io", Version: "v1beta1"}: fmt.Errorf("service unavailable"),
+			{Group: "custom.example.com", Version: "v1"}:  fmt.Errorf("service unavailable"),
+		},
+	}}
+
+	out := &bytes.Buffer{}
+	uiLogger := logger.NewUILogger(ui.NewWriterUI(out, out, ui.NewNoopLogger()))
+
+	resTypes := ctlres.NewResourceTypesImpl(coreClient, ctlres.ResourceTypesImplOpts{IgnoreFailingAPIServices: true}, uiLogger)
+
+	types, err := resTypes.All(false)
+	require.NoError(t, err)
+	require.Len(t, types, 1)
+	require.Equal(t, "ConfigMap", types[0].Kind)
+
+	require.Contains(t, out.String(), "Skipped failing API group version custom.example.com/v1 (service unavailable)")
+	require.Contains(t, out.String(), "Skipped failing API group version metrics.k8s.io/v1beta1 (service unavailable)")
+	require.Less(t, bytes.Index(out.Bytes(), []byte("custom.example.com/v1")), bytes.Index(out.Bytes(), []byte("metrics.k8s.io/v1beta1")),
+		"Expected skipped group versions to be sorted")
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+const (
+	warningHeader = "Warning"
+)
+
+type resourceWarningsCtxKey struct{}
+
+// ResourceWarnings collects warnings returned by the API server
+// (e.g. about use of deprecated APIs) and associates them with resources
+// that were being changed when warnings were returned. Once enabled,
+// collected warnings are no longer passed on to the client's warning handler
+// so that they are not shown twice.
+type ResourceWarnings struct {
+	enabled  atomic.Bool // read concurrently by in-flight requests
+	lock     sync.Mutex
+	resDescs []string
+	warnings map[string][]string
+}
+
+type ResourceWarning struct {
+	ResourceDescription string
+	Messages            []string
+}
+
+func NewResourceWarnings() *ResourceWarnings {
+	return &ResourceWarnings{warnings: map[string][]string{}}
+}
+
+func (w *ResourceWarnings) Enable() { w.enabled.Store(true) }
+
+// WrapTransport is meant to be used with rest.Config's WrapTransport
+func (w *ResourceWarnings) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return resourceWarningsRoundTripper{rt: rt, warnings: w}
+}
+
+// List returns resources with warnings in the order they were first seen
+func (w *ResourceWarnings) List() []ResourceWarning {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var result []ResourceWarning
+	for _, desc := range w.resDescs {
+		result = append(result, ResourceWarning{ResourceDescription: desc, Messages: w.warnings[desc]})
+	}
+	return result
+}
+
+func (w *ResourceWarnings) add(resDesc string, msg string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	msgs, found := w.warnings[resDesc]
+	if !found {
+		w.resDescs = append(w.resDescs, resDesc)
+	}
+	for _, existingMsg := range msgs {
+		if existingMsg == msg {
+			return // Requests are retried hence same warning may be seen again
+		}
+	}
+	w.warnings[resDesc] = append(msgs, msg)
+}
+
+// resourceWarningsContext marks requests made on behalf of a resource
+func resourceWarningsContext(resource Resource) context.Context {
+	return context.WithValue(context.TODO(), resourceWarningsCtxKey{}, resource.Description())
+}
+
+type resourceWarningsRoundTripper struct {
+	rt       http.RoundTripper
+	warnings *ResourceWarnings
+}
+
+func (t resourceWarningsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil || resp == nil || !t.warnings.enabled.Load() {
+		return resp, err
+	}
+
+	resDesc, ok := req.Context().Value(resourceWarningsCtxKey{}).(string)
+	if !ok {
+		return resp, err
+	}
+
+	headers := resp.Header.Values(warningHeader)
+	if len(headers) == 0 {
+		return resp, err
+	}
+
+	parsed, _ := utilnet.ParseWarningHeaders(headers)
+	for _, warning := range parsed {
+		t.warnings.add(resDesc, warning.Text)
+	}
+
+	resp.Header.Del(warningHeader)
+
+	return resp, err
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type warningRoundTripper struct {
+	warnings []string
+}
+
+func (t warningRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	for _, warning := range t.warnings {
+		resp.Header.Add(warningHeader, warning)
+	}
+	return resp, nil
+}
+
+func TestResourceWarningsCollectsOnceEnabled(t *testing.T) {
+	res := MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: ns
+`))
+
+	resWarnings := NewResourceWarnings()
+	rt := resWarnings.WrapTransport(warningRoundTripper{warnings: []string{
+		`299 - "v1 ConfigMap is deprecated"`,
+		`299 - "v1 ConfigMap is deprecated"`,
+	}})
+
+	req := mustNewWarningsRequest(t, res)
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Len(t, resp.Header.Values(warningHeader), 2, "Expected warnings to be passed through when disabled")
+	require.Empty(t, resWarnings.List())
+
+	resWarnings.Enable()
+
+	resp, err = rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Empty(t, resp.Header.Values(warningHeader), "Expected collected warnings to be removed from response")
+	require.Equal(t, []ResourceWarning{{
+		ResourceDescription: res.Description(),
+		Messages:            []string{"v1 ConfigMap is deprecated"},
+	}}, resWarnings.List())
+}
+
+func TestResourceWarningsConcurrentEnable(t *testing.T) {
+	res := MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`))
+
+	resWarnings := NewResourceWarnings()
+	rt := resWarnings.WrapTransport(warningRoundTripper{warnings: []string{`299 - "warning"`}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			resWarnings.Enable()
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := rt.RoundTrip(mustNewWarningsRequest(t, res))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	_, err := rt.RoundTrip(mustNewWarningsRequest(t, res))
+	require.NoError(t, err)
+	require.Len(t, resWarnings.List(), 1)
+}
+
+func mustNewWarningsRequest(t *testing.T, res Resource) *http.Request {
+	req, err := http.NewRequestWithContext(resourceWarningsContext(res), http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+	return req
+}
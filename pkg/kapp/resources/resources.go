@@ -40,18 +40,28 @@ const (
 
 type Resources interface {
 	All([]ResourceType, AllOpts) ([]Resource, error)
-	Delete(Resource) error
+	Delete(Resource, DeleteOpts) error
 	Exists(Resource, ExistsOpts) (Resource, bool, error)
 	Get(Resource) (Resource, error)
 	Patch(Resource, types.PatchType, []byte) (Resource, error)
 	Update(Resource) (Resource, error)
 	Create(resource Resource) (Resource, error)
+
+	// CreateDryRun and UpdateDryRun make server process requests
+	// (including admission webhooks) without persisting results
+	CreateDryRun(Resource) (Resource, error)
+	UpdateDryRun(Resource) (Resource, error)
 }
 
 type ExistsOpts struct {
 	SameUID bool
 }
 
+type DeleteOpts struct {
+	// PropagationPolicy defaults to Background
+	PropagationPolicy metav1.DeletionPropagation
+}
+
 type ResourcesImpl struct {
 	resourceTypes      ResourceTypes
 	coreClient         kubernetes.Interface
@@ -258,7 +268,7 @@ func (c *ResourcesImpl) Create(resource Resource) (Resource, error) {
 	var createdUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		createdUn, err = resClient.Create(context.TODO(), resource.unstructuredPtr(), metav1.CreateOptions{})
+		createdUn, err = resClient.Create(resourceWarningsContext(resource), resource.unstructuredPtr(), metav1.CreateOptions{})
 		return err
 	})
 	if err != nil {
@@ -285,7 +295,7 @@ func (c *ResourcesImpl) Update(resource Resource) (Resource, error) {
 	var updatedUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		updatedUn, err = resClient.Update(context.TODO(), resource.unstructuredPtr(), metav1.UpdateOptions{})
+		updatedUn, err = resClient.Update(resourceWarningsContext(resource), resource.unstructuredPtr(), metav1.UpdateOptions{})
 		return err
 	})
 	if err != nil {
@@ -295,6 +305,44 @@ func (c *ResourcesImpl) Update(resource Resource) (Resource, error) {
 	return NewResourceUnstructured(*updatedUn, resType), nil
 }
 
+func (c *ResourcesImpl) CreateDryRun(resource Resource) (Resource, error) {
+	resClient, resType, err := c.resourceClient(resource, resourceClientOpts{Warnings: false})
+	if err != nil {
+		return nil, err
+	}
+
+	var createdUn *unstructured.Unstructured
+
+	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
+		createdUn, err = resClient.Create(context.TODO(), resource.unstructuredPtr(), metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+		return err
+	})
+	if err != nil {
+		return nil, c.resourceErr(err, "Creating (dry run)", resource)
+	}
+
+	return NewResourceUnstructured(*createdUn, resType), nil
+}
+
+func (c *ResourcesImpl) UpdateDryRun(resource Resource) (Resource, error) {
+	resClient, resType, err := c.resourceClient(resource, resourceClientOpts{Warnings: false})
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedUn *unstructured.Unstructured
+
+	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
+		updatedUn, err = resClient.Update(context.TODO(), resource.unstructuredPtr(), metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+		return err
+	})
+	if err != nil {
+		return nil, c.resourceErr(err, "Updating (dry run)", resource)
+	}
+
+	return NewResourceUnstructured(*updatedUn, resType), nil
+}
+
 func (c *ResourcesImpl) Patch(resource Resource, patchType types.PatchType, data []byte) (Resource, error) {
 	if resourcesDebug {
 		t1 := time.Now().UTC()
@@ -309,7 +357,7 @@ func (c *ResourcesImpl) Patch(resource Resource, patchType types.PatchType, data
 	var patchedUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		patchedUn, err = resClient.Patch(context.TODO(), resource.Name(), patchType, data, metav1.PatchOptions{})
+		patchedUn, err = resClient.Patch(resourceWarningsContext(resource), resource.Name(), patchType, data, metav1.PatchOptions{})
 		return err
 	})
 	if err != nil {
@@ -319,7 +367,7 @@ func (c *ResourcesImpl) Patch(resource Resource, patchType types.PatchType, data
 	return NewResourceUnstructured(*patchedUn, resType), nil
 }
 
-func (c *ResourcesImpl) Delete(resource Resource) error {
+func (c *ResourcesImpl) Delete(resource Resource, opts DeleteOpts) error {
 	if resourcesDebug {
 		t1 := time.Now().UTC()
 		defer func() { c.logger.Debug("delete %s", time.Now().UTC().Sub(t1)) }()
@@ -336,9 +384,11 @@ func (c *ResourcesImpl) Delete(resource Resource) error {
 	}
 
 	if resType.Deletable() {
-		// TODO is setting deletion policy a correct thing to do?
 		// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#setting-the-cascading-deletion-policy
-		delPol := metav1.DeletePropagationBackground
+		delPol := opts.PropagationPolicy
+		if len(delPol) == 0 {
+			delPol = metav1.DeletePropagationBackground
+		}
 		delOpts := metav1.DeleteOptions{PropagationPolicy: &delPol}
 
 		// Some resources may not have UID (example: PodMetrics.metrics.k8s.io)
@@ -347,7 +397,7 @@ func (c *ResourcesImpl) Delete(resource Resource) error {
 			delOpts.Preconditions = &metav1.Preconditions{UID: &resUID}
 		}
 
-		err = resClient.Delete(context.TODO(), resource.Name(), delOpts)
+		err = resClient.Delete(resourceWarningsContext(resource), resource.Name(), delOpts)
 		if err != nil {
 			if errors.IsNotFound(err) {
 				c.logger.Info("TODO resource '%s' is already gone", resource.Description())
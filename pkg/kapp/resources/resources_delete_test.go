@@ -0,0 +1,102 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"context"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestResourcesDeletePropagationPolicy(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+  uid: web-uid
+`))
+
+	exs := []struct {
+		Policy   metav1.DeletionPropagation
+		Expected metav1.DeletionPropagation
+	}{
+		{"", metav1.DeletePropagationBackground},
+		{metav1.DeletePropagationBackground, metav1.DeletePropagationBackground},
+		{metav1.DeletePropagationForeground, metav1.DeletePropagationForeground},
+		{metav1.DeletePropagationOrphan, metav1.DeletePropagationOrphan},
+	}
+
+	for _, ex := range exs {
+		dynamicClient := &deleteFakeDynamicClient{}
+
+		resources := ctlres.NewResourcesImpl(deleteFakeResourceTypes{}, nil, dynamicClient,
+			dynamicClient, ctlres.ResourcesImplOpts{}, logger.NewUILogger(ui.NewNoopUI()))
+
+		err := resources.Delete(res, ctlres.DeleteOpts{PropagationPolicy: ex.Policy})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"default/web"}, dynamicClient.resClient.deletedNames)
+		require.Len(t, dynamicClient.resClient.deletedOpts, 1)
+
+		delOpts := dynamicClient.resClient.deletedOpts[0]
+		require.NotNil(t, delOpts.PropagationPolicy)
+		require.Equal(t, ex.Expected, *delOpts.PropagationPolicy)
+		require.NotNil(t, delOpts.Preconditions)
+		require.Equal(t, "web-uid", string(*delOpts.Preconditions.UID))
+	}
+}
+
+type deleteFakeResourceTypes struct{}
+
+var _ ctlres.ResourceTypes = deleteFakeResourceTypes{}
+
+func (deleteFakeResourceTypes) All(bool) ([]ctlres.ResourceType, error) { return nil, nil }
+
+func (deleteFakeResourceTypes) Find(ctlres.Resource) (ctlres.ResourceType, error) {
+	return ctlres.ResourceType{
+		GroupVersionResource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		APIResource:          metav1.APIResource{Name: "deployments", Namespaced: true, Verbs: []string{"delete"}},
+	}, nil
+}
+
+func (deleteFakeResourceTypes) CanIgnoreFailingGroupVersion(schema.GroupVersion) bool { return false }
+
+type deleteFakeDynamicClient struct {
+	resClient deleteFakeResourceClient
+}
+
+var _ dynamic.Interface = &deleteFakeDynamicClient{}
+
+func (c *deleteFakeDynamicClient) Resource(schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &c.resClient
+}
+
+// deleteFakeResourceClient only implements Delete (other methods panic)
+type deleteFakeResourceClient struct {
+	dynamic.NamespaceableResourceInterface
+
+	namespace    string
+	deletedNames []string
+	deletedOpts  []metav1.DeleteOptions
+}
+
+func (c *deleteFakeResourceClient) Namespace(ns string) dynamic.ResourceInterface {
+	c.namespace = ns
+	return c
+}
+
+func (c *deleteFakeResourceClient) Delete(_ context.Context, name string, opts metav1.DeleteOptions, _ ...string) error {
+	c.deletedNames = append(c.deletedNames, c.namespace+"/"+name)
+	c.deletedOpts = append(c.deletedOpts, opts)
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	semver "github.com/hashicorp/go-version"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// MinServerVersion checks that cluster runs at least specified Kubernetes version.
+// Only major, minor and patch segments are compared since providers
+// add their own suffixes (e.g. v1.27.3-eks-a5565ad, v1.27.3-gke.100).
+type MinServerVersion struct {
+	min *semver.Version
+}
+
+func NewMinServerVersion(minVersion string) (MinServerVersion, error) {
+	min, err := semver.NewVersion(minVersion)
+	if err != nil {
+		return MinServerVersion{}, fmt.Errorf("Parsing minimum server version '%s': %w", minVersion, err)
+	}
+	return MinServerVersion{min.Core()}, nil
+}
+
+func (v MinServerVersion) Check(info *version.Info) error {
+	serverVersion, err := v.serverVersion(info)
+	if err != nil {
+		return err
+	}
+	if serverVersion.LessThan(v.min) {
+		return fmt.Errorf("Expected Kubernetes server version to be at least '%s', but was '%s'",
+			v.min, v.serverVersionDesc(info))
+	}
+	return nil
+}
+
+func (MinServerVersion) serverVersion(info *version.Info) (*semver.Version, error) {
+	if len(info.GitVersion) > 0 {
+		ver, err := semver.NewVersion(info.GitVersion)
+		if err == nil {
+			return ver.Core(), nil
+		}
+	}
+
+	// Some providers report minor version with a suffix (e.g. '27+')
+	minor := strings.TrimRight(info.Minor, "+")
+
+	ver, err := semver.NewVersion(info.Major + "." + minor)
+	if err != nil {
+		return nil, fmt.Errorf("Parsing Kubernetes server version (major '%s', minor '%s', git version '%s'): %w",
+			info.Major, info.Minor, info.GitVersion, err)
+	}
+	return ver, nil
+}
+
+func (MinServerVersion) serverVersionDesc(info *version.Info) string {
+	if len(info.GitVersion) > 0 {
+		return info.GitVersion
+	}
+	return info.Major + "." + info.Minor
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestMinServerVersion(t *testing.T) {
+	exs := []struct {
+		Min         string
+		Info        version.Info
+		ExpectedErr string
+	}{
+		{Min: "1.27", Info: version.Info{GitVersion: "v1.27.0"}},
+		{Min: "1.27", Info: version.Info{GitVersion: "v1.28.2"}},
+		{Min: "v1.27.3", Info: version.Info{GitVersion: "v1.27.3"}},
+		// Provider suffixes are ignored (otherwise treated as pre-releases)
+		{Min: "1.27", Info: version.Info{GitVersion: "v1.27.3-eks-a5565ad"}},
+		{Min: "1.27.3", Info: version.Info{GitVersion: "v1.27.3-gke.100"}},
+		{Min: "1.27", Info: version.Info{GitVersion: "v1.27.1+k3s1"}},
+		{
+			Min:         "1.27",
+			Info:        version.Info{GitVersion: "v1.26.9-eks-a5565ad"},
+			ExpectedErr: "Expected Kubernetes server version to be at least '1.27.0', but was 'v1.26.9-eks-a5565ad'",
+		},
+		{
+			Min:         "1.27.4",
+			Info:        version.Info{GitVersion: "v1.27.3"},
+			ExpectedErr: "Expected Kubernetes server version to be at least '1.27.4', but was 'v1.27.3'",
+		},
+		// Falls back to major and minor (minor may contain suffix)
+		{Min: "1.27", Info: version.Info{Major: "1", Minor: "27+", GitVersion: "custom"}},
+		{
+			Min:         "1.27",
+			Info:        version.Info{Major: "1", Minor: "26+"},
+			ExpectedErr: "Expected Kubernetes server version to be at least '1.27.0', but was '1.26+'",
+		},
+		{
+			Min:         "1.27",
+			Info:        version.Info{GitVersion: "custom"},
+			ExpectedErr: "Parsing Kubernetes server version (major '', minor '', git version 'custom'): Malformed version: .",
+		},
+	}
+
+	for _, ex := range exs {
+		minVersion, err := ctlres.NewMinServerVersion(ex.Min)
+		require.NoError(t, err)
+
+		info := ex.Info
+		err = minVersion.Check(&info)
+		if len(ex.ExpectedErr) > 0 {
+			require.EqualError(t, err, ex.ExpectedErr, "Min %s, info %#v", ex.Min, ex.Info)
+		} else {
+			require.NoError(t, err, "Min %s, info %#v", ex.Min, ex.Info)
+		}
+	}
+
+	_, err := ctlres.NewMinServerVersion("latest")
+	require.EqualError(t, err, "Parsing minimum server version 'latest': Malformed version: latest")
+}
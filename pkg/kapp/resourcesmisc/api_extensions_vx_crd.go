@@ -56,14 +56,18 @@ type crdSpec struct {
 	Version  string           `yaml:"version"`
 	Versions []crdSpecVersion `yaml:"versions"`
 	Names    crdSpecNames     `yaml:"names"`
+
+	AdditionalPrinterColumns []crdSpecPrinterColumn `yaml:"additionalPrinterColumns"`
 }
 
 type crdSpecVersion struct {
-	Name string `yaml:"name"`
+	Name                     string                 `yaml:"name"`
+	AdditionalPrinterColumns []crdSpecPrinterColumn `yaml:"additionalPrinterColumns"`
 }
 
 type crdSpecNames struct {
-	Kind string `yaml:"kind"`
+	Kind   string `yaml:"kind"`
+	Plural string `yaml:"plural"`
 }
 
 func (o crdObj) Versions() []string {
@@ -97,6 +101,22 @@ func (s APIExtensionsVxCRD) Kind() (crdName string, err error) {
 	return crdObj.Spec.Names.Kind, err
 }
 
+func (s APIExtensionsVxCRD) Plural() (crdPlural string, err error) {
+	crdObj, err := s.contents()
+	if err != nil {
+		return crdPlural, err
+	}
+	return crdObj.Spec.Names.Plural, err
+}
+
+func (s APIExtensionsVxCRD) Namespaced() (bool, error) {
+	crdObj, err := s.contents()
+	if err != nil {
+		return false, err
+	}
+	return crdObj.Spec.Scope != "Cluster", err
+}
+
 /*
 
 ---
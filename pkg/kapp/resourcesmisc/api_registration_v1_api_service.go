@@ -26,7 +26,7 @@ func NewAPIRegistrationV1APIService(resource ctlres.Resource, ignoreFailing bool
 }
 
 func (s APIRegistrationV1APIService) IsDoneApplying() DoneApplyState {
-	allTrue, msg := Conditions{s.resource}.IsSelectedTrue([]string{"Available"})
+	allTrue, msg := apiServiceAvailable(s.resource)
 
 	if !allTrue && s.ignoreFailing {
 		return DoneApplyState{Done: true, Successful: true, Message: fmt.Sprintf("Ignoring (%s)", msg)}
@@ -35,6 +35,22 @@ func (s APIRegistrationV1APIService) IsDoneApplying() DoneApplyState {
 	return DoneApplyState{Done: allTrue, Successful: allTrue, Message: msg}
 }
 
+// apiServiceAvailable includes message of Available condition when
+// APIService is not available since it typically explains what's wrong
+// with backing service (e.g. 'failing or missing response from https://...')
+func apiServiceAvailable(resource ctlres.Resource) (bool, string) {
+	conds := Conditions{resource}
+
+	available, msg := conds.IsSelectedTrue([]string{"Available"})
+	if !available {
+		if cond, found := conds.find("Available"); found && len(cond.Message) > 0 {
+			msg += ": " + cond.Message
+		}
+	}
+
+	return available, msg
+}
+
 /*
 
 status:
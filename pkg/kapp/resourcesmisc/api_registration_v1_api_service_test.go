@@ -0,0 +1,87 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIRegistrationV1APIServiceAvailable(t *testing.T) {
+	currentData := `
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1beta1.metrics.k8s.io
+`
+
+	state := buildAPIService(currentData, false, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Condition Available is not set",
+	}
+	require.Equal(t, expectedState, state)
+
+	currentData = `
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1beta1.metrics.k8s.io
+status:
+  conditions:
+  - type: Available
+    status: "False"
+    reason: FailedDiscoveryCheck
+    message: 'failing or missing response from https://10.96.0.10:443/apis/metrics.k8s.io/v1beta1'
+`
+
+	state = buildAPIService(currentData, false, t).IsDoneApplying()
+	expectedState = ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message: "Condition Available is not True (False): " +
+			"failing or missing response from https://10.96.0.10:443/apis/metrics.k8s.io/v1beta1",
+	}
+	require.Equal(t, expectedState, state)
+
+	state = buildAPIService(currentData, true, t).IsDoneApplying()
+	expectedState = ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: true,
+		Message: "Ignoring (Condition Available is not True (False): " +
+			"failing or missing response from https://10.96.0.10:443/apis/metrics.k8s.io/v1beta1)",
+	}
+	require.Equal(t, expectedState, state)
+
+	currentData = `
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1beta1.metrics.k8s.io
+status:
+  conditions:
+  - type: Available
+    status: "True"
+    reason: Passed
+    message: all checks passed
+`
+
+	state = buildAPIService(currentData, false, t).IsDoneApplying()
+	expectedState = ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: true,
+	}
+	require.Equal(t, expectedState, state)
+}
+
+func buildAPIService(resourcesBs string, ignoreFailing bool, t *testing.T) *ctlresm.APIRegistrationV1APIService {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	return ctlresm.NewAPIRegistrationV1APIService(newResources[0], ignoreFailing)
+}
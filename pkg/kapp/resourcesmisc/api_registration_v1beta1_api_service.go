@@ -26,7 +26,7 @@ func NewAPIRegistrationV1Beta1APIService(resource ctlres.Resource, ignoreFailing
 }
 
 func (s APIRegistrationV1Beta1APIService) IsDoneApplying() DoneApplyState {
-	allTrue, msg := Conditions{s.resource}.IsSelectedTrue([]string{"Available"})
+	allTrue, msg := apiServiceAvailable(s.resource)
 
 	if !allTrue && s.ignoreFailing {
 		return DoneApplyState{Done: true, Successful: true, Message: fmt.Sprintf("Ignoring (%s)", msg)}
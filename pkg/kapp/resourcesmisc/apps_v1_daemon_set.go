@@ -38,17 +38,34 @@ func (s AppsV1DaemonSet) IsDoneApplying() DoneApplyState {
 			"Waiting for generation %d to be observed", dset.Generation)}
 	}
 
+	desired := dset.Status.DesiredNumberScheduled
+
+	// DaemonSet may not match any nodes (e.g. due to node selector)
+	// in which case there is nothing to wait for
+	if desired == 0 {
+		return DoneApplyState{Done: true, Successful: true}
+	}
+
 	// ensure updated pods are actually scheduled before checking number unavailable to avoid
 	// race condition between pod scheduler and kapp state check
-	notReady := dset.Status.DesiredNumberScheduled - dset.Status.UpdatedNumberScheduled
+	notReady := desired - dset.Status.UpdatedNumberScheduled
 	if notReady > 0 {
 		return DoneApplyState{Done: false, Message: fmt.Sprintf(
-			"Waiting for %d updated pods to be scheduled", notReady)}
+			"Waiting for %d updated pods to be scheduled (%d/%d nodes updated)",
+			notReady, dset.Status.UpdatedNumberScheduled, desired)}
 	}
 
 	if dset.Status.NumberUnavailable > 0 {
 		return DoneApplyState{Done: false, Message: fmt.Sprintf(
-			"Waiting for %d unavailable pods", dset.Status.NumberUnavailable)}
+			"Waiting for %d unavailable pods (%d/%d nodes available)",
+			dset.Status.NumberUnavailable, desired-dset.Status.NumberUnavailable, desired)}
+	}
+
+	notReady = desired - dset.Status.NumberReady
+	if notReady > 0 {
+		return DoneApplyState{Done: false, Message: fmt.Sprintf(
+			"Waiting for %d pods to be ready (%d/%d nodes ready)",
+			notReady, dset.Status.NumberReady, desired)}
 	}
 
 	return DoneApplyState{Done: true, Successful: true}
@@ -37,6 +37,7 @@ metadata:
   generation: 1
 status:
   desiredNumberScheduled: 3
+  numberReady: 2
   numberUnavailable: 1
   observedGeneration: 1
   updatedNumberScheduled: 2
@@ -46,7 +47,7 @@ status:
 	expectedState = ctlresm.DoneApplyState{
 		Done:       false,
 		Successful: false,
-		Message:    "Waiting for 1 updated pods to be scheduled",
+		Message:    "Waiting for 1 updated pods to be scheduled (2/3 nodes updated)",
 	}
 	require.Equal(t, expectedState, state)
 
@@ -56,12 +57,22 @@ status:
 	expectedState = ctlresm.DoneApplyState{
 		Done:       false,
 		Successful: false,
-		Message:    "Waiting for 1 unavailable pods",
+		Message:    "Waiting for 1 unavailable pods (2/3 nodes available)",
 	}
 	require.Equal(t, expectedState, state)
 
 	currentData = strings.Replace(currentData, "numberUnavailable: 1", "numberUnavailable: 0", -1)
 
+	state = buildDaemonSet(currentData, t).IsDoneApplying()
+	expectedState = ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Waiting for 1 pods to be ready (2/3 nodes ready)",
+	}
+	require.Equal(t, expectedState, state)
+
+	currentData = strings.Replace(currentData, "numberReady: 2", "numberReady: 3", -1)
+
 	state = buildDaemonSet(currentData, t).IsDoneApplying()
 	expectedState = ctlresm.DoneApplyState{
 		Done:       true,
@@ -81,6 +92,7 @@ metadata:
   generation: 2
 status:
   desiredNumberScheduled: 3
+  numberReady: 3
   numberUnavailable: 0
   observedGeneration: 1
   updatedNumberScheduled: 3
@@ -102,7 +114,7 @@ status:
 	expectedState = ctlresm.DoneApplyState{
 		Done:       false,
 		Successful: false,
-		Message:    "Waiting for 3 updated pods to be scheduled",
+		Message:    "Waiting for 3 updated pods to be scheduled (0/3 nodes updated)",
 	}
 	require.Equal(t, expectedState, state)
 
@@ -114,7 +126,7 @@ status:
 	expectedState = ctlresm.DoneApplyState{
 		Done:       false,
 		Successful: false,
-		Message:    "Waiting for 2 updated pods to be scheduled",
+		Message:    "Waiting for 2 updated pods to be scheduled (1/3 nodes updated)",
 	}
 	require.Equal(t, expectedState, state)
 
@@ -125,7 +137,7 @@ status:
 	expectedState = ctlresm.DoneApplyState{
 		Done:       false,
 		Successful: false,
-		Message:    "Waiting for 1 unavailable pods",
+		Message:    "Waiting for 1 unavailable pods (2/3 nodes available)",
 	}
 	require.Equal(t, expectedState, state)
 
@@ -140,6 +152,29 @@ status:
 	require.Equal(t, expectedState, state)
 }
 
+func TestAppsV1DaemonSetNoDesiredNodes(t *testing.T) {
+	currentData := `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: fluentd
+  generation: 1
+status:
+  desiredNumberScheduled: 0
+  numberReady: 0
+  observedGeneration: 1
+  updatedNumberScheduled: 0
+`
+
+	state := buildDaemonSet(currentData, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: true,
+		Message:    "",
+	}
+	require.Equal(t, expectedState, state)
+}
+
 func buildDaemonSet(resourcesBs string, t *testing.T) *ctlresm.AppsV1DaemonSet {
 	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
 	require.NoErrorf(t, err, "Expected resources to parse")
@@ -46,7 +46,12 @@ func (s AppsV1StatefulSet) IsDoneApplying() DoneApplyState {
 	toUpdate := *statefulSet.Spec.Replicas
 	clarification := ""
 	if s.partition(statefulSet) {
+		// Only pods with ordinals at or above partition are updated;
+		// pods below it intentionally keep running current revision
 		toUpdate -= *statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition
+		if toUpdate < 0 {
+			toUpdate = 0
+		}
 		clarification = fmt.Sprintf(" (updating only %d of %d total)",
 			toUpdate, *statefulSet.Spec.Replicas)
 	}
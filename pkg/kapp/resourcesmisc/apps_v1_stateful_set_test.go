@@ -231,6 +231,98 @@ status:
 
 }
 
+func TestAppsV1StatefulSetUpdatePartitionCoveringAllReplicas(t *testing.T) {
+	// Partition at or above replicas count stages update without rolling out any pods
+	currentData := `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: web
+  generation: 2
+spec:
+  replicas: 3
+  updateStrategy:
+    rollingUpdate:
+      partition: 5
+status:
+  replicas: 3
+  currentReplicas: 3
+  observedGeneration: 2
+  updatedReplicas: 0
+  readyReplicas: 2
+`
+
+	state := buildStatefulSet(currentData, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Waiting for 1 replicas to be ready",
+	}
+	require.Equal(t, expectedState, state, "Found incorrect state")
+
+	currentData = strings.Replace(currentData, "readyReplicas: 2", "readyReplicas: 3", -1)
+
+	state = buildStatefulSet(currentData, t).IsDoneApplying()
+	expectedState = ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: true,
+		Message:    "",
+	}
+	require.Equal(t, expectedState, state, "Found incorrect state")
+}
+
+func TestAppsV1StatefulSetScaleUpPartition(t *testing.T) {
+	// Lower ordinal pods (0, 1) stay on current revision, new pods (2, 3) are created with update revision
+	currentData := `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: web
+  generation: 3
+spec:
+  replicas: 4
+  updateStrategy:
+    rollingUpdate:
+      partition: 2
+status:
+  replicas: 3
+  currentReplicas: 2
+  observedGeneration: 3
+  updatedReplicas: 1
+  readyReplicas: 3
+`
+
+	state := buildStatefulSet(currentData, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Waiting for 1 replicas to be updated (updating only 2 of 4 total)",
+	}
+	require.Equal(t, expectedState, state, "Found incorrect state")
+
+	currentData = strings.Replace(currentData, "status:\n  replicas: 3", "status:\n  replicas: 4", -1)
+	currentData = strings.Replace(currentData, "updatedReplicas: 1", "updatedReplicas: 2", -1)
+
+	state = buildStatefulSet(currentData, t).IsDoneApplying()
+	expectedState = ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Waiting for 1 replicas to be ready",
+	}
+	require.Equal(t, expectedState, state, "Found incorrect state")
+
+	// Pods below partition are not expected to be updated
+	currentData = strings.Replace(currentData, "readyReplicas: 3", "readyReplicas: 4", -1)
+
+	state = buildStatefulSet(currentData, t).IsDoneApplying()
+	expectedState = ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: true,
+		Message:    "",
+	}
+	require.Equal(t, expectedState, state, "Found incorrect state")
+}
+
 func TestAppsV1StatefulSetScaleDown(t *testing.T) {
 	currentData := `
 apiVersion: apps/v1
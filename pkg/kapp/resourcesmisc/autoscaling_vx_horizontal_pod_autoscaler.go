@@ -0,0 +1,123 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+const (
+	// autoscaling/v1 does not have status.conditions; they are stored in an annotation instead
+	hpaV1ConditionsAnnKey = "autoscaling.alpha.kubernetes.io/conditions"
+)
+
+type AutoscalingVxHorizontalPodAutoscaler struct {
+	resource ctlres.Resource
+}
+
+func NewAutoscalingVxHorizontalPodAutoscaler(resource ctlres.Resource) *AutoscalingVxHorizontalPodAutoscaler {
+	matcher := ctlres.APIGroupKindMatcher{
+		APIGroup: "autoscaling",
+		Kind:     "HorizontalPodAutoscaler",
+	}
+	if matcher.Matches(resource) {
+		return &AutoscalingVxHorizontalPodAutoscaler{resource}
+	}
+	return nil
+}
+
+func (s AutoscalingVxHorizontalPodAutoscaler) IsDoneApplying() DoneApplyState {
+	conds, err := s.conditions()
+	if err != nil {
+		return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf(
+			"Error: Failed to parse conditions: %s", err)}
+	}
+
+	ableToScale, found := conds["AbleToScale"]
+	if !found {
+		return DoneApplyState{Done: false, Message: "Condition AbleToScale is not set"}
+	}
+	if ableToScale.Status != "True" {
+		return DoneApplyState{Done: false, Message: fmt.Sprintf(
+			"Condition AbleToScale is not True (%s)", s.reasonAndMessage(ableToScale))}
+	}
+
+	scalingActive, found := conds["ScalingActive"]
+	if !found {
+		return DoneApplyState{Done: false, Message: "Condition ScalingActive is not set"}
+	}
+
+	switch {
+	case scalingActive.Status == "True":
+		return DoneApplyState{Done: true, Successful: true}
+
+	// Reason is specific to metric type (e.g. FailedGetMetrics, FailedGetResourceMetric)
+	case scalingActive.Status == "False" && strings.HasPrefix(scalingActive.Reason, "FailedGet"):
+		return DoneApplyState{Done: false, Message: fmt.Sprintf(
+			"Waiting for metrics to become available (%s)", s.reasonAndMessage(scalingActive))}
+
+	case scalingActive.Status == "False" && scalingActive.Reason == "ScalingDisabled":
+		// Target was scaled to zero replicas hence autoscaling is intentionally turned off
+		return DoneApplyState{Done: true, Successful: true, Message: "Scaling is disabled"}
+
+	default:
+		return DoneApplyState{Done: false, Message: fmt.Sprintf(
+			"Condition ScalingActive is not True (%s)", s.reasonAndMessage(scalingActive))}
+	}
+}
+
+func (s AutoscalingVxHorizontalPodAutoscaler) conditions() (map[string]condition, error) {
+	result := map[string]condition{}
+
+	for _, t := range []string{"AbleToScale", "ScalingActive"} {
+		if cond, found := (Conditions{s.resource}).find(t); found {
+			result[t] = cond
+		}
+	}
+	if len(result) > 0 {
+		return result, nil
+	}
+
+	if val, found := s.resource.Annotations()[hpaV1ConditionsAnnKey]; found {
+		var conds []condition
+
+		err := json.Unmarshal([]byte(val), &conds)
+		if err != nil {
+			return nil, err
+		}
+		for _, cond := range conds {
+			result[cond.Type] = cond
+		}
+	}
+
+	return result, nil
+}
+
+func (s AutoscalingVxHorizontalPodAutoscaler) reasonAndMessage(cond condition) string {
+	if len(cond.Message) > 0 {
+		return fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
+	}
+	return cond.Reason
+}
+
+/*
+
+status:
+  conditions:
+  - lastTransitionTime: "2024-01-10T18:43:14Z"
+    message: recommended size matches current size
+    reason: ReadyForNewScale
+    status: "True"
+    type: AbleToScale
+  - lastTransitionTime: "2024-01-10T18:43:14Z"
+    message: 'the HPA was unable to compute the replica count: failed to get cpu utilization'
+    reason: FailedGetResourceMetric
+    status: "False"
+    type: ScalingActive
+
+*/
@@ -0,0 +1,106 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoscalingHPAWithoutConditions(t *testing.T) {
+	currentData := `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+`
+
+	state := buildHPA(currentData, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Condition AbleToScale is not set",
+	}
+	require.Equal(t, expectedState, state)
+}
+
+func TestAutoscalingHPAFailedGetMetrics(t *testing.T) {
+	currentData := `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+status:
+  conditions:
+  - type: AbleToScale
+    status: "True"
+    reason: SucceededGetScale
+  - type: ScalingActive
+    status: "False"
+    reason: FailedGetMetrics
+    message: unable to get metrics for resource cpu
+`
+
+	state := buildHPA(currentData, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Waiting for metrics to become available (FailedGetMetrics: unable to get metrics for resource cpu)",
+	}
+	require.Equal(t, expectedState, state)
+}
+
+func TestAutoscalingHPAScalingActive(t *testing.T) {
+	currentData := `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+status:
+  conditions:
+  - type: AbleToScale
+    status: "True"
+    reason: SucceededGetScale
+  - type: ScalingActive
+    status: "True"
+    reason: ValidMetricFound
+`
+
+	state := buildHPA(currentData, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: true,
+		Message:    "",
+	}
+	require.Equal(t, expectedState, state)
+}
+
+func TestAutoscalingHPAV1ConditionsAnnotation(t *testing.T) {
+	currentData := `
+apiVersion: autoscaling/v1
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+  annotations:
+    autoscaling.alpha.kubernetes.io/conditions: '[{"type":"AbleToScale","status":"True","reason":"SucceededGetScale"},{"type":"ScalingActive","status":"False","reason":"ScalingDisabled"}]'
+`
+
+	state := buildHPA(currentData, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: true,
+		Message:    "Scaling is disabled",
+	}
+	require.Equal(t, expectedState, state)
+}
+
+func buildHPA(resourcesBs string, t *testing.T) *ctlresm.AutoscalingVxHorizontalPodAutoscaler {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	return ctlresm.NewAutoscalingVxHorizontalPodAutoscaler(newResources[0])
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"fmt"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	certManagerIoCertificateFailedReason = "Failed"
+)
+
+// certManagerIoCertificate includes only fields used for determining readiness
+// so that kapp does not need to depend on cert-manager API packages
+type certManagerIoCertificate struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status struct {
+		Conditions []metav1.Condition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type CertManagerIoVxCertificate struct {
+	resource ctlres.Resource
+}
+
+func NewCertManagerIoVxCertificate(resource ctlres.Resource) *CertManagerIoVxCertificate {
+	matcher := ctlres.APIGroupKindMatcher{
+		APIGroup: "cert-manager.io",
+		Kind:     "Certificate",
+	}
+	if matcher.Matches(resource) {
+		return &CertManagerIoVxCertificate{resource}
+	}
+	return nil
+}
+
+func (s CertManagerIoVxCertificate) IsDoneApplying() DoneApplyState {
+	cert := certManagerIoCertificate{}
+
+	err := s.resource.AsUncheckedTypedObj(&cert)
+	if err != nil {
+		return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: Failed obj conversion: %s", err)}
+	}
+
+	var readyCond, issuingCond *metav1.Condition
+
+	for i, cond := range cert.Status.Conditions {
+		switch cond.Type {
+		case "Ready":
+			readyCond = &cert.Status.Conditions[i]
+		case "Issuing":
+			issuingCond = &cert.Status.Conditions[i]
+		}
+	}
+
+	// Issuing condition is only present while certificate is being issued,
+	// and is set to False with reason Failed when last issuance failed
+	if issuingCond != nil && issuingCond.Status == metav1.ConditionFalse && issuingCond.Reason == certManagerIoCertificateFailedReason {
+		return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf(
+			"Failed to issue certificate (reason: %s, message: %s)", issuingCond.Reason, issuingCond.Message)}
+	}
+
+	if readyCond == nil {
+		return DoneApplyState{Done: false, Message: "Waiting for Ready condition"}
+	}
+
+	if readyCond.ObservedGeneration != 0 && readyCond.ObservedGeneration < cert.Generation {
+		return DoneApplyState{Done: false, Message: fmt.Sprintf(
+			"Waiting for generation %d to be observed", cert.Generation)}
+	}
+
+	if readyCond.Status != metav1.ConditionTrue {
+		return DoneApplyState{Done: false, Message: fmt.Sprintf(
+			"Waiting for certificate to be ready (reason: %s, message: %s)", readyCond.Reason, readyCond.Message)}
+	}
+
+	return DoneApplyState{Done: true, Successful: true}
+}
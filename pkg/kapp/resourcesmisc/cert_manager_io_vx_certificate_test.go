@@ -0,0 +1,110 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertManagerIoVxCertificateReady(t *testing.T) {
+	currentData := `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: app-tls
+  generation: 1
+`
+
+	state := buildCertificate(currentData, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Waiting for Ready condition",
+	}
+	require.Equal(t, expectedState, state)
+
+	currentData = `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: app-tls
+  generation: 1
+status:
+  conditions:
+  - type: Ready
+    status: "False"
+    reason: DoesNotExist
+    message: Issuing certificate as Secret does not exist
+    observedGeneration: 1
+`
+
+	state = buildCertificate(currentData, t).IsDoneApplying()
+	expectedState = ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Waiting for certificate to be ready (reason: DoesNotExist, message: Issuing certificate as Secret does not exist)",
+	}
+	require.Equal(t, expectedState, state)
+
+	currentData = `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: app-tls
+  generation: 1
+status:
+  conditions:
+  - type: Ready
+    status: "True"
+    reason: Ready
+    observedGeneration: 1
+`
+
+	state = buildCertificate(currentData, t).IsDoneApplying()
+	expectedState = ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: true,
+	}
+	require.Equal(t, expectedState, state)
+}
+
+func TestCertManagerIoVxCertificateFailed(t *testing.T) {
+	currentData := `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: app-tls
+  generation: 1
+status:
+  conditions:
+  - type: Ready
+    status: "False"
+    reason: DoesNotExist
+    observedGeneration: 1
+  - type: Issuing
+    status: "False"
+    reason: Failed
+    message: The certificate request has failed to complete
+    observedGeneration: 1
+`
+
+	state := buildCertificate(currentData, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: false,
+		Message:    "Failed to issue certificate (reason: Failed, message: The certificate request has failed to complete)",
+	}
+	require.Equal(t, expectedState, state)
+}
+
+func buildCertificate(resourcesBs string, t *testing.T) *ctlresm.CertManagerIoVxCertificate {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	return ctlresm.NewCertManagerIoVxCertificate(newResources[0])
+}
@@ -44,6 +44,30 @@ func (c Conditions) IsAllTrue() (bool, string) {
 	return true, ""
 }
 
+type condition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+func (c Conditions) find(checkedType string) (condition, bool) {
+	if conditions, ok := c.resource.Status()["conditions"].([]interface{}); ok {
+		for _, cond := range conditions {
+			if typedCond, ok := cond.(map[string]interface{}); ok {
+				if typedType, ok := typedCond["type"].(string); ok && typedType == checkedType {
+					result := condition{Type: typedType}
+					result.Status, _ = typedCond["status"].(string)
+					result.Reason, _ = typedCond["reason"].(string)
+					result.Message, _ = typedCond["message"].(string)
+					return result, true
+				}
+			}
+		}
+	}
+	return condition{}, false
+}
+
 func (c Conditions) statuses() map[string]string {
 	statuses := map[string]string{}
 	if conditions, ok := c.resource.Status()["conditions"].([]interface{}); ok {
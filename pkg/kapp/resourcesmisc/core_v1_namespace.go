@@ -0,0 +1,47 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"fmt"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type CoreV1Namespace struct {
+	resource ctlres.Resource
+}
+
+func NewCoreV1Namespace(resource ctlres.Resource) *CoreV1Namespace {
+	matcher := ctlres.APIVersionKindMatcher{
+		APIVersion: "v1",
+		Kind:       "Namespace",
+	}
+	if matcher.Matches(resource) {
+		return &CoreV1Namespace{resource}
+	}
+	return nil
+}
+
+func (s CoreV1Namespace) IsDoneApplying() DoneApplyState {
+	ns := corev1.Namespace{}
+
+	err := s.resource.AsTypedObj(&ns)
+	if err != nil {
+		return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: Failed obj conversion: %s", err)}
+	}
+
+	// Namespace that is being deleted will not accept new resources,
+	// hence there is no point in waiting for it
+	if ns.DeletionTimestamp != nil || ns.Status.Phase == corev1.NamespaceTerminating {
+		return DoneApplyState{Done: true, Successful: false, Message: "Namespace is terminating"}
+	}
+
+	if ns.Status.Phase != corev1.NamespaceActive {
+		return DoneApplyState{Done: false, Message: fmt.Sprintf("Waiting for namespace to become active (phase: %s)", ns.Status.Phase)}
+	}
+
+	return DoneApplyState{Done: true, Successful: true}
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoreV1NamespaceActive(t *testing.T) {
+	currentData := `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app-ns
+`
+
+	state := buildNamespace(currentData, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Waiting for namespace to become active (phase: )",
+	}
+	require.Equal(t, expectedState, state)
+
+	currentData = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app-ns
+status:
+  phase: Active
+`
+
+	state = buildNamespace(currentData, t).IsDoneApplying()
+	expectedState = ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: true,
+	}
+	require.Equal(t, expectedState, state)
+}
+
+func TestCoreV1NamespaceTerminating(t *testing.T) {
+	currentData := `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app-ns
+  deletionTimestamp: "2024-01-01T00:00:00Z"
+status:
+  phase: Terminating
+`
+
+	state := buildNamespace(currentData, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       true,
+		Successful: false,
+		Message:    "Namespace is terminating",
+	}
+	require.Equal(t, expectedState, state)
+}
+
+func buildNamespace(resourcesBs string, t *testing.T) *ctlresm.CoreV1Namespace {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	return ctlresm.NewCoreV1Namespace(newResources[0])
+}
@@ -8,23 +8,43 @@ import (
 
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+const (
+	coreV1ServiceWaitForEndpointsAnnKey = "kapp.k14s.io/wait-for-endpoints" // valid value is ''
+	endpointSliceServiceNameLabelKey    = discoveryv1.LabelServiceName
 )
 
 type CoreV1Service struct {
-	resource ctlres.Resource
+	resource         ctlres.Resource
+	associatedRs     []ctlres.Resource
+	waitForEndpoints bool
 }
 
 func NewCoreV1Service(resource ctlres.Resource) *CoreV1Service {
+	return NewCoreV1ServiceWithEndpoints(resource, nil, false)
+}
+
+// NewCoreV1ServiceWithEndpoints returns service that (when waitForEndpoints is true)
+// is considered done only when associated EndpointSlices have at least one ready endpoint
+func NewCoreV1ServiceWithEndpoints(resource ctlres.Resource, associatedRs []ctlres.Resource, waitForEndpoints bool) *CoreV1Service {
 	matcher := ctlres.APIVersionKindMatcher{
 		APIVersion: "v1",
 		Kind:       "Service",
 	}
 	if matcher.Matches(resource) {
-		return &CoreV1Service{resource}
+		return &CoreV1Service{resource, associatedRs, waitForEndpoints}
 	}
 	return nil
 }
 
+// CoreV1ServiceWaitsForEndpoints returns true if service is annotated to wait for endpoints
+func CoreV1ServiceWaitsForEndpoints(resource ctlres.Resource) bool {
+	_, found := resource.Annotations()[coreV1ServiceWaitForEndpointsAnnKey]
+	return found
+}
+
 func (s CoreV1Service) IsDoneApplying() DoneApplyState {
 	svc := corev1.Service{}
 
@@ -37,7 +57,11 @@ func (s CoreV1Service) IsDoneApplying() DoneApplyState {
 		return DoneApplyState{Done: true, Successful: true, Message: "External service"}
 	}
 
-	if svc.Spec.ClusterIP != corev1.ClusterIPNone && len(svc.Spec.ClusterIP) == 0 {
+	if svc.Spec.ClusterIP == corev1.ClusterIPNone {
+		return DoneApplyState{Done: true, Successful: true, Message: "Headless service"}
+	}
+
+	if len(svc.Spec.ClusterIP) == 0 {
 		return DoneApplyState{Done: false, Message: "ClusterIP is empty"}
 	}
 
@@ -47,5 +71,33 @@ func (s CoreV1Service) IsDoneApplying() DoneApplyState {
 		}
 	}
 
+	if s.waitForEndpoints {
+		return s.isEndpointReady(svc)
+	}
+
 	return DoneApplyState{Done: true, Successful: true}
 }
+
+func (s CoreV1Service) isEndpointReady(svc corev1.Service) DoneApplyState {
+	for _, res := range s.associatedRs {
+		if res.Kind() != "EndpointSlice" || res.Labels()[endpointSliceServiceNameLabelKey] != svc.Name {
+			continue
+		}
+
+		slice := discoveryv1.EndpointSlice{}
+
+		err := res.AsUncheckedTypedObj(&slice)
+		if err != nil {
+			return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: Failed obj conversion: %s", err)}
+		}
+
+		for _, endpoint := range slice.Endpoints {
+			// Nil ready condition should be interpreted as ready
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				return DoneApplyState{Done: true, Successful: true}
+			}
+		}
+	}
+
+	return DoneApplyState{Done: false, Message: "Waiting for at least one ready endpoint"}
+}
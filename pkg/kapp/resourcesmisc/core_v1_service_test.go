@@ -0,0 +1,116 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoreV1ServiceWaitForEndpoints(t *testing.T) {
+	svcData := `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+  annotations:
+    kapp.k14s.io/wait-for-endpoints: ""
+spec:
+  clusterIP: 10.0.0.1
+`
+
+	svcRes := buildServiceRes(svcData, t)
+	require.True(t, ctlresm.CoreV1ServiceWaitsForEndpoints(svcRes))
+
+	state := ctlresm.NewCoreV1ServiceWithEndpoints(svcRes, nil, true).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: false, Message: "Waiting for at least one ready endpoint"}, state)
+
+	notReadySlice := buildServiceRes(`
+apiVersion: discovery.k8s.io/v1
+kind: EndpointSlice
+metadata:
+  name: svc-abc
+  labels:
+    kubernetes.io/service-name: svc
+addressType: IPv4
+endpoints:
+- addresses: [10.1.0.1]
+  conditions:
+    ready: false
+`, t)
+
+	state = ctlresm.NewCoreV1ServiceWithEndpoints(svcRes, []ctlres.Resource{notReadySlice}, true).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: false, Message: "Waiting for at least one ready endpoint"}, state)
+
+	otherSvcSlice := buildServiceRes(`
+apiVersion: discovery.k8s.io/v1
+kind: EndpointSlice
+metadata:
+  name: other-svc-abc
+  labels:
+    kubernetes.io/service-name: other-svc
+addressType: IPv4
+endpoints:
+- addresses: [10.1.0.2]
+  conditions:
+    ready: true
+`, t)
+
+	state = ctlresm.NewCoreV1ServiceWithEndpoints(svcRes, []ctlres.Resource{notReadySlice, otherSvcSlice}, true).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: false, Message: "Waiting for at least one ready endpoint"}, state)
+
+	readySlice := buildServiceRes(`
+apiVersion: discovery.k8s.io/v1
+kind: EndpointSlice
+metadata:
+  name: svc-def
+  labels:
+    kubernetes.io/service-name: svc
+addressType: IPv4
+endpoints:
+- addresses: [10.1.0.3]
+  conditions:
+    ready: true
+`, t)
+
+	state = ctlresm.NewCoreV1ServiceWithEndpoints(svcRes, []ctlres.Resource{notReadySlice, readySlice}, true).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: true, Successful: true}, state)
+}
+
+func TestCoreV1ServiceWaitForEndpointsHeadlessAndExternal(t *testing.T) {
+	headlessRes := buildServiceRes(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  clusterIP: None
+`, t)
+
+	state := ctlresm.NewCoreV1ServiceWithEndpoints(headlessRes, nil, true).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: true, Successful: true, Message: "Headless service"}, state)
+
+	externalRes := buildServiceRes(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  type: ExternalName
+  externalName: example.com
+`, t)
+
+	state = ctlresm.NewCoreV1ServiceWithEndpoints(externalRes, nil, true).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: true, Successful: true, Message: "External service"}, state)
+}
+
+func buildServiceRes(resourcesBs string, t *testing.T) ctlres.Resource {
+	newResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resourcesBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	return newResources[0]
+}
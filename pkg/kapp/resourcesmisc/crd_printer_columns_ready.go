@@ -0,0 +1,127 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+const (
+	crdPrinterColumnsReadyColumnName = "Ready"
+)
+
+// CRDPrinterColumnsReady determines readiness of a custom resource
+// based on the value of 'Ready' column listed in its CRD's
+// additionalPrinterColumns (e.g. .status.conditions[?(@.type=="Ready")].status).
+// It's only used for custom resources that do not have more specific waiting logic.
+type CRDPrinterColumnsReady struct {
+	resource ctlres.Resource
+	path     ctlres.JSONPath
+}
+
+// NewCRDPrinterColumnsReady returns nil if CRD is not provided
+// or it does not have a suitable printer column
+func NewCRDPrinterColumnsReady(resource, crd ctlres.Resource) *CRDPrinterColumnsReady {
+	if crd == nil {
+		return nil
+	}
+
+	crdRes := NewAPIExtensionsVxCRD(crd)
+	if crdRes == nil {
+		return nil
+	}
+
+	contents, err := crdRes.contents()
+	if err != nil {
+		return nil
+	}
+
+	if contents.Spec.Group != resource.APIGroup() || contents.Spec.Names.Kind != resource.Kind() {
+		return nil
+	}
+
+	for _, column := range contents.PrinterColumns(resource.APIVersion()) {
+		if !strings.EqualFold(column.Name, crdPrinterColumnsReadyColumnName) {
+			continue
+		}
+		if column.Type != "string" && column.Type != "boolean" {
+			continue
+		}
+		path, err := ctlres.NewJSONPath(column.JSONPath)
+		if err != nil {
+			// Fallback to existence check for unsupported paths
+			return nil
+		}
+		return &CRDPrinterColumnsReady{resource, path}
+	}
+
+	return nil
+}
+
+func (s CRDPrinterColumnsReady) IsDoneApplying() DoneApplyState {
+	deletingRes := NewDeleting(s.resource)
+	if deletingRes != nil {
+		return deletingRes.IsDoneApplying()
+	}
+
+	vals := s.path.Eval(s.resource.UnstructuredObject())
+	if len(vals) == 0 {
+		return DoneApplyState{Done: false, Message: fmt.Sprintf(
+			"Waiting for printer column '%s' to be populated", crdPrinterColumnsReadyColumnName)}
+	}
+
+	val := fmt.Sprintf("%v", vals[0])
+
+	if s.isReady(val) {
+		return DoneApplyState{Done: true, Successful: true}
+	}
+
+	return DoneApplyState{Done: false, Message: fmt.Sprintf(
+		"Waiting for printer column '%s' to be ready (currently: %s)", crdPrinterColumnsReadyColumnName, val)}
+}
+
+func (s CRDPrinterColumnsReady) isReady(val string) bool {
+	if strings.EqualFold(val, "true") {
+		return true
+	}
+
+	// Some resources show number of ready replicas (e.g. 2/2)
+	pieces := strings.Split(val, "/")
+	if len(pieces) == 2 {
+		ready, err1 := strconv.Atoi(strings.TrimSpace(pieces[0]))
+		desired, err2 := strconv.Atoi(strings.TrimSpace(pieces[1]))
+		return err1 == nil && err2 == nil && desired > 0 && ready == desired
+	}
+
+	return false
+}
+
+// JSONPath is matched case-insensitively hence
+// works for both v1 (jsonPath) and v1beta1 (JSONPath) CRDs
+type crdSpecPrinterColumn struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"`
+	JSONPath string `yaml:"jsonPath"`
+}
+
+// PrinterColumns returns printer columns for given resource API version
+// (v1 CRDs specify them per version, v1beta1 CRDs may specify them for all versions)
+func (o crdObj) PrinterColumns(apiVersion string) []crdSpecPrinterColumn {
+	version := apiVersion
+	if idx := strings.LastIndex(apiVersion, "/"); idx >= 0 {
+		version = apiVersion[idx+1:]
+	}
+
+	for _, ver := range o.Spec.Versions {
+		if ver.Name == version && len(ver.AdditionalPrinterColumns) > 0 {
+			return ver.AdditionalPrinterColumns
+		}
+	}
+
+	return o.Spec.AdditionalPrinterColumns
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRDPrinterColumnsReadyConditions(t *testing.T) {
+	crd := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: databases.example.com
+spec:
+  group: example.com
+  names:
+    kind: Database
+    plural: databases
+  versions:
+  - name: v1
+    additionalPrinterColumns:
+    - name: Ready
+      type: string
+      jsonPath: .status.conditions[?(@.type=="Ready")].status
+`))
+
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+`))
+
+	state := ctlresm.NewCRDPrinterColumnsReady(res, crd).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: false, Message: "Waiting for printer column 'Ready' to be populated"}, state)
+
+	res = ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+status:
+  conditions:
+  - type: Provisioned
+    status: "True"
+  - type: Ready
+    status: "False"
+`))
+
+	state = ctlresm.NewCRDPrinterColumnsReady(res, crd).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: false, Message: "Waiting for printer column 'Ready' to be ready (currently: False)"}, state)
+
+	res = ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+status:
+  conditions:
+  - type: Ready
+    status: "True"
+`))
+
+	state = ctlresm.NewCRDPrinterColumnsReady(res, crd).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: true, Successful: true}, state)
+}
+
+func TestCRDPrinterColumnsReadyV1beta1(t *testing.T) {
+	crd := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: caches.example.com
+spec:
+  group: example.com
+  version: v1alpha1
+  names:
+    kind: Cache
+    plural: caches
+  additionalPrinterColumns:
+  - name: READY
+    type: string
+    JSONPath: .status.readyReplicas
+`))
+
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1alpha1
+kind: Cache
+metadata:
+  name: cache
+status:
+  readyReplicas: 1/2
+`))
+
+	state := ctlresm.NewCRDPrinterColumnsReady(res, crd).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: false, Message: "Waiting for printer column 'Ready' to be ready (currently: 1/2)"}, state)
+
+	res = ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1alpha1
+kind: Cache
+metadata:
+  name: cache
+status:
+  readyReplicas: 2/2
+`))
+
+	state = ctlresm.NewCRDPrinterColumnsReady(res, crd).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: true, Successful: true}, state)
+}
+
+func TestCRDPrinterColumnsReadyWithoutSuitableColumn(t *testing.T) {
+	crd := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: databases.example.com
+spec:
+  group: example.com
+  names:
+    kind: Database
+    plural: databases
+  versions:
+  - name: v1
+    additionalPrinterColumns:
+    - name: Age
+      type: date
+      jsonPath: .metadata.creationTimestamp
+`))
+
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+`))
+
+	require.Nil(t, ctlresm.NewCRDPrinterColumnsReady(res, crd))
+	require.Nil(t, ctlresm.NewCRDPrinterColumnsReady(res, nil))
+}
@@ -18,6 +18,10 @@ type CustomWaitingResource struct {
 
 func NewCustomWaitingResource(resource ctlres.Resource, waitRules []ctlconf.WaitRule) *CustomWaitingResource {
 	for _, rule := range waitRules {
+		// Services waiting for endpoints are handled by CoreV1Service
+		if rule.WaitForEndpoints {
+			continue
+		}
 		if rule.ResourceMatcher().Matches(resource) {
 			return &CustomWaitingResource{resource, rule}
 		}
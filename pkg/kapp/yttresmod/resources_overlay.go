@@ -0,0 +1,80 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package yttresmod
+
+import (
+	"fmt"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	cmdtpl "github.com/k14s/ytt/pkg/cmd/template"
+	"github.com/k14s/ytt/pkg/cmd/ui"
+	"github.com/k14s/ytt/pkg/files"
+)
+
+const (
+	resourcesOverlayResourcesFile = "resources.yml"
+	resourcesOverlayOverlayFile   = "overlay.yml"
+)
+
+// ResourcesOverlay applies ytt overlay to a whole set of resources
+// (as opposed to OverlayContractV1Mod which works on a single resource)
+type ResourcesOverlay struct {
+	overlayYAML []byte
+	description string
+}
+
+func NewResourcesOverlay(overlayYAML []byte, description string) ResourcesOverlay {
+	return ResourcesOverlay{overlayYAML, description}
+}
+
+func (o ResourcesOverlay) Apply(rs []ctlres.Resource) ([]ctlres.Resource, error) {
+	var resourcesYAML []byte
+
+	origins := map[string]string{}
+
+	for _, res := range rs {
+		resBs, err := res.AsYAMLBytes()
+		if err != nil {
+			return nil, err
+		}
+		resourcesYAML = append(resourcesYAML, "---\n"...)
+		resourcesYAML = append(resourcesYAML, resBs...)
+
+		origins[ctlres.NewUniqueResourceKey(res).String()] = res.Origin()
+	}
+
+	opts := cmdtpl.NewOptions()
+
+	// Files have to be ordered since overlays may produce multiple outputs
+	filesToProcess := files.NewSortedFiles([]*files.File{
+		files.MustNewFileFromSource(files.NewBytesSource(resourcesOverlayResourcesFile, resourcesYAML)),
+		files.MustNewFileFromSource(files.NewBytesSource(resourcesOverlayOverlayFile, o.overlayYAML)),
+	})
+
+	out := opts.RunWithFiles(cmdtpl.Input{Files: filesToProcess}, ui.NewTTY(false))
+	if out.Err != nil {
+		return nil, fmt.Errorf("Evaluating overlay %s: %w", o.description, out.Err)
+	}
+
+	// Documents appended by overlays end up in overlay output
+	var result []ctlres.Resource
+
+	for _, outFile := range out.Files {
+		fileRs, err := ctlres.NewFileResource(ctlres.NewBytesSource(outFile.Bytes())).Resources()
+		if err != nil {
+			return nil, fmt.Errorf("Deserializing result: %w", err)
+		}
+		result = append(result, fileRs...)
+	}
+
+	for _, res := range result {
+		if origin, found := origins[ctlres.NewUniqueResourceKey(res).String()]; found {
+			res.SetOrigin(origin)
+		} else {
+			res.SetOrigin(o.description)
+		}
+	}
+
+	return result, nil
+}
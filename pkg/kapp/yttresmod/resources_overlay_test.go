@@ -0,0 +1,101 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package yttresmod_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"carvel.dev/kapp/pkg/kapp/yttresmod"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourcesOverlay(t *testing.T) {
+	deploymentRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app
+`))
+	deploymentRes.SetOrigin("app.yml")
+
+	configMapRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+`))
+	configMapRes.SetOrigin("config.yml")
+
+	overlay := yttresmod.NewResourcesOverlay([]byte(`
+#@ load("@ytt:overlay", "overlay")
+
+#@overlay/match by=overlay.subset({"kind": "Deployment"}), expects="1+"
+---
+spec:
+  template:
+    spec:
+      containers:
+      #@overlay/append
+      - name: sidecar
+        image: sidecar
+
+#@overlay/append
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: sidecar
+  namespace: default
+`), "file 'overlay.yml'")
+
+	result, err := overlay.Apply([]ctlres.Resource{deploymentRes, configMapRes})
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+
+	require.Equal(t, deploymentRes.Description(), result[0].Description())
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"name": "app", "image": "app"},
+		map[string]interface{}{"name": "sidecar", "image": "sidecar"},
+	}, result[0].DeepCopyRaw()["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"])
+	require.Equal(t, "app.yml", result[0].Origin(), "Expected origin of transformed resource to be preserved")
+
+	require.Equal(t, configMapRes.Description(), result[1].Description())
+	require.Equal(t, "config.yml", result[1].Origin())
+
+	require.Equal(t, "serviceaccount/sidecar (v1) namespace: default", result[2].Description())
+	require.Equal(t, "file 'overlay.yml'", result[2].Origin(), "Expected added resource to originate from overlay")
+}
+
+func TestResourcesOverlayEvaluationError(t *testing.T) {
+	configMapRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`))
+
+	overlay := yttresmod.NewResourcesOverlay([]byte(`
+#@ load("@ytt:overlay", "overlay")
+
+#@overlay/match by=overlay.subset({"kind": "Deployment"})
+---
+metadata:
+  labels:
+    app: app
+`), "file 'overlay.yml'")
+
+	_, err := overlay.Apply([]ctlres.Resource{configMapRes})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Evaluating overlay file 'overlay.yml'")
+	require.Contains(t, err.Error(), "Expected number of matched nodes to be 1, but was 0")
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowedNamespaces(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+
+	name := "test-allowed-namespaces"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("refuse deploy before app is recorded outside of allowed namespaces", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--allowed-namespaces", "kapp-test-other-ns"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected app namespace '"+env.Namespace+"' to be within allowed namespaces (kapp-test-other-ns)")
+
+		NewMissingClusterResource(t, "configmap", name, env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "config", env.Namespace, kubectl)
+	})
+
+	logger.Section("deploy within allowed namespaces", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--allowed-namespaces", env.Namespace},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		NewPresentClusterResource("configmap", "config", env.Namespace, kubectl)
+	})
+
+	logger.Section("refuse delete outside of allowed namespaces", func() {
+		_, err := kapp.RunWithOpts([]string{"delete", "-a", name, "--allowed-namespaces", "kapp-test-other-ns"},
+			RunOpts{AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected app namespace '"+env.Namespace+"' to be within allowed namespaces")
+
+		NewPresentClusterResource("configmap", "config", env.Namespace, kubectl)
+	})
+}
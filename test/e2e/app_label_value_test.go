@@ -0,0 +1,58 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppLabelValueUniqueAcrossNamespaces(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	otherNs := "kapp-test-app-label-value"
+	name1 := "test-app-label-value-1"
+	name2 := "test-app-label-value-2"
+
+	yaml := func(name string) string {
+		return `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + name + `-cm
+`
+	}
+
+	cleanUp := func() {
+		kapp.RunWithOpts([]string{"delete", "-a", name1, "--app-namespace", otherNs}, RunOpts{AllowError: true})
+		kapp.Run([]string{"delete", "-a", name2})
+		kubectl.RunWithOpts([]string{"delete", "ns", otherNs, "--ignore-not-found"}, RunOpts{NoNamespace: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	kubectl.RunWithOpts([]string{"create", "ns", otherNs}, RunOpts{NoNamespace: true})
+
+	logger.Section("deploy app storing its record in another namespace", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name1, "--app-namespace", otherNs, "--app-label-value", "shared-value"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml(name1))})
+	})
+
+	logger.Section("deploy app with the same label value", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name2, "--app-label-value", "shared-value"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml(name2))})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected --app-label-value 'shared-value' to be unique")
+
+		NewMissingClusterResource(t, "configmap", name2+"-cm", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCPreview(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-b
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-orphaned
+  annotations:
+    kapp.k14s.io/delete-strategy: orphan
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  ports:
+  - port: 6380
+    targetPort: 6380
+  selector:
+    app: gc-preview
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-a
+`
+
+	name := "test-gc-preview"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kubectl.RunWithOpts([]string{"delete", "configmap", "cm-orphaned"}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("preview deletions without applying", func() {
+		out, _ := kapp.RunWithOpts([]string{"tools", "gc-preview", "-f", "-", "-a", name, "--json"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		var deleted []string
+		for _, row := range uitest.JSONUIFromBytes(t, []byte(out)).Tables[0].Rows {
+			deleted = append(deleted, row["kind"]+"/"+row["name"])
+		}
+		sort.Strings(deleted)
+
+		// Orphaned and transient (Endpoints) resources are not deleted by deploy
+		require.Equal(t, []string{"ConfigMap/cm-b", "Service/svc"}, deleted)
+
+		NewPresentClusterResource("configmap", "cm-b", env.Namespace, kubectl)
+		NewPresentClusterResource("service", "svc", env.Namespace, kubectl)
+	})
+
+	logger.Section("preview with all resources", func() {
+		out, _ := kapp.RunWithOpts([]string{"tools", "gc-preview", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Contains(t, out, "No resources would be deleted")
+	})
+}
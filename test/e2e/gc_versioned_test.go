@@ -0,0 +1,78 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCVersioned(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := func(data string) string {
+		return `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  annotations:
+    kapp.k14s.io/versioned: ""
+    kapp.k14s.io/num-versions: "3"
+data:
+  key: ` + data + `
+`
+	}
+
+	name := "test-gc-versioned"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy two versions", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml("1"))})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml("2"))})
+
+		// Simulate number of versions to keep being lowered without completing a deploy
+		kubectl.Run([]string{"annotate", "configmap", "config-ver-2", "kapp.k14s.io/num-versions=1", "--overwrite"})
+	})
+
+	logger.Section("list without deleting unless --yes is specified", func() {
+		out, _ := kapp.RunWithOpts([]string{"tools", "gc-versioned", "-a", name}, RunOpts{Interactive: true})
+
+		require.Contains(t, out, "config-ver-1")
+		require.NotContains(t, out, "Deleted")
+
+		NewPresentClusterResource("configmap", "config-ver-1", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "config-ver-2", env.Namespace, kubectl)
+	})
+
+	logger.Section("refuse deleting outside of allowed namespaces", func() {
+		_, err := kapp.RunWithOpts([]string{"tools", "gc-versioned", "-a", name,
+			"--allowed-namespaces", "kapp-test-other-ns"}, RunOpts{AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is outside of allowed namespaces")
+
+		NewPresentClusterResource("configmap", "config-ver-1", env.Namespace, kubectl)
+	})
+
+	logger.Section("delete when requested", func() {
+		out := kapp.Run([]string{"tools", "gc-versioned", "-a", name})
+
+		require.Contains(t, out, "Deleted 1 versioned resources")
+
+		NewMissingClusterResource(t, "configmap", "config-ver-1", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "config-ver-2", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteWithIdentityAnnotationDisabled(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+managedAnnotations:
+  disable:
+  - kapp.k14s.io/identity
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-without-identity
+data:
+  key: value
+`
+
+	name := "test-delete-with-identity-annotation-disabled"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy without identity annotation", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		cm := NewPresentClusterResource("configmap", "cm-without-identity", env.Namespace, kubectl)
+		annotations := cm.RawPath(ctlres.NewPathFromStrings([]string{"metadata", "annotations"}))
+		if annotations != nil {
+			require.NotContains(t, annotations, "kapp.k14s.io/identity")
+		}
+	})
+
+	logger.Section("inspect shows resource as owned by kapp", func() {
+		out := kapp.Run([]string{"inspect", "-a", name, "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 1)
+		require.Equal(t, "cm-without-identity", resp.Tables[0].Rows[0]["name"])
+		require.Equal(t, "kapp", resp.Tables[0].Rows[0]["owner"])
+	})
+
+	logger.Section("delete removes resource", func() {
+		kapp.Run([]string{"delete", "-a", name})
+		NewMissingClusterResource(t, "configmap", "cm-without-identity", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseBeforeAndResume(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-gated
+  annotations:
+    kapp.k14s.io/pause-before: ""
+    kapp.k14s.io/change-group: "gated"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-after
+  annotations:
+    kapp.k14s.io/change-rule: "upsert after upserting gated"
+`
+
+	name := "test-pause-resume"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy pauses before annotated resource", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Paused before applying: configmap/cm-gated")
+		require.Contains(t, err.Error(), "exit code: '4'")
+
+		NewPresentClusterResource("configmap", "cm-first", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "cm-gated", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "cm-after", env.Namespace, kubectl)
+	})
+
+	logger.Section("resume applies only changes that were not applied", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--resume"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Contains(t, out, "Resuming 2 resource(s) that were not applied during last app change")
+		require.NotContains(t, out, "cm-first")
+
+		NewPresentClusterResource("configmap", "cm-gated", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "cm-after", env.Namespace, kubectl)
+	})
+}
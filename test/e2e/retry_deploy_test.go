@@ -0,0 +1,63 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryDeployFromStdinKeepsResources(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-retried
+data:
+  key: value
+`
+
+	// Unknown kind fails every deploy attempt before anything is applied
+	yaml2 := yaml1 + `
+---
+apiVersion: kapp.k14s.io/v1
+kind: MissingKindForRetry
+metadata:
+  name: missing
+`
+
+	name := "test-retry-deploy-from-stdin"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy initial", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		NewPresentClusterResource("configmap", "cm-retried", env.Namespace, kubectl)
+	})
+
+	logger.Section("retried deploy uses same resources on every attempt", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name,
+			"--retry-deploy-on", "MissingKindForRetry", "--retry-deploy-count", "1", "--retry-deploy-backoff", "0s",
+			"--dangerous-allow-empty-list-of-resources"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Deploy failed after 2 attempts")
+
+		NewPresentClusterResource("configmap", "cm-retried", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedResourceRemovedByCoOwnerWithoutLabel(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	sharedYAML := func(key string) string {
+		return `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+  annotations:
+    kapp.k14s.io/shared: ""
+data:
+  ` + key + `: owned
+`
+	}
+
+	otherYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-a-only
+`
+
+	appA := "test-shared-res-a"
+	appB := "test-shared-res-b"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", appA})
+		kapp.Run([]string{"delete", "-a", appB})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	sharedAppliedAnns := func(res ClusterResource) []string {
+		var keys []string
+		for key := range res.res.Annotations() {
+			if strings.HasPrefix(key, "kapp.k14s.io/shared-applied.") {
+				keys = append(keys, key)
+			}
+		}
+		return keys
+	}
+
+	logger.Section("deploy shared resource from both apps", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", appA},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(sharedYAML("a") + otherYAML)})
+
+		// Deploy with disabled non-labeled resources check to make sure
+		// shared resource is found without relying on it
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", appB, "--existing-non-labeled-resources-check=false"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(sharedYAML("b"))})
+
+		res := NewPresentClusterResource("configmap", "shared", env.Namespace, kubectl)
+		require.Equal(t, "owned", res.RawPath(ctlres.NewPathFromStrings([]string{"data", "a"})))
+		require.Equal(t, "owned", res.RawPath(ctlres.NewPathFromStrings([]string{"data", "b"})))
+		require.Len(t, sharedAppliedAnns(res), 2)
+	})
+
+	logger.Section("remove shared resource from app that does not hold the label", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", appA},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(otherYAML)})
+
+		res := NewPresentClusterResource("configmap", "shared", env.Namespace, kubectl)
+		require.Equal(t, map[string]interface{}{"b": "owned"}, res.RawPath(ctlres.NewPathFromStrings([]string{"data"})))
+		require.Len(t, sharedAppliedAnns(res), 1)
+	})
+
+	logger.Section("delete last co-owner", func() {
+		kapp.Run([]string{"delete", "-a", appB})
+
+		NewMissingClusterResource(t, "configmap", "shared", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "app-a-only", env.Namespace, kubectl)
+	})
+}
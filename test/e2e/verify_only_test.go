@@ -0,0 +1,79 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployVerifyOnly(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	name := "test-deploy-verify-only"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	yaml1 := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: value1
+`
+
+	yaml2 := strings.Replace(yaml1, "value1", "value2", 1)
+
+	logger.Section("drift before deploy", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--verify-only"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Errorf(t, err, "Expected to receive error")
+		require.Containsf(t, err.Error(), "Detected drift between provided resources and cluster state", "Expected to find stderr output")
+		require.Containsf(t, err.Error(), "exit code: '3'", "Expected to find exit code")
+
+		NewMissingClusterResource(t, "configmap", "config", env.Namespace, kubectl)
+	})
+
+	logger.Section("no drift after deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--verify-only"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Contains(t, out, "Verified: no drift detected")
+	})
+
+	logger.Section("drift with changed configuration", func() {
+		out, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--verify-only", "--diff-summary-only"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Errorf(t, err, "Expected to receive error")
+		require.Containsf(t, err.Error(), "exit code: '3'", "Expected to find exit code")
+		require.Contains(t, out, "update v1/ConfigMap "+env.Namespace+"/config")
+
+		cm := NewPresentClusterResource("configmap", "config", env.Namespace, kubectl)
+		require.Equal(t, "value1", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+	})
+
+	logger.Section("watch is not allowed", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--verify-only", "--watch"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Errorf(t, err, "Expected to receive error")
+		require.Contains(t, err.Error(), "Expected --verify-only to not be used with --watch")
+	})
+}